@@ -0,0 +1,30 @@
+package costing
+
+// MovingAverage - HPP per unit setelah menerima quantity unit seharga unitCost ke stok
+// yang sudah ada dengan HPP cost. Stok kosong atau minus (terjual lebih dulu sebelum
+// barang datang) tidak punya nilai sehingga HPP baru sama dengan harga beli.
+func MovingAverage(stock, cost, quantity, unitCost int) int {
+	if quantity <= 0 {
+		return cost
+	}
+	if stock <= 0 {
+		return unitCost
+	}
+	return roundDiv(stock*cost+quantity*unitCost, stock+quantity)
+}
+
+// MarginBps - margin kotor dalam basis poin dari pendapatan, 0 jika tidak ada pendapatan
+func MarginBps(revenue, cost int) int {
+	if revenue <= 0 {
+		return 0
+	}
+	return roundDiv((revenue-cost)*10000, revenue)
+}
+
+// roundDiv - pembagian bulat ke terdekat, setengah dibulatkan menjauhi nol
+func roundDiv(a, b int) int {
+	if a < 0 {
+		return -((-a + b/2) / b)
+	}
+	return (a + b/2) / b
+}
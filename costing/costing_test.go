@@ -0,0 +1,28 @@
+package costing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMovingAverage(t *testing.T) {
+	// 10 unit @ 5.000 + 30 unit @ 6.000 = 230.000 / 40 unit
+	assert.Equal(t, 5750, MovingAverage(10, 5000, 30, 6000))
+	// 3 unit @ 1.000 + 1 unit @ 1.001 = 4.001 / 4, dibulatkan
+	assert.Equal(t, 1000, MovingAverage(3, 1000, 1, 1001))
+	assert.Equal(t, 1001, MovingAverage(1, 1000, 1, 1001))
+}
+
+func TestMovingAverageEmptyStock(t *testing.T) {
+	assert.Equal(t, 6000, MovingAverage(0, 5000, 10, 6000))
+	assert.Equal(t, 6000, MovingAverage(-4, 5000, 10, 6000))
+	assert.Equal(t, 5000, MovingAverage(10, 5000, 0, 6000))
+}
+
+func TestMarginBps(t *testing.T) {
+	assert.Equal(t, 2500, MarginBps(20000, 15000))
+	assert.Equal(t, -5000, MarginBps(10000, 15000))
+	assert.Equal(t, 3333, MarginBps(3, 2))
+	assert.Equal(t, 0, MarginBps(0, 500))
+}
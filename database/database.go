@@ -2,18 +2,44 @@ package database
 
 import (
 	"database/sql"
+	"fmt"
 	"log"
+	"regexp"
+	"slices"
+	"strings"
 
 	_ "github.com/lib/pq"
 )
 
+// Driver yang didukung. Driver dipilih dari skema DB_CONN.
+const (
+	Postgres = "postgres"
+	SQLite   = "sqlite"
+)
+
 func InitDB(connectionString string) (*sql.DB, error) {
+	driver, dsn := ParseConn(connectionString)
+	if !slices.Contains(sql.Drivers(), driver) {
+		return nil, fmt.Errorf("driver %s tidak tersedia, build dengan -tags %s", driver, driver)
+	}
+
 	// Open database
-	db, err := sql.Open("postgres", connectionString)
+	db, err := sql.Open(driver, dsn)
 	if err != nil {
 		return nil, err
 	}
 
+	// SQLite hanya punya satu penulis; satu koneksi menghindari "database is locked"
+	// dan menjaga PRAGMA foreign_keys tetap aktif di koneksi yang dipakai
+	if driver == SQLite {
+		db.SetMaxOpenConns(1)
+		db.SetMaxIdleConns(1)
+		if _, err := db.Exec("PRAGMA foreign_keys = ON"); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+
 	// Test connection
 	err = db.Ping()
 	if err != nil {
@@ -21,9 +47,42 @@ func InitDB(connectionString string) (*sql.DB, error) {
 	}
 
 	// Set connection pool settings (optional tapi recommended)
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(5)
+	if driver == Postgres {
+		db.SetMaxOpenConns(25)
+		db.SetMaxIdleConns(5)
+	}
 
 	log.Println("Database connected successfully")
 	return db, nil
-}
\ No newline at end of file
+}
+
+// ParseConn - driver dan DSN dari DB_CONN. sqlite://kasir.db, sqlite:///var/lib/kasir.db
+// atau sqlite:kasir.db memakai SQLite; selain itu (postgres://... atau key=value) PostgreSQL.
+func ParseConn(conn string) (driver, dsn string) {
+	if path, ok := strings.CutPrefix(conn, "sqlite:"); ok {
+		return SQLite, strings.TrimPrefix(path, "//")
+	}
+	return Postgres, conn
+}
+
+// Driver - nama driver yang dipakai db, Postgres atau SQLite
+func Driver(db *sql.DB) string {
+	if strings.Contains(strings.ToLower(fmt.Sprintf("%T", db.Driver())), "sqlite") {
+		return SQLite
+	}
+	return Postgres
+}
+
+var placeholder = regexp.MustCompile(`\$(\d+)`)
+
+// Rebind - sesuaikan SQL gaya PostgreSQL dengan driver. Untuk SQLite placeholder $n
+// menjadi ?n, ILIKE menjadi LIKE (tidak membedakan huruf besar/kecil untuk ASCII) dan
+// FOR UPDATE dihapus karena SQLite mengunci seluruh database saat transaksi menulis.
+func Rebind(driver, query string) string {
+	if driver != SQLite {
+		return query
+	}
+	query = placeholder.ReplaceAllString(query, "?$1")
+	query = strings.ReplaceAll(query, " ILIKE ", " LIKE ")
+	return strings.ReplaceAll(query, " FOR UPDATE", "")
+}
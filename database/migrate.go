@@ -0,0 +1,221 @@
+package database
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Skema SQLite hanya berisi katalog (user, pajak, kategori, produk dan ledger stok)
+//
+//go:embed migrations/sqlite/*.sql
+var sqliteMigrationFiles embed.FS
+
+// Migration adalah satu versi skema dengan script up dan down
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+}
+
+// LoadMigrations - baca file migrations/<versi>_<nama>.(up|down).sql yang di-embed, urut berdasarkan versi
+func LoadMigrations() ([]Migration, error) {
+	return loadMigrations(migrationFiles, "migrations")
+}
+
+// LoadSQLiteMigrations - migration untuk SQLite dari migrations/sqlite
+func LoadSQLiteMigrations() ([]Migration, error) {
+	return loadMigrations(sqliteMigrationFiles, "migrations/sqlite")
+}
+
+func loadMigrations(files embed.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(files, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		filename := entry.Name()
+		base, direction, ok := strings.Cut(strings.TrimSuffix(filename, ".sql"), ".")
+		if !ok || (direction != "up" && direction != "down") {
+			return nil, fmt.Errorf("nama file migration tidak valid: %s", filename)
+		}
+		versionStr, name, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("nama file migration tidak valid: %s", filename)
+		}
+		version, err := strconv.Atoi(versionStr)
+		if err != nil {
+			return nil, fmt.Errorf("versi migration tidak valid: %s", filename)
+		}
+
+		content, err := files.ReadFile(dir + "/" + filename)
+		if err != nil {
+			return nil, err
+		}
+
+		m, exists := byVersion[version]
+		if !exists {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		}
+		if m.Name != name {
+			return nil, fmt.Errorf("versi migration %d dipakai lebih dari satu nama", version)
+		}
+		if direction == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s harus punya file up dan down", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// MigrateUp - jalankan semua migration yang belum diterapkan, masing-masing dalam transaction sendiri
+func MigrateUp(db *sql.DB) ([]Migration, error) {
+	migrations, applied, err := loadState(db)
+	if err != nil {
+		return nil, err
+	}
+
+	done := make([]Migration, 0)
+	for _, m := range migrations {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+
+		err := runInTx(db, m.Up, Rebind(Driver(db), "INSERT INTO schema_migrations (version, name) VALUES ($1, $2)"), m.Version, m.Name)
+		if err != nil {
+			return done, fmt.Errorf("migration %04d_%s gagal: %w", m.Version, m.Name, err)
+		}
+		done = append(done, m)
+	}
+
+	return done, nil
+}
+
+// MigrateDown - batalkan sejumlah steps migration terakhir yang sudah diterapkan
+func MigrateDown(db *sql.DB, steps int) ([]Migration, error) {
+	migrations, applied, err := loadState(db)
+	if err != nil {
+		return nil, err
+	}
+
+	done := make([]Migration, 0)
+	for i := len(migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		m := migrations[i]
+		if _, ok := applied[m.Version]; !ok {
+			continue
+		}
+
+		err := runInTx(db, m.Down, Rebind(Driver(db), "DELETE FROM schema_migrations WHERE version = $1"), m.Version)
+		if err != nil {
+			return done, fmt.Errorf("rollback %04d_%s gagal: %w", m.Version, m.Name, err)
+		}
+		done = append(done, m)
+	}
+
+	return done, nil
+}
+
+// GetMigrationStatus - daftar semua migration beserta waktu diterapkan (nil jika belum)
+func GetMigrationStatus(db *sql.DB) ([]MigrationStatus, error) {
+	migrations, applied, err := loadState(db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		status := MigrationStatus{Version: m.Version, Name: m.Name}
+		if at, ok := applied[m.Version]; ok {
+			status.AppliedAt = &at
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+func loadState(db *sql.DB) ([]Migration, map[int]time.Time, error) {
+	load, appliedAt := LoadMigrations, "TIMESTAMPTZ NOT NULL DEFAULT NOW()"
+	if Driver(db) == SQLite {
+		load, appliedAt = LoadSQLiteMigrations, "TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP"
+	}
+	migrations, err := load()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INT PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			applied_at ` + appliedAt + `
+		)
+	`)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := db.Query("SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var at time.Time
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, nil, err
+		}
+		applied[version] = at
+	}
+
+	return migrations, applied, rows.Err()
+}
+
+func runInTx(db *sql.DB, script, bookkeeping string, args ...interface{}) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(script); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(bookkeeping, args...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
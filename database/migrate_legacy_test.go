@@ -0,0 +1,143 @@
+package database
+
+import (
+	"database/sql"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// legacySchema - skema yang dulu dibuat manual mengikuti readme sebelum ada migration
+// (tabel katalog awal ditambah tabel transaksi, barcode, user dan ledger stok)
+const legacySchema = `
+CREATE TABLE categories (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE products (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    price INT NOT NULL,
+    stock INT NOT NULL DEFAULT 0,
+    category_id INT REFERENCES categories(id) ON DELETE SET NULL
+);
+
+CREATE TABLE transactions (
+    id SERIAL PRIMARY KEY,
+    total_amount INT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE transaction_details (
+    id SERIAL PRIMARY KEY,
+    transaction_id INT NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    product_id INT NOT NULL REFERENCES products(id),
+    product_name VARCHAR(255) NOT NULL,
+    quantity INT NOT NULL,
+    price INT NOT NULL,
+    subtotal INT NOT NULL
+);
+
+ALTER TABLE products ADD COLUMN sku VARCHAR(64) UNIQUE;
+
+CREATE TABLE product_barcodes (
+    code VARCHAR(13) PRIMARY KEY,
+    product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE
+);
+
+CREATE TABLE users (
+    id SERIAL PRIMARY KEY,
+    username VARCHAR(100) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    role VARCHAR(20) NOT NULL,
+    password_hash TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE stock_movements (
+    id SERIAL PRIMARY KEY,
+    product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL,
+    quantity INT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    username VARCHAR(100) NOT NULL DEFAULT '',
+    reference_id INT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+`
+
+// TestLegacyMigrationsIdempotent - migration untuk tabel yang dulu dibuat manual harus
+// bisa jalan di atas tabel yang sudah ada
+func TestLegacyMigrationsIdempotent(t *testing.T) {
+	migrations, err := LoadMigrations()
+	require.NoError(t, err)
+
+	plain := regexp.MustCompile(`(?i)CREATE (TABLE|INDEX|UNIQUE INDEX) (\w+)`)
+	for _, m := range migrations[:5] {
+		for _, match := range plain.FindAllStringSubmatch(m.Up, -1) {
+			assert.Equal(t, "IF", match[2], "%04d_%s: %s", m.Version, m.Name, match[0])
+		}
+	}
+}
+
+// TestMigrateLegacySchema berjalan jika TEST_DB_CONN diisi. Migration dijalankan di
+// schema sendiri sehingga tidak bentrok dengan test repository di schema public.
+func TestMigrateLegacySchema(t *testing.T) {
+	conn := os.Getenv("TEST_DB_CONN")
+	if conn == "" {
+		t.Skip("TEST_DB_CONN tidak diisi")
+	}
+	db, err := InitDB(conn)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	// Satu koneksi supaya search_path berlaku untuk semua query berikutnya
+	db.SetMaxOpenConns(1)
+	_, err = db.Exec(`
+		DROP SCHEMA IF EXISTS kasir_legacy CASCADE;
+		CREATE SCHEMA kasir_legacy;
+		SET search_path TO kasir_legacy;
+	`)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Exec("DROP SCHEMA IF EXISTS kasir_legacy CASCADE") })
+
+	_, err = db.Exec(legacySchema + `
+		INSERT INTO products (name, price, stock, sku) VALUES ('Kopi', 5000, 10, 'KP-01'), ('Teh', 3000, 0, 'TH-01');
+		INSERT INTO stock_movements (product_id, type, quantity, username) VALUES (1, 'restock', 4, 'siti');
+		INSERT INTO users (username, role, password_hash) VALUES ('admin', 'admin', 'hash');
+	`)
+	require.NoError(t, err)
+
+	_, err = MigrateUp(db)
+	require.NoError(t, err)
+
+	var ledger int
+	require.NoError(t, db.QueryRow("SELECT COALESCE(SUM(quantity), 0) FROM stock_movements WHERE product_id = 1").Scan(&ledger))
+	assert.Equal(t, 10, ledger, "saldo awal melengkapi ledger lama")
+	var opening int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM stock_movements WHERE product_id = 2").Scan(&opening))
+	assert.Equal(t, 0, opening, "produk tanpa stok tidak mendapat saldo awal")
+
+	var users int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM users").Scan(&users))
+	assert.Equal(t, 1, users)
+
+	var createdAt string
+	require.NoError(t, db.QueryRow(
+		"SELECT data_type FROM information_schema.columns WHERE table_schema = 'kasir_legacy' AND table_name = 'transactions' AND column_name = 'created_at'",
+	).Scan(&createdAt))
+	assert.Equal(t, "timestamp with time zone", createdAt)
+
+	_, err = db.Exec("DELETE FROM products WHERE id = 1")
+	assert.Error(t, err, "ledger menahan penghapusan produk")
+
+	var missing sql.NullString
+	err = db.QueryRow("SELECT to_regclass('kasir_legacy.idx_stock_movements_product_id')::text").Scan(&missing)
+	require.NoError(t, err)
+	assert.True(t, missing.Valid)
+}
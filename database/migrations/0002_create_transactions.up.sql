@@ -0,0 +1,21 @@
+-- IF NOT EXISTS: readme lama meminta tabel transaksi dibuat manual sebelum ada migration.
+CREATE TABLE IF NOT EXISTS transactions (
+    id SERIAL PRIMARY KEY,
+    total_amount INT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Tabel buatan manual memakai TIMESTAMP tanpa zona waktu
+ALTER TABLE transactions ALTER COLUMN created_at TYPE TIMESTAMPTZ;
+
+CREATE TABLE IF NOT EXISTS transaction_details (
+    id SERIAL PRIMARY KEY,
+    transaction_id INT NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    product_id INT NOT NULL REFERENCES products(id),
+    product_name VARCHAR(255) NOT NULL,
+    quantity INT NOT NULL,
+    price INT NOT NULL,
+    subtotal INT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_transaction_details_transaction_id ON transaction_details(transaction_id);
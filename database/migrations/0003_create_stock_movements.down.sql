@@ -0,0 +1 @@
+DROP TABLE stock_movements;
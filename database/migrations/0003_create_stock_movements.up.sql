@@ -0,0 +1,30 @@
+-- IF NOT EXISTS: readme lama meminta ledger dibuat manual sebelum ada migration.
+CREATE TABLE IF NOT EXISTS stock_movements (
+    id SERIAL PRIMARY KEY,
+    product_id INT NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    quantity INT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    username VARCHAR(100) NOT NULL DEFAULT '',
+    reference_id INT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE stock_movements ALTER COLUMN created_at TYPE TIMESTAMPTZ;
+
+-- Ledger tidak boleh hilang: produk yang punya riwayat stok tidak bisa dihapus. Tabel
+-- buatan manual memakai ON DELETE CASCADE, jadi constraint-nya diganti.
+ALTER TABLE stock_movements DROP CONSTRAINT IF EXISTS stock_movements_product_id_fkey;
+ALTER TABLE stock_movements ADD CONSTRAINT stock_movements_product_id_fkey
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE RESTRICT;
+
+CREATE INDEX IF NOT EXISTS idx_stock_movements_product_id ON stock_movements(product_id, created_at);
+
+-- Saldo awal: selisih stok dengan ledger yang sudah ada dicatat sebagai satu adjustment
+-- per produk supaya jumlah ledger sama dengan products.stock.
+INSERT INTO stock_movements (product_id, type, quantity, reason)
+SELECT p.id, 'adjustment', p.stock - COALESCE(SUM(m.quantity), 0), 'saldo awal'
+FROM products p
+LEFT JOIN stock_movements m ON m.product_id = p.id
+GROUP BY p.id, p.stock
+HAVING p.stock - COALESCE(SUM(m.quantity), 0) <> 0;
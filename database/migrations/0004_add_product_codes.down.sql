@@ -0,0 +1,2 @@
+DROP TABLE product_barcodes;
+ALTER TABLE products DROP COLUMN sku;
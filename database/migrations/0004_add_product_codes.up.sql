@@ -0,0 +1,9 @@
+-- IF NOT EXISTS: readme lama meminta kolom dan tabel ini dibuat manual sebelum ada migration.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS sku VARCHAR(64) UNIQUE;
+
+CREATE TABLE IF NOT EXISTS product_barcodes (
+    code VARCHAR(13) PRIMARY KEY,
+    product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_barcodes_product_id ON product_barcodes(product_id);
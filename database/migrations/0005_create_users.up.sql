@@ -0,0 +1,11 @@
+-- IF NOT EXISTS: readme lama meminta tabel users dibuat manual sebelum ada migration.
+CREATE TABLE IF NOT EXISTS users (
+    id SERIAL PRIMARY KEY,
+    username VARCHAR(100) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    role VARCHAR(20) NOT NULL,
+    password_hash TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE users ALTER COLUMN created_at TYPE TIMESTAMPTZ;
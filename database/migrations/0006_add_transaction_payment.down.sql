@@ -0,0 +1,4 @@
+ALTER TABLE transactions
+    DROP COLUMN cashier,
+    DROP COLUMN change_amount,
+    DROP COLUMN paid_amount;
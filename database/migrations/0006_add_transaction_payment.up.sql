@@ -0,0 +1,4 @@
+ALTER TABLE transactions
+    ADD COLUMN paid_amount INT NOT NULL DEFAULT 0,
+    ADD COLUMN change_amount INT NOT NULL DEFAULT 0,
+    ADD COLUMN cashier VARCHAR(100) NOT NULL DEFAULT '';
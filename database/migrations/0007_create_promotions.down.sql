@@ -0,0 +1,11 @@
+DROP TABLE transaction_discounts;
+
+ALTER TABLE transaction_details
+    DROP COLUMN discount;
+
+ALTER TABLE transactions
+    DROP COLUMN voucher_code,
+    DROP COLUMN discount_amount,
+    DROP COLUMN subtotal_amount;
+
+DROP TABLE promotions;
@@ -0,0 +1,46 @@
+CREATE TABLE promotions (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    value INT NOT NULL DEFAULT 0,
+    product_id INT REFERENCES products(id) ON DELETE CASCADE,
+    category_id INT REFERENCES categories(id) ON DELETE CASCADE,
+    buy_quantity INT NOT NULL DEFAULT 0,
+    get_quantity INT NOT NULL DEFAULT 0,
+    min_spend INT NOT NULL DEFAULT 0,
+    voucher_code VARCHAR(50),
+    starts_at TIMESTAMPTZ,
+    ends_at TIMESTAMPTZ,
+    happy_hour_start VARCHAR(5) NOT NULL DEFAULT '',
+    happy_hour_end VARCHAR(5) NOT NULL DEFAULT '',
+    priority INT NOT NULL DEFAULT 0,
+    stackable BOOLEAN NOT NULL DEFAULT FALSE,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (value >= 0),
+    CHECK (type IN ('percentage', 'fixed', 'buy_x_get_y', 'min_spend'))
+);
+
+CREATE UNIQUE INDEX idx_promotions_voucher_code ON promotions (UPPER(voucher_code));
+
+ALTER TABLE transactions
+    ADD COLUMN subtotal_amount INT NOT NULL DEFAULT 0,
+    ADD COLUMN discount_amount INT NOT NULL DEFAULT 0,
+    ADD COLUMN voucher_code VARCHAR(50) NOT NULL DEFAULT '';
+
+UPDATE transactions SET subtotal_amount = total_amount;
+
+ALTER TABLE transaction_details
+    ADD COLUMN discount INT NOT NULL DEFAULT 0;
+
+CREATE TABLE transaction_discounts (
+    id SERIAL PRIMARY KEY,
+    transaction_id INT NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    promotion_id INT REFERENCES promotions(id) ON DELETE SET NULL,
+    product_id INT,
+    name VARCHAR(255) NOT NULL,
+    amount INT NOT NULL,
+    explanation TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_transaction_discounts_transaction ON transaction_discounts (transaction_id);
@@ -0,0 +1,18 @@
+DROP TABLE transaction_taxes;
+
+ALTER TABLE transaction_details
+    DROP COLUMN tax,
+    DROP COLUMN dpp,
+    DROP COLUMN tax_rate_bps;
+
+ALTER TABLE transactions
+    DROP COLUMN prices_include_tax,
+    DROP COLUMN tax_amount;
+
+ALTER TABLE products
+    DROP COLUMN tax_rate_id;
+
+ALTER TABLE categories
+    DROP COLUMN tax_rate_id;
+
+DROP TABLE tax_rates;
@@ -0,0 +1,42 @@
+CREATE TABLE tax_rates (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    rate_bps INT NOT NULL,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (rate_bps >= 0 AND rate_bps <= 10000)
+);
+
+-- Hanya boleh ada satu tarif default
+CREATE UNIQUE INDEX idx_tax_rates_default ON tax_rates (is_default) WHERE is_default;
+
+INSERT INTO tax_rates (name, rate_bps, is_default) VALUES ('PPN', 1100, TRUE), ('Bebas PPN', 0, FALSE);
+
+ALTER TABLE categories
+    ADD COLUMN tax_rate_id INT REFERENCES tax_rates(id) ON DELETE SET NULL;
+
+ALTER TABLE products
+    ADD COLUMN tax_rate_id INT REFERENCES tax_rates(id) ON DELETE SET NULL;
+
+ALTER TABLE transactions
+    ADD COLUMN tax_amount INT NOT NULL DEFAULT 0,
+    ADD COLUMN prices_include_tax BOOLEAN NOT NULL DEFAULT TRUE;
+
+ALTER TABLE transaction_details
+    ADD COLUMN tax_rate_bps INT NOT NULL DEFAULT 0,
+    ADD COLUMN dpp INT NOT NULL DEFAULT 0,
+    ADD COLUMN tax INT NOT NULL DEFAULT 0;
+
+UPDATE transaction_details SET dpp = subtotal - discount;
+
+CREATE TABLE transaction_taxes (
+    id SERIAL PRIMARY KEY,
+    transaction_id INT NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    tax_rate_id INT REFERENCES tax_rates(id) ON DELETE SET NULL,
+    name VARCHAR(100) NOT NULL,
+    rate_bps INT NOT NULL,
+    dpp INT NOT NULL,
+    tax INT NOT NULL
+);
+
+CREATE INDEX idx_transaction_taxes_transaction ON transaction_taxes (transaction_id);
@@ -0,0 +1,4 @@
+DROP TABLE payments;
+
+ALTER TABLE transactions
+    DROP COLUMN status;
@@ -0,0 +1,26 @@
+ALTER TABLE transactions
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'paid';
+
+CREATE TABLE payments (
+    id SERIAL PRIMARY KEY,
+    transaction_id INT NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    method VARCHAR(20) NOT NULL,
+    amount INT NOT NULL CHECK (amount > 0),
+    reference VARCHAR(100) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL,
+    provider VARCHAR(50) NOT NULL DEFAULT '',
+    provider_ref VARCHAR(100),
+    payload TEXT NOT NULL DEFAULT '',
+    expires_at TIMESTAMPTZ,
+    paid_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (provider, provider_ref)
+);
+
+CREATE INDEX idx_payments_transaction ON payments (transaction_id);
+
+-- Transaksi lama dianggap dibayar tunai
+INSERT INTO payments (transaction_id, method, amount, status, paid_at, created_at)
+SELECT id, 'cash', GREATEST(paid_amount, total_amount), 'paid', created_at, created_at
+FROM transactions
+WHERE GREATEST(paid_amount, total_amount) > 0;
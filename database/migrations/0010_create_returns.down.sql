@@ -0,0 +1,3 @@
+DROP TABLE refunds;
+DROP TABLE return_lines;
+DROP TABLE returns;
@@ -0,0 +1,36 @@
+CREATE TABLE returns (
+    id SERIAL PRIMARY KEY,
+    transaction_id INT NOT NULL REFERENCES transactions(id) ON DELETE RESTRICT,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    reason VARCHAR(255) NOT NULL,
+    refund_amount INT NOT NULL CHECK (refund_amount >= 0),
+    requested_by VARCHAR(100) NOT NULL,
+    decided_by VARCHAR(100) NOT NULL DEFAULT '',
+    decision_note VARCHAR(255) NOT NULL DEFAULT '',
+    decided_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_returns_transaction ON returns (transaction_id);
+
+CREATE TABLE return_lines (
+    id SERIAL PRIMARY KEY,
+    return_id INT NOT NULL REFERENCES returns(id) ON DELETE CASCADE,
+    transaction_detail_id INT NOT NULL REFERENCES transaction_details(id) ON DELETE RESTRICT,
+    product_id INT NOT NULL,
+    product_name VARCHAR(255) NOT NULL,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    amount INT NOT NULL CHECK (amount >= 0),
+    disposition VARCHAR(20) NOT NULL
+);
+
+CREATE INDEX idx_return_lines_detail ON return_lines (transaction_detail_id);
+
+CREATE TABLE refunds (
+    id SERIAL PRIMARY KEY,
+    return_id INT NOT NULL REFERENCES returns(id) ON DELETE CASCADE,
+    method VARCHAR(20) NOT NULL,
+    amount INT NOT NULL CHECK (amount > 0),
+    reference VARCHAR(100) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
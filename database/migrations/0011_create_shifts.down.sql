@@ -0,0 +1,9 @@
+ALTER TABLE returns
+    DROP COLUMN shift_id;
+
+ALTER TABLE transactions
+    DROP COLUMN shift_id;
+
+DROP TABLE shift_cash_events;
+
+DROP TABLE shifts;
@@ -0,0 +1,39 @@
+CREATE TABLE shifts (
+    id SERIAL PRIMARY KEY,
+    cashier VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+    opening_float INT NOT NULL CHECK (opening_float >= 0),
+    expected_cash INT,
+    counted_cash INT CHECK (counted_cash >= 0),
+    variance INT,
+    note VARCHAR(255) NOT NULL DEFAULT '',
+    opened_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    closed_at TIMESTAMPTZ,
+    closed_by VARCHAR(100) NOT NULL DEFAULT '',
+    locked_at TIMESTAMPTZ,
+    locked_by VARCHAR(100) NOT NULL DEFAULT '',
+    z_report JSONB
+);
+
+-- Satu kasir hanya boleh punya satu shift terbuka
+CREATE UNIQUE INDEX idx_shifts_open_cashier ON shifts (cashier) WHERE status = 'open';
+
+CREATE TABLE shift_cash_events (
+    id SERIAL PRIMARY KEY,
+    shift_id INT NOT NULL REFERENCES shifts(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL,
+    amount INT NOT NULL CHECK (amount > 0),
+    reason VARCHAR(255) NOT NULL,
+    username VARCHAR(100) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_shift_cash_events_shift ON shift_cash_events (shift_id);
+
+ALTER TABLE transactions
+    ADD COLUMN shift_id INT REFERENCES shifts(id) ON DELETE RESTRICT;
+
+CREATE INDEX idx_transactions_shift ON transactions (shift_id);
+
+ALTER TABLE returns
+    ADD COLUMN shift_id INT REFERENCES shifts(id) ON DELETE RESTRICT;
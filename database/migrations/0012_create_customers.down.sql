@@ -0,0 +1,4 @@
+ALTER TABLE transactions
+    DROP COLUMN customer_id;
+
+DROP TABLE customers;
@@ -0,0 +1,15 @@
+CREATE TABLE customers (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    phone VARCHAR(20) NOT NULL UNIQUE,
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    address VARCHAR(1000) NOT NULL DEFAULT '',
+    notes VARCHAR(1000) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Riwayat belanja tetap ada meskipun data pelanggan dihapus
+ALTER TABLE transactions
+    ADD COLUMN customer_id INT REFERENCES customers(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_transactions_customer ON transactions (customer_id, created_at);
@@ -0,0 +1,9 @@
+ALTER TABLE transactions
+    DROP COLUMN points_earned,
+    DROP COLUMN points_redeemed,
+    DROP COLUMN point_value,
+    DROP COLUMN points_expire_at;
+
+DROP TABLE point_entries;
+DROP TABLE loyalty_category_rules;
+DROP TABLE loyalty_settings;
@@ -0,0 +1,46 @@
+-- Satu baris pengaturan program poin. earn_amount adalah belanja (Rp) untuk 1 poin,
+-- point_value adalah nilai 1 poin (Rp) saat ditukar, expiry_days 0 berarti tidak kedaluwarsa.
+CREATE TABLE loyalty_settings (
+    id INT PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    earn_amount INT NOT NULL CHECK (earn_amount > 0),
+    point_value INT NOT NULL CHECK (point_value > 0),
+    expiry_days INT NOT NULL DEFAULT 0 CHECK (expiry_days >= 0),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO loyalty_settings (earn_amount, point_value, expiry_days) VALUES (10000, 100, 365);
+
+CREATE TABLE loyalty_category_rules (
+    category_id INT PRIMARY KEY REFERENCES categories(id) ON DELETE CASCADE,
+    multiplier_percent INT NOT NULL DEFAULT 100 CHECK (multiplier_percent >= 0),
+    excluded BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+-- Ledger poin. Entri bertambah (earn, restore, adjust positif) menyimpan sisa poin di
+-- remaining yang dipakai FIFO berdasarkan tanggal kedaluwarsa, sehingga saldo selalu
+-- sama dengan jumlah kolom points.
+CREATE TABLE point_entries (
+    id SERIAL PRIMARY KEY,
+    customer_id INT NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL,
+    points INT NOT NULL,
+    remaining INT NOT NULL DEFAULT 0 CHECK (remaining >= 0),
+    expires_at TIMESTAMPTZ,
+    transaction_id INT REFERENCES transactions(id),
+    return_id INT REFERENCES returns(id),
+    note VARCHAR(255) NOT NULL DEFAULT '',
+    username VARCHAR(100) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_point_entries_customer ON point_entries (customer_id, id);
+CREATE INDEX idx_point_entries_open ON point_entries (expires_at) WHERE remaining > 0;
+CREATE INDEX idx_point_entries_transaction ON point_entries (transaction_id);
+
+-- Poin transaksi dihitung saat checkout dan baru dikreditkan ketika transaksi lunas
+ALTER TABLE transactions
+    ADD COLUMN points_earned INT NOT NULL DEFAULT 0,
+    ADD COLUMN points_redeemed INT NOT NULL DEFAULT 0,
+    ADD COLUMN point_value INT NOT NULL DEFAULT 0,
+    ADD COLUMN points_expire_at TIMESTAMPTZ;
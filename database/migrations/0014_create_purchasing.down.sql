@@ -0,0 +1,5 @@
+DROP TABLE goods_receipt_lines;
+DROP TABLE goods_receipts;
+DROP TABLE purchase_order_lines;
+DROP TABLE purchase_orders;
+DROP TABLE suppliers;
@@ -0,0 +1,60 @@
+CREATE TABLE suppliers (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    contact_name VARCHAR(255) NOT NULL DEFAULT '',
+    phone VARCHAR(20) NOT NULL DEFAULT '',
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    address VARCHAR(1000) NOT NULL DEFAULT '',
+    notes VARCHAR(1000) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Supplier yang sudah punya purchase order tidak bisa dihapus
+CREATE TABLE purchase_orders (
+    id SERIAL PRIMARY KEY,
+    supplier_id INT NOT NULL REFERENCES suppliers(id) ON DELETE RESTRICT,
+    status VARCHAR(20) NOT NULL DEFAULT 'draft',
+    notes VARCHAR(1000) NOT NULL DEFAULT '',
+    total_cost INT NOT NULL DEFAULT 0,
+    created_by VARCHAR(100) NOT NULL,
+    ordered_at TIMESTAMPTZ,
+    received_at TIMESTAMPTZ,
+    cancelled_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_purchase_orders_supplier ON purchase_orders (supplier_id);
+CREATE INDEX idx_purchase_orders_status ON purchase_orders (status);
+
+CREATE TABLE purchase_order_lines (
+    id SERIAL PRIMARY KEY,
+    purchase_order_id INT NOT NULL REFERENCES purchase_orders(id) ON DELETE CASCADE,
+    product_id INT NOT NULL REFERENCES products(id) ON DELETE RESTRICT,
+    product_name VARCHAR(255) NOT NULL,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    received_quantity INT NOT NULL DEFAULT 0 CHECK (received_quantity >= 0 AND received_quantity <= quantity),
+    unit_cost INT NOT NULL CHECK (unit_cost >= 0)
+);
+
+CREATE INDEX idx_purchase_order_lines_order ON purchase_order_lines (purchase_order_id);
+
+-- Penerimaan barang, satu PO bisa diterima bertahap. unit_cost adalah harga beli
+-- sebenarnya menurut faktur supplier.
+CREATE TABLE goods_receipts (
+    id SERIAL PRIMARY KEY,
+    purchase_order_id INT NOT NULL REFERENCES purchase_orders(id) ON DELETE RESTRICT,
+    note VARCHAR(255) NOT NULL DEFAULT '',
+    received_by VARCHAR(100) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_goods_receipts_order ON goods_receipts (purchase_order_id);
+
+CREATE TABLE goods_receipt_lines (
+    id SERIAL PRIMARY KEY,
+    goods_receipt_id INT NOT NULL REFERENCES goods_receipts(id) ON DELETE CASCADE,
+    purchase_order_line_id INT NOT NULL REFERENCES purchase_order_lines(id) ON DELETE RESTRICT,
+    product_id INT NOT NULL,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    unit_cost INT NOT NULL CHECK (unit_cost >= 0)
+);
@@ -0,0 +1,7 @@
+DROP INDEX idx_transactions_created_at;
+
+ALTER TABLE transaction_details
+    DROP COLUMN cost;
+
+ALTER TABLE products
+    DROP COLUMN cost;
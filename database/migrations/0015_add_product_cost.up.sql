@@ -0,0 +1,9 @@
+-- HPP per unit (rata-rata bergerak), diperbarui setiap penerimaan barang
+ALTER TABLE products
+    ADD COLUMN cost INT NOT NULL DEFAULT 0 CHECK (cost >= 0);
+
+-- HPP per unit saat barang terjual, untuk laporan margin
+ALTER TABLE transaction_details
+    ADD COLUMN cost INT NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_transactions_created_at ON transactions (created_at);
@@ -0,0 +1,7 @@
+DROP TABLE stock_transfer_lines;
+DROP TABLE stock_transfers;
+ALTER TABLE transactions DROP COLUMN outlet_id;
+DROP INDEX idx_stock_movements_outlet;
+ALTER TABLE stock_movements DROP COLUMN outlet_id;
+DROP TABLE outlet_stocks;
+DROP TABLE outlets;
@@ -0,0 +1,69 @@
+-- Outlet adalah toko atau gudang yang menyimpan stok. Tepat satu outlet menjadi default:
+-- stok lama dipindahkan ke sana, dan pergerakan tanpa outlet (penerimaan PO, checkout
+-- tanpa outlet_id) dicatat di sana.
+CREATE TABLE outlets (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(20) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    type VARCHAR(20) NOT NULL DEFAULT 'store',
+    address VARCHAR(1000) NOT NULL DEFAULT '',
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (type IN ('store', 'warehouse'))
+);
+
+CREATE UNIQUE INDEX idx_outlets_default ON outlets (is_default) WHERE is_default;
+
+INSERT INTO outlets (code, name, is_default) VALUES ('PUSAT', 'Outlet Pusat', TRUE);
+
+-- products.stock tetap menjadi total stok semua outlet
+CREATE TABLE outlet_stocks (
+    outlet_id INT NOT NULL REFERENCES outlets(id) ON DELETE RESTRICT,
+    product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    stock INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (outlet_id, product_id)
+);
+
+CREATE INDEX idx_outlet_stocks_product ON outlet_stocks (product_id);
+
+INSERT INTO outlet_stocks (outlet_id, product_id, stock)
+SELECT o.id, p.id, p.stock FROM products p CROSS JOIN outlets o WHERE o.is_default AND p.stock <> 0;
+
+ALTER TABLE stock_movements ADD COLUMN outlet_id INT REFERENCES outlets(id) ON DELETE RESTRICT;
+UPDATE stock_movements SET outlet_id = (SELECT id FROM outlets WHERE is_default);
+ALTER TABLE stock_movements ALTER COLUMN outlet_id SET NOT NULL;
+
+CREATE INDEX idx_stock_movements_outlet ON stock_movements (outlet_id, product_id);
+
+ALTER TABLE transactions ADD COLUMN outlet_id INT REFERENCES outlets(id) ON DELETE RESTRICT;
+UPDATE transactions SET outlet_id = (SELECT id FROM outlets WHERE is_default);
+ALTER TABLE transactions ALTER COLUMN outlet_id SET NOT NULL;
+
+-- Barang yang sudah dikirim tapi belum diterima adalah stok dalam perjalanan
+CREATE TABLE stock_transfers (
+    id SERIAL PRIMARY KEY,
+    from_outlet_id INT NOT NULL REFERENCES outlets(id) ON DELETE RESTRICT,
+    to_outlet_id INT NOT NULL REFERENCES outlets(id) ON DELETE RESTRICT,
+    status VARCHAR(20) NOT NULL DEFAULT 'draft',
+    note VARCHAR(1000) NOT NULL DEFAULT '',
+    created_by VARCHAR(100) NOT NULL,
+    sent_by VARCHAR(100) NOT NULL DEFAULT '',
+    received_by VARCHAR(100) NOT NULL DEFAULT '',
+    sent_at TIMESTAMPTZ,
+    received_at TIMESTAMPTZ,
+    cancelled_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (from_outlet_id <> to_outlet_id)
+);
+
+CREATE INDEX idx_stock_transfers_status ON stock_transfers (status);
+
+CREATE TABLE stock_transfer_lines (
+    id SERIAL PRIMARY KEY,
+    transfer_id INT NOT NULL REFERENCES stock_transfers(id) ON DELETE CASCADE,
+    product_id INT NOT NULL REFERENCES products(id) ON DELETE RESTRICT,
+    product_name VARCHAR(255) NOT NULL,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    UNIQUE (transfer_id, product_id)
+);
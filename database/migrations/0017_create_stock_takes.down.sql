@@ -0,0 +1,2 @@
+DROP TABLE stock_take_lines;
+DROP TABLE stock_takes;
@@ -0,0 +1,31 @@
+-- Stock opname: stok sistem per produk di-snapshot saat sesi dimulai, hasil hitung fisik
+-- dikumpulkan selama status counting, lalu selisihnya diposting sebagai adjustment saat disetujui.
+-- category_id kosong berarti seluruh produk di outlet.
+CREATE TABLE stock_takes (
+    id SERIAL PRIMARY KEY,
+    outlet_id INT NOT NULL REFERENCES outlets(id) ON DELETE RESTRICT,
+    category_id INT REFERENCES categories(id) ON DELETE SET NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'counting',
+    note VARCHAR(1000) NOT NULL DEFAULT '',
+    created_by VARCHAR(100) NOT NULL,
+    approved_by VARCHAR(100) NOT NULL DEFAULT '',
+    approved_at TIMESTAMPTZ,
+    cancelled_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_stock_takes_outlet_status ON stock_takes (outlet_id, status);
+
+-- counted NULL berarti produk belum dihitung
+CREATE TABLE stock_take_lines (
+    id SERIAL PRIMARY KEY,
+    stock_take_id INT NOT NULL REFERENCES stock_takes(id) ON DELETE CASCADE,
+    product_id INT NOT NULL REFERENCES products(id) ON DELETE RESTRICT,
+    product_name VARCHAR(255) NOT NULL,
+    system_stock INT NOT NULL,
+    cost INT NOT NULL DEFAULT 0,
+    counted INT CHECK (counted >= 0),
+    counted_by VARCHAR(100) NOT NULL DEFAULT '',
+    counted_at TIMESTAMPTZ,
+    UNIQUE (stock_take_id, product_id)
+);
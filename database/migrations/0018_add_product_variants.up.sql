@@ -0,0 +1,34 @@
+-- Varian adalah baris products dengan parent_id sehingga punya SKU, barcode, harga, HPP
+-- dan stok sendiri yang lewat ledger seperti produk biasa. price_override FALSE berarti
+-- harga varian mengikuti harga produk induk.
+ALTER TABLE products
+    ADD COLUMN parent_id INT REFERENCES products(id) ON DELETE RESTRICT,
+    ADD COLUMN price_override BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX idx_products_parent_id ON products (parent_id);
+
+-- Atribut varian produk induk (ukuran, warna, rasa) beserta pilihannya, urut position
+CREATE TABLE product_attributes (
+    id SERIAL PRIMARY KEY,
+    product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    position INT NOT NULL DEFAULT 0,
+    UNIQUE (product_id, name)
+);
+
+CREATE TABLE product_attribute_options (
+    id SERIAL PRIMARY KEY,
+    attribute_id INT NOT NULL REFERENCES product_attributes(id) ON DELETE CASCADE,
+    value VARCHAR(100) NOT NULL,
+    position INT NOT NULL DEFAULT 0,
+    UNIQUE (attribute_id, value)
+);
+
+-- Pilihan tiap varian, satu per atribut. Pilihan yang masih dipakai varian tidak bisa dihapus.
+CREATE TABLE product_variant_options (
+    product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    option_id INT NOT NULL REFERENCES product_attribute_options(id) ON DELETE RESTRICT,
+    PRIMARY KEY (product_id, option_id)
+);
+
+CREATE INDEX idx_product_variant_options_option ON product_variant_options (option_id);
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS stock_movements;
+DROP TABLE IF EXISTS product_barcodes;
+DROP TABLE IF EXISTS products;
+DROP TABLE IF EXISTS categories;
+DROP TABLE IF EXISTS tax_rates;
+DROP TABLE IF EXISTS users;
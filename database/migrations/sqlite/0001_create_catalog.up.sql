@@ -0,0 +1,65 @@
+-- Skema katalog untuk SQLite, setara dengan tabel yang sama di migration PostgreSQL
+-- sampai 0015. Modul lain (transaksi, pembayaran, pembelian, dst.) membutuhkan PostgreSQL.
+CREATE TABLE users (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    username VARCHAR(100) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    role VARCHAR(20) NOT NULL,
+    password_hash TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE tax_rates (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(100) NOT NULL,
+    rate_bps INT NOT NULL,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (rate_bps >= 0 AND rate_bps <= 10000)
+);
+
+-- Hanya boleh ada satu tarif default
+CREATE UNIQUE INDEX idx_tax_rates_default ON tax_rates (is_default) WHERE is_default;
+
+INSERT INTO tax_rates (name, rate_bps, is_default) VALUES ('PPN', 1100, TRUE), ('Bebas PPN', 0, FALSE);
+
+CREATE TABLE categories (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    tax_rate_id INT REFERENCES tax_rates(id) ON DELETE SET NULL
+);
+
+CREATE TABLE products (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    sku VARCHAR(64) UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    price INT NOT NULL,
+    stock INT NOT NULL DEFAULT 0,
+    cost INT NOT NULL DEFAULT 0 CHECK (cost >= 0),
+    category_id INT REFERENCES categories(id) ON DELETE SET NULL,
+    tax_rate_id INT REFERENCES tax_rates(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_products_category_id ON products(category_id);
+
+CREATE TABLE product_barcodes (
+    code VARCHAR(13) PRIMARY KEY,
+    product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_product_barcodes_product_id ON product_barcodes(product_id);
+
+-- Ledger tidak boleh hilang: produk yang punya riwayat stok tidak bisa dihapus.
+CREATE TABLE stock_movements (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    product_id INT NOT NULL REFERENCES products(id) ON DELETE RESTRICT,
+    type VARCHAR(20) NOT NULL,
+    quantity INT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    username VARCHAR(100) NOT NULL DEFAULT '',
+    reference_id INT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_stock_movements_product_id ON stock_movements(product_id, created_at);
@@ -0,0 +1,21 @@
+-- SQLite tidak bisa DROP COLUMN yang punya foreign key, sehingga ledger dibangun ulang
+CREATE TABLE stock_movements_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL,
+    quantity INT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    username VARCHAR(100) NOT NULL DEFAULT '',
+    reference_id INT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO stock_movements_old (id, product_id, type, quantity, reason, username, reference_id, created_at)
+SELECT id, product_id, type, quantity, reason, username, reference_id, created_at FROM stock_movements;
+
+DROP TABLE stock_movements;
+ALTER TABLE stock_movements_old RENAME TO stock_movements;
+CREATE INDEX idx_stock_movements_product_id ON stock_movements(product_id, created_at);
+
+DROP TABLE outlet_stocks;
+DROP TABLE outlets;
@@ -0,0 +1,36 @@
+-- Outlet dan stok per outlet, setara migration PostgreSQL 0016. Mode SQLite hanya
+-- membaca stok per outlet; transfer antar outlet membutuhkan PostgreSQL.
+CREATE TABLE outlets (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    code VARCHAR(20) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    type VARCHAR(20) NOT NULL DEFAULT 'store',
+    address VARCHAR(1000) NOT NULL DEFAULT '',
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (type IN ('store', 'warehouse'))
+);
+
+CREATE UNIQUE INDEX idx_outlets_default ON outlets (is_default) WHERE is_default;
+
+INSERT INTO outlets (code, name, is_default) VALUES ('PUSAT', 'Outlet Pusat', TRUE);
+
+CREATE TABLE outlet_stocks (
+    outlet_id INT NOT NULL REFERENCES outlets(id) ON DELETE RESTRICT,
+    product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    stock INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (outlet_id, product_id)
+);
+
+CREATE INDEX idx_outlet_stocks_product ON outlet_stocks (product_id);
+
+INSERT INTO outlet_stocks (outlet_id, product_id, stock)
+SELECT o.id, p.id, p.stock FROM products p CROSS JOIN outlets o WHERE o.is_default AND p.stock <> 0;
+
+-- SQLite tidak bisa menambah kolom NOT NULL tanpa default, sehingga stok lama diisi
+-- lewat UPDATE dan movement baru selalu mengisi outlet_id
+ALTER TABLE stock_movements ADD COLUMN outlet_id INT REFERENCES outlets(id) ON DELETE RESTRICT;
+UPDATE stock_movements SET outlet_id = (SELECT id FROM outlets WHERE is_default);
+
+CREATE INDEX idx_stock_movements_outlet ON stock_movements (outlet_id, product_id);
@@ -0,0 +1,8 @@
+DROP TABLE product_variant_options;
+DROP TABLE product_attribute_options;
+DROP TABLE product_attributes;
+
+DROP INDEX idx_products_parent_id;
+
+ALTER TABLE products DROP COLUMN price_override;
+ALTER TABLE products DROP COLUMN parent_id;
@@ -0,0 +1,31 @@
+-- Varian produk, setara migration PostgreSQL 0018. parent_id tidak memakai REFERENCES
+-- karena SQLite tidak bisa DROP COLUMN yang punya foreign key; repository menolak
+-- menghapus produk induk yang masih punya varian.
+ALTER TABLE products ADD COLUMN parent_id INT;
+ALTER TABLE products ADD COLUMN price_override BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX idx_products_parent_id ON products (parent_id);
+
+CREATE TABLE product_attributes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    position INT NOT NULL DEFAULT 0,
+    UNIQUE (product_id, name)
+);
+
+CREATE TABLE product_attribute_options (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    attribute_id INT NOT NULL REFERENCES product_attributes(id) ON DELETE CASCADE,
+    value VARCHAR(100) NOT NULL,
+    position INT NOT NULL DEFAULT 0,
+    UNIQUE (attribute_id, value)
+);
+
+CREATE TABLE product_variant_options (
+    product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    option_id INT NOT NULL REFERENCES product_attribute_options(id) ON DELETE RESTRICT,
+    PRIMARY KEY (product_id, option_id)
+);
+
+CREATE INDEX idx_product_variant_options_option ON product_variant_options (option_id);
@@ -0,0 +1,7 @@
+//go:build sqlite
+
+package database
+
+// Driver SQLite murni Go (tanpa cgo) hanya ikut di-build dengan -tags sqlite supaya
+// binary PostgreSQL tidak membawa dependency SQLite.
+import _ "modernc.org/sqlite"
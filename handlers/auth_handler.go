@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"kasir-api/middleware"
+	"kasir-api/models"
+	"kasir-api/response"
+	"kasir-api/services"
+	"net/http"
+)
+
+type AuthHandler struct {
+	service services.AuthServiceInterface
+}
+
+func NewAuthHandler(service services.AuthServiceInterface) *AuthHandler {
+	return &AuthHandler{service: service}
+}
+
+// HandleLogin - POST /api/auth/login
+func (h *AuthHandler) HandleLogin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, r)
+		return
+	}
+
+	var req models.LoginRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		invalidBody(w, r)
+		return
+	}
+
+	resp, err := h.service.Login(req.Username, req.Password)
+	if errors.Is(err, services.ErrInvalidCredentials) {
+		writeError(w, r, http.StatusUnauthorized, response.CodeInvalidCredentials, err.Error())
+		return
+	}
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// HandleMe - GET /api/auth/me
+func (h *AuthHandler) HandleMe(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.ClaimsFromContext(r.Context())
+	if claims == nil {
+		writeError(w, r, http.StatusUnauthorized, response.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(claims)
+}
+
+// HandleUsers - GET/POST /api/users
+func (h *AuthHandler) HandleUsers(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetUsers(w, r)
+	case http.MethodPost:
+		h.CreateUser(w, r)
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+func (h *AuthHandler) GetUsers(w http.ResponseWriter, r *http.Request) {
+	users, err := h.service.GetUsers()
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(users)
+}
+
+func (h *AuthHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	var user models.User
+	err := json.NewDecoder(r.Body).Decode(&user)
+	if err != nil {
+		invalidBody(w, r)
+		return
+	}
+
+	err = h.service.CreateUser(&user)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	user.Password = ""
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(user)
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"kasir-api/middleware"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"kasir-api/response"
+	"kasir-api/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAuthService is a mock of AuthService
+type MockAuthService struct {
+	mock.Mock
+}
+
+func (m *MockAuthService) Login(username, password string) (*models.LoginResponse, error) {
+	args := m.Called(username, password)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LoginResponse), args.Error(1)
+}
+
+func (m *MockAuthService) ParseToken(token string) (*models.Claims, error) {
+	args := m.Called(token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Claims), args.Error(1)
+}
+
+func (m *MockAuthService) GetUsers() ([]models.User, error) {
+	args := m.Called()
+	return args.Get(0).([]models.User), args.Error(1)
+}
+
+func (m *MockAuthService) CreateUser(user *models.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+func TestLogin(t *testing.T) {
+	mockService := new(MockAuthService)
+	handler := NewAuthHandler(mockService)
+
+	expected := &models.LoginResponse{
+		Token:     "signed.token.value",
+		ExpiresAt: time.Now().Add(time.Hour),
+		User:      models.User{ID: 1, Username: "siti", Role: models.RoleCashier},
+	}
+	mockService.On("Login", "siti", "rahasia123").Return(expected, nil)
+
+	body, _ := json.Marshal(models.LoginRequest{Username: "siti", Password: "rahasia123"})
+	req, err := http.NewRequest(http.MethodPost, "/api/auth/login", bytes.NewBuffer(body))
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler.HandleLogin(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response models.LoginResponse
+	err = json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "signed.token.value", response.Token)
+	assert.Equal(t, models.RoleCashier, response.User.Role)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestLogin_WrongPassword(t *testing.T) {
+	mockService := new(MockAuthService)
+	handler := NewAuthHandler(mockService)
+
+	mockService.On("Login", "siti", "salah").Return(nil, services.ErrInvalidCredentials)
+
+	body, _ := json.Marshal(models.LoginRequest{Username: "siti", Password: "salah"})
+	req, err := http.NewRequest(http.MethodPost, "/api/auth/login", bytes.NewBuffer(body))
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler.HandleLogin(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	var errBody response.ErrorBody
+	err = json.Unmarshal(rr.Body.Bytes(), &errBody)
+	assert.NoError(t, err)
+	assert.Equal(t, response.CodeInvalidCredentials, errBody.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestLogin_MethodNotAllowed(t *testing.T) {
+	mockService := new(MockAuthService)
+	handler := NewAuthHandler(mockService)
+
+	req, err := http.NewRequest(http.MethodGet, "/api/auth/login", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler.HandleLogin(rr, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+}
+
+func TestMe(t *testing.T) {
+	mockService := new(MockAuthService)
+	handler := NewAuthHandler(mockService)
+
+	claims := &models.Claims{UserID: 2, Username: "andi", Role: models.RoleSupervisor}
+	req, err := http.NewRequest(http.MethodGet, "/api/auth/me", nil)
+	assert.NoError(t, err)
+	req = req.WithContext(middleware.WithClaims(req.Context(), claims))
+
+	rr := httptest.NewRecorder()
+	handler.HandleMe(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response models.Claims
+	err = json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "andi", response.Username)
+}
+
+func TestCreateUser(t *testing.T) {
+	mockService := new(MockAuthService)
+	handler := NewAuthHandler(mockService)
+
+	mockService.On("CreateUser", mock.AnythingOfType("*models.User")).Return(nil)
+
+	body, _ := json.Marshal(models.User{Username: "budi", Name: "Budi", Role: models.RoleCashier, Password: "rahasia123"})
+	req, err := http.NewRequest(http.MethodPost, "/api/users", bytes.NewBuffer(body))
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler.HandleUsers(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.NotContains(t, rr.Body.String(), "rahasia123")
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateUser_ServiceError(t *testing.T) {
+	mockService := new(MockAuthService)
+	handler := NewAuthHandler(mockService)
+
+	mockService.On("CreateUser", mock.AnythingOfType("*models.User")).Return(repositories.NewError(repositories.ErrValidation, "password minimal 8 karakter"))
+
+	body, _ := json.Marshal(models.User{Username: "budi", Role: models.RoleCashier, Password: "123"})
+	req, err := http.NewRequest(http.MethodPost, "/api/users", bytes.NewBuffer(body))
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler.CreateUser(rr, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	mockService.AssertExpectations(t)
+}
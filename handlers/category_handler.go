@@ -25,14 +25,32 @@ func (h *CategoryHandler) HandleCategories(w http.ResponseWriter, r *http.Reques
 	case http.MethodPost:
 		h.Create(w, r)
 	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		methodNotAllowed(w, r)
 	}
 }
 
+// GetAll - GET /api/kategori?page=&limit=&search=&sort=
 func (h *CategoryHandler) GetAll(w http.ResponseWriter, r *http.Request) {
-	categories, err := h.service.GetAll()
+	q := r.URL.Query()
+	page, err := queryInt(q, "page")
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		invalidQuery(w, r, err)
+		return
+	}
+	limit, err := queryInt(q, "limit")
+	if err != nil {
+		invalidQuery(w, r, err)
+		return
+	}
+
+	categories, err := h.service.GetAll(models.CategoryFilter{
+		Page:   page,
+		Limit:  limit,
+		Search: strings.TrimSpace(q.Get("search")),
+		Sort:   q.Get("sort"),
+	})
+	if err != nil {
+		writeServiceError(w, r, err)
 		return
 	}
 
@@ -44,13 +62,13 @@ func (h *CategoryHandler) Create(w http.ResponseWriter, r *http.Request) {
 	var category models.Category
 	err := json.NewDecoder(r.Body).Decode(&category)
 	if err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		invalidBody(w, r)
 		return
 	}
 
 	err = h.service.Create(&category)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeServiceError(w, r, err)
 		return
 	}
 
@@ -69,7 +87,7 @@ func (h *CategoryHandler) HandleCategoryByID(w http.ResponseWriter, r *http.Requ
 	case http.MethodDelete:
 		h.Delete(w, r)
 	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		methodNotAllowed(w, r)
 	}
 }
 
@@ -78,13 +96,13 @@ func (h *CategoryHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	idStr := strings.TrimPrefix(r.URL.Path, "/api/kategori/")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		http.Error(w, "Invalid category ID", http.StatusBadRequest)
+		invalidID(w, r, "Invalid category ID")
 		return
 	}
 
 	category, err := h.service.GetByID(id)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusNotFound)
+		writeServiceError(w, r, err)
 		return
 	}
 
@@ -96,21 +114,21 @@ func (h *CategoryHandler) Update(w http.ResponseWriter, r *http.Request) {
 	idStr := strings.TrimPrefix(r.URL.Path, "/api/kategori/")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		http.Error(w, "Invalid category ID", http.StatusBadRequest)
+		invalidID(w, r, "Invalid category ID")
 		return
 	}
 
 	var category models.Category
 	err = json.NewDecoder(r.Body).Decode(&category)
 	if err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		invalidBody(w, r)
 		return
 	}
 
 	category.ID = id
 	err = h.service.Update(&category)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeServiceError(w, r, err)
 		return
 	}
 
@@ -123,13 +141,13 @@ func (h *CategoryHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	idStr := strings.TrimPrefix(r.URL.Path, "/api/kategori/")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		http.Error(w, "Invalid category ID", http.StatusBadRequest)
+		invalidID(w, r, "Invalid category ID")
 		return
 	}
 
 	err = h.service.Delete(id)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeServiceError(w, r, err)
 		return
 	}
 
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"kasir-api/models"
+	"kasir-api/repositories"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -18,9 +19,12 @@ type MockCategoryService struct {
 	mock.Mock
 }
 
-func (m *MockCategoryService) GetAll() ([]models.Category, error) {
-	args := m.Called()
-	return args.Get(0).([]models.Category), args.Error(1)
+func (m *MockCategoryService) GetAll(filter models.CategoryFilter) (*models.Page[models.Category], error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Page[models.Category]), args.Error(1)
 }
 
 func (m *MockCategoryService) GetByID(id int) (*models.Category, error) {
@@ -55,7 +59,7 @@ func TestGetAllCategories(t *testing.T) {
 		{ID: 2, Name: "Category 2", Description: "Description 2"},
 	}
 
-	mockService.On("GetAll").Return(expectedCategories, nil)
+	mockService.On("GetAll", models.CategoryFilter{}).Return(models.NewPage(expectedCategories, 1, 20, 2), nil)
 
 	req, err := http.NewRequest(http.MethodGet, "/api/kategori", nil)
 	assert.NoError(t, err)
@@ -66,11 +70,13 @@ func TestGetAllCategories(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rr.Code)
 	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
 
-	var categories []models.Category
-	err = json.Unmarshal(rr.Body.Bytes(), &categories)
+	var page models.Page[models.Category]
+	err = json.Unmarshal(rr.Body.Bytes(), &page)
 	assert.NoError(t, err)
-	assert.Equal(t, 2, len(categories))
-	assert.Equal(t, expectedCategories[0].Name, categories[0].Name)
+	assert.Equal(t, 2, len(page.Data))
+	assert.Equal(t, 2, page.Total)
+	assert.Nil(t, page.NextPage)
+	assert.Equal(t, expectedCategories[0].Name, page.Data[0].Name)
 
 	mockService.AssertExpectations(t)
 }
@@ -79,7 +85,7 @@ func TestGetAllCategories_ServiceError(t *testing.T) {
 	mockService := new(MockCategoryService)
 	handler := NewCategoryHandler(mockService)
 
-	mockService.On("GetAll").Return([]models.Category{}, errors.New("database error"))
+	mockService.On("GetAll", models.CategoryFilter{}).Return(nil, errors.New("database error"))
 
 	req, err := http.NewRequest(http.MethodGet, "/api/kategori", nil)
 	assert.NoError(t, err)
@@ -91,6 +97,36 @@ func TestGetAllCategories_ServiceError(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestGetAllCategories_WithQuery(t *testing.T) {
+	mockService := new(MockCategoryService)
+	handler := NewCategoryHandler(mockService)
+
+	filter := models.CategoryFilter{Page: 2, Limit: 5, Search: "minum", Sort: "-name"}
+	mockService.On("GetAll", filter).Return(models.NewPage([]models.Category{}, 2, 5, 0), nil)
+
+	req, err := http.NewRequest(http.MethodGet, "/api/kategori?page=2&limit=5&search=minum&sort=-name", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler.GetAll(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetAllCategories_InvalidQuery(t *testing.T) {
+	mockService := new(MockCategoryService)
+	handler := NewCategoryHandler(mockService)
+
+	req, err := http.NewRequest(http.MethodGet, "/api/kategori?page=abc", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler.GetAll(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
 func TestCreateCategory(t *testing.T) {
 	mockService := new(MockCategoryService)
 	handler := NewCategoryHandler(mockService)
@@ -144,7 +180,7 @@ func TestCreateCategory_ServiceError(t *testing.T) {
 		Description: "New Description",
 	}
 
-	mockService.On("Create", mock.AnythingOfType("*models.Category")).Return(errors.New("validation error"))
+	mockService.On("Create", mock.AnythingOfType("*models.Category")).Return(repositories.NewError(repositories.ErrValidation, "nama kategori wajib diisi"))
 
 	body, _ := json.Marshal(newCategory)
 	req, err := http.NewRequest(http.MethodPost, "/api/kategori", bytes.NewBuffer(body))
@@ -154,7 +190,7 @@ func TestCreateCategory_ServiceError(t *testing.T) {
 	rr := httptest.NewRecorder()
 	handler.Create(rr, req)
 
-	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
 	mockService.AssertExpectations(t)
 }
 
@@ -204,7 +240,7 @@ func TestGetCategoryByID_NotFound(t *testing.T) {
 	mockService := new(MockCategoryService)
 	handler := NewCategoryHandler(mockService)
 
-	mockService.On("GetByID", 999).Return((*models.Category)(nil), errors.New("category not found"))
+	mockService.On("GetByID", 999).Return((*models.Category)(nil), repositories.NewError(repositories.ErrNotFound, "kategori tidak ditemukan"))
 
 	req, err := http.NewRequest(http.MethodGet, "/api/kategori/999", nil)
 	assert.NoError(t, err)
@@ -284,7 +320,7 @@ func TestUpdateCategory_ServiceError(t *testing.T) {
 		Description: "Updated Description",
 	}
 
-	mockService.On("Update", mock.AnythingOfType("*models.Category")).Return(errors.New("update failed"))
+	mockService.On("Update", mock.AnythingOfType("*models.Category")).Return(repositories.ErrForeignKey)
 
 	body, _ := json.Marshal(updatedCategory)
 	req, err := http.NewRequest(http.MethodPut, "/api/kategori/1", bytes.NewBuffer(body))
@@ -294,7 +330,7 @@ func TestUpdateCategory_ServiceError(t *testing.T) {
 	rr := httptest.NewRecorder()
 	handler.Update(rr, req)
 
-	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, http.StatusConflict, rr.Code)
 	mockService.AssertExpectations(t)
 }
 
@@ -338,7 +374,7 @@ func TestDeleteCategory_ServiceError(t *testing.T) {
 	mockService := new(MockCategoryService)
 	handler := NewCategoryHandler(mockService)
 
-	mockService.On("Delete", 999).Return(errors.New("category not found"))
+	mockService.On("Delete", 999).Return(repositories.NewError(repositories.ErrNotFound, "kategori tidak ditemukan"))
 
 	req, err := http.NewRequest(http.MethodDelete, "/api/kategori/999", nil)
 	assert.NoError(t, err)
@@ -346,7 +382,7 @@ func TestDeleteCategory_ServiceError(t *testing.T) {
 	rr := httptest.NewRecorder()
 	handler.Delete(rr, req)
 
-	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
 	mockService.AssertExpectations(t)
 }
 
@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/models"
+	"kasir-api/services"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type CustomerHandler struct {
+	service services.CustomerServiceInterface
+}
+
+func NewCustomerHandler(service services.CustomerServiceInterface) *CustomerHandler {
+	return &CustomerHandler{service: service}
+}
+
+// HandleCustomers - GET/POST /api/pelanggan
+func (h *CustomerHandler) HandleCustomers(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetAll(w, r)
+	case http.MethodPost:
+		h.Create(w, r)
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+// GetAll - GET /api/pelanggan?page=&limit=&search=&sort=
+func (h *CustomerHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := models.CustomerFilter{Search: strings.TrimSpace(q.Get("search")), Sort: q.Get("sort")}
+	var err error
+	if filter.Page, err = queryInt(q, "page"); err != nil {
+		invalidQuery(w, r, err)
+		return
+	}
+	if filter.Limit, err = queryInt(q, "limit"); err != nil {
+		invalidQuery(w, r, err)
+		return
+	}
+
+	customers, err := h.service.GetAll(filter)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(customers)
+}
+
+func (h *CustomerHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var customer models.Customer
+	err := json.NewDecoder(r.Body).Decode(&customer)
+	if err != nil {
+		invalidBody(w, r)
+		return
+	}
+
+	err = h.service.Create(&customer)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(customer)
+}
+
+// HandleCustomerByPhone - GET /api/pelanggan/telepon/{phone}
+func (h *CustomerHandler) HandleCustomerByPhone(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, r)
+		return
+	}
+
+	phone := strings.TrimPrefix(r.URL.Path, "/api/pelanggan/telepon/")
+	if phone == "" || strings.Contains(phone, "/") {
+		invalidID(w, r, "Invalid phone number")
+		return
+	}
+
+	customer, err := h.service.GetByPhone(phone)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(customer)
+}
+
+// HandleCustomerByID - GET/PUT/DELETE /api/pelanggan/{id} dan GET /api/pelanggan/{id}/riwayat
+func (h *CustomerHandler) HandleCustomerByID(w http.ResponseWriter, r *http.Request) {
+	if strings.HasSuffix(r.URL.Path, "/riwayat") {
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w, r)
+			return
+		}
+		h.History(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.GetByID(w, r)
+	case http.MethodPut:
+		h.Update(w, r)
+	case http.MethodDelete:
+		h.Delete(w, r)
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+// GetByID - GET /api/pelanggan/{id}
+func (h *CustomerHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/pelanggan/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		invalidID(w, r, "Invalid customer ID")
+		return
+	}
+
+	customer, err := h.service.GetByID(id)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(customer)
+}
+
+func (h *CustomerHandler) Update(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/pelanggan/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		invalidID(w, r, "Invalid customer ID")
+		return
+	}
+
+	var customer models.Customer
+	err = json.NewDecoder(r.Body).Decode(&customer)
+	if err != nil {
+		invalidBody(w, r)
+		return
+	}
+
+	customer.ID = id
+	err = h.service.Update(&customer)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(customer)
+}
+
+// Delete - DELETE /api/pelanggan/{id}
+func (h *CustomerHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/pelanggan/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		invalidID(w, r, "Invalid customer ID")
+		return
+	}
+
+	err = h.service.Delete(id)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "customer deleted successfully",
+	})
+}
+
+// History - GET /api/pelanggan/{id}/riwayat?page=&limit=
+func (h *CustomerHandler) History(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/pelanggan/"), "/riwayat")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		invalidID(w, r, "Invalid customer ID")
+		return
+	}
+
+	q := r.URL.Query()
+	page, err := queryInt(q, "page")
+	if err != nil {
+		invalidQuery(w, r, err)
+		return
+	}
+	limit, err := queryInt(q, "limit")
+	if err != nil {
+		invalidQuery(w, r, err)
+		return
+	}
+
+	history, err := h.service.History(id, page, limit)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCustomerService is a mock of CustomerService
+type MockCustomerService struct {
+	mock.Mock
+}
+
+func (m *MockCustomerService) GetAll(filter models.CustomerFilter) (*models.Page[models.Customer], error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Page[models.Customer]), args.Error(1)
+}
+
+func (m *MockCustomerService) GetByID(id int) (*models.Customer, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Customer), args.Error(1)
+}
+
+func (m *MockCustomerService) GetByPhone(phone string) (*models.Customer, error) {
+	args := m.Called(phone)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Customer), args.Error(1)
+}
+
+func (m *MockCustomerService) Create(customer *models.Customer) error {
+	args := m.Called(customer)
+	return args.Error(0)
+}
+
+func (m *MockCustomerService) Update(customer *models.Customer) error {
+	args := m.Called(customer)
+	return args.Error(0)
+}
+
+func (m *MockCustomerService) Delete(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockCustomerService) History(customerID, page, limit int) (*models.CustomerHistory, error) {
+	args := m.Called(customerID, page, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CustomerHistory), args.Error(1)
+}
+
+func TestGetAllCustomers(t *testing.T) {
+	mockService := new(MockCustomerService)
+	handler := NewCustomerHandler(mockService)
+
+	page := models.NewPage([]models.Customer{{ID: 1, Name: "Ani", Phone: "081234567890"}}, 1, 20, 1)
+	mockService.On("GetAll", models.CustomerFilter{Search: "ani"}).Return(page, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/pelanggan?search=ani", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleCustomers(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateCustomer(t *testing.T) {
+	mockService := new(MockCustomerService)
+	handler := NewCustomerHandler(mockService)
+
+	mockService.On("Create", &models.Customer{Name: "Ani", Phone: "+6281234567890"}).Return(nil)
+
+	body, _ := json.Marshal(models.Customer{Name: "Ani", Phone: "+6281234567890"})
+	req, _ := http.NewRequest(http.MethodPost, "/api/pelanggan", bytes.NewBuffer(body))
+	rr := httptest.NewRecorder()
+	handler.HandleCustomers(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateCustomer_DuplicatePhone(t *testing.T) {
+	mockService := new(MockCustomerService)
+	handler := NewCustomerHandler(mockService)
+
+	mockService.On("Create", &models.Customer{Name: "Ani", Phone: "081234567890"}).
+		Return(repositories.NewError(repositories.ErrConflict, "nomor telepon 081234567890 sudah terdaftar"))
+
+	body, _ := json.Marshal(models.Customer{Name: "Ani", Phone: "081234567890"})
+	req, _ := http.NewRequest(http.MethodPost, "/api/pelanggan", bytes.NewBuffer(body))
+	rr := httptest.NewRecorder()
+	handler.HandleCustomers(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetCustomerByPhone(t *testing.T) {
+	mockService := new(MockCustomerService)
+	handler := NewCustomerHandler(mockService)
+
+	mockService.On("GetByPhone", "+6281234567890").Return(&models.Customer{ID: 1, Name: "Ani", Phone: "081234567890"}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/pelanggan/telepon/+6281234567890", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleCustomerByPhone(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response models.Customer
+	err := json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, response.ID)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestUpdateCustomer(t *testing.T) {
+	mockService := new(MockCustomerService)
+	handler := NewCustomerHandler(mockService)
+
+	mockService.On("Update", &models.Customer{ID: 1, Name: "Ani Lestari", Phone: "081234567890"}).Return(nil)
+
+	body, _ := json.Marshal(models.Customer{Name: "Ani Lestari", Phone: "081234567890"})
+	req, _ := http.NewRequest(http.MethodPut, "/api/pelanggan/1", bytes.NewBuffer(body))
+	rr := httptest.NewRecorder()
+	handler.HandleCustomerByID(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestDeleteCustomer_NotFound(t *testing.T) {
+	mockService := new(MockCustomerService)
+	handler := NewCustomerHandler(mockService)
+
+	mockService.On("Delete", 9).Return(repositories.NewError(repositories.ErrNotFound, "pelanggan tidak ditemukan"))
+
+	req, _ := http.NewRequest(http.MethodDelete, "/api/pelanggan/9", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleCustomerByID(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCustomerHistory(t *testing.T) {
+	mockService := new(MockCustomerService)
+	handler := NewCustomerHandler(mockService)
+
+	lastVisit := time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC)
+	history := &models.CustomerHistory{
+		Customer:         models.Customer{ID: 1, Name: "Ani"},
+		TransactionCount: 2,
+		TotalSpend:       75000,
+		RefundTotal:      5000,
+		LifetimeSpend:    70000,
+		LastVisit:        &lastVisit,
+		Transactions:     models.NewPage([]models.TransactionSummary{{ID: 9, TotalAmount: 50000}, {ID: 4, TotalAmount: 25000}}, 1, 20, 2),
+	}
+	mockService.On("History", 1, 0, 0).Return(history, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/pelanggan/1/riwayat", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleCustomerByID(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response models.CustomerHistory
+	err := json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, 70000, response.LifetimeSpend)
+	assert.Equal(t, 2, len(response.Transactions.Data))
+
+	mockService.AssertExpectations(t)
+}
+
+func TestCustomerHistory_MethodNotAllowed(t *testing.T) {
+	mockService := new(MockCustomerService)
+	handler := NewCustomerHandler(mockService)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/pelanggan/1/riwayat", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleCustomerByID(rr, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"errors"
+	"kasir-api/repositories"
+	"kasir-api/response"
+	"kasir-api/services"
+	"log"
+	"net/http"
+)
+
+func writeError(w http.ResponseWriter, r *http.Request, status int, code, message string) {
+	response.Error(w, r, status, code, message, nil)
+}
+
+func methodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	writeError(w, r, http.StatusMethodNotAllowed, response.CodeMethodNotAllowed, "Method not allowed")
+}
+
+func invalidBody(w http.ResponseWriter, r *http.Request) {
+	writeError(w, r, http.StatusBadRequest, response.CodeInvalidBody, "Invalid request body")
+}
+
+func invalidID(w http.ResponseWriter, r *http.Request, message string) {
+	writeError(w, r, http.StatusBadRequest, response.CodeInvalidID, message)
+}
+
+func invalidQuery(w http.ResponseWriter, r *http.Request, err error) {
+	writeError(w, r, http.StatusBadRequest, response.CodeInvalidQuery, err.Error())
+}
+
+// writeServiceError - petakan error dari service/repository ke status HTTP dan kode error.
+// Error yang tidak dikenal dicatat ke log dan tidak diteruskan ke client.
+func writeServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	var validationErr *services.ValidationError
+	if errors.As(err, &validationErr) {
+		response.Error(w, r, http.StatusUnprocessableEntity, response.CodeValidation, "data tidak valid", validationErr.Fields)
+		return
+	}
+
+	var status int
+	var code string
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		status, code = http.StatusNotFound, response.CodeNotFound
+	case errors.Is(err, repositories.ErrValidation):
+		status, code = http.StatusUnprocessableEntity, response.CodeValidation
+	case errors.Is(err, repositories.ErrConflict):
+		status, code = http.StatusConflict, response.CodeConflict
+	case errors.Is(err, repositories.ErrForeignKey):
+		status, code = http.StatusConflict, response.CodeForeignKey
+	case errors.Is(err, services.ErrForbidden):
+		status, code = http.StatusForbidden, response.CodeForbidden
+	default:
+		log.Printf("request %s: %v", response.RequestID(r.Context()), err)
+		writeError(w, r, http.StatusInternalServerError, response.CodeInternal, "Terjadi kesalahan pada server")
+		return
+	}
+
+	writeError(w, r, status, code, err.Error())
+}
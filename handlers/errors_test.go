@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"kasir-api/repositories"
+	"kasir-api/response"
+	"kasir-api/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteServiceError(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		status  int
+		code    string
+		message string
+	}{
+		{"not found", repositories.NewError(repositories.ErrNotFound, "produk tidak ditemukan"), http.StatusNotFound, response.CodeNotFound, "produk tidak ditemukan"},
+		{"validation", repositories.NewError(repositories.ErrValidation, "keranjang kosong"), http.StatusUnprocessableEntity, response.CodeValidation, "keranjang kosong"},
+		{"conflict", repositories.NewError(repositories.ErrConflict, "data dengan nilai yang sama sudah ada"), http.StatusConflict, response.CodeConflict, "data dengan nilai yang sama sudah ada"},
+		{"foreign key", repositories.ErrForeignKey, http.StatusConflict, response.CodeForeignKey, repositories.ErrForeignKey.Error()},
+		{"forbidden", services.ErrForbidden, http.StatusForbidden, response.CodeForbidden, services.ErrForbidden.Error()},
+		{"wrapped", fmt.Errorf("checkout: %w", repositories.NewError(repositories.ErrNotFound, "transaksi tidak ditemukan")), http.StatusNotFound, response.CodeNotFound, "checkout: transaksi tidak ditemukan"},
+		{"database outage", errors.New("dial tcp 10.0.0.5:5432: connect: connection refused"), http.StatusInternalServerError, response.CodeInternal, "Terjadi kesalahan pada server"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/produk/1", nil)
+			req = req.WithContext(response.WithRequestID(req.Context(), "req-123"))
+
+			rr := httptest.NewRecorder()
+			writeServiceError(rr, req, tt.err)
+
+			assert.Equal(t, tt.status, rr.Code)
+			assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+
+			var body response.ErrorBody
+			err := json.Unmarshal(rr.Body.Bytes(), &body)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.code, body.Code)
+			assert.Equal(t, tt.message, body.Message)
+			assert.Equal(t, "req-123", body.RequestID)
+		})
+	}
+}
+
+func TestWriteServiceError_ValidationFields(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/produk", nil)
+	rr := httptest.NewRecorder()
+
+	writeServiceError(rr, req, &services.ValidationError{Fields: []services.FieldError{
+		{Field: "name", Message: "wajib diisi"},
+		{Field: "price", Message: "tidak boleh negatif"},
+	}})
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+
+	var body struct {
+		Code    string                `json:"code"`
+		Details []services.FieldError `json:"details"`
+	}
+	err := json.Unmarshal(rr.Body.Bytes(), &body)
+	assert.NoError(t, err)
+	assert.Equal(t, response.CodeValidation, body.Code)
+	assert.Equal(t, []services.FieldError{
+		{Field: "name", Message: "wajib diisi"},
+		{Field: "price", Message: "tidak boleh negatif"},
+	}, body.Details)
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/middleware"
+	"kasir-api/models"
+	"kasir-api/services"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type LoyaltyHandler struct {
+	service services.LoyaltyServiceInterface
+}
+
+func NewLoyaltyHandler(service services.LoyaltyServiceInterface) *LoyaltyHandler {
+	return &LoyaltyHandler{service: service}
+}
+
+// HandleSettings - GET/PUT /api/poin/aturan
+func (h *LoyaltyHandler) HandleSettings(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetSettings(w, r)
+	case http.MethodPut:
+		h.UpdateSettings(w, r)
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+func (h *LoyaltyHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	settings, err := h.service.GetSettings()
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings)
+}
+
+func (h *LoyaltyHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	var settings models.LoyaltySettings
+	if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+		invalidBody(w, r)
+		return
+	}
+
+	if err := h.service.UpdateSettings(&settings); err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings)
+}
+
+// HandleCustomerPoints - GET/POST /api/pelanggan/{id}/poin
+func (h *LoyaltyHandler) HandleCustomerPoints(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.Ledger(w, r)
+	case http.MethodPost:
+		h.Adjust(w, r)
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+func customerIDFromPointsPath(path string) (int, error) {
+	return strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(path, "/api/pelanggan/"), "/poin"))
+}
+
+// Ledger - GET /api/pelanggan/{id}/poin?page=&limit=
+func (h *LoyaltyHandler) Ledger(w http.ResponseWriter, r *http.Request) {
+	id, err := customerIDFromPointsPath(r.URL.Path)
+	if err != nil {
+		invalidID(w, r, "Invalid customer ID")
+		return
+	}
+
+	q := r.URL.Query()
+	page, err := queryInt(q, "page")
+	if err != nil {
+		invalidQuery(w, r, err)
+		return
+	}
+	limit, err := queryInt(q, "limit")
+	if err != nil {
+		invalidQuery(w, r, err)
+		return
+	}
+
+	ledger, err := h.service.Ledger(id, page, limit)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ledger)
+}
+
+// Adjust - POST /api/pelanggan/{id}/poin, koreksi saldo manual
+func (h *LoyaltyHandler) Adjust(w http.ResponseWriter, r *http.Request) {
+	id, err := customerIDFromPointsPath(r.URL.Path)
+	if err != nil {
+		invalidID(w, r, "Invalid customer ID")
+		return
+	}
+
+	var adj models.PointAdjustment
+	if err := json.NewDecoder(r.Body).Decode(&adj); err != nil {
+		invalidBody(w, r)
+		return
+	}
+	if claims := middleware.ClaimsFromContext(r.Context()); claims != nil {
+		adj.User = claims.Username
+	}
+
+	entry, err := h.service.Adjust(id, &adj)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(entry)
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockLoyaltyService is a mock of LoyaltyService
+type MockLoyaltyService struct {
+	mock.Mock
+}
+
+func (m *MockLoyaltyService) GetSettings() (*models.LoyaltySettings, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LoyaltySettings), args.Error(1)
+}
+
+func (m *MockLoyaltyService) UpdateSettings(settings *models.LoyaltySettings) error {
+	args := m.Called(settings)
+	return args.Error(0)
+}
+
+func (m *MockLoyaltyService) Ledger(customerID, page, limit int) (*models.PointLedger, error) {
+	args := m.Called(customerID, page, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PointLedger), args.Error(1)
+}
+
+func (m *MockLoyaltyService) Adjust(customerID int, adj *models.PointAdjustment) (*models.PointEntry, error) {
+	args := m.Called(customerID, adj)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PointEntry), args.Error(1)
+}
+
+func TestUpdateLoyaltySettings(t *testing.T) {
+	mockService := new(MockLoyaltyService)
+	handler := NewLoyaltyHandler(mockService)
+
+	settings := models.LoyaltySettings{
+		Active:        true,
+		EarnAmount:    10000,
+		PointValue:    100,
+		ExpiryDays:    365,
+		CategoryRules: []models.LoyaltyCategoryRule{{CategoryID: 2, MultiplierPercent: 200}},
+	}
+	mockService.On("UpdateSettings", &settings).Return(nil)
+
+	body, _ := json.Marshal(settings)
+	req, _ := http.NewRequest(http.MethodPut, "/api/poin/aturan", bytes.NewBuffer(body))
+	rr := httptest.NewRecorder()
+	handler.HandleSettings(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestPointLedger(t *testing.T) {
+	mockService := new(MockLoyaltyService)
+	handler := NewLoyaltyHandler(mockService)
+
+	entries := models.NewPage([]models.PointEntry{{ID: 3, CustomerID: 7, Type: models.PointEarn, Points: 12, Remaining: 12}}, 2, 10, 11)
+	mockService.On("Ledger", 7, 2, 10).Return(&models.PointLedger{CustomerID: 7, Balance: 12, Entries: entries}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/pelanggan/7/poin?page=2&limit=10", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleCustomerPoints(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response models.PointLedger
+	err := json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, 12, response.Balance)
+	assert.Equal(t, models.PointEarn, response.Entries.Data[0].Type)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestAdjustPoints_InsufficientBalance(t *testing.T) {
+	mockService := new(MockLoyaltyService)
+	handler := NewLoyaltyHandler(mockService)
+
+	mockService.On("Adjust", 7, &models.PointAdjustment{Points: -50, Note: "salah input", User: "siti"}).
+		Return(nil, repositories.NewError(repositories.ErrConflict, "poin tidak mencukupi, saldo 12"))
+
+	body, _ := json.Marshal(models.PointAdjustment{Points: -50, Note: "salah input"})
+	req, _ := http.NewRequest(http.MethodPost, "/api/pelanggan/7/poin", bytes.NewBuffer(body))
+	req, _ = withCashier(req)
+	rr := httptest.NewRecorder()
+	handler.HandleCustomerPoints(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestPointLedger_InvalidID(t *testing.T) {
+	mockService := new(MockLoyaltyService)
+	handler := NewLoyaltyHandler(mockService)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/pelanggan/abc/poin", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleCustomerPoints(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertNotCalled(t, "Ledger")
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/models"
+	"kasir-api/services"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type OutletHandler struct {
+	service services.OutletServiceInterface
+}
+
+func NewOutletHandler(service services.OutletServiceInterface) *OutletHandler {
+	return &OutletHandler{service: service}
+}
+
+// HandleOutlets - GET/POST /api/outlet
+func (h *OutletHandler) HandleOutlets(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetAll(w, r)
+	case http.MethodPost:
+		h.Create(w, r)
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+// GetAll - GET /api/outlet?page=&limit=&search=&active=
+func (h *OutletHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := models.OutletFilter{Search: q.Get("search")}
+	var err error
+	if filter.Page, err = queryInt(q, "page"); err != nil {
+		invalidQuery(w, r, err)
+		return
+	}
+	if filter.Limit, err = queryInt(q, "limit"); err != nil {
+		invalidQuery(w, r, err)
+		return
+	}
+	if filter.Active, err = queryBoolPtr(q, "active"); err != nil {
+		invalidQuery(w, r, err)
+		return
+	}
+
+	outlets, err := h.service.GetAll(filter)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(outlets)
+}
+
+func (h *OutletHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var outlet models.Outlet
+	err := json.NewDecoder(r.Body).Decode(&outlet)
+	if err != nil {
+		invalidBody(w, r)
+		return
+	}
+
+	err = h.service.Create(&outlet)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(outlet)
+}
+
+// HandleOutletByID - GET/PUT/DELETE /api/outlet/{id}
+func (h *OutletHandler) HandleOutletByID(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetByID(w, r)
+	case http.MethodPut:
+		h.Update(w, r)
+	case http.MethodDelete:
+		h.Delete(w, r)
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+func (h *OutletHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/outlet/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		invalidID(w, r, "Invalid outlet ID")
+		return
+	}
+
+	outlet, err := h.service.GetByID(id)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(outlet)
+}
+
+func (h *OutletHandler) Update(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/outlet/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		invalidID(w, r, "Invalid outlet ID")
+		return
+	}
+
+	var outlet models.Outlet
+	err = json.NewDecoder(r.Body).Decode(&outlet)
+	if err != nil {
+		invalidBody(w, r)
+		return
+	}
+
+	outlet.ID = id
+	err = h.service.Update(&outlet)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(outlet)
+}
+
+func (h *OutletHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/outlet/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		invalidID(w, r, "Invalid outlet ID")
+		return
+	}
+
+	err = h.service.Delete(id)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "outlet deleted successfully",
+	})
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockOutletService is a mock of OutletService
+type MockOutletService struct {
+	mock.Mock
+}
+
+func (m *MockOutletService) GetAll(filter models.OutletFilter) (*models.Page[models.Outlet], error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Page[models.Outlet]), args.Error(1)
+}
+
+func (m *MockOutletService) GetByID(id int) (*models.Outlet, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Outlet), args.Error(1)
+}
+
+func (m *MockOutletService) Create(outlet *models.Outlet) error {
+	args := m.Called(outlet)
+	return args.Error(0)
+}
+
+func (m *MockOutletService) Update(outlet *models.Outlet) error {
+	args := m.Called(outlet)
+	return args.Error(0)
+}
+
+func (m *MockOutletService) Delete(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func TestGetAllOutlets(t *testing.T) {
+	mockService := new(MockOutletService)
+	handler := NewOutletHandler(mockService)
+
+	active := true
+	page := models.NewPage([]models.Outlet{{ID: 1, Code: "PUSAT", Name: "Outlet Pusat", IsDefault: true, Active: true}}, 1, 20, 1)
+	mockService.On("GetAll", models.OutletFilter{Search: "pusat", Active: &active}).Return(page, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/outlet?search=pusat&active=true", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleOutlets(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetAllOutlets_InvalidQuery(t *testing.T) {
+	mockService := new(MockOutletService)
+	handler := NewOutletHandler(mockService)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/outlet?active=kadang", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleOutlets(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertNotCalled(t, "GetAll")
+}
+
+func TestCreateOutlet(t *testing.T) {
+	mockService := new(MockOutletService)
+	handler := NewOutletHandler(mockService)
+
+	outlet := models.Outlet{Code: "GDG", Name: "Gudang Cikarang", Type: models.OutletWarehouse, Active: true}
+	mockService.On("Create", &outlet).Return(nil)
+
+	body, _ := json.Marshal(outlet)
+	req, _ := http.NewRequest(http.MethodPost, "/api/outlet", bytes.NewBuffer(body))
+	rr := httptest.NewRecorder()
+	handler.HandleOutlets(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestDeleteOutlet_Default(t *testing.T) {
+	mockService := new(MockOutletService)
+	handler := NewOutletHandler(mockService)
+
+	mockService.On("Delete", 1).Return(repositories.NewError(repositories.ErrConflict, "outlet default tidak bisa dihapus"))
+
+	req, _ := http.NewRequest(http.MethodDelete, "/api/outlet/1", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleOutletByID(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"kasir-api/models"
+	"kasir-api/payment"
+	"kasir-api/response"
+	"kasir-api/services"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// maxCallbackBody - batas ukuran body callback provider
+const maxCallbackBody = 64 << 10
+
+type PaymentHandler struct {
+	service services.PaymentServiceInterface
+}
+
+func NewPaymentHandler(service services.PaymentServiceInterface) *PaymentHandler {
+	return &PaymentHandler{service: service}
+}
+
+// HandlePaymentByID - GET /api/pembayaran/{id} dan POST /api/pembayaran/{id}/simulasi
+func (h *PaymentHandler) HandlePaymentByID(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/simulasi") && r.Method == http.MethodPost:
+		h.Simulate(w, r)
+	case !strings.HasSuffix(r.URL.Path, "/simulasi") && r.Method == http.MethodGet:
+		h.GetByID(w, r)
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+// GetByID - GET /api/pembayaran/{id}
+func (h *PaymentHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/pembayaran/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		invalidID(w, r, "Invalid payment ID")
+		return
+	}
+
+	p, err := h.service.GetByID(id)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(p)
+}
+
+// Simulate - POST /api/pembayaran/{id}/simulasi, body {"status": "paid"|"expired"}
+func (h *PaymentHandler) Simulate(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/pembayaran/"), "/simulasi")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		invalidID(w, r, "Invalid payment ID")
+		return
+	}
+
+	var req models.PaymentSimulation
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		invalidBody(w, r)
+		return
+	}
+
+	p, err := h.service.Simulate(id, req.Status)
+	if err != nil {
+		writePaymentError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(p)
+}
+
+// HandleCallback - POST /api/pembayaran/callback/{provider}. Tanpa login; keaslian
+// dicek dari header X-Signature atas body mentah.
+func (h *PaymentHandler) HandleCallback(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, r)
+		return
+	}
+
+	provider := strings.TrimPrefix(r.URL.Path, "/api/pembayaran/callback/")
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxCallbackBody))
+	if err != nil {
+		invalidBody(w, r)
+		return
+	}
+
+	p, err := h.service.HandleCallback(provider, body, r.Header.Get("X-Signature"))
+	if err != nil {
+		writePaymentError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(p)
+}
+
+// writePaymentError - callback dengan signature salah dibalas 401, selebihnya seperti error service lain
+func writePaymentError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, payment.ErrInvalidSignature) {
+		writeError(w, r, http.StatusUnauthorized, response.CodeUnauthorized, err.Error())
+		return
+	}
+	writeServiceError(w, r, err)
+}
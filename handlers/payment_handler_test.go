@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"kasir-api/models"
+	"kasir-api/payment"
+	"kasir-api/repositories"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPaymentService is a mock of PaymentService
+type MockPaymentService struct {
+	mock.Mock
+}
+
+func (m *MockPaymentService) GetByID(id int) (*models.Payment, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Payment), args.Error(1)
+}
+
+func (m *MockPaymentService) HandleCallback(provider string, body []byte, signature string) (*models.Payment, error) {
+	args := m.Called(provider, body, signature)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Payment), args.Error(1)
+}
+
+func (m *MockPaymentService) Simulate(paymentID int, status string) (*models.Payment, error) {
+	args := m.Called(paymentID, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Payment), args.Error(1)
+}
+
+func TestGetPaymentByID(t *testing.T) {
+	mockService := new(MockPaymentService)
+	handler := NewPaymentHandler(mockService)
+
+	mockService.On("GetByID", 3).Return(&models.Payment{ID: 3, Method: models.PaymentQRIS, Amount: 15000, Status: models.PaymentPending}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/pembayaran/3", nil)
+	rr := httptest.NewRecorder()
+	handler.HandlePaymentByID(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response models.Payment
+	err := json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, models.PaymentPending, response.Status)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestSimulatePayment(t *testing.T) {
+	mockService := new(MockPaymentService)
+	handler := NewPaymentHandler(mockService)
+
+	mockService.On("Simulate", 3, models.PaymentPaid).Return(&models.Payment{ID: 3, Status: models.PaymentPaid}, nil)
+
+	body, _ := json.Marshal(models.PaymentSimulation{Status: models.PaymentPaid})
+	req, _ := http.NewRequest(http.MethodPost, "/api/pembayaran/3/simulasi", bytes.NewBuffer(body))
+	rr := httptest.NewRecorder()
+	handler.HandlePaymentByID(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestSimulatePayment_MethodNotAllowed(t *testing.T) {
+	mockService := new(MockPaymentService)
+	handler := NewPaymentHandler(mockService)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/pembayaran/3/simulasi", nil)
+	rr := httptest.NewRecorder()
+	handler.HandlePaymentByID(rr, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+}
+
+func TestPaymentCallback(t *testing.T) {
+	mockService := new(MockPaymentService)
+	handler := NewPaymentHandler(mockService)
+
+	body := []byte(`{"provider_ref":"FQ-1","status":"paid"}`)
+	mockService.On("HandleCallback", "fake-qris", body, "abc").Return(&models.Payment{ID: 3, Status: models.PaymentPaid}, nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/pembayaran/callback/fake-qris", bytes.NewBuffer(body))
+	req.Header.Set("X-Signature", "abc")
+	rr := httptest.NewRecorder()
+	handler.HandleCallback(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestPaymentCallback_InvalidSignature(t *testing.T) {
+	mockService := new(MockPaymentService)
+	handler := NewPaymentHandler(mockService)
+
+	body := []byte(`{"provider_ref":"FQ-1","status":"paid"}`)
+	mockService.On("HandleCallback", "fake-qris", body, "salah").Return(nil, payment.ErrInvalidSignature)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/pembayaran/callback/fake-qris", bytes.NewBuffer(body))
+	req.Header.Set("X-Signature", "salah")
+	rr := httptest.NewRecorder()
+	handler.HandleCallback(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestPaymentCallback_AlreadySettled(t *testing.T) {
+	mockService := new(MockPaymentService)
+	handler := NewPaymentHandler(mockService)
+
+	body := []byte(`{"provider_ref":"FQ-1","status":"expired"}`)
+	mockService.On("HandleCallback", "fake-qris", body, "abc").Return(nil, repositories.NewError(repositories.ErrConflict, "pembayaran sudah paid"))
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/pembayaran/callback/fake-qris", bytes.NewBuffer(body))
+	req.Header.Set("X-Signature", "abc")
+	rr := httptest.NewRecorder()
+	handler.HandleCallback(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	mockService.AssertExpectations(t)
+}
@@ -73,7 +73,7 @@ func TestProductWithCategoryIntegration(t *testing.T) {
 			},
 		}
 
-		mockService.On("GetAll").Return(expectedProducts, nil).Once()
+		mockService.On("GetAll", models.ProductFilter{}).Return(models.NewPage(expectedProducts, 1, 20, 3), nil).Once()
 
 		req, _ := http.NewRequest(http.MethodGet, "/api/produk", nil)
 		rr := httptest.NewRecorder()
@@ -82,8 +82,9 @@ func TestProductWithCategoryIntegration(t *testing.T) {
 
 		assert.Equal(t, http.StatusOK, rr.Code)
 
-		var products []models.Product
-		json.Unmarshal(rr.Body.Bytes(), &products)
+		var page models.Page[models.Product]
+		json.Unmarshal(rr.Body.Bytes(), &page)
+		products := page.Data
 
 		assert.Equal(t, 3, len(products))
 		assert.Equal(t, "Electronics", products[0].CategoryName)
@@ -5,6 +5,7 @@ import (
 	"kasir-api/models"
 	"kasir-api/services"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 )
@@ -25,14 +26,21 @@ func (h *ProductHandler) HandleProducts(w http.ResponseWriter, r *http.Request)
 	case http.MethodPost:
 		h.Create(w, r)
 	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		methodNotAllowed(w, r)
 	}
 }
 
+// GetAll - GET /api/produk?page=&limit=&category_id=&outlet=&min_price=&max_price=&in_stock=&search=&sort=
 func (h *ProductHandler) GetAll(w http.ResponseWriter, r *http.Request) {
-	products, err := h.service.GetAll()
+	filter, err := parseProductFilter(r.URL.Query())
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		invalidQuery(w, r, err)
+		return
+	}
+
+	products, err := h.service.GetAll(filter)
+	if err != nil {
+		writeServiceError(w, r, err)
 		return
 	}
 
@@ -44,13 +52,13 @@ func (h *ProductHandler) Create(w http.ResponseWriter, r *http.Request) {
 	var product models.Product
 	err := json.NewDecoder(r.Body).Decode(&product)
 	if err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		invalidBody(w, r)
 		return
 	}
 
 	err = h.service.Create(&product)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeServiceError(w, r, err)
 		return
 	}
 
@@ -59,32 +67,149 @@ func (h *ProductHandler) Create(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(product)
 }
 
-// HandleProductByID - GET/PUT/DELETE /api/produk/{id}
+// HandleProductByID - GET/PUT/DELETE /api/produk/{id}, PUT /api/produk/{id}/atribut,
+// POST /api/produk/{id}/varian dan PUT /api/produk/{id}/varian/{variant_id}
 func (h *ProductHandler) HandleProductByID(w http.ResponseWriter, r *http.Request) {
-	switch r.Method {
-	case http.MethodGet:
+	path := strings.TrimPrefix(r.URL.Path, "/api/produk/")
+	switch {
+	case r.Method == http.MethodPut && strings.HasSuffix(path, "/atribut"):
+		h.SetAttributes(w, r)
+	case r.Method == http.MethodPost && strings.HasSuffix(path, "/varian"):
+		h.CreateVariant(w, r)
+	case r.Method == http.MethodPut && strings.Contains(path, "/varian/"):
+		h.UpdateVariant(w, r)
+	case strings.Contains(path, "/"):
+		methodNotAllowed(w, r)
+	case r.Method == http.MethodGet:
 		h.GetByID(w, r)
-	case http.MethodPut:
+	case r.Method == http.MethodPut:
 		h.Update(w, r)
-	case http.MethodDelete:
+	case r.Method == http.MethodDelete:
 		h.Delete(w, r)
 	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		methodNotAllowed(w, r)
 	}
 }
 
-// GetByID - GET /api/produk/{id}
+// GetByID - GET /api/produk/{id}, produk induk beserta atribut dan variannya
 func (h *ProductHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	idStr := strings.TrimPrefix(r.URL.Path, "/api/produk/")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		invalidID(w, r, "Invalid product ID")
 		return
 	}
 
 	product, err := h.service.GetByID(id)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusNotFound)
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(product)
+}
+
+// SetAttributes - PUT /api/produk/{id}/atribut, body {"attributes": [{"name": "Ukuran", "options": ["Regular", "Large"]}]}
+func (h *ProductHandler) SetAttributes(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/produk/"), "/atribut"))
+	if err != nil {
+		invalidID(w, r, "Invalid product ID")
+		return
+	}
+
+	var req models.ProductAttributesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		invalidBody(w, r)
+		return
+	}
+
+	product, err := h.service.SetAttributes(id, &req)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(product)
+}
+
+// CreateVariant - POST /api/produk/{id}/varian, body {"sku": "KS-L", "price": 22000, "stock": 10, "options": {"Ukuran": "Large"}}
+func (h *ProductHandler) CreateVariant(w http.ResponseWriter, r *http.Request) {
+	parentID, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/produk/"), "/varian"))
+	if err != nil {
+		invalidID(w, r, "Invalid product ID")
+		return
+	}
+
+	var req models.ProductVariantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		invalidBody(w, r)
+		return
+	}
+
+	variant, err := h.service.CreateVariant(parentID, &req)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(variant)
+}
+
+// UpdateVariant - PUT /api/produk/{id}/varian/{variant_id}, body {"sku": "KS-L", "price": null}
+func (h *ProductHandler) UpdateVariant(w http.ResponseWriter, r *http.Request) {
+	parentStr, idStr, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/produk/"), "/varian/")
+	parentID, err := strconv.Atoi(parentStr)
+	if err != nil {
+		invalidID(w, r, "Invalid product ID")
+		return
+	}
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		invalidID(w, r, "Invalid variant ID")
+		return
+	}
+
+	var req models.ProductVariantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		invalidBody(w, r)
+		return
+	}
+
+	variant, err := h.service.UpdateVariant(parentID, id, &req)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(variant)
+}
+
+// HandleProductByBarcode - GET /api/produk/barcode/{code}
+func (h *ProductHandler) HandleProductByBarcode(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetByBarcode(w, r)
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+// GetByBarcode - GET /api/produk/barcode/{code}
+func (h *ProductHandler) GetByBarcode(w http.ResponseWriter, r *http.Request) {
+	code := strings.TrimPrefix(r.URL.Path, "/api/produk/barcode/")
+	if code == "" || strings.Contains(code, "/") {
+		invalidID(w, r, "Invalid barcode")
+		return
+	}
+
+	product, err := h.service.GetByCode(code)
+	if err != nil {
+		writeServiceError(w, r, err)
 		return
 	}
 
@@ -96,21 +221,21 @@ func (h *ProductHandler) Update(w http.ResponseWriter, r *http.Request) {
 	idStr := strings.TrimPrefix(r.URL.Path, "/api/produk/")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		invalidID(w, r, "Invalid product ID")
 		return
 	}
 
 	var product models.Product
 	err = json.NewDecoder(r.Body).Decode(&product)
 	if err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		invalidBody(w, r)
 		return
 	}
 
 	product.ID = id
 	err = h.service.Update(&product)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeServiceError(w, r, err)
 		return
 	}
 
@@ -123,13 +248,13 @@ func (h *ProductHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	idStr := strings.TrimPrefix(r.URL.Path, "/api/produk/")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		invalidID(w, r, "Invalid product ID")
 		return
 	}
 
 	err = h.service.Delete(id)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeServiceError(w, r, err)
 		return
 	}
 
@@ -138,3 +263,34 @@ func (h *ProductHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		"message": "Product deleted successfully",
 	})
 }
+
+func parseProductFilter(q url.Values) (models.ProductFilter, error) {
+	var filter models.ProductFilter
+	var err error
+
+	if filter.Page, err = queryInt(q, "page"); err != nil {
+		return filter, err
+	}
+	if filter.Limit, err = queryInt(q, "limit"); err != nil {
+		return filter, err
+	}
+	if filter.CategoryID, err = queryInt(q, "category_id"); err != nil {
+		return filter, err
+	}
+	if filter.OutletID, err = queryInt(q, "outlet"); err != nil {
+		return filter, err
+	}
+	if filter.MinPrice, err = queryIntPtr(q, "min_price"); err != nil {
+		return filter, err
+	}
+	if filter.MaxPrice, err = queryIntPtr(q, "max_price"); err != nil {
+		return filter, err
+	}
+	if filter.InStock, err = queryBoolPtr(q, "in_stock"); err != nil {
+		return filter, err
+	}
+	filter.Search = strings.TrimSpace(q.Get("search"))
+	filter.Sort = q.Get("sort")
+
+	return filter, nil
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"kasir-api/models"
+	"kasir-api/repositories"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -18,9 +19,12 @@ type MockProductService struct {
 	mock.Mock
 }
 
-func (m *MockProductService) GetAll() ([]models.Product, error) {
-	args := m.Called()
-	return args.Get(0).([]models.Product), args.Error(1)
+func (m *MockProductService) GetAll(filter models.ProductFilter) (*models.Page[models.Product], error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Page[models.Product]), args.Error(1)
 }
 
 func (m *MockProductService) GetByID(id int) (*models.Product, error) {
@@ -31,6 +35,14 @@ func (m *MockProductService) GetByID(id int) (*models.Product, error) {
 	return args.Get(0).(*models.Product), args.Error(1)
 }
 
+func (m *MockProductService) GetByCode(code string) (*models.Product, error) {
+	args := m.Called(code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Product), args.Error(1)
+}
+
 func (m *MockProductService) Create(product *models.Product) error {
 	args := m.Called(product)
 	return args.Error(0)
@@ -46,6 +58,46 @@ func (m *MockProductService) Delete(id int) error {
 	return args.Error(0)
 }
 
+func (m *MockProductService) Export() ([][]string, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([][]string), args.Error(1)
+}
+
+func (m *MockProductService) Import(req *models.ProductImportRequest) (*models.ProductImportResult, error) {
+	args := m.Called(req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ProductImportResult), args.Error(1)
+}
+
+func (m *MockProductService) SetAttributes(id int, req *models.ProductAttributesRequest) (*models.Product, error) {
+	args := m.Called(id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Product), args.Error(1)
+}
+
+func (m *MockProductService) CreateVariant(parentID int, req *models.ProductVariantRequest) (*models.Product, error) {
+	args := m.Called(parentID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Product), args.Error(1)
+}
+
+func (m *MockProductService) UpdateVariant(parentID, id int, req *models.ProductVariantRequest) (*models.Product, error) {
+	args := m.Called(parentID, id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Product), args.Error(1)
+}
+
 func TestGetAllProducts(t *testing.T) {
 	mockService := new(MockProductService)
 	handler := NewProductHandler(mockService)
@@ -55,7 +107,7 @@ func TestGetAllProducts(t *testing.T) {
 		{ID: 2, Name: "Product 2", Price: 20000, Stock: 30, CategoryID: 2, CategoryName: "Furniture"},
 	}
 
-	mockService.On("GetAll").Return(expectedProducts, nil)
+	mockService.On("GetAll", models.ProductFilter{}).Return(models.NewPage(expectedProducts, 1, 20, 2), nil)
 
 	req, err := http.NewRequest(http.MethodGet, "/api/produk", nil)
 	assert.NoError(t, err)
@@ -66,11 +118,12 @@ func TestGetAllProducts(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rr.Code)
 	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
 
-	var products []models.Product
-	err = json.Unmarshal(rr.Body.Bytes(), &products)
+	var page models.Page[models.Product]
+	err = json.Unmarshal(rr.Body.Bytes(), &page)
 	assert.NoError(t, err)
-	assert.Equal(t, 2, len(products))
-	assert.Equal(t, expectedProducts[0].Name, products[0].Name)
+	assert.Equal(t, 2, len(page.Data))
+	assert.Equal(t, 2, page.Total)
+	assert.Equal(t, expectedProducts[0].Name, page.Data[0].Name)
 
 	mockService.AssertExpectations(t)
 }
@@ -79,7 +132,7 @@ func TestGetAllProducts_ServiceError(t *testing.T) {
 	mockService := new(MockProductService)
 	handler := NewProductHandler(mockService)
 
-	mockService.On("GetAll").Return([]models.Product{}, errors.New("database error"))
+	mockService.On("GetAll", models.ProductFilter{}).Return(nil, errors.New("database error"))
 
 	req, err := http.NewRequest(http.MethodGet, "/api/produk", nil)
 	assert.NoError(t, err)
@@ -91,6 +144,60 @@ func TestGetAllProducts_ServiceError(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestGetAllProducts_WithFilters(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+
+	minPrice, maxPrice, inStock := 1000, 50000, true
+	filter := models.ProductFilter{
+		Page:       2,
+		Limit:      10,
+		CategoryID: 3,
+		OutletID:   2,
+		MinPrice:   &minPrice,
+		MaxPrice:   &maxPrice,
+		InStock:    &inStock,
+		Search:     "kopi",
+		Sort:       "-price",
+	}
+	products := []models.Product{{ID: 11, Name: "Kopi Susu", Price: 18000, Stock: 4, CategoryID: 3, OutletID: 2}}
+	mockService.On("GetAll", filter).Return(models.NewPage(products, 2, 10, 25), nil)
+
+	req, err := http.NewRequest(http.MethodGet, "/api/produk?page=2&limit=10&category_id=3&outlet=2&min_price=1000&max_price=50000&in_stock=true&search=kopi&sort=-price", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler.GetAll(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var page models.Page[models.Product]
+	err = json.Unmarshal(rr.Body.Bytes(), &page)
+	assert.NoError(t, err)
+	assert.Equal(t, 25, page.Total)
+	assert.Equal(t, 3, page.TotalPages)
+	if assert.NotNil(t, page.NextPage) {
+		assert.Equal(t, 3, *page.NextPage)
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestGetAllProducts_InvalidQuery(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+
+	for _, query := range []string{"page=x", "outlet=pusat", "min_price=murah", "in_stock=maybe"} {
+		req, err := http.NewRequest(http.MethodGet, "/api/produk?"+query, nil)
+		assert.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		handler.GetAll(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+}
+
 func TestCreateProduct(t *testing.T) {
 	mockService := new(MockProductService)
 	handler := NewProductHandler(mockService)
@@ -148,7 +255,7 @@ func TestCreateProduct_ServiceError(t *testing.T) {
 		CategoryID: 1,
 	}
 
-	mockService.On("Create", mock.AnythingOfType("*models.Product")).Return(errors.New("validation error"))
+	mockService.On("Create", mock.AnythingOfType("*models.Product")).Return(repositories.NewError(repositories.ErrValidation, "nama produk wajib diisi"))
 
 	body, _ := json.Marshal(newProduct)
 	req, err := http.NewRequest(http.MethodPost, "/api/produk", bytes.NewBuffer(body))
@@ -158,7 +265,7 @@ func TestCreateProduct_ServiceError(t *testing.T) {
 	rr := httptest.NewRecorder()
 	handler.Create(rr, req)
 
-	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
 	mockService.AssertExpectations(t)
 }
 
@@ -211,7 +318,7 @@ func TestGetProductByID_NotFound(t *testing.T) {
 	mockService := new(MockProductService)
 	handler := NewProductHandler(mockService)
 
-	mockService.On("GetByID", 999).Return((*models.Product)(nil), errors.New("product not found"))
+	mockService.On("GetByID", 999).Return((*models.Product)(nil), repositories.NewError(repositories.ErrNotFound, "produk tidak ditemukan"))
 
 	req, err := http.NewRequest(http.MethodGet, "/api/produk/999", nil)
 	assert.NoError(t, err)
@@ -297,7 +404,7 @@ func TestUpdateProduct_ServiceError(t *testing.T) {
 		CategoryID: 2,
 	}
 
-	mockService.On("Update", mock.AnythingOfType("*models.Product")).Return(errors.New("update failed"))
+	mockService.On("Update", mock.AnythingOfType("*models.Product")).Return(repositories.ErrForeignKey)
 
 	body, _ := json.Marshal(updatedProduct)
 	req, err := http.NewRequest(http.MethodPut, "/api/produk/1", bytes.NewBuffer(body))
@@ -307,7 +414,7 @@ func TestUpdateProduct_ServiceError(t *testing.T) {
 	rr := httptest.NewRecorder()
 	handler.Update(rr, req)
 
-	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, http.StatusConflict, rr.Code)
 	mockService.AssertExpectations(t)
 }
 
@@ -351,7 +458,7 @@ func TestDeleteProduct_ServiceError(t *testing.T) {
 	mockService := new(MockProductService)
 	handler := NewProductHandler(mockService)
 
-	mockService.On("Delete", 999).Return(errors.New("product not found"))
+	mockService.On("Delete", 999).Return(repositories.NewError(repositories.ErrNotFound, "produk tidak ditemukan"))
 
 	req, err := http.NewRequest(http.MethodDelete, "/api/produk/999", nil)
 	assert.NoError(t, err)
@@ -359,7 +466,7 @@ func TestDeleteProduct_ServiceError(t *testing.T) {
 	rr := httptest.NewRecorder()
 	handler.Delete(rr, req)
 
-	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
 	mockService.AssertExpectations(t)
 }
 
@@ -388,3 +495,185 @@ func TestHandleProductByID_MethodNotAllowed(t *testing.T) {
 
 	assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
 }
+
+func TestGetProductByID_WithVariants(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+
+	mockService.On("GetByID", 1).Return(&models.Product{
+		ID:         1,
+		Name:       "Kopi Susu",
+		Price:      18000,
+		Attributes: []models.ProductAttribute{{Name: "Ukuran", Options: []string{"Regular", "Large"}}},
+		Variants: []models.Product{
+			{ID: 2, ParentID: 1, Name: "Kopi Susu Regular", Price: 18000, Stock: 12, Options: map[string]string{"Ukuran": "Regular"}},
+			{ID: 3, ParentID: 1, Name: "Kopi Susu Large", Price: 22000, PriceOverride: true, Stock: 8, Options: map[string]string{"Ukuran": "Large"}},
+		},
+	}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/produk/1", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleProductByID(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var product models.Product
+	err := json.Unmarshal(rr.Body.Bytes(), &product)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Regular", "Large"}, product.Attributes[0].Options)
+	assert.Len(t, product.Variants, 2)
+	assert.Equal(t, "Large", product.Variants[1].Options["Ukuran"])
+	assert.True(t, product.Variants[1].PriceOverride)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestSetProductAttributes(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+
+	attributes := []models.ProductAttribute{{Name: "Ukuran", Options: []string{"Regular", "Large"}}}
+	mockService.On("SetAttributes", 1, &models.ProductAttributesRequest{Attributes: attributes}).
+		Return(&models.Product{ID: 1, Attributes: attributes}, nil)
+
+	body, _ := json.Marshal(models.ProductAttributesRequest{Attributes: attributes})
+	req, _ := http.NewRequest(http.MethodPut, "/api/produk/1/atribut", bytes.NewBuffer(body))
+	rr := httptest.NewRecorder()
+	handler.HandleProductByID(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateProductVariant(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+
+	price := 22000
+	variantReq := &models.ProductVariantRequest{SKU: "KS-L", Price: &price, Stock: 8, Options: map[string]string{"Ukuran": "Large"}}
+	mockService.On("CreateVariant", 1, variantReq).
+		Return(&models.Product{ID: 3, ParentID: 1, SKU: "KS-L", Name: "Kopi Susu Large", Price: 22000, PriceOverride: true, Stock: 8}, nil)
+
+	body, _ := json.Marshal(variantReq)
+	req, _ := http.NewRequest(http.MethodPost, "/api/produk/1/varian", bytes.NewBuffer(body))
+	rr := httptest.NewRecorder()
+	handler.HandleProductByID(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	var variant models.Product
+	err := json.Unmarshal(rr.Body.Bytes(), &variant)
+	assert.NoError(t, err)
+	assert.Equal(t, "Kopi Susu Large", variant.Name)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateProductVariant_DuplicateOptions(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+
+	mockService.On("CreateVariant", 1, mock.Anything).Return(nil, repositories.NewError(repositories.ErrConflict, "varian dengan pilihan yang sama sudah ada"))
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/produk/1/varian", bytes.NewBufferString(`{"options": {"Ukuran": "Large"}}`))
+	rr := httptest.NewRecorder()
+	handler.HandleProductByID(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestUpdateProductVariant(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+
+	mockService.On("UpdateVariant", 1, 3, &models.ProductVariantRequest{SKU: "KS-L"}).
+		Return(&models.Product{ID: 3, ParentID: 1, SKU: "KS-L", Price: 18000}, nil)
+
+	req, _ := http.NewRequest(http.MethodPut, "/api/produk/1/varian/3", bytes.NewBufferString(`{"sku": "KS-L", "price": null}`))
+	rr := httptest.NewRecorder()
+	handler.HandleProductByID(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestProductVariant_InvalidIDAndMethod(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+
+	req, _ := http.NewRequest(http.MethodPut, "/api/produk/1/varian/abc", bytes.NewBufferString(`{}`))
+	rr := httptest.NewRecorder()
+	handler.HandleProductByID(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	req, _ = http.NewRequest(http.MethodDelete, "/api/produk/1/varian/3", nil)
+	rr = httptest.NewRecorder()
+	handler.HandleProductByID(rr, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+
+	mockService.AssertNotCalled(t, "UpdateVariant")
+}
+
+func TestGetProductByBarcode(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+
+	expectedProduct := &models.Product{
+		ID:           5,
+		SKU:          "IDM-GRG",
+		Barcodes:     []string{"8992761002015"},
+		Name:         "Indomie Goreng",
+		Price:        3500,
+		Stock:        120,
+		CategoryID:   2,
+		CategoryName: "Makanan",
+	}
+	mockService.On("GetByCode", "8992761002015").Return(expectedProduct, nil)
+
+	req, err := http.NewRequest(http.MethodGet, "/api/produk/barcode/8992761002015", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler.HandleProductByBarcode(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var product models.Product
+	err = json.Unmarshal(rr.Body.Bytes(), &product)
+	assert.NoError(t, err)
+	assert.Equal(t, "IDM-GRG", product.SKU)
+	assert.Equal(t, "Makanan", product.CategoryName)
+	assert.Equal(t, []string{"8992761002015"}, product.Barcodes)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestGetProductByBarcode_NotFound(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+
+	mockService.On("GetByCode", "0000000000000").Return(nil, repositories.NewError(repositories.ErrNotFound, "produk tidak ditemukan"))
+
+	req, err := http.NewRequest(http.MethodGet, "/api/produk/barcode/0000000000000", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler.GetByBarcode(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetProductByBarcode_Empty(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+
+	req, err := http.NewRequest(http.MethodGet, "/api/produk/barcode/", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler.GetByBarcode(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
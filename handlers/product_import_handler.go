@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"kasir-api/middleware"
+	"kasir-api/models"
+	"kasir-api/response"
+	"kasir-api/spreadsheet"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// maxImportSize membatasi ukuran file import katalog
+const maxImportSize = 10 << 20
+
+const xlsxContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+// productNumericColumns - kolom ekspor yang ditulis sebagai angka di XLSX
+var productNumericColumns = []string{"price", "cost", "stock", "tax_rate_id"}
+
+// HandleExport - GET /api/produk/export?format=csv|xlsx
+func (h *ProductHandler) HandleExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, r)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "xlsx" {
+		invalidQuery(w, r, errors.New("parameter format harus csv atau xlsx"))
+		return
+	}
+
+	rows, err := h.service.Export()
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	var buf bytes.Buffer
+	contentType := "text/csv; charset=utf-8"
+	if format == "xlsx" {
+		contentType = xlsxContentType
+		var numeric []int
+		for i, name := range models.ProductColumns {
+			for _, n := range productNumericColumns {
+				if name == n {
+					numeric = append(numeric, i)
+				}
+			}
+		}
+		err = spreadsheet.WriteXLSX(&buf, "Produk", rows, numeric...)
+	} else {
+		err = spreadsheet.WriteCSV(&buf, rows)
+	}
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="produk.`+format+`"`)
+	w.Write(buf.Bytes())
+}
+
+// HandleImport - POST /api/produk/import?dry_run=&create_categories=&format=
+//
+// File CSV atau XLSX dikirim sebagai field "file" multipart/form-data atau langsung
+// sebagai body. Format ditentukan dari parameter format, ekstensi nama file atau
+// Content-Type. Import yang gagal (bukan dry run) menghasilkan 422 beserta laporan per baris.
+func (h *ProductHandler) HandleImport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, r)
+		return
+	}
+
+	q := r.URL.Query()
+	dryRun, err := queryBoolPtr(q, "dry_run")
+	if err != nil {
+		invalidQuery(w, r, err)
+		return
+	}
+	createCategories, err := queryBoolPtr(q, "create_categories")
+	if err != nil {
+		invalidQuery(w, r, err)
+		return
+	}
+
+	data, filename, err := readImportFile(w, r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, response.CodeInvalidBody, err.Error())
+		return
+	}
+
+	format := q.Get("format")
+	if format == "" {
+		format = importFormat(filename, r.Header.Get("Content-Type"))
+	}
+	var rows [][]string
+	switch format {
+	case "csv":
+		rows, err = spreadsheet.ReadCSV(bytes.NewReader(data))
+	case "xlsx":
+		rows, err = spreadsheet.ReadXLSX(bytes.NewReader(data), int64(len(data)))
+	default:
+		invalidQuery(w, r, errors.New("parameter format harus csv atau xlsx"))
+		return
+	}
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, response.CodeInvalidBody, fmt.Sprintf("file %s tidak bisa dibaca: %v", format, err))
+		return
+	}
+
+	req := &models.ProductImportRequest{Rows: rows}
+	req.DryRun = dryRun != nil && *dryRun
+	req.CreateCategories = createCategories != nil && *createCategories
+	if claims := middleware.ClaimsFromContext(r.Context()); claims != nil {
+		req.User = claims.Username
+	}
+
+	result, err := h.service.Import(req)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if !result.DryRun && !result.Committed {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
+	json.NewEncoder(w).Encode(result)
+}
+
+// readImportFile - isi file dari field multipart "file" atau dari body request
+func readImportFile(w http.ResponseWriter, r *http.Request) ([]byte, string, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := r.ParseMultipartForm(maxImportSize); err != nil {
+			return nil, "", fmt.Errorf("file tidak valid atau lebih dari %d MB", maxImportSize>>20)
+		}
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			return nil, "", errors.New("field file wajib diisi")
+		}
+		defer file.Close()
+		data, err := io.ReadAll(file)
+		return data, header.Filename, err
+	}
+
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("file tidak valid atau lebih dari %d MB", maxImportSize>>20)
+	}
+	if len(data) == 0 {
+		return nil, "", errors.New("file wajib diisi")
+	}
+	return data, "", nil
+}
+
+// importFormat - tebak format dari ekstensi nama file lalu Content-Type body
+func importFormat(filename, contentType string) string {
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".csv":
+		return "csv"
+	case ".xlsx":
+		return "xlsx"
+	}
+	switch {
+	case strings.HasPrefix(contentType, xlsxContentType):
+		return "xlsx"
+	case strings.HasPrefix(contentType, "text/csv"), strings.HasPrefix(contentType, "text/plain"):
+		return "csv"
+	}
+	return ""
+}
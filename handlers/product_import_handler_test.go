@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"kasir-api/models"
+	"kasir-api/spreadsheet"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func exportRows() [][]string {
+	return [][]string{
+		models.ProductColumns,
+		{"KP-01", "Kopi Susu", "Minuman", "18000", "9000", "40", "8992761002015", ""},
+	}
+}
+
+func TestExportProductsCSV(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+	mockService.On("Export").Return(exportRows(), nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/produk/export", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleExport(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", rr.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="produk.csv"`, rr.Header().Get("Content-Disposition"))
+	assert.Equal(t, "sku,name,category,price,cost,stock,barcodes,tax_rate_id\nKP-01,Kopi Susu,Minuman,18000,9000,40,8992761002015,\n", rr.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestExportProductsXLSX(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+	mockService.On("Export").Return(exportRows(), nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/produk/export?format=xlsx", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleExport(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, xlsxContentType, rr.Header().Get("Content-Type"))
+	rows, err := spreadsheet.ReadXLSX(bytes.NewReader(rr.Body.Bytes()), int64(rr.Body.Len()))
+	require.NoError(t, err)
+	assert.Equal(t, "Kopi Susu", rows[1][1])
+}
+
+func TestExportProductsInvalidFormat(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/produk/export?format=pdf", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleExport(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertNotCalled(t, "Export")
+}
+
+func TestImportProductsMultipartDryRun(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+
+	expected := &models.ProductImportRequest{
+		Rows:             [][]string{{"sku", "name", "price"}, {"KP-01", "Kopi Susu", "18000"}},
+		DryRun:           true,
+		CreateCategories: true,
+	}
+	result := &models.ProductImportResult{DryRun: true, Total: 1, Created: 1,
+		Rows: []models.ProductImportRow{{Row: 2, SKU: "KP-01", Name: "Kopi Susu", Action: models.ImportCreate}}}
+	mockService.On("Import", expected).Return(result, nil)
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, _ := mw.CreateFormFile("file", "katalog.csv")
+	part.Write([]byte("sku;name;price\nKP-01;Kopi Susu;18000\n"))
+	mw.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/produk/import?dry_run=true&create_categories=true", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	rr := httptest.NewRecorder()
+	handler.HandleImport(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var got models.ProductImportResult
+	json.Unmarshal(rr.Body.Bytes(), &got)
+	assert.Equal(t, 1, got.Created)
+	mockService.AssertExpectations(t)
+}
+
+func TestImportProductsRowErrors(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+
+	result := &models.ProductImportResult{Total: 1, Failed: 1,
+		Rows: []models.ProductImportRow{{Row: 2, SKU: "KP-01", Action: models.ImportError, Errors: []string{"price: harus angka bulat"}}}}
+	mockService.On("Import", mock.Anything).Return(result, nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/produk/import", bytes.NewBufferString("sku,name,price\nKP-01,Kopi,abc\n"))
+	req.Header.Set("Content-Type", "text/csv")
+	rr := httptest.NewRecorder()
+	handler.HandleImport(rr, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	assert.Contains(t, rr.Body.String(), "harus angka bulat")
+}
+
+func TestImportProductsUnknownFormat(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/produk/import", bytes.NewBufferString("data"))
+	req.Header.Set("Content-Type", "application/octet-stream")
+	rr := httptest.NewRecorder()
+	handler.HandleImport(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertNotCalled(t, "Import", mock.Anything)
+}
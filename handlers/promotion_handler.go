@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/models"
+	"kasir-api/services"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type PromotionHandler struct {
+	service services.PromotionServiceInterface
+}
+
+func NewPromotionHandler(service services.PromotionServiceInterface) *PromotionHandler {
+	return &PromotionHandler{service: service}
+}
+
+// HandlePromotions - GET/POST /api/promosi
+func (h *PromotionHandler) HandlePromotions(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetAll(w, r)
+	case http.MethodPost:
+		h.Create(w, r)
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+// GetAll - GET /api/promosi?page=&limit=&active=
+func (h *PromotionHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	var filter models.PromotionFilter
+	var err error
+	if filter.Page, err = queryInt(q, "page"); err != nil {
+		invalidQuery(w, r, err)
+		return
+	}
+	if filter.Limit, err = queryInt(q, "limit"); err != nil {
+		invalidQuery(w, r, err)
+		return
+	}
+	if filter.Active, err = queryBoolPtr(q, "active"); err != nil {
+		invalidQuery(w, r, err)
+		return
+	}
+
+	promotions, err := h.service.GetAll(filter)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(promotions)
+}
+
+func (h *PromotionHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var promotion models.Promotion
+	err := json.NewDecoder(r.Body).Decode(&promotion)
+	if err != nil {
+		invalidBody(w, r)
+		return
+	}
+
+	err = h.service.Create(&promotion)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(promotion)
+}
+
+// HandlePromotionByID - GET/PUT/DELETE /api/promosi/{id}
+func (h *PromotionHandler) HandlePromotionByID(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetByID(w, r)
+	case http.MethodPut:
+		h.Update(w, r)
+	case http.MethodDelete:
+		h.Delete(w, r)
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+// GetByID - GET /api/promosi/{id}
+func (h *PromotionHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/promosi/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		invalidID(w, r, "Invalid promotion ID")
+		return
+	}
+
+	promotion, err := h.service.GetByID(id)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(promotion)
+}
+
+func (h *PromotionHandler) Update(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/promosi/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		invalidID(w, r, "Invalid promotion ID")
+		return
+	}
+
+	var promotion models.Promotion
+	err = json.NewDecoder(r.Body).Decode(&promotion)
+	if err != nil {
+		invalidBody(w, r)
+		return
+	}
+
+	promotion.ID = id
+	err = h.service.Update(&promotion)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(promotion)
+}
+
+// Delete - DELETE /api/promosi/{id}
+func (h *PromotionHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/promosi/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		invalidID(w, r, "Invalid promotion ID")
+		return
+	}
+
+	err = h.service.Delete(id)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "promotion deleted successfully",
+	})
+}
+
+// HandlePreview - POST /api/promosi/preview
+func (h *PromotionHandler) HandlePreview(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.Preview(w, r)
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+// Preview - POST /api/promosi/preview, body sama dengan checkout
+func (h *PromotionHandler) Preview(w http.ResponseWriter, r *http.Request) {
+	var req models.CheckoutRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		invalidBody(w, r)
+		return
+	}
+
+	basket, err := h.service.Preview(&req)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(basket)
+}
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"kasir-api/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPromotionService is a mock of PromotionService
+type MockPromotionService struct {
+	mock.Mock
+}
+
+func (m *MockPromotionService) GetAll(filter models.PromotionFilter) (*models.Page[models.Promotion], error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Page[models.Promotion]), args.Error(1)
+}
+
+func (m *MockPromotionService) GetByID(id int) (*models.Promotion, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Promotion), args.Error(1)
+}
+
+func (m *MockPromotionService) Create(promotion *models.Promotion) error {
+	args := m.Called(promotion)
+	return args.Error(0)
+}
+
+func (m *MockPromotionService) Update(promotion *models.Promotion) error {
+	args := m.Called(promotion)
+	return args.Error(0)
+}
+
+func (m *MockPromotionService) Delete(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockPromotionService) Preview(req *models.CheckoutRequest) (*models.PricedBasket, error) {
+	args := m.Called(req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PricedBasket), args.Error(1)
+}
+
+func TestGetAllPromotions(t *testing.T) {
+	mockService := new(MockPromotionService)
+	handler := NewPromotionHandler(mockService)
+
+	active := true
+	promotions := []models.Promotion{{ID: 1, Name: "Happy hour kopi", Type: models.PromotionPercentage, Value: 20, Active: true}}
+	mockService.On("GetAll", models.PromotionFilter{Page: 2, Active: &active}).Return(models.NewPage(promotions, 2, 20, 21), nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/promosi?page=2&active=true", nil)
+	rr := httptest.NewRecorder()
+	handler.HandlePromotions(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var page models.Page[models.Promotion]
+	json.Unmarshal(rr.Body.Bytes(), &page)
+	assert.Equal(t, 21, page.Total)
+	assert.Equal(t, "Happy hour kopi", page.Data[0].Name)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetAllPromotions_InvalidQuery(t *testing.T) {
+	mockService := new(MockPromotionService)
+	handler := NewPromotionHandler(mockService)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/promosi?active=kadang", nil)
+	rr := httptest.NewRecorder()
+	handler.HandlePromotions(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertNotCalled(t, "GetAll", mock.Anything)
+}
+
+func TestCreatePromotion(t *testing.T) {
+	mockService := new(MockPromotionService)
+	handler := NewPromotionHandler(mockService)
+
+	promotion := models.Promotion{Name: "Beli 2 gratis 1", Type: models.PromotionBuyXGetY, BuyQuantity: 2, GetQuantity: 1, ProductID: 3, Active: true}
+	mockService.On("Create", &promotion).Return(nil).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.Promotion).ID = 5
+	})
+
+	body, _ := json.Marshal(promotion)
+	req, _ := http.NewRequest(http.MethodPost, "/api/promosi", bytes.NewBuffer(body))
+	rr := httptest.NewRecorder()
+	handler.HandlePromotions(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	var created models.Promotion
+	json.Unmarshal(rr.Body.Bytes(), &created)
+	assert.Equal(t, 5, created.ID)
+	mockService.AssertExpectations(t)
+}
+
+func TestCreatePromotion_ValidationError(t *testing.T) {
+	mockService := new(MockPromotionService)
+	handler := NewPromotionHandler(mockService)
+
+	mockService.On("Create", mock.Anything).Return(&services.ValidationError{Fields: []services.FieldError{{Field: "type", Message: "harus percentage, fixed, buy_x_get_y atau min_spend"}}})
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/promosi", bytes.NewBufferString(`{"name":"x","type":"gratis"}`))
+	rr := httptest.NewRecorder()
+	handler.HandlePromotions(rr, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+}
+
+func TestUpdatePromotion(t *testing.T) {
+	mockService := new(MockPromotionService)
+	handler := NewPromotionHandler(mockService)
+
+	mockService.On("Update", &models.Promotion{ID: 4, Name: "Voucher", Type: models.PromotionMinSpend, Value: 10000, MinSpend: 100000}).Return(nil)
+
+	req, _ := http.NewRequest(http.MethodPut, "/api/promosi/4", bytes.NewBufferString(`{"name":"Voucher","type":"min_spend","value":10000,"min_spend":100000}`))
+	rr := httptest.NewRecorder()
+	handler.HandlePromotionByID(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetPromotionByID_NotFound(t *testing.T) {
+	mockService := new(MockPromotionService)
+	handler := NewPromotionHandler(mockService)
+
+	mockService.On("GetByID", 9).Return(nil, repositories.NewError(repositories.ErrNotFound, "promosi tidak ditemukan"))
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/promosi/9", nil)
+	rr := httptest.NewRecorder()
+	handler.HandlePromotionByID(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestDeletePromotion_InvalidID(t *testing.T) {
+	mockService := new(MockPromotionService)
+	handler := NewPromotionHandler(mockService)
+
+	req, _ := http.NewRequest(http.MethodDelete, "/api/promosi/abc", nil)
+	rr := httptest.NewRecorder()
+	handler.HandlePromotionByID(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestPreviewPromotions(t *testing.T) {
+	mockService := new(MockPromotionService)
+	handler := NewPromotionHandler(mockService)
+
+	request := &models.CheckoutRequest{Items: []models.CheckoutItem{{ProductID: 1, Quantity: 3}}, VoucherCode: "HEMAT"}
+	basket := &models.PricedBasket{
+		Lines: []models.PricedLine{{
+			ProductID: 1, ProductName: "Kopi", Quantity: 3, Price: 20000, Subtotal: 60000, Discount: 20000, Total: 40000,
+			Discounts: []models.AppliedDiscount{{PromotionID: 2, Name: "Beli 2 gratis 1", ProductID: 1, Amount: 20000, Explanation: "Beli 2 gratis 1: 1 Kopi gratis"}},
+		}},
+		Discounts:     []models.AppliedDiscount{},
+		Subtotal:      60000,
+		DiscountTotal: 20000,
+		Total:         40000,
+	}
+	mockService.On("Preview", request).Return(basket, nil)
+
+	body, _ := json.Marshal(request)
+	req, _ := http.NewRequest(http.MethodPost, "/api/promosi/preview", bytes.NewBuffer(body))
+	rr := httptest.NewRecorder()
+	handler.HandlePreview(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response models.PricedBasket
+	json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.Equal(t, 40000, response.Total)
+	assert.Equal(t, "Beli 2 gratis 1: 1 Kopi gratis", response.Lines[0].Discounts[0].Explanation)
+	mockService.AssertExpectations(t)
+}
+
+func TestPreviewPromotions_MethodNotAllowed(t *testing.T) {
+	handler := NewPromotionHandler(new(MockPromotionService))
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/promosi/preview", nil)
+	rr := httptest.NewRecorder()
+	handler.HandlePreview(rr, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+}
@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/middleware"
+	"kasir-api/models"
+	"kasir-api/services"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type PurchaseOrderHandler struct {
+	service services.PurchaseOrderServiceInterface
+}
+
+func NewPurchaseOrderHandler(service services.PurchaseOrderServiceInterface) *PurchaseOrderHandler {
+	return &PurchaseOrderHandler{service: service}
+}
+
+// HandlePurchaseOrders - GET/POST /api/pembelian
+func (h *PurchaseOrderHandler) HandlePurchaseOrders(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetAll(w, r)
+	case http.MethodPost:
+		h.Create(w, r)
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+// GetAll - GET /api/pembelian?page=&limit=&supplier_id=&status=
+func (h *PurchaseOrderHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := models.PurchaseOrderFilter{Status: q.Get("status")}
+	var err error
+	if filter.Page, err = queryInt(q, "page"); err != nil {
+		invalidQuery(w, r, err)
+		return
+	}
+	if filter.Limit, err = queryInt(q, "limit"); err != nil {
+		invalidQuery(w, r, err)
+		return
+	}
+	if filter.SupplierID, err = queryInt(q, "supplier_id"); err != nil {
+		invalidQuery(w, r, err)
+		return
+	}
+
+	orders, err := h.service.GetAll(filter)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(orders)
+}
+
+// Create - POST /api/pembelian, buat purchase order draft
+func (h *PurchaseOrderHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.PurchaseOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		invalidBody(w, r)
+		return
+	}
+	if claims := middleware.ClaimsFromContext(r.Context()); claims != nil {
+		req.CreatedBy = claims.Username
+	}
+
+	po, err := h.service.Create(&req)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(po)
+}
+
+// HandlePurchaseOrderByID - GET/PUT /api/pembelian/{id}, POST /api/pembelian/{id}/pesan,
+// /api/pembelian/{id}/batal dan /api/pembelian/{id}/terima
+func (h *PurchaseOrderHandler) HandlePurchaseOrderByID(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/pembelian/")
+	switch {
+	case r.Method == http.MethodGet && !strings.Contains(path, "/"):
+		h.GetByID(w, r)
+	case r.Method == http.MethodPut && !strings.Contains(path, "/"):
+		h.Update(w, r)
+	case r.Method == http.MethodPost && strings.HasSuffix(path, "/pesan"):
+		h.Transition(w, r, "/pesan", h.service.Order)
+	case r.Method == http.MethodPost && strings.HasSuffix(path, "/batal"):
+		h.Transition(w, r, "/batal", h.service.Cancel)
+	case r.Method == http.MethodPost && strings.HasSuffix(path, "/terima"):
+		h.Receive(w, r)
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+func purchaseOrderID(path, suffix string) (int, error) {
+	return strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(path, "/api/pembelian/"), suffix))
+}
+
+func (h *PurchaseOrderHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := purchaseOrderID(r.URL.Path, "")
+	if err != nil {
+		invalidID(w, r, "Invalid purchase order ID")
+		return
+	}
+
+	po, err := h.service.GetByID(id)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(po)
+}
+
+// Update - PUT /api/pembelian/{id}, hanya untuk draft
+func (h *PurchaseOrderHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := purchaseOrderID(r.URL.Path, "")
+	if err != nil {
+		invalidID(w, r, "Invalid purchase order ID")
+		return
+	}
+
+	var req models.PurchaseOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		invalidBody(w, r)
+		return
+	}
+
+	po, err := h.service.Update(id, &req)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(po)
+}
+
+// Transition - POST /api/pembelian/{id}/pesan|batal
+func (h *PurchaseOrderHandler) Transition(w http.ResponseWriter, r *http.Request, suffix string, transition func(int) (*models.PurchaseOrder, error)) {
+	id, err := purchaseOrderID(r.URL.Path, suffix)
+	if err != nil {
+		invalidID(w, r, "Invalid purchase order ID")
+		return
+	}
+
+	po, err := transition(id)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(po)
+}
+
+// Receive - POST /api/pembelian/{id}/terima, catat penerimaan barang
+func (h *PurchaseOrderHandler) Receive(w http.ResponseWriter, r *http.Request) {
+	id, err := purchaseOrderID(r.URL.Path, "/terima")
+	if err != nil {
+		invalidID(w, r, "Invalid purchase order ID")
+		return
+	}
+
+	var req models.GoodsReceiptRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		invalidBody(w, r)
+		return
+	}
+	if claims := middleware.ClaimsFromContext(r.Context()); claims != nil {
+		req.ReceivedBy = claims.Username
+	}
+
+	po, err := h.service.Receive(id, &req)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(po)
+}
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPurchaseOrderService is a mock of PurchaseOrderService
+type MockPurchaseOrderService struct {
+	mock.Mock
+}
+
+func (m *MockPurchaseOrderService) GetAll(filter models.PurchaseOrderFilter) (*models.Page[models.PurchaseOrder], error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Page[models.PurchaseOrder]), args.Error(1)
+}
+
+func (m *MockPurchaseOrderService) GetByID(id int) (*models.PurchaseOrder, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PurchaseOrder), args.Error(1)
+}
+
+func (m *MockPurchaseOrderService) Create(req *models.PurchaseOrderRequest) (*models.PurchaseOrder, error) {
+	args := m.Called(req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PurchaseOrder), args.Error(1)
+}
+
+func (m *MockPurchaseOrderService) Update(id int, req *models.PurchaseOrderRequest) (*models.PurchaseOrder, error) {
+	args := m.Called(id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PurchaseOrder), args.Error(1)
+}
+
+func (m *MockPurchaseOrderService) Order(id int) (*models.PurchaseOrder, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PurchaseOrder), args.Error(1)
+}
+
+func (m *MockPurchaseOrderService) Cancel(id int) (*models.PurchaseOrder, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PurchaseOrder), args.Error(1)
+}
+
+func (m *MockPurchaseOrderService) Receive(id int, req *models.GoodsReceiptRequest) (*models.PurchaseOrder, error) {
+	args := m.Called(id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PurchaseOrder), args.Error(1)
+}
+
+func TestCreatePurchaseOrder(t *testing.T) {
+	mockService := new(MockPurchaseOrderService)
+	handler := NewPurchaseOrderHandler(mockService)
+
+	lines := []models.PurchaseLineRequest{{ProductID: 3, Quantity: 24, UnitCost: 3500}}
+	mockService.On("Create", &models.PurchaseOrderRequest{SupplierID: 1, Lines: lines, CreatedBy: "siti"}).
+		Return(&models.PurchaseOrder{ID: 9, SupplierID: 1, Status: models.PurchaseDraft, TotalCost: 84000}, nil)
+
+	body, _ := json.Marshal(models.PurchaseOrderRequest{SupplierID: 1, Lines: lines})
+	req, _ := http.NewRequest(http.MethodPost, "/api/pembelian", bytes.NewBuffer(body))
+	req, _ = withCashier(req)
+	rr := httptest.NewRecorder()
+	handler.HandlePurchaseOrders(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	var response models.PurchaseOrder
+	err := json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, 84000, response.TotalCost)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestOrderPurchaseOrder(t *testing.T) {
+	mockService := new(MockPurchaseOrderService)
+	handler := NewPurchaseOrderHandler(mockService)
+
+	mockService.On("Order", 9).Return(&models.PurchaseOrder{ID: 9, Status: models.PurchaseOrdered}, nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/pembelian/9/pesan", nil)
+	rr := httptest.NewRecorder()
+	handler.HandlePurchaseOrderByID(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestReceivePurchaseOrder(t *testing.T) {
+	mockService := new(MockPurchaseOrderService)
+	handler := NewPurchaseOrderHandler(mockService)
+
+	unitCost := 3400
+	lines := []models.GoodsReceiptLineRequest{{LineID: 15, Quantity: 12, UnitCost: &unitCost}}
+	mockService.On("Receive", 9, &models.GoodsReceiptRequest{Note: "faktur 001", Lines: lines, ReceivedBy: "siti"}).
+		Return(&models.PurchaseOrder{ID: 9, Status: models.PurchasePartiallyReceived}, nil)
+
+	body, _ := json.Marshal(models.GoodsReceiptRequest{Note: "faktur 001", Lines: lines})
+	req, _ := http.NewRequest(http.MethodPost, "/api/pembelian/9/terima", bytes.NewBuffer(body))
+	req, _ = withCashier(req)
+	rr := httptest.NewRecorder()
+	handler.HandlePurchaseOrderByID(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestReceivePurchaseOrder_ZeroCost(t *testing.T) {
+	mockService := new(MockPurchaseOrderService)
+	handler := NewPurchaseOrderHandler(mockService)
+
+	// unit_cost 0 (barang bonus) harus sampai ke service, bukan dianggap kosong
+	free := 0
+	lines := []models.GoodsReceiptLineRequest{{LineID: 15, Quantity: 2, UnitCost: &free}, {LineID: 16, Quantity: 5}}
+	mockService.On("Receive", 9, &models.GoodsReceiptRequest{Lines: lines, ReceivedBy: "siti"}).
+		Return(&models.PurchaseOrder{ID: 9, Status: models.PurchasePartiallyReceived}, nil)
+
+	body := `{"lines": [{"line_id": 15, "quantity": 2, "unit_cost": 0}, {"line_id": 16, "quantity": 5}]}`
+	req, _ := http.NewRequest(http.MethodPost, "/api/pembelian/9/terima", bytes.NewBufferString(body))
+	req, _ = withCashier(req)
+	rr := httptest.NewRecorder()
+	handler.HandlePurchaseOrderByID(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestReceivePurchaseOrder_NotOrdered(t *testing.T) {
+	mockService := new(MockPurchaseOrderService)
+	handler := NewPurchaseOrderHandler(mockService)
+
+	lines := []models.GoodsReceiptLineRequest{{LineID: 15, Quantity: 12}}
+	mockService.On("Receive", 9, &models.GoodsReceiptRequest{Lines: lines}).
+		Return(nil, repositories.NewError(repositories.ErrConflict, "purchase order berstatus draft tidak bisa diterima"))
+
+	body, _ := json.Marshal(models.GoodsReceiptRequest{Lines: lines})
+	req, _ := http.NewRequest(http.MethodPost, "/api/pembelian/9/terima", bytes.NewBuffer(body))
+	rr := httptest.NewRecorder()
+	handler.HandlePurchaseOrderByID(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestUpdatePurchaseOrder_MethodNotAllowed(t *testing.T) {
+	mockService := new(MockPurchaseOrderService)
+	handler := NewPurchaseOrderHandler(mockService)
+
+	req, _ := http.NewRequest(http.MethodDelete, "/api/pembelian/9", nil)
+	rr := httptest.NewRecorder()
+	handler.HandlePurchaseOrderByID(rr, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// queryInt - baca parameter query integer opsional, 0 jika kosong
+func queryInt(q url.Values, key string) (int, error) {
+	value := q.Get(key)
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("parameter %s harus berupa angka", key)
+	}
+	return n, nil
+}
+
+// queryIntPtr - seperti queryInt tapi nil jika parameter tidak dikirim
+func queryIntPtr(q url.Values, key string) (*int, error) {
+	if q.Get(key) == "" {
+		return nil, nil
+	}
+	n, err := queryInt(q, key)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// queryBoolPtr - baca parameter query boolean opsional
+func queryBoolPtr(q url.Values, key string) (*bool, error) {
+	value := q.Get(key)
+	if value == "" {
+		return nil, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("parameter %s harus berupa true atau false", key)
+	}
+	return &b, nil
+}
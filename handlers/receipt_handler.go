@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"kasir-api/services"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type ReceiptHandler struct {
+	service services.ReceiptServiceInterface
+}
+
+func NewReceiptHandler(service services.ReceiptServiceInterface) *ReceiptHandler {
+	return &ReceiptHandler{service: service}
+}
+
+// HandleReceipt - GET /api/transaksi/{id}/struk?format=text|escpos|pdf&width=32|48
+func (h *ReceiptHandler) HandleReceipt(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.Render(w, r)
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+// Render - GET /api/transaksi/{id}/struk
+func (h *ReceiptHandler) Render(w http.ResponseWriter, r *http.Request) {
+	id, err := transactionIDFromReceiptPath(r.URL.Path)
+	if err != nil {
+		invalidID(w, r, "Invalid transaction ID")
+		return
+	}
+
+	q := r.URL.Query()
+	width, err := queryInt(q, "width")
+	if err != nil {
+		invalidQuery(w, r, err)
+		return
+	}
+
+	receipt, err := h.service.Render(id, q.Get("format"), width)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", receipt.ContentType)
+	w.Header().Set("Content-Disposition", `inline; filename="`+receipt.Filename+`"`)
+	w.Write(receipt.Body)
+}
+
+func transactionIDFromReceiptPath(path string) (int, error) {
+	idStr := strings.TrimPrefix(path, "/api/transaksi/")
+	idStr = strings.TrimSuffix(idStr, "/struk")
+	return strconv.Atoi(idStr)
+}
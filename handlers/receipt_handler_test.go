@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"kasir-api/response"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockReceiptService is a mock of ReceiptService
+type MockReceiptService struct {
+	mock.Mock
+}
+
+func (m *MockReceiptService) Render(transactionID int, format string, width int) (*models.RenderedReceipt, error) {
+	args := m.Called(transactionID, format, width)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RenderedReceipt), args.Error(1)
+}
+
+func TestRenderReceipt(t *testing.T) {
+	mockService := new(MockReceiptService)
+	handler := NewReceiptHandler(mockService)
+
+	rendered := &models.RenderedReceipt{
+		ContentType: "application/pdf",
+		Filename:    "struk-7.pdf",
+		Body:        []byte("%PDF-1.4"),
+	}
+	mockService.On("Render", 7, "pdf", 48).Return(rendered, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/transaksi/7/struk?format=pdf&width=48", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleReceipt(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/pdf", rr.Header().Get("Content-Type"))
+	assert.Equal(t, `inline; filename="struk-7.pdf"`, rr.Header().Get("Content-Disposition"))
+	assert.Equal(t, "%PDF-1.4", rr.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestRenderReceiptDefaults(t *testing.T) {
+	mockService := new(MockReceiptService)
+	handler := NewReceiptHandler(mockService)
+
+	rendered := &models.RenderedReceipt{ContentType: "text/plain; charset=utf-8", Filename: "struk-7.txt", Body: []byte("TOTAL")}
+	mockService.On("Render", 7, "", 0).Return(rendered, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/transaksi/7/struk", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleReceipt(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "TOTAL", rr.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestRenderReceiptNotFound(t *testing.T) {
+	mockService := new(MockReceiptService)
+	handler := NewReceiptHandler(mockService)
+
+	mockService.On("Render", 99, "text", 0).Return(nil, repositories.NewError(repositories.ErrNotFound, "transaction not found"))
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/transaksi/99/struk?format=text", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleReceipt(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	var body response.ErrorBody
+	json.Unmarshal(rr.Body.Bytes(), &body)
+	assert.Equal(t, response.CodeNotFound, body.Code)
+}
+
+func TestRenderReceiptInvalidWidth(t *testing.T) {
+	mockService := new(MockReceiptService)
+	handler := NewReceiptHandler(mockService)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/transaksi/7/struk?width=lebar", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleReceipt(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertNotCalled(t, "Render", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRenderReceiptInvalidID(t *testing.T) {
+	mockService := new(MockReceiptService)
+	handler := NewReceiptHandler(mockService)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/transaksi/abc/struk", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleReceipt(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
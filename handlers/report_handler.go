@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/models"
+	"kasir-api/services"
+	"net/http"
+)
+
+type ReportHandler struct {
+	service services.ReportServiceInterface
+}
+
+func NewReportHandler(service services.ReportServiceInterface) *ReportHandler {
+	return &ReportHandler{service: service}
+}
+
+// HandleSales - GET /api/laporan/penjualan?from=&to=&group_by=day|week|month
+func (h *ReportHandler) HandleSales(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, r)
+		return
+	}
+
+	report, err := h.service.Sales(reportFilter(r))
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// HandleTopProducts - GET /api/laporan/produk-terlaris?from=&to=&sort=quantity|revenue&limit=
+func (h *ReportHandler) HandleTopProducts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, r)
+		return
+	}
+
+	filter := reportFilter(r)
+	filter.Sort = r.URL.Query().Get("sort")
+	var err error
+	if filter.Limit, err = queryInt(r.URL.Query(), "limit"); err != nil {
+		invalidQuery(w, r, err)
+		return
+	}
+
+	report, err := h.service.TopProducts(filter)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// HandleCategories - GET /api/laporan/kategori?from=&to=
+func (h *ReportHandler) HandleCategories(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, r)
+		return
+	}
+
+	report, err := h.service.Categories(reportFilter(r))
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// HandleHourly - GET /api/laporan/jam?from=&to=
+func (h *ReportHandler) HandleHourly(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, r)
+		return
+	}
+
+	report, err := h.service.Hourly(reportFilter(r))
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// HandleMargin - GET /api/laporan/margin?from=&to=&group_by=product|category|day|week|month
+func (h *ReportHandler) HandleMargin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, r)
+		return
+	}
+
+	report, err := h.service.Margin(reportFilter(r))
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+func reportFilter(r *http.Request) models.ReportFilter {
+	q := r.URL.Query()
+	return models.ReportFilter{From: q.Get("from"), To: q.Get("to"), GroupBy: q.Get("group_by")}
+}
@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockReportService is a mock of ReportService
+type MockReportService struct {
+	mock.Mock
+}
+
+func (m *MockReportService) Margin(filter models.ReportFilter) (*models.MarginReport, error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MarginReport), args.Error(1)
+}
+
+func (m *MockReportService) Sales(filter models.ReportFilter) (*models.SalesReport, error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SalesReport), args.Error(1)
+}
+
+func (m *MockReportService) TopProducts(filter models.ReportFilter) (*models.TopProductsReport, error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TopProductsReport), args.Error(1)
+}
+
+func (m *MockReportService) Categories(filter models.ReportFilter) (*models.CategoryReport, error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CategoryReport), args.Error(1)
+}
+
+func (m *MockReportService) Hourly(filter models.ReportFilter) (*models.HourlyReport, error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.HourlyReport), args.Error(1)
+}
+
+func TestSalesReport(t *testing.T) {
+	mockService := new(MockReportService)
+	handler := NewReportHandler(mockService)
+
+	filter := models.ReportFilter{From: "2026-10-01", To: "2026-10-07", GroupBy: "week"}
+	report := &models.SalesReport{
+		From: "2026-10-01", To: "2026-10-07", GroupBy: "week",
+		Rows: []models.SalesRow{{Period: "2026-09-28", Transactions: 4, Revenue: 200000, NetRevenue: 200000, AverageBasket: 50000}},
+	}
+	mockService.On("Sales", filter).Return(report, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/laporan/penjualan?from=2026-10-01&to=2026-10-07&group_by=week", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleSales(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var body models.SalesReport
+	json.Unmarshal(rr.Body.Bytes(), &body)
+	assert.Equal(t, 50000, body.Rows[0].AverageBasket)
+	mockService.AssertExpectations(t)
+}
+
+func TestTopProductsReport(t *testing.T) {
+	mockService := new(MockReportService)
+	handler := NewReportHandler(mockService)
+
+	filter := models.ReportFilter{Sort: "revenue", Limit: 5}
+	report := &models.TopProductsReport{Sort: "revenue", Rows: []models.ProductSales{{ProductID: 3, Name: "Kopi Susu", Quantity: 40, Revenue: 720000}}}
+	mockService.On("TopProducts", filter).Return(report, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/laporan/produk-terlaris?sort=revenue&limit=5", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleTopProducts(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestTopProductsReportInvalidLimit(t *testing.T) {
+	mockService := new(MockReportService)
+	handler := NewReportHandler(mockService)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/laporan/produk-terlaris?limit=abc", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleTopProducts(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertNotCalled(t, "TopProducts", mock.Anything)
+}
+
+func TestCategoryReport(t *testing.T) {
+	mockService := new(MockReportService)
+	handler := NewReportHandler(mockService)
+
+	report := &models.CategoryReport{
+		Rows:    []models.CategorySales{{CategoryID: 2, CategoryName: "Minuman", Revenue: 750000, ShareBps: 7500}},
+		Revenue: 1000000,
+	}
+	mockService.On("Categories", models.ReportFilter{From: "2026-10-01"}).Return(report, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/laporan/kategori?from=2026-10-01", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleCategories(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestHourlyReportMethodNotAllowed(t *testing.T) {
+	mockService := new(MockReportService)
+	handler := NewReportHandler(mockService)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/laporan/jam", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleHourly(rr, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+}
+
+func TestMarginReport(t *testing.T) {
+	mockService := new(MockReportService)
+	handler := NewReportHandler(mockService)
+
+	filter := models.ReportFilter{From: "2026-10-01", To: "2026-10-31", GroupBy: "category"}
+	report := &models.MarginReport{
+		From: "2026-10-01", To: "2026-10-31", GroupBy: "category",
+		Rows:  []models.MarginRow{{ID: 1, Name: "Minuman", Quantity: 10, Revenue: 50000, Cost: 35000, GrossProfit: 15000, MarginBps: 3000}},
+		Total: models.MarginRow{Quantity: 10, Revenue: 50000, Cost: 35000, GrossProfit: 15000, MarginBps: 3000},
+	}
+	mockService.On("Margin", filter).Return(report, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/laporan/margin?from=2026-10-01&to=2026-10-31&group_by=category", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleMargin(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var body models.MarginReport
+	json.Unmarshal(rr.Body.Bytes(), &body)
+	assert.Equal(t, 3000, body.Total.MarginBps)
+	assert.Equal(t, "Minuman", body.Rows[0].Name)
+	mockService.AssertExpectations(t)
+}
+
+func TestMarginReportInvalidGroup(t *testing.T) {
+	mockService := new(MockReportService)
+	handler := NewReportHandler(mockService)
+
+	mockService.On("Margin", models.ReportFilter{GroupBy: "year"}).
+		Return(nil, repositories.NewError(repositories.ErrValidation, "group_by tidak dikenal"))
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/laporan/margin?group_by=year", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleMargin(rr, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	mockService.AssertExpectations(t)
+}
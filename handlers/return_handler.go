@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/middleware"
+	"kasir-api/models"
+	"kasir-api/services"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type ReturnHandler struct {
+	service services.ReturnServiceInterface
+}
+
+func NewReturnHandler(service services.ReturnServiceInterface) *ReturnHandler {
+	return &ReturnHandler{service: service}
+}
+
+// HandleReturns - GET/POST /api/retur
+func (h *ReturnHandler) HandleReturns(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetAll(w, r)
+	case http.MethodPost:
+		h.Create(w, r)
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+// GetAll - GET /api/retur?page=&limit=&transaction_id=&status=
+func (h *ReturnHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := models.ReturnFilter{Status: q.Get("status")}
+	var err error
+	if filter.Page, err = queryInt(q, "page"); err != nil {
+		invalidQuery(w, r, err)
+		return
+	}
+	if filter.Limit, err = queryInt(q, "limit"); err != nil {
+		invalidQuery(w, r, err)
+		return
+	}
+	if filter.TransactionID, err = queryInt(q, "transaction_id"); err != nil {
+		invalidQuery(w, r, err)
+		return
+	}
+
+	returns, err := h.service.GetAll(filter)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(returns)
+}
+
+// Create - POST /api/retur
+func (h *ReturnHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.ReturnRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		invalidBody(w, r)
+		return
+	}
+
+	if claims := middleware.ClaimsFromContext(r.Context()); claims != nil {
+		req.RequestedBy = claims.Username
+	}
+
+	ret, err := h.service.Create(&req)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(ret)
+}
+
+// HandleReturnByID - GET /api/retur/{id}, POST /api/retur/{id}/approve dan /api/retur/{id}/reject
+func (h *ReturnHandler) HandleReturnByID(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/retur/")
+	switch {
+	case r.Method == http.MethodGet && !strings.Contains(path, "/"):
+		h.GetByID(w, r)
+	case r.Method == http.MethodPost && strings.HasSuffix(path, "/approve"):
+		h.Decide(w, r, "/approve", h.service.Approve)
+	case r.Method == http.MethodPost && strings.HasSuffix(path, "/reject"):
+		h.Decide(w, r, "/reject", h.service.Reject)
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+// GetByID - GET /api/retur/{id}
+func (h *ReturnHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/retur/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		invalidID(w, r, "Invalid return ID")
+		return
+	}
+
+	ret, err := h.service.GetByID(id)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ret)
+}
+
+// Decide - POST /api/retur/{id}/approve|reject, body {"note": "..."}
+func (h *ReturnHandler) Decide(w http.ResponseWriter, r *http.Request, suffix string, decide func(int, *models.ReturnDecision) (*models.Return, error)) {
+	idStr := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/retur/"), suffix)
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		invalidID(w, r, "Invalid return ID")
+		return
+	}
+
+	var decision models.ReturnDecision
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&decision); err != nil {
+			invalidBody(w, r)
+			return
+		}
+	}
+	if claims := middleware.ClaimsFromContext(r.Context()); claims != nil {
+		decision.DecidedBy = claims.Username
+	}
+
+	ret, err := decide(id, &decision)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ret)
+}
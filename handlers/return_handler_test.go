@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"kasir-api/middleware"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockReturnService is a mock of ReturnService
+type MockReturnService struct {
+	mock.Mock
+}
+
+func (m *MockReturnService) GetAll(filter models.ReturnFilter) (*models.Page[models.Return], error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Page[models.Return]), args.Error(1)
+}
+
+func (m *MockReturnService) GetByID(id int) (*models.Return, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Return), args.Error(1)
+}
+
+func (m *MockReturnService) Create(req *models.ReturnRequest) (*models.Return, error) {
+	args := m.Called(req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Return), args.Error(1)
+}
+
+func (m *MockReturnService) Approve(id int, decision *models.ReturnDecision) (*models.Return, error) {
+	args := m.Called(id, decision)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Return), args.Error(1)
+}
+
+func (m *MockReturnService) Reject(id int, decision *models.ReturnDecision) (*models.Return, error) {
+	args := m.Called(id, decision)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Return), args.Error(1)
+}
+
+func TestGetAllReturns(t *testing.T) {
+	mockService := new(MockReturnService)
+	handler := NewReturnHandler(mockService)
+
+	page := models.NewPage([]models.Return{{ID: 1, TransactionID: 7, Status: models.ReturnPending}}, 1, 20, 1)
+	mockService.On("GetAll", models.ReturnFilter{TransactionID: 7, Status: models.ReturnPending}).Return(page, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/retur?transaction_id=7&status=pending", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleReturns(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateReturn(t *testing.T) {
+	mockService := new(MockReturnService)
+	handler := NewReturnHandler(mockService)
+
+	lines := []models.ReturnLineRequest{{TransactionDetailID: 11, Quantity: 1, Disposition: models.DispositionRestock}}
+	expected := &models.Return{ID: 1, TransactionID: 7, Status: models.ReturnPending, RefundAmount: 3500, RequestedBy: "siti"}
+	mockService.On("Create", &models.ReturnRequest{TransactionID: 7, Reason: "salah beli", Lines: lines, RequestedBy: "siti"}).Return(expected, nil)
+
+	body, _ := json.Marshal(models.ReturnRequest{TransactionID: 7, Reason: "salah beli", Lines: lines})
+	req, _ := http.NewRequest(http.MethodPost, "/api/retur", bytes.NewBuffer(body))
+	req = req.WithContext(middleware.WithClaims(req.Context(), &models.Claims{Username: "siti", Role: models.RoleCashier}))
+	rr := httptest.NewRecorder()
+	handler.HandleReturns(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	var response models.Return
+	err := json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, 3500, response.RefundAmount)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateReturn_ExceedsSold(t *testing.T) {
+	mockService := new(MockReturnService)
+	handler := NewReturnHandler(mockService)
+
+	lines := []models.ReturnLineRequest{{TransactionDetailID: 11, Quantity: 5, Disposition: models.DispositionRestock}}
+	mockService.On("Create", &models.ReturnRequest{TransactionID: 7, Reason: "rusak", Lines: lines}).
+		Return(nil, repositories.NewError(repositories.ErrValidation, "retur Indomie melebihi jumlah terjual, sisa 2"))
+
+	body, _ := json.Marshal(models.ReturnRequest{TransactionID: 7, Reason: "rusak", Lines: lines})
+	req, _ := http.NewRequest(http.MethodPost, "/api/retur", bytes.NewBuffer(body))
+	rr := httptest.NewRecorder()
+	handler.HandleReturns(rr, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestApproveReturn(t *testing.T) {
+	mockService := new(MockReturnService)
+	handler := NewReturnHandler(mockService)
+
+	mockService.On("Approve", 1, &models.ReturnDecision{DecidedBy: "budi"}).Return(&models.Return{ID: 1, Status: models.ReturnApproved, DecidedBy: "budi"}, nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/retur/1/approve", nil)
+	req = req.WithContext(middleware.WithClaims(req.Context(), &models.Claims{Username: "budi", Role: models.RoleSupervisor}))
+	rr := httptest.NewRecorder()
+	handler.HandleReturnByID(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestRejectReturn_AlreadyDecided(t *testing.T) {
+	mockService := new(MockReturnService)
+	handler := NewReturnHandler(mockService)
+
+	mockService.On("Reject", 1, &models.ReturnDecision{Note: "tidak ada struk"}).
+		Return(nil, repositories.NewError(repositories.ErrConflict, "retur sudah approved"))
+
+	body, _ := json.Marshal(models.ReturnDecision{Note: "tidak ada struk"})
+	req, _ := http.NewRequest(http.MethodPost, "/api/retur/1/reject", bytes.NewBuffer(body))
+	rr := httptest.NewRecorder()
+	handler.HandleReturnByID(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleReturnByID_MethodNotAllowed(t *testing.T) {
+	mockService := new(MockReturnService)
+	handler := NewReturnHandler(mockService)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/retur/1/approve", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleReturnByID(rr, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+}
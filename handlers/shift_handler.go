@@ -0,0 +1,274 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/middleware"
+	"kasir-api/models"
+	"kasir-api/services"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type ShiftHandler struct {
+	service services.ShiftServiceInterface
+}
+
+func NewShiftHandler(service services.ShiftServiceInterface) *ShiftHandler {
+	return &ShiftHandler{service: service}
+}
+
+// HandleShifts - GET/POST /api/shift
+func (h *ShiftHandler) HandleShifts(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetAll(w, r)
+	case http.MethodPost:
+		h.Open(w, r)
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+// GetAll - GET /api/shift?page=&limit=&cashier=&status=
+func (h *ShiftHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := models.ShiftFilter{Cashier: q.Get("cashier"), Status: q.Get("status")}
+	var err error
+	if filter.Page, err = queryInt(q, "page"); err != nil {
+		invalidQuery(w, r, err)
+		return
+	}
+	if filter.Limit, err = queryInt(q, "limit"); err != nil {
+		invalidQuery(w, r, err)
+		return
+	}
+
+	shifts, err := h.service.GetAll(filter)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(shifts)
+}
+
+// Open - POST /api/shift, buka shift untuk kasir yang login
+func (h *ShiftHandler) Open(w http.ResponseWriter, r *http.Request) {
+	var req models.OpenShiftRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		invalidBody(w, r)
+		return
+	}
+
+	if claims := middleware.ClaimsFromContext(r.Context()); claims != nil {
+		req.Cashier = claims.Username
+	}
+
+	shift, err := h.service.Open(&req)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(shift)
+}
+
+// HandleCurrent - GET /api/shift/current, shift terbuka milik kasir yang login
+func (h *ShiftHandler) HandleCurrent(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, r)
+		return
+	}
+
+	cashier := ""
+	if claims := middleware.ClaimsFromContext(r.Context()); claims != nil {
+		cashier = claims.Username
+	}
+
+	shift, err := h.service.GetCurrent(cashier)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(shift)
+}
+
+// HandleShiftByID - GET /api/shift/{id}, POST /api/shift/{id}/kas, POST /api/shift/{id}/tutup,
+// GET /api/shift/{id}/x dan POST /api/shift/{id}/z
+func (h *ShiftHandler) HandleShiftByID(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/shift/")
+	switch {
+	case r.Method == http.MethodGet && !strings.Contains(path, "/"):
+		h.GetByID(w, r)
+	case r.Method == http.MethodPost && strings.HasSuffix(path, "/kas"):
+		h.AddCashEvent(w, r)
+	case r.Method == http.MethodPost && strings.HasSuffix(path, "/tutup"):
+		h.Close(w, r)
+	case r.Method == http.MethodGet && strings.HasSuffix(path, "/x"):
+		h.XReport(w, r)
+	case r.Method == http.MethodPost && strings.HasSuffix(path, "/z"):
+		h.ZReport(w, r)
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+// shiftIDFromPath - ambil ID shift dari /api/shift/{id} atau /api/shift/{id}/...
+func shiftIDFromPath(path string) (int, error) {
+	idStr := strings.TrimPrefix(path, "/api/shift/")
+	if i := strings.Index(idStr, "/"); i >= 0 {
+		idStr = idStr[:i]
+	}
+	return strconv.Atoi(idStr)
+}
+
+// GetByID - GET /api/shift/{id}
+func (h *ShiftHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := shiftIDFromPath(r.URL.Path)
+	if err != nil {
+		invalidID(w, r, "Invalid shift ID")
+		return
+	}
+
+	shift, err := h.service.GetByID(id, middleware.ClaimsFromContext(r.Context()))
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(shift)
+}
+
+// AddCashEvent - POST /api/shift/{id}/kas, body {"type": "cash_in"|"cash_out", "amount": 50000, "reason": "..."}
+func (h *ShiftHandler) AddCashEvent(w http.ResponseWriter, r *http.Request) {
+	id, err := shiftIDFromPath(r.URL.Path)
+	if err != nil {
+		invalidID(w, r, "Invalid shift ID")
+		return
+	}
+
+	var event models.CashEvent
+	err = json.NewDecoder(r.Body).Decode(&event)
+	if err != nil {
+		invalidBody(w, r)
+		return
+	}
+
+	claims := middleware.ClaimsFromContext(r.Context())
+	event.ShiftID = id
+	if claims != nil {
+		event.User = claims.Username
+	}
+
+	err = h.service.AddCashEvent(&event, claims)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(event)
+}
+
+// Close - POST /api/shift/{id}/tutup, body {"counted_cash": 1250000}
+func (h *ShiftHandler) Close(w http.ResponseWriter, r *http.Request) {
+	id, err := shiftIDFromPath(r.URL.Path)
+	if err != nil {
+		invalidID(w, r, "Invalid shift ID")
+		return
+	}
+
+	var req models.CloseShiftRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		invalidBody(w, r)
+		return
+	}
+
+	claims := middleware.ClaimsFromContext(r.Context())
+	if claims != nil {
+		req.ClosedBy = claims.Username
+	}
+
+	shift, err := h.service.Close(id, &req, claims)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(shift)
+}
+
+// XReport - GET /api/shift/{id}/x?format=json|text|escpos|pdf&width=32|48
+func (h *ShiftHandler) XReport(w http.ResponseWriter, r *http.Request) {
+	id, err := shiftIDFromPath(r.URL.Path)
+	if err != nil {
+		invalidID(w, r, "Invalid shift ID")
+		return
+	}
+
+	report, err := h.service.XReport(id, middleware.ClaimsFromContext(r.Context()))
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+	h.writeReport(w, r, report)
+}
+
+// ZReport - POST /api/shift/{id}/z?format=json|text|escpos|pdf&width=32|48, mengunci shift
+func (h *ShiftHandler) ZReport(w http.ResponseWriter, r *http.Request) {
+	id, err := shiftIDFromPath(r.URL.Path)
+	if err != nil {
+		invalidID(w, r, "Invalid shift ID")
+		return
+	}
+
+	user := ""
+	if claims := middleware.ClaimsFromContext(r.Context()); claims != nil {
+		user = claims.Username
+	}
+
+	report, err := h.service.ZReport(id, user)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+	h.writeReport(w, r, report)
+}
+
+// writeReport - tulis laporan sebagai JSON (default) atau format cetak
+func (h *ShiftHandler) writeReport(w http.ResponseWriter, r *http.Request, report *models.ShiftReport) {
+	q := r.URL.Query()
+	format := q.Get("format")
+	if format == "" || format == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(report)
+		return
+	}
+
+	width, err := queryInt(q, "width")
+	if err != nil {
+		invalidQuery(w, r, err)
+		return
+	}
+
+	rendered, err := h.service.RenderReport(report, format, width)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", rendered.ContentType)
+	w.Header().Set("Content-Disposition", `inline; filename="`+rendered.Filename+`"`)
+	w.Write(rendered.Body)
+}
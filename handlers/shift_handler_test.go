@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"kasir-api/middleware"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"kasir-api/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockShiftService is a mock of ShiftService
+type MockShiftService struct {
+	mock.Mock
+}
+
+func (m *MockShiftService) GetAll(filter models.ShiftFilter) (*models.Page[models.Shift], error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Page[models.Shift]), args.Error(1)
+}
+
+func (m *MockShiftService) GetByID(id int, actor *models.Claims) (*models.Shift, error) {
+	args := m.Called(id, actor)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Shift), args.Error(1)
+}
+
+func (m *MockShiftService) GetCurrent(cashier string) (*models.Shift, error) {
+	args := m.Called(cashier)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Shift), args.Error(1)
+}
+
+func (m *MockShiftService) Open(req *models.OpenShiftRequest) (*models.Shift, error) {
+	args := m.Called(req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Shift), args.Error(1)
+}
+
+func (m *MockShiftService) AddCashEvent(event *models.CashEvent, actor *models.Claims) error {
+	args := m.Called(event, actor)
+	return args.Error(0)
+}
+
+func (m *MockShiftService) Close(id int, req *models.CloseShiftRequest, actor *models.Claims) (*models.Shift, error) {
+	args := m.Called(id, req, actor)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Shift), args.Error(1)
+}
+
+func (m *MockShiftService) XReport(id int, actor *models.Claims) (*models.ShiftReport, error) {
+	args := m.Called(id, actor)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ShiftReport), args.Error(1)
+}
+
+func (m *MockShiftService) ZReport(id int, user string) (*models.ShiftReport, error) {
+	args := m.Called(id, user)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ShiftReport), args.Error(1)
+}
+
+func (m *MockShiftService) RenderReport(report *models.ShiftReport, format string, width int) (*models.RenderedReceipt, error) {
+	args := m.Called(report, format, width)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RenderedReceipt), args.Error(1)
+}
+
+func withCashier(req *http.Request) (*http.Request, *models.Claims) {
+	claims := &models.Claims{Username: "siti", Role: models.RoleCashier}
+	return req.WithContext(middleware.WithClaims(req.Context(), claims)), claims
+}
+
+func TestOpenShift(t *testing.T) {
+	mockService := new(MockShiftService)
+	handler := NewShiftHandler(mockService)
+
+	mockService.On("Open", &models.OpenShiftRequest{OpeningFloat: 200000, Cashier: "siti"}).
+		Return(&models.Shift{ID: 1, Cashier: "siti", Status: models.ShiftOpen, OpeningFloat: 200000}, nil)
+
+	body, _ := json.Marshal(models.OpenShiftRequest{OpeningFloat: 200000})
+	req, _ := http.NewRequest(http.MethodPost, "/api/shift", bytes.NewBuffer(body))
+	req, _ = withCashier(req)
+	rr := httptest.NewRecorder()
+	handler.HandleShifts(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestOpenShift_AlreadyOpen(t *testing.T) {
+	mockService := new(MockShiftService)
+	handler := NewShiftHandler(mockService)
+
+	mockService.On("Open", &models.OpenShiftRequest{Cashier: "siti"}).
+		Return(nil, repositories.NewError(repositories.ErrConflict, "kasir siti masih punya shift terbuka"))
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/shift", bytes.NewBufferString(`{}`))
+	req, _ = withCashier(req)
+	rr := httptest.NewRecorder()
+	handler.HandleShifts(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCurrentShift_NoneOpen(t *testing.T) {
+	mockService := new(MockShiftService)
+	handler := NewShiftHandler(mockService)
+
+	mockService.On("GetCurrent", "siti").Return(nil, repositories.NewError(repositories.ErrNotFound, "tidak ada shift terbuka"))
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/shift/current", nil)
+	req, _ = withCashier(req)
+	rr := httptest.NewRecorder()
+	handler.HandleCurrent(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestAddCashEvent(t *testing.T) {
+	mockService := new(MockShiftService)
+	handler := NewShiftHandler(mockService)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/shift/4/kas", bytes.NewBufferString(`{"type":"cash_out","amount":50000,"reason":"beli galon"}`))
+	req, claims := withCashier(req)
+	mockService.On("AddCashEvent", &models.CashEvent{ShiftID: 4, Type: models.CashOut, Amount: 50000, Reason: "beli galon", User: "siti"}, claims).Return(nil)
+
+	rr := httptest.NewRecorder()
+	handler.HandleShiftByID(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCloseShift_OtherCashier(t *testing.T) {
+	mockService := new(MockShiftService)
+	handler := NewShiftHandler(mockService)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/shift/4/tutup", bytes.NewBufferString(`{"counted_cash":1000000}`))
+	req, claims := withCashier(req)
+	mockService.On("Close", 4, &models.CloseShiftRequest{CountedCash: 1000000, ClosedBy: "siti"}, claims).Return(nil, services.ErrForbidden)
+
+	rr := httptest.NewRecorder()
+	handler.HandleShiftByID(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestXReport_JSON(t *testing.T) {
+	mockService := new(MockShiftService)
+	handler := NewShiftHandler(mockService)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/shift/4/x", nil)
+	req, claims := withCashier(req)
+	report := &models.ShiftReport{Type: models.ReportX, Shift: models.Shift{ID: 4}, ExpectedCash: 750000}
+	mockService.On("XReport", 4, claims).Return(report, nil)
+
+	rr := httptest.NewRecorder()
+	handler.HandleShiftByID(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+
+	var response models.ShiftReport
+	err := json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, 750000, response.ExpectedCash)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestZReport_Text(t *testing.T) {
+	mockService := new(MockShiftService)
+	handler := NewShiftHandler(mockService)
+
+	report := &models.ShiftReport{Type: models.ReportZ, Shift: models.Shift{ID: 4, Status: models.ShiftLocked}}
+	mockService.On("ZReport", 4, "budi").Return(report, nil)
+	mockService.On("RenderReport", report, "text", 48).Return(&models.RenderedReceipt{
+		ContentType: "text/plain; charset=utf-8",
+		Filename:    "z-report-shift-4.txt",
+		Body:        []byte("Z REPORT\n"),
+	}, nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/shift/4/z?format=text&width=48", nil)
+	req = req.WithContext(middleware.WithClaims(req.Context(), &models.Claims{Username: "budi", Role: models.RoleSupervisor}))
+	rr := httptest.NewRecorder()
+	handler.HandleShiftByID(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "Z REPORT\n", rr.Body.String())
+	assert.Contains(t, rr.Header().Get("Content-Disposition"), "z-report-shift-4.txt")
+	mockService.AssertExpectations(t)
+}
+
+func TestZReport_ShiftStillOpen(t *testing.T) {
+	mockService := new(MockShiftService)
+	handler := NewShiftHandler(mockService)
+
+	mockService.On("ZReport", 4, "").Return(nil, repositories.NewError(repositories.ErrConflict, "shift harus ditutup sebelum Z report"))
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/shift/4/z", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleShiftByID(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleShiftByID_InvalidID(t *testing.T) {
+	mockService := new(MockShiftService)
+	handler := NewShiftHandler(mockService)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/shift/abc/x", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleShiftByID(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/middleware"
+	"kasir-api/models"
+	"kasir-api/services"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type StockMovementHandler struct {
+	service services.StockMovementServiceInterface
+}
+
+func NewStockMovementHandler(service services.StockMovementServiceInterface) *StockMovementHandler {
+	return &StockMovementHandler{service: service}
+}
+
+// HandleProductStock - GET/POST /api/produk/{id}/stok
+func (h *StockMovementHandler) HandleProductStock(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.History(w, r)
+	case http.MethodPost:
+		h.Record(w, r)
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+// Record - POST /api/produk/{id}/stok
+func (h *StockMovementHandler) Record(w http.ResponseWriter, r *http.Request) {
+	productID, err := productIDFromStockPath(r.URL.Path)
+	if err != nil {
+		invalidID(w, r, "Invalid product ID")
+		return
+	}
+
+	var movement models.StockMovement
+	err = json.NewDecoder(r.Body).Decode(&movement)
+	if err != nil {
+		invalidBody(w, r)
+		return
+	}
+
+	movement.ProductID = productID
+	movement.ReferenceID = nil
+	if claims := middleware.ClaimsFromContext(r.Context()); claims != nil {
+		movement.User = claims.Username
+	}
+	err = h.service.Record(&movement)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(movement)
+}
+
+// History - GET /api/produk/{id}/stok
+func (h *StockMovementHandler) History(w http.ResponseWriter, r *http.Request) {
+	productID, err := productIDFromStockPath(r.URL.Path)
+	if err != nil {
+		invalidID(w, r, "Invalid product ID")
+		return
+	}
+
+	history, err := h.service.History(productID)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
+
+func productIDFromStockPath(path string) (int, error) {
+	idStr := strings.TrimPrefix(path, "/api/produk/")
+	idStr = strings.TrimSuffix(idStr, "/stok")
+	return strconv.Atoi(idStr)
+}
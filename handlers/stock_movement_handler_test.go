@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockStockMovementService is a mock of StockMovementService
+type MockStockMovementService struct {
+	mock.Mock
+}
+
+func (m *MockStockMovementService) Record(movement *models.StockMovement) error {
+	args := m.Called(movement)
+	return args.Error(0)
+}
+
+func (m *MockStockMovementService) History(productID int) (*models.StockHistory, error) {
+	args := m.Called(productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StockHistory), args.Error(1)
+}
+
+func TestRecordStockMovement(t *testing.T) {
+	mockService := new(MockStockMovementService)
+	handler := NewStockMovementHandler(mockService)
+
+	mockService.On("Record", mock.MatchedBy(func(m *models.StockMovement) bool {
+		return m.ProductID == 3 && m.Type == models.MovementRestock && m.Quantity == 24
+	})).Return(nil)
+
+	body, _ := json.Marshal(models.StockMovement{Type: models.MovementRestock, Quantity: 24, Reason: "kiriman supplier", User: "budi"})
+	req, err := http.NewRequest(http.MethodPost, "/api/produk/3/stok", bytes.NewBuffer(body))
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler.HandleProductStock(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	var response models.StockMovement
+	err = json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, response.ProductID)
+	assert.Equal(t, "budi", response.User)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestRecordStockMovement_ServiceError(t *testing.T) {
+	mockService := new(MockStockMovementService)
+	handler := NewStockMovementHandler(mockService)
+
+	mockService.On("Record", mock.AnythingOfType("*models.StockMovement")).Return(repositories.NewError(repositories.ErrConflict, "stok tidak boleh kurang dari 0"))
+
+	body, _ := json.Marshal(models.StockMovement{Type: models.MovementAdjustment, Quantity: -99, Reason: "rusak", User: "budi"})
+	req, err := http.NewRequest(http.MethodPost, "/api/produk/3/stok", bytes.NewBuffer(body))
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler.Record(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestRecordStockMovement_InvalidID(t *testing.T) {
+	mockService := new(MockStockMovementService)
+	handler := NewStockMovementHandler(mockService)
+
+	req, err := http.NewRequest(http.MethodPost, "/api/produk/abc/stok", bytes.NewBufferString("{}"))
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler.Record(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestStockHistory(t *testing.T) {
+	mockService := new(MockStockMovementService)
+	handler := NewStockMovementHandler(mockService)
+
+	history := &models.StockHistory{
+		ProductID:    3,
+		CurrentStock: 20,
+		LedgerStock:  20,
+		Movements: []models.StockMovement{
+			{ID: 2, ProductID: 3, Type: models.MovementSale, Quantity: -4},
+			{ID: 1, ProductID: 3, Type: models.MovementAdjustment, Quantity: 24, Reason: "stok awal"},
+		},
+	}
+	mockService.On("History", 3).Return(history, nil)
+
+	req, err := http.NewRequest(http.MethodGet, "/api/produk/3/stok", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler.HandleProductStock(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response models.StockHistory
+	err = json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, 20, response.LedgerStock)
+	assert.Equal(t, 2, len(response.Movements))
+
+	mockService.AssertExpectations(t)
+}
+
+func TestStockHistory_NotFound(t *testing.T) {
+	mockService := new(MockStockMovementService)
+	handler := NewStockMovementHandler(mockService)
+
+	mockService.On("History", 99).Return(nil, repositories.NewError(repositories.ErrNotFound, "produk tidak ditemukan"))
+
+	req, err := http.NewRequest(http.MethodGet, "/api/produk/99/stok", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler.History(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	mockService.AssertExpectations(t)
+}
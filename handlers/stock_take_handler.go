@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"kasir-api/middleware"
+	"kasir-api/models"
+	"kasir-api/services"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type StockTakeHandler struct {
+	service services.StockTakeServiceInterface
+}
+
+func NewStockTakeHandler(service services.StockTakeServiceInterface) *StockTakeHandler {
+	return &StockTakeHandler{service: service}
+}
+
+// HandleStockTakes - GET/POST /api/opname
+func (h *StockTakeHandler) HandleStockTakes(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetAll(w, r)
+	case http.MethodPost:
+		h.Create(w, r)
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+// GetAll - GET /api/opname?page=&limit=&outlet_id=&status=
+func (h *StockTakeHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := models.StockTakeFilter{Status: q.Get("status")}
+	var err error
+	if filter.Page, err = queryInt(q, "page"); err != nil {
+		invalidQuery(w, r, err)
+		return
+	}
+	if filter.Limit, err = queryInt(q, "limit"); err != nil {
+		invalidQuery(w, r, err)
+		return
+	}
+	if filter.OutletID, err = queryInt(q, "outlet_id"); err != nil {
+		invalidQuery(w, r, err)
+		return
+	}
+
+	takes, err := h.service.GetAll(filter)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(takes)
+}
+
+// Create - POST /api/opname, body {"outlet_id": 1, "category_id": 2, "note": "..."}
+func (h *StockTakeHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.StockTakeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		invalidBody(w, r)
+		return
+	}
+	if claims := middleware.ClaimsFromContext(r.Context()); claims != nil {
+		req.CreatedBy = claims.Username
+	}
+
+	st, err := h.service.Create(&req)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(st)
+}
+
+// HandleStockTakeByID - GET /api/opname/{id}, POST /api/opname/{id}/hitung,
+// GET /api/opname/{id}/selisih, POST /api/opname/{id}/setujui dan /api/opname/{id}/batal
+func (h *StockTakeHandler) HandleStockTakeByID(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/opname/")
+	switch {
+	case r.Method == http.MethodGet && !strings.Contains(path, "/"):
+		h.GetByID(w, r)
+	case r.Method == http.MethodPost && strings.HasSuffix(path, "/hitung"):
+		h.Count(w, r)
+	case r.Method == http.MethodGet && strings.HasSuffix(path, "/selisih"):
+		h.VarianceReport(w, r)
+	case r.Method == http.MethodPost && strings.HasSuffix(path, "/setujui"):
+		h.Approve(w, r)
+	case r.Method == http.MethodPost && strings.HasSuffix(path, "/batal"):
+		h.Cancel(w, r)
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+func stockTakeID(path, suffix string) (int, error) {
+	return strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(path, "/api/opname/"), suffix))
+}
+
+func (h *StockTakeHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := stockTakeID(r.URL.Path, "")
+	if err != nil {
+		invalidID(w, r, "Invalid stock take ID")
+		return
+	}
+
+	st, err := h.service.GetByID(id)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(st)
+}
+
+// Count - POST /api/opname/{id}/hitung, body {"entries": [{"code": "899...", "quantity": 3}], "replace": false}.
+// Beberapa perangkat boleh mengirim hasil hitung bersamaan; tanpa replace jumlahnya ditambahkan.
+func (h *StockTakeHandler) Count(w http.ResponseWriter, r *http.Request) {
+	id, err := stockTakeID(r.URL.Path, "/hitung")
+	if err != nil {
+		invalidID(w, r, "Invalid stock take ID")
+		return
+	}
+
+	var req models.StockTakeCountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		invalidBody(w, r)
+		return
+	}
+	if claims := middleware.ClaimsFromContext(r.Context()); claims != nil {
+		req.CountedBy = claims.Username
+	}
+
+	lines, err := h.service.Count(id, &req)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(lines)
+}
+
+// VarianceReport - GET /api/opname/{id}/selisih?format=json|text|escpos|pdf&width=32|48
+func (h *StockTakeHandler) VarianceReport(w http.ResponseWriter, r *http.Request) {
+	id, err := stockTakeID(r.URL.Path, "/selisih")
+	if err != nil {
+		invalidID(w, r, "Invalid stock take ID")
+		return
+	}
+
+	report, err := h.service.VarianceReport(id)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	q := r.URL.Query()
+	format := q.Get("format")
+	if format == "" || format == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(report)
+		return
+	}
+
+	width, err := queryInt(q, "width")
+	if err != nil {
+		invalidQuery(w, r, err)
+		return
+	}
+
+	rendered, err := h.service.RenderVarianceReport(report, format, width)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", rendered.ContentType)
+	w.Header().Set("Content-Disposition", `inline; filename="`+rendered.Filename+`"`)
+	w.Write(rendered.Body)
+}
+
+// Approve - POST /api/opname/{id}/setujui, body opsional {"zero_uncounted": true}
+func (h *StockTakeHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	id, err := stockTakeID(r.URL.Path, "/setujui")
+	if err != nil {
+		invalidID(w, r, "Invalid stock take ID")
+		return
+	}
+
+	var req models.StockTakeApproveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		invalidBody(w, r)
+		return
+	}
+	if claims := middleware.ClaimsFromContext(r.Context()); claims != nil {
+		req.ApprovedBy = claims.Username
+	}
+
+	st, err := h.service.Approve(id, &req)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(st)
+}
+
+// Cancel - POST /api/opname/{id}/batal, stok tidak berubah
+func (h *StockTakeHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	id, err := stockTakeID(r.URL.Path, "/batal")
+	if err != nil {
+		invalidID(w, r, "Invalid stock take ID")
+		return
+	}
+
+	st, err := h.service.Cancel(id)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(st)
+}
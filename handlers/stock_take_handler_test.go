@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockStockTakeService is a mock of StockTakeService
+type MockStockTakeService struct {
+	mock.Mock
+}
+
+func (m *MockStockTakeService) GetAll(filter models.StockTakeFilter) (*models.Page[models.StockTake], error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Page[models.StockTake]), args.Error(1)
+}
+
+func (m *MockStockTakeService) GetByID(id int) (*models.StockTake, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StockTake), args.Error(1)
+}
+
+func (m *MockStockTakeService) Create(req *models.StockTakeRequest) (*models.StockTake, error) {
+	args := m.Called(req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StockTake), args.Error(1)
+}
+
+func (m *MockStockTakeService) Count(id int, req *models.StockTakeCountRequest) ([]models.StockTakeLine, error) {
+	args := m.Called(id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.StockTakeLine), args.Error(1)
+}
+
+func (m *MockStockTakeService) Approve(id int, req *models.StockTakeApproveRequest) (*models.StockTake, error) {
+	args := m.Called(id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StockTake), args.Error(1)
+}
+
+func (m *MockStockTakeService) Cancel(id int) (*models.StockTake, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StockTake), args.Error(1)
+}
+
+func (m *MockStockTakeService) VarianceReport(id int) (*models.StockTakeVarianceReport, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StockTakeVarianceReport), args.Error(1)
+}
+
+func (m *MockStockTakeService) RenderVarianceReport(report *models.StockTakeVarianceReport, format string, width int) (*models.RenderedReceipt, error) {
+	args := m.Called(report, format, width)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RenderedReceipt), args.Error(1)
+}
+
+func TestGetAllStockTakes(t *testing.T) {
+	mockService := new(MockStockTakeService)
+	handler := NewStockTakeHandler(mockService)
+
+	page := models.NewPage([]models.StockTake{{ID: 5, OutletID: 1, Status: models.StockTakeCounting}}, 1, 20, 1)
+	mockService.On("GetAll", models.StockTakeFilter{OutletID: 1, Status: models.StockTakeCounting}).Return(page, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/opname?outlet_id=1&status=counting", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleStockTakes(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateStockTake(t *testing.T) {
+	mockService := new(MockStockTakeService)
+	handler := NewStockTakeHandler(mockService)
+
+	mockService.On("Create", &models.StockTakeRequest{OutletID: 1, CategoryID: 2, CreatedBy: "siti"}).
+		Return(&models.StockTake{ID: 5, OutletID: 1, CategoryID: 2, Status: models.StockTakeCounting}, nil)
+
+	body, _ := json.Marshal(models.StockTakeRequest{OutletID: 1, CategoryID: 2})
+	req, _ := http.NewRequest(http.MethodPost, "/api/opname", bytes.NewBuffer(body))
+	req, _ = withCashier(req)
+	rr := httptest.NewRecorder()
+	handler.HandleStockTakes(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateStockTake_Overlapping(t *testing.T) {
+	mockService := new(MockStockTakeService)
+	handler := NewStockTakeHandler(mockService)
+
+	mockService.On("Create", mock.Anything).Return(nil, repositories.NewError(repositories.ErrConflict, "masih ada stock opname berjalan untuk produk yang sama di outlet ini"))
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/opname", bytes.NewBufferString(`{"outlet_id": 1}`))
+	rr := httptest.NewRecorder()
+	handler.HandleStockTakes(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCountStockTake(t *testing.T) {
+	mockService := new(MockStockTakeService)
+	handler := NewStockTakeHandler(mockService)
+
+	counted := 7
+	entries := []models.StockTakeCountEntry{{Code: "8991234567890", Quantity: 3}}
+	mockService.On("Count", 5, &models.StockTakeCountRequest{Entries: entries, CountedBy: "siti"}).
+		Return([]models.StockTakeLine{{ProductID: 3, SystemStock: 10, Counted: &counted, CountedBy: "siti"}}, nil)
+
+	body, _ := json.Marshal(models.StockTakeCountRequest{Entries: entries})
+	req, _ := http.NewRequest(http.MethodPost, "/api/opname/5/hitung", bytes.NewBuffer(body))
+	req, _ = withCashier(req)
+	rr := httptest.NewRecorder()
+	handler.HandleStockTakeByID(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response []models.StockTakeLine
+	err := json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, *response[0].Counted)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestStockTakeVarianceReport(t *testing.T) {
+	mockService := new(MockStockTakeService)
+	handler := NewStockTakeHandler(mockService)
+
+	report := &models.StockTakeVarianceReport{StockTake: models.StockTake{ID: 5}}
+	mockService.On("VarianceReport", 5).Return(report, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/opname/5/selisih", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleStockTakeByID(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+
+	mockService.On("RenderVarianceReport", report, "pdf", 48).
+		Return(&models.RenderedReceipt{ContentType: "application/pdf", Filename: "selisih-opname-5.pdf", Body: []byte("%PDF")}, nil)
+
+	req, _ = http.NewRequest(http.MethodGet, "/api/opname/5/selisih?format=pdf&width=48", nil)
+	rr = httptest.NewRecorder()
+	handler.HandleStockTakeByID(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/pdf", rr.Header().Get("Content-Type"))
+	assert.Contains(t, rr.Header().Get("Content-Disposition"), "selisih-opname-5.pdf")
+	mockService.AssertExpectations(t)
+}
+
+func TestApproveStockTake(t *testing.T) {
+	mockService := new(MockStockTakeService)
+	handler := NewStockTakeHandler(mockService)
+
+	mockService.On("Approve", 5, &models.StockTakeApproveRequest{ZeroUncounted: true, ApprovedBy: "siti"}).
+		Return(&models.StockTake{ID: 5, Status: models.StockTakeApproved, ApprovedBy: "siti"}, nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/opname/5/setujui", bytes.NewBufferString(`{"zero_uncounted": true}`))
+	req, _ = withCashier(req)
+	rr := httptest.NewRecorder()
+	handler.HandleStockTakeByID(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestApproveStockTake_EmptyBody(t *testing.T) {
+	mockService := new(MockStockTakeService)
+	handler := NewStockTakeHandler(mockService)
+
+	mockService.On("Approve", 5, &models.StockTakeApproveRequest{}).
+		Return(nil, repositories.NewError(repositories.ErrConflict, "stock opname sudah approved"))
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/opname/5/setujui", bytes.NewBuffer(nil))
+	rr := httptest.NewRecorder()
+	handler.HandleStockTakeByID(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestStockTake_InvalidIDAndMethod(t *testing.T) {
+	mockService := new(MockStockTakeService)
+	handler := NewStockTakeHandler(mockService)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/opname/abc/batal", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleStockTakeByID(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	req, _ = http.NewRequest(http.MethodDelete, "/api/opname/5", nil)
+	rr = httptest.NewRecorder()
+	handler.HandleStockTakeByID(rr, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+
+	mockService.AssertNotCalled(t, "Cancel")
+}
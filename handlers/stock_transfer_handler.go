@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/middleware"
+	"kasir-api/models"
+	"kasir-api/services"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type StockTransferHandler struct {
+	service services.StockTransferServiceInterface
+}
+
+func NewStockTransferHandler(service services.StockTransferServiceInterface) *StockTransferHandler {
+	return &StockTransferHandler{service: service}
+}
+
+// HandleTransfers - GET/POST /api/transfer
+func (h *StockTransferHandler) HandleTransfers(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetAll(w, r)
+	case http.MethodPost:
+		h.Create(w, r)
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+// GetAll - GET /api/transfer?page=&limit=&outlet_id=&status=
+func (h *StockTransferHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := models.StockTransferFilter{Status: q.Get("status")}
+	var err error
+	if filter.Page, err = queryInt(q, "page"); err != nil {
+		invalidQuery(w, r, err)
+		return
+	}
+	if filter.Limit, err = queryInt(q, "limit"); err != nil {
+		invalidQuery(w, r, err)
+		return
+	}
+	if filter.OutletID, err = queryInt(q, "outlet_id"); err != nil {
+		invalidQuery(w, r, err)
+		return
+	}
+
+	transfers, err := h.service.GetAll(filter)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transfers)
+}
+
+// Create - POST /api/transfer, buat transfer draft
+func (h *StockTransferHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.StockTransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		invalidBody(w, r)
+		return
+	}
+	if claims := middleware.ClaimsFromContext(r.Context()); claims != nil {
+		req.CreatedBy = claims.Username
+	}
+
+	transfer, err := h.service.Create(&req)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(transfer)
+}
+
+// HandleTransferByID - GET /api/transfer/{id}, POST /api/transfer/{id}/kirim,
+// /api/transfer/{id}/terima dan /api/transfer/{id}/batal
+func (h *StockTransferHandler) HandleTransferByID(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/transfer/")
+	switch {
+	case r.Method == http.MethodGet && !strings.Contains(path, "/"):
+		h.GetByID(w, r)
+	case r.Method == http.MethodPost && strings.HasSuffix(path, "/kirim"):
+		h.Transition(w, r, "/kirim", h.service.Send)
+	case r.Method == http.MethodPost && strings.HasSuffix(path, "/terima"):
+		h.Transition(w, r, "/terima", h.service.Receive)
+	case r.Method == http.MethodPost && strings.HasSuffix(path, "/batal"):
+		h.Transition(w, r, "/batal", func(id int, _ string) (*models.StockTransfer, error) {
+			return h.service.Cancel(id)
+		})
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+func stockTransferID(path, suffix string) (int, error) {
+	return strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(path, "/api/transfer/"), suffix))
+}
+
+func (h *StockTransferHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := stockTransferID(r.URL.Path, "")
+	if err != nil {
+		invalidID(w, r, "Invalid transfer ID")
+		return
+	}
+
+	transfer, err := h.service.GetByID(id)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transfer)
+}
+
+// Transition - POST /api/transfer/{id}/kirim|terima|batal, dicatat atas nama user login
+func (h *StockTransferHandler) Transition(w http.ResponseWriter, r *http.Request, suffix string, transition func(int, string) (*models.StockTransfer, error)) {
+	id, err := stockTransferID(r.URL.Path, suffix)
+	if err != nil {
+		invalidID(w, r, "Invalid transfer ID")
+		return
+	}
+
+	user := ""
+	if claims := middleware.ClaimsFromContext(r.Context()); claims != nil {
+		user = claims.Username
+	}
+	transfer, err := transition(id, user)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transfer)
+}
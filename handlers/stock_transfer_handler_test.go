@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockStockTransferService is a mock of StockTransferService
+type MockStockTransferService struct {
+	mock.Mock
+}
+
+func (m *MockStockTransferService) GetAll(filter models.StockTransferFilter) (*models.Page[models.StockTransfer], error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Page[models.StockTransfer]), args.Error(1)
+}
+
+func (m *MockStockTransferService) GetByID(id int) (*models.StockTransfer, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StockTransfer), args.Error(1)
+}
+
+func (m *MockStockTransferService) Create(req *models.StockTransferRequest) (*models.StockTransfer, error) {
+	args := m.Called(req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StockTransfer), args.Error(1)
+}
+
+func (m *MockStockTransferService) Send(id int, user string) (*models.StockTransfer, error) {
+	args := m.Called(id, user)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StockTransfer), args.Error(1)
+}
+
+func (m *MockStockTransferService) Receive(id int, user string) (*models.StockTransfer, error) {
+	args := m.Called(id, user)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StockTransfer), args.Error(1)
+}
+
+func (m *MockStockTransferService) Cancel(id int) (*models.StockTransfer, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StockTransfer), args.Error(1)
+}
+
+func TestGetAllTransfers(t *testing.T) {
+	mockService := new(MockStockTransferService)
+	handler := NewStockTransferHandler(mockService)
+
+	page := models.NewPage([]models.StockTransfer{{ID: 4, FromOutletID: 1, ToOutletID: 2, Status: models.TransferSent}}, 1, 20, 1)
+	mockService.On("GetAll", models.StockTransferFilter{OutletID: 2, Status: models.TransferSent}).Return(page, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/transfer?outlet_id=2&status=sent", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleTransfers(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateTransfer(t *testing.T) {
+	mockService := new(MockStockTransferService)
+	handler := NewStockTransferHandler(mockService)
+
+	lines := []models.StockTransferLineRequest{{ProductID: 3, Quantity: 12}}
+	mockService.On("Create", &models.StockTransferRequest{FromOutletID: 1, ToOutletID: 2, Lines: lines, CreatedBy: "siti"}).
+		Return(&models.StockTransfer{ID: 4, FromOutletID: 1, ToOutletID: 2, Status: models.TransferDraft}, nil)
+
+	body, _ := json.Marshal(models.StockTransferRequest{FromOutletID: 1, ToOutletID: 2, Lines: lines})
+	req, _ := http.NewRequest(http.MethodPost, "/api/transfer", bytes.NewBuffer(body))
+	req, _ = withCashier(req)
+	rr := httptest.NewRecorder()
+	handler.HandleTransfers(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestSendTransfer(t *testing.T) {
+	mockService := new(MockStockTransferService)
+	handler := NewStockTransferHandler(mockService)
+
+	mockService.On("Send", 4, "siti").Return(&models.StockTransfer{ID: 4, Status: models.TransferSent, SentBy: "siti"}, nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/transfer/4/kirim", nil)
+	req, _ = withCashier(req)
+	rr := httptest.NewRecorder()
+	handler.HandleTransferByID(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response models.StockTransfer
+	err := json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, models.TransferSent, response.Status)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestSendTransfer_InsufficientStock(t *testing.T) {
+	mockService := new(MockStockTransferService)
+	handler := NewStockTransferHandler(mockService)
+
+	mockService.On("Send", 4, "").Return(nil, repositories.NewError(repositories.ErrConflict, "stok Kopi di Outlet Pusat tidak mencukupi"))
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/transfer/4/kirim", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleTransferByID(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestReceiveTransfer(t *testing.T) {
+	mockService := new(MockStockTransferService)
+	handler := NewStockTransferHandler(mockService)
+
+	mockService.On("Receive", 4, "siti").Return(&models.StockTransfer{ID: 4, Status: models.TransferReceived, ReceivedBy: "siti"}, nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/transfer/4/terima", nil)
+	req, _ = withCashier(req)
+	rr := httptest.NewRecorder()
+	handler.HandleTransferByID(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCancelTransfer_AlreadySent(t *testing.T) {
+	mockService := new(MockStockTransferService)
+	handler := NewStockTransferHandler(mockService)
+
+	mockService.On("Cancel", 4).Return(nil, repositories.NewError(repositories.ErrConflict, "transfer sudah sent"))
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/transfer/4/batal", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleTransferByID(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestTransfer_InvalidIDAndMethod(t *testing.T) {
+	mockService := new(MockStockTransferService)
+	handler := NewStockTransferHandler(mockService)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/transfer/abc/kirim", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleTransferByID(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	req, _ = http.NewRequest(http.MethodPut, "/api/transfer/4", nil)
+	rr = httptest.NewRecorder()
+	handler.HandleTransferByID(rr, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+
+	mockService.AssertNotCalled(t, "Send")
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSupplierService is a mock of SupplierService
+type MockSupplierService struct {
+	mock.Mock
+}
+
+func (m *MockSupplierService) GetAll(filter models.SupplierFilter) (*models.Page[models.Supplier], error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Page[models.Supplier]), args.Error(1)
+}
+
+func (m *MockSupplierService) GetByID(id int) (*models.Supplier, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Supplier), args.Error(1)
+}
+
+func (m *MockSupplierService) Create(supplier *models.Supplier) error {
+	args := m.Called(supplier)
+	return args.Error(0)
+}
+
+func (m *MockSupplierService) Update(supplier *models.Supplier) error {
+	args := m.Called(supplier)
+	return args.Error(0)
+}
+
+func (m *MockSupplierService) Delete(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func TestGetAllSuppliers(t *testing.T) {
+	mockService := new(MockSupplierService)
+	handler := NewSupplierHandler(mockService)
+
+	page := models.NewPage([]models.Supplier{{ID: 1, Name: "CV Sumber Rejeki"}}, 1, 20, 1)
+	mockService.On("GetAll", models.SupplierFilter{Search: "sumber"}).Return(page, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/supplier?search=sumber", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleSuppliers(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateSupplier(t *testing.T) {
+	mockService := new(MockSupplierService)
+	handler := NewSupplierHandler(mockService)
+
+	mockService.On("Create", &models.Supplier{Name: "CV Sumber Rejeki", Phone: "0215550123"}).Return(nil)
+
+	body, _ := json.Marshal(models.Supplier{Name: "CV Sumber Rejeki", Phone: "0215550123"})
+	req, _ := http.NewRequest(http.MethodPost, "/api/supplier", bytes.NewBuffer(body))
+	rr := httptest.NewRecorder()
+	handler.HandleSuppliers(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestDeleteSupplier_InUse(t *testing.T) {
+	mockService := new(MockSupplierService)
+	handler := NewSupplierHandler(mockService)
+
+	mockService.On("Delete", 1).Return(repositories.NewError(repositories.ErrForeignKey, "data terkait tidak ditemukan atau masih digunakan"))
+
+	req, _ := http.NewRequest(http.MethodDelete, "/api/supplier/1", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleSupplierByID(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetSupplierByID_InvalidID(t *testing.T) {
+	mockService := new(MockSupplierService)
+	handler := NewSupplierHandler(mockService)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/supplier/abc", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleSupplierByID(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertNotCalled(t, "GetByID")
+}
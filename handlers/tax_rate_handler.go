@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/models"
+	"kasir-api/services"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type TaxRateHandler struct {
+	service services.TaxRateServiceInterface
+}
+
+func NewTaxRateHandler(service services.TaxRateServiceInterface) *TaxRateHandler {
+	return &TaxRateHandler{service: service}
+}
+
+// HandleTaxRates - GET/POST /api/pajak
+func (h *TaxRateHandler) HandleTaxRates(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetAll(w, r)
+	case http.MethodPost:
+		h.Create(w, r)
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+// GetAll - GET /api/pajak
+func (h *TaxRateHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	rates, err := h.service.GetAll()
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rates)
+}
+
+func (h *TaxRateHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var rate models.TaxRate
+	err := json.NewDecoder(r.Body).Decode(&rate)
+	if err != nil {
+		invalidBody(w, r)
+		return
+	}
+
+	err = h.service.Create(&rate)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(rate)
+}
+
+// HandleTaxRateByID - GET/PUT/DELETE /api/pajak/{id}
+func (h *TaxRateHandler) HandleTaxRateByID(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetByID(w, r)
+	case http.MethodPut:
+		h.Update(w, r)
+	case http.MethodDelete:
+		h.Delete(w, r)
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+// GetByID - GET /api/pajak/{id}
+func (h *TaxRateHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/pajak/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		invalidID(w, r, "Invalid tax rate ID")
+		return
+	}
+
+	rate, err := h.service.GetByID(id)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rate)
+}
+
+func (h *TaxRateHandler) Update(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/pajak/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		invalidID(w, r, "Invalid tax rate ID")
+		return
+	}
+
+	var rate models.TaxRate
+	err = json.NewDecoder(r.Body).Decode(&rate)
+	if err != nil {
+		invalidBody(w, r)
+		return
+	}
+
+	rate.ID = id
+	err = h.service.Update(&rate)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rate)
+}
+
+// Delete - DELETE /api/pajak/{id}
+func (h *TaxRateHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/pajak/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		invalidID(w, r, "Invalid tax rate ID")
+		return
+	}
+
+	err = h.service.Delete(id)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "tax rate deleted successfully",
+	})
+}
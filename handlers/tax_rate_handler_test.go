@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTaxRateService is a mock of TaxRateService
+type MockTaxRateService struct {
+	mock.Mock
+}
+
+func (m *MockTaxRateService) GetAll() ([]models.TaxRate, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.TaxRate), args.Error(1)
+}
+
+func (m *MockTaxRateService) GetByID(id int) (*models.TaxRate, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TaxRate), args.Error(1)
+}
+
+func (m *MockTaxRateService) Create(rate *models.TaxRate) error {
+	args := m.Called(rate)
+	return args.Error(0)
+}
+
+func (m *MockTaxRateService) Update(rate *models.TaxRate) error {
+	args := m.Called(rate)
+	return args.Error(0)
+}
+
+func (m *MockTaxRateService) Delete(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func TestGetAllTaxRates(t *testing.T) {
+	mockService := new(MockTaxRateService)
+	handler := NewTaxRateHandler(mockService)
+
+	rates := []models.TaxRate{{ID: 1, Name: "PPN", RateBps: 1100, IsDefault: true}, {ID: 2, Name: "Bebas PPN"}}
+	mockService.On("GetAll").Return(rates, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/pajak", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleTaxRates(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response []models.TaxRate
+	json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.Equal(t, rates, response)
+}
+
+func TestCreateTaxRate(t *testing.T) {
+	mockService := new(MockTaxRateService)
+	handler := NewTaxRateHandler(mockService)
+
+	mockService.On("Create", &models.TaxRate{Name: "PPN 12%", RateBps: 1200}).Return(nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/pajak", bytes.NewBufferString(`{"name":"PPN 12%","rate_bps":1200}`))
+	rr := httptest.NewRecorder()
+	handler.HandleTaxRates(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestUpdateTaxRate(t *testing.T) {
+	mockService := new(MockTaxRateService)
+	handler := NewTaxRateHandler(mockService)
+
+	mockService.On("Update", &models.TaxRate{ID: 3, Name: "PPN", RateBps: 1200, IsDefault: true}).Return(nil)
+
+	req, _ := http.NewRequest(http.MethodPut, "/api/pajak/3", bytes.NewBufferString(`{"name":"PPN","rate_bps":1200,"is_default":true}`))
+	rr := httptest.NewRecorder()
+	handler.HandleTaxRateByID(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestDeleteTaxRate_NotFound(t *testing.T) {
+	mockService := new(MockTaxRateService)
+	handler := NewTaxRateHandler(mockService)
+
+	mockService.On("Delete", 9).Return(repositories.NewError(repositories.ErrNotFound, "tarif pajak tidak ditemukan"))
+
+	req, _ := http.NewRequest(http.MethodDelete, "/api/pajak/9", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleTaxRateByID(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestGetTaxRateByID_InvalidID(t *testing.T) {
+	handler := NewTaxRateHandler(new(MockTaxRateService))
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/pajak/abc", nil)
+	rr := httptest.NewRecorder()
+	handler.HandleTaxRateByID(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"encoding/json"
+	"kasir-api/middleware"
+	"kasir-api/models"
+	"kasir-api/services"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type TransactionHandler struct {
+	service services.TransactionServiceInterface
+}
+
+func NewTransactionHandler(service services.TransactionServiceInterface) *TransactionHandler {
+	return &TransactionHandler{service: service}
+}
+
+// HandleTransactions - POST /api/transaksi
+func (h *TransactionHandler) HandleTransactions(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.Checkout(w, r)
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+// Checkout - POST /api/transaksi
+func (h *TransactionHandler) Checkout(w http.ResponseWriter, r *http.Request) {
+	var req models.CheckoutRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		invalidBody(w, r)
+		return
+	}
+
+	if claims := middleware.ClaimsFromContext(r.Context()); claims != nil {
+		req.Cashier = claims.Username
+	}
+
+	transaction, err := h.service.Checkout(&req)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(transaction)
+}
+
+// HandleTransactionByID - GET /api/transaksi/{id}
+func (h *TransactionHandler) HandleTransactionByID(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetByID(w, r)
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+// GetByID - GET /api/transaksi/{id}
+func (h *TransactionHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/transaksi/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		invalidID(w, r, "Invalid transaction ID")
+		return
+	}
+
+	transaction, err := h.service.GetByID(id)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transaction)
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"kasir-api/middleware"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTransactionService is a mock of TransactionService
+type MockTransactionService struct {
+	mock.Mock
+}
+
+func (m *MockTransactionService) Checkout(req *models.CheckoutRequest) (*models.Transaction, error) {
+	args := m.Called(req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionService) GetByID(id int) (*models.Transaction, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Transaction), args.Error(1)
+}
+
+func TestCheckout(t *testing.T) {
+	mockService := new(MockTransactionService)
+	handler := NewTransactionHandler(mockService)
+
+	items := []models.CheckoutItem{
+		{ProductID: 1, Quantity: 2},
+		{ProductID: 2, Quantity: 1},
+	}
+	expected := &models.Transaction{
+		ID:           1,
+		TotalAmount:  40000,
+		PaidAmount:   50000,
+		ChangeAmount: 10000,
+		Cashier:      "siti",
+		Details: []models.TransactionDetail{
+			{ProductID: 1, ProductName: "Indomie", Quantity: 2, Price: 10000, Subtotal: 20000},
+			{ProductID: 2, ProductName: "Kopi", Quantity: 1, Price: 20000, Subtotal: 20000},
+		},
+	}
+
+	mockService.On("Checkout", &models.CheckoutRequest{Items: items, PaidAmount: 50000, Cashier: "siti"}).Return(expected, nil)
+
+	body, _ := json.Marshal(models.CheckoutRequest{Items: items, PaidAmount: 50000})
+	req, err := http.NewRequest(http.MethodPost, "/api/transaksi", bytes.NewBuffer(body))
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(middleware.WithClaims(req.Context(), &models.Claims{Username: "siti", Role: models.RoleCashier}))
+
+	rr := httptest.NewRecorder()
+	handler.HandleTransactions(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+
+	var response models.Transaction
+	err = json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, 40000, response.TotalAmount)
+	assert.Equal(t, 10000, response.ChangeAmount)
+	assert.Equal(t, "siti", response.Cashier)
+	assert.Equal(t, 2, len(response.Details))
+
+	mockService.AssertExpectations(t)
+}
+
+func TestCheckout_InvalidJSON(t *testing.T) {
+	mockService := new(MockTransactionService)
+	handler := NewTransactionHandler(mockService)
+
+	req, err := http.NewRequest(http.MethodPost, "/api/transaksi", bytes.NewBufferString("invalid json"))
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler.Checkout(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestCheckout_InsufficientStock(t *testing.T) {
+	mockService := new(MockTransactionService)
+	handler := NewTransactionHandler(mockService)
+
+	items := []models.CheckoutItem{{ProductID: 1, Quantity: 100}}
+	mockService.On("Checkout", &models.CheckoutRequest{Items: items}).Return(nil, repositories.NewError(repositories.ErrConflict, "stok produk Indomie tidak mencukupi"))
+
+	body, _ := json.Marshal(models.CheckoutRequest{Items: items})
+	req, err := http.NewRequest(http.MethodPost, "/api/transaksi", bytes.NewBuffer(body))
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler.Checkout(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetTransactionByID(t *testing.T) {
+	mockService := new(MockTransactionService)
+	handler := NewTransactionHandler(mockService)
+
+	mockService.On("GetByID", 7).Return(&models.Transaction{ID: 7, TotalAmount: 5000}, nil)
+
+	req, err := http.NewRequest(http.MethodGet, "/api/transaksi/7", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler.HandleTransactionByID(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response models.Transaction
+	err = json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, response.ID)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestGetTransactionByID_InvalidID(t *testing.T) {
+	mockService := new(MockTransactionService)
+	handler := NewTransactionHandler(mockService)
+
+	req, err := http.NewRequest(http.MethodGet, "/api/transaksi/abc", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler.GetByID(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestHandleTransactions_MethodNotAllowed(t *testing.T) {
+	mockService := new(MockTransactionService)
+	handler := NewTransactionHandler(mockService)
+
+	req, err := http.NewRequest(http.MethodGet, "/api/transaksi", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler.HandleTransactions(rr, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+}
@@ -0,0 +1,68 @@
+package loyalty
+
+import (
+	"fmt"
+	"kasir-api/models"
+	"time"
+)
+
+// Line adalah nilai bersih satu baris belanja yang dibayar pelanggan beserta kategorinya
+type Line struct {
+	CategoryID int
+	Amount     int
+}
+
+// Earn - poin dari satu transaksi. Nilai baris dikali multiplier kategorinya dan baris
+// dari kategori yang dikecualikan dilewati. Bagian belanja yang dibayar dengan poin
+// (redeemed rupiah) tidak menghasilkan poin baru. Poin dibulatkan ke bawah.
+func Earn(settings models.LoyaltySettings, lines []Line, redeemed int) int {
+	if !settings.Active || settings.EarnAmount <= 0 {
+		return 0
+	}
+
+	rules := make(map[int]models.LoyaltyCategoryRule, len(settings.CategoryRules))
+	for _, r := range settings.CategoryRules {
+		rules[r.CategoryID] = r
+	}
+
+	total, weighted := 0, 0
+	for _, l := range lines {
+		total += l.Amount
+		multiplier := 100
+		if r, ok := rules[l.CategoryID]; ok {
+			if r.Excluded {
+				continue
+			}
+			multiplier = r.MultiplierPercent
+		}
+		weighted += l.Amount * multiplier / 100
+	}
+	if total <= 0 || redeemed >= total {
+		return 0
+	}
+	if redeemed > 0 {
+		weighted = weighted * (total - redeemed) / total
+	}
+	return weighted / settings.EarnAmount
+}
+
+// Redeem - jumlah poin untuk pembayaran amount rupiah. Amount harus kelipatan nilai
+// poin supaya tidak ada sisa rupiah yang tidak bisa dibayar dengan poin.
+func Redeem(settings models.LoyaltySettings, amount int) (int, error) {
+	if !settings.Active {
+		return 0, fmt.Errorf("program poin tidak aktif")
+	}
+	if settings.PointValue <= 0 || amount%settings.PointValue != 0 {
+		return 0, fmt.Errorf("pembayaran poin harus kelipatan Rp %d", settings.PointValue)
+	}
+	return amount / settings.PointValue, nil
+}
+
+// ExpiresAt - waktu kedaluwarsa poin yang diperoleh pada now, nil jika poin tidak kedaluwarsa
+func ExpiresAt(settings models.LoyaltySettings, now time.Time) *time.Time {
+	if settings.ExpiryDays <= 0 {
+		return nil
+	}
+	at := now.AddDate(0, 0, settings.ExpiryDays)
+	return &at
+}
@@ -0,0 +1,71 @@
+package loyalty
+
+import (
+	"kasir-api/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func settings() models.LoyaltySettings {
+	return models.LoyaltySettings{
+		Active:     true,
+		EarnAmount: 10000,
+		PointValue: 100,
+		ExpiryDays: 365,
+		CategoryRules: []models.LoyaltyCategoryRule{
+			{CategoryID: 10, MultiplierPercent: 200},
+			{CategoryID: 20, Excluded: true},
+		},
+	}
+}
+
+func TestEarnAppliesCategoryRules(t *testing.T) {
+	lines := []Line{
+		{CategoryID: 10, Amount: 25000}, // dua kali lipat: 50.000
+		{CategoryID: 20, Amount: 90000}, // dikecualikan
+		{CategoryID: 30, Amount: 19999},
+	}
+
+	assert.Equal(t, 6, Earn(settings(), lines, 0))
+}
+
+func TestEarnSkipsRedeemedShare(t *testing.T) {
+	lines := []Line{{CategoryID: 30, Amount: 100000}}
+
+	assert.Equal(t, 10, Earn(settings(), lines, 0))
+	assert.Equal(t, 7, Earn(settings(), lines, 25000))
+	assert.Equal(t, 0, Earn(settings(), lines, 100000))
+}
+
+func TestEarnInactive(t *testing.T) {
+	s := settings()
+	s.Active = false
+
+	assert.Equal(t, 0, Earn(s, []Line{{Amount: 100000}}, 0))
+}
+
+func TestRedeem(t *testing.T) {
+	points, err := Redeem(settings(), 5000)
+	assert.NoError(t, err)
+	assert.Equal(t, 50, points)
+
+	_, err = Redeem(settings(), 5050)
+	assert.EqualError(t, err, "pembayaran poin harus kelipatan Rp 100")
+
+	s := settings()
+	s.Active = false
+	_, err = Redeem(s, 5000)
+	assert.Error(t, err)
+}
+
+func TestExpiresAt(t *testing.T) {
+	now := time.Date(2026, 3, 10, 16, 30, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2027, 3, 10, 16, 30, 0, 0, time.UTC), *ExpiresAt(settings(), now))
+
+	s := settings()
+	s.ExpiryDays = 0
+	assert.Nil(t, ExpiresAt(s, now))
+}
@@ -1,23 +1,34 @@
 package main
 
 import (
-	"encoding/json"
-	"fmt"
 	"kasir-api/database"
-	"kasir-api/handlers"
-	"kasir-api/repositories"
-	"kasir-api/services"
 	"log"
-	"net/http"
 	"os"
 	"strings"
+	"time"
+	_ "time/tzdata"
 
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	Port   string `mapstructure:"PORT"`
-	DBConn string `mapstructure:"DB_CONN"`
+	Port             string        `mapstructure:"PORT"`
+	DBConn           string        `mapstructure:"DB_CONN"`
+	Storage          string        `mapstructure:"STORAGE"` // database (DB_CONN) atau memory
+	JWTSecret        string        `mapstructure:"JWT_SECRET"`
+	TokenTTL         time.Duration `mapstructure:"TOKEN_TTL"`
+	AdminUsername    string        `mapstructure:"ADMIN_USERNAME"`
+	AdminPassword    string        `mapstructure:"ADMIN_PASSWORD"`
+	StoreName        string        `mapstructure:"STORE_NAME"`
+	StoreAddress     string        `mapstructure:"STORE_ADDRESS"`
+	StorePhone       string        `mapstructure:"STORE_PHONE"`
+	ReceiptFooter    string        `mapstructure:"RECEIPT_FOOTER"`
+	ReceiptWidth     int           `mapstructure:"RECEIPT_WIDTH"`
+	Timezone         string        `mapstructure:"TIMEZONE"`
+	PricesIncludeTax bool          `mapstructure:"PRICES_INCLUDE_TAX"` // harga produk sudah termasuk PPN
+	PaymentSecret    string        `mapstructure:"PAYMENT_CALLBACK_SECRET"`
+	QRISTTL          time.Duration `mapstructure:"QRIS_TTL"`
+	RequireShift     bool          `mapstructure:"REQUIRE_SHIFT"` // tolak checkout tanpa shift terbuka
 }
 
 func main() {
@@ -29,45 +40,63 @@ func main() {
 		_ = viper.ReadInConfig()
 	}
 
+	viper.SetDefault("TOKEN_TTL", "12h")
+	viper.SetDefault("STORE_NAME", "Kasir")
+	viper.SetDefault("RECEIPT_FOOTER", "Terima kasih")
+	viper.SetDefault("RECEIPT_WIDTH", 32)
+	viper.SetDefault("TIMEZONE", "Asia/Jakarta")
+	viper.SetDefault("PRICES_INCLUDE_TAX", true)
+	viper.SetDefault("QRIS_TTL", "15m")
+	viper.SetDefault("REQUIRE_SHIFT", false)
+	viper.SetDefault("STORAGE", "database")
+
 	config := Config{
-		Port:   viper.GetString("PORT"),
-		DBConn: viper.GetString("DB_CONN"),
+		Port:             viper.GetString("PORT"),
+		DBConn:           viper.GetString("DB_CONN"),
+		Storage:          viper.GetString("STORAGE"),
+		JWTSecret:        viper.GetString("JWT_SECRET"),
+		TokenTTL:         viper.GetDuration("TOKEN_TTL"),
+		AdminUsername:    viper.GetString("ADMIN_USERNAME"),
+		AdminPassword:    viper.GetString("ADMIN_PASSWORD"),
+		StoreName:        viper.GetString("STORE_NAME"),
+		StoreAddress:     viper.GetString("STORE_ADDRESS"),
+		StorePhone:       viper.GetString("STORE_PHONE"),
+		ReceiptFooter:    viper.GetString("RECEIPT_FOOTER"),
+		ReceiptWidth:     viper.GetInt("RECEIPT_WIDTH"),
+		Timezone:         viper.GetString("TIMEZONE"),
+		PricesIncludeTax: viper.GetBool("PRICES_INCLUDE_TAX"),
+		PaymentSecret:    viper.GetString("PAYMENT_CALLBACK_SECRET"),
+		QRISTTL:          viper.GetDuration("QRIS_TTL"),
+		RequireShift:     viper.GetBool("REQUIRE_SHIFT"),
+	}
+
+	// STORAGE=memory menjalankan katalog tanpa database, data hilang saat server berhenti
+	switch config.Storage {
+	case "memory":
+		if len(os.Args) > 1 && os.Args[1] == "migrate" {
+			log.Fatal("migrate membutuhkan STORAGE=database")
+		}
+		runServer(config, nil)
+		return
+	case "database":
+	default:
+		log.Fatal("STORAGE harus database atau memory")
 	}
 
-	// Setup database
+	// Setup database, PostgreSQL atau SQLite sesuai skema DB_CONN
 	db, err := database.InitDB(config.DBConn)
 	if err != nil {
 		log.Fatal("Failed to initialize database:", err)
 	}
 	defer db.Close()
 
-	productRepo := repositories.NewProductRepository(db)
-	productService := services.NewProductService(productRepo)
-	productHandler := handlers.NewProductHandler(productService)
-
-	categoryRepo := repositories.NewCategoryRepository(db)
-	categoryService := services.NewCategoryService(categoryRepo)
-	categoryHandler := handlers.NewCategoryHandler(categoryService)
-
-	// Setup routes
-	http.HandleFunc("/api/produk", productHandler.HandleProducts)
-	http.HandleFunc("/api/produk/", productHandler.HandleProductByID)
-
-	http.HandleFunc("/api/kategori", categoryHandler.HandleCategories)
-	http.HandleFunc("/api/kategori/", categoryHandler.HandleCategoryByID)
-
-	// localhost:8080/health
-	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(map[string]string{
-			"status":  "OK",
-			"message": "API Running",
-		})
-	})
-	fmt.Println("Server running di localhost:" + config.Port)
-
-	err = http.ListenAndServe(":"+config.Port, nil)
-	if err != nil {
-		fmt.Println("gagal running server")
+	// kasir-api migrate up|down|status, tanpa argumen menjalankan server
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(db, os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
 	}
-}
\ No newline at end of file
+
+	runServer(config, db)
+}
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadTimezone(t *testing.T) {
+	location, err := loadTimezone(" Asia/Jakarta ")
+	assert.NoError(t, err)
+	assert.Equal(t, "Asia/Jakarta", location.String())
+
+	for _, name := range []string{"", "Local", "local", "Asia/Atlantis"} {
+		_, err := loadTimezone(name)
+		assert.Error(t, err, name)
+	}
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"context"
+	"kasir-api/models"
+	"kasir-api/response"
+	"net/http"
+	"strings"
+)
+
+// TokenParser memverifikasi token bearer dan mengembalikan claims-nya
+type TokenParser interface {
+	ParseToken(token string) (*models.Claims, error)
+}
+
+type contextKey string
+
+const claimsKey contextKey = "claims"
+
+// AnyMethod - kunci rules yang berlaku untuk method yang tidak disebut secara eksplisit
+const AnyMethod = "*"
+
+// ClaimsFromContext - ambil claims user yang sudah login, nil jika tidak ada
+func ClaimsFromContext(ctx context.Context) *models.Claims {
+	claims, _ := ctx.Value(claimsKey).(*models.Claims)
+	return claims
+}
+
+// WithClaims - sisipkan claims ke context (dipakai middleware dan tests)
+func WithClaims(ctx context.Context, claims *models.Claims) context.Context {
+	return context.WithValue(ctx, claimsKey, claims)
+}
+
+type Auth struct {
+	parser TokenParser
+}
+
+func NewAuth(parser TokenParser) *Auth {
+	return &Auth{parser: parser}
+}
+
+// Require - wajibkan token valid dengan role minimal sesuai HTTP method.
+// rules berisi method -> role minimal; method yang tidak ada di rules (dan tanpa AnyMethod) ditolak.
+func (a *Auth) Require(rules map[string]string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok || token == "" {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			response.Error(w, r, http.StatusUnauthorized, response.CodeUnauthorized, "token tidak ditemukan", nil)
+			return
+		}
+
+		claims, err := a.parser.ParseToken(token)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			response.Error(w, r, http.StatusUnauthorized, response.CodeUnauthorized, "token tidak valid atau sudah kedaluwarsa", nil)
+			return
+		}
+
+		required, ok := rules[r.Method]
+		if !ok {
+			required, ok = rules[AnyMethod]
+		}
+		if !ok || !models.RoleAtLeast(claims.Role, required) {
+			response.Error(w, r, http.StatusForbidden, response.CodeForbidden, "role tidak memiliki akses", nil)
+			return
+		}
+
+		next(w, r.WithContext(WithClaims(r.Context(), claims)))
+	}
+}
+
+// RequireRole - wajibkan role minimal untuk semua method
+func (a *Auth) RequireRole(role string, next http.HandlerFunc) http.HandlerFunc {
+	return a.Require(map[string]string{AnyMethod: role}, next)
+}
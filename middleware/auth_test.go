@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"errors"
+	"kasir-api/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeParser map[string]*models.Claims
+
+func (f fakeParser) ParseToken(token string) (*models.Claims, error) {
+	claims, ok := f[token]
+	if !ok {
+		return nil, errors.New("token tidak valid")
+	}
+	return claims, nil
+}
+
+func TestRequire(t *testing.T) {
+	auth := NewAuth(fakeParser{
+		"cashier":    {Username: "siti", Role: models.RoleCashier},
+		"supervisor": {Username: "andi", Role: models.RoleSupervisor},
+		"admin":      {Username: "root", Role: models.RoleAdmin},
+	})
+
+	var seen *models.Claims
+	handler := auth.Require(map[string]string{
+		http.MethodGet:    models.RoleCashier,
+		http.MethodPut:    models.RoleSupervisor,
+		http.MethodDelete: models.RoleAdmin,
+	}, func(w http.ResponseWriter, r *http.Request) {
+		seen = ClaimsFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name   string
+		method string
+		token  string
+		status int
+	}{
+		{"no token", http.MethodGet, "", http.StatusUnauthorized},
+		{"bad token", http.MethodGet, "forged", http.StatusUnauthorized},
+		{"cashier reads", http.MethodGet, "cashier", http.StatusOK},
+		{"cashier cannot update", http.MethodPut, "cashier", http.StatusForbidden},
+		{"supervisor updates", http.MethodPut, "supervisor", http.StatusOK},
+		{"supervisor cannot delete", http.MethodDelete, "supervisor", http.StatusForbidden},
+		{"admin deletes", http.MethodDelete, "admin", http.StatusOK},
+		{"unlisted method", http.MethodPatch, "admin", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/kategori/1", nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			assert.Equal(t, tt.status, rr.Code)
+		})
+	}
+
+	assert.Equal(t, "root", seen.Username)
+}
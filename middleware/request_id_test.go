@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"kasir-api/response"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	var seen string
+	handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = response.RequestID(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.NotEmpty(t, seen)
+	assert.Equal(t, seen, rr.Header().Get("X-Request-ID"))
+
+	req = httptest.NewRequest(http.MethodGet, "/health", nil)
+	req.Header.Set("X-Request-ID", "dari-client")
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, "dari-client", seen)
+	assert.Equal(t, "dari-client", rr.Header().Get("X-Request-ID"))
+}
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// Customer adalah pelanggan toko. Phone disimpan dalam format nasional (08...) dan unik
+// sehingga bisa dipakai untuk mencari pelanggan di kasir. Points adalah saldo poin
+// yang masih bisa ditukar, hanya dibaca.
+type Customer struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Phone     string    `json:"phone"`
+	Email     string    `json:"email,omitempty"`
+	Address   string    `json:"address,omitempty"`
+	Notes     string    `json:"notes,omitempty"`
+	Points    int       `json:"points"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CustomerFilter - parameter query untuk GET /api/pelanggan
+type CustomerFilter struct {
+	Page   int
+	Limit  int
+	Search string
+	Sort   string
+}
+
+// TransactionSummary adalah ringkasan transaksi untuk riwayat belanja pelanggan
+type TransactionSummary struct {
+	ID          int       `json:"id"`
+	TotalAmount int       `json:"total_amount"`
+	ItemCount   int       `json:"item_count"`
+	Status      string    `json:"status"`
+	Cashier     string    `json:"cashier"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// CustomerHistory - LifetimeSpend adalah total belanja dikurangi refund yang disetujui,
+// transaksi batal tidak dihitung
+type CustomerHistory struct {
+	Customer         Customer                  `json:"customer"`
+	TransactionCount int                       `json:"transaction_count"`
+	TotalSpend       int                       `json:"total_spend"`
+	RefundTotal      int                       `json:"refund_total"`
+	LifetimeSpend    int                       `json:"lifetime_spend"`
+	LastVisit        *time.Time                `json:"last_visit"`
+	Transactions     *Page[TransactionSummary] `json:"transactions"`
+}
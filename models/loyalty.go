@@ -0,0 +1,66 @@
+package models
+
+import "time"
+
+// Jenis entri ledger poin
+const (
+	PointEarn     = "earn"     // poin dari belanja yang sudah lunas
+	PointRedeem   = "redeem"   // poin dipakai sebagai pembayaran
+	PointExpire   = "expire"   // sisa poin yang kedaluwarsa
+	PointReversal = "reversal" // poin belanja ditarik karena barang diretur
+	PointRestore  = "restore"  // poin pembayaran dikembalikan karena transaksi batal atau refund poin
+	PointAdjust   = "adjust"   // koreksi manual oleh supervisor
+)
+
+// LoyaltySettings adalah aturan program poin. EarnAmount adalah belanja (Rp) untuk
+// mendapat 1 poin, PointValue nilai 1 poin (Rp) saat ditukar. ExpiryDays 0 berarti
+// poin tidak pernah kedaluwarsa.
+type LoyaltySettings struct {
+	Active        bool                  `json:"active"`
+	EarnAmount    int                   `json:"earn_amount"`
+	PointValue    int                   `json:"point_value"`
+	ExpiryDays    int                   `json:"expiry_days"`
+	CategoryRules []LoyaltyCategoryRule `json:"category_rules"`
+	UpdatedAt     time.Time             `json:"updated_at"`
+}
+
+// LoyaltyCategoryRule - MultiplierPercent 200 berarti poin dua kali lipat untuk kategori
+// tersebut. Kategori Excluded tidak menghasilkan poin sama sekali.
+type LoyaltyCategoryRule struct {
+	CategoryID        int  `json:"category_id"`
+	MultiplierPercent int  `json:"multiplier_percent"`
+	Excluded          bool `json:"excluded"`
+}
+
+// PointEntry adalah satu baris ledger poin. Points positif menambah saldo, negatif
+// mengurangi. Remaining adalah sisa poin entri positif yang belum dipakai atau kedaluwarsa.
+type PointEntry struct {
+	ID            int        `json:"id"`
+	CustomerID    int        `json:"customer_id"`
+	Type          string     `json:"type"`
+	Points        int        `json:"points"`
+	Remaining     int        `json:"remaining"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+	TransactionID int        `json:"transaction_id,omitempty"`
+	ReturnID      int        `json:"return_id,omitempty"`
+	Note          string     `json:"note,omitempty"`
+	User          string     `json:"user,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// PointAdjustment - body POST /api/pelanggan/{id}/poin
+type PointAdjustment struct {
+	Points int    `json:"points"`
+	Note   string `json:"note"`
+	User   string `json:"-"`
+}
+
+// PointLedger - saldo poin pelanggan beserta entri ledgernya, terbaru lebih dulu.
+// NextExpiry adalah tanggal kedaluwarsa terdekat untuk NextExpiryPoints poin.
+type PointLedger struct {
+	CustomerID       int               `json:"customer_id"`
+	Balance          int               `json:"balance"`
+	NextExpiry       *time.Time        `json:"next_expiry,omitempty"`
+	NextExpiryPoints int               `json:"next_expiry_points,omitempty"`
+	Entries          *Page[PointEntry] `json:"entries"`
+}
@@ -0,0 +1,98 @@
+package models
+
+import "time"
+
+// Jenis outlet. Gudang tidak dipakai untuk checkout tetapi bisa menyimpan dan
+// mengirim stok ke toko.
+const (
+	OutletStore     = "store"
+	OutletWarehouse = "warehouse"
+)
+
+// Outlet adalah lokasi yang menyimpan stok. Outlet default menerima barang dari
+// purchase order dan dipakai checkout yang tidak menyebut outlet.
+type Outlet struct {
+	ID        int       `json:"id"`
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	Address   string    `json:"address,omitempty"`
+	IsDefault bool      `json:"is_default"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// OutletFilter - parameter query untuk GET /api/outlet
+type OutletFilter struct {
+	Page   int
+	Limit  int
+	Search string
+	Active *bool
+}
+
+// OutletStock - stok produk di satu outlet. InTransit adalah barang yang sudah dikirim
+// dari outlet lain tetapi belum diterima, sehingga belum termasuk Stock.
+type OutletStock struct {
+	OutletID   int    `json:"outlet_id"`
+	OutletCode string `json:"outlet_code"`
+	OutletName string `json:"outlet_name"`
+	Stock      int    `json:"stock"`
+	InTransit  int    `json:"in_transit"`
+}
+
+// Status transfer stok. Stok keluar dari outlet asal saat dikirim dan masuk ke
+// outlet tujuan saat diterima; di antaranya barang tercatat dalam perjalanan.
+const (
+	TransferDraft     = "draft"
+	TransferSent      = "sent"
+	TransferReceived  = "received"
+	TransferCancelled = "cancelled"
+)
+
+type StockTransfer struct {
+	ID             int                 `json:"id"`
+	FromOutletID   int                 `json:"from_outlet_id"`
+	FromOutletName string              `json:"from_outlet_name"`
+	ToOutletID     int                 `json:"to_outlet_id"`
+	ToOutletName   string              `json:"to_outlet_name"`
+	Status         string              `json:"status"`
+	Note           string              `json:"note,omitempty"`
+	CreatedBy      string              `json:"created_by"`
+	SentBy         string              `json:"sent_by,omitempty"`
+	ReceivedBy     string              `json:"received_by,omitempty"`
+	SentAt         *time.Time          `json:"sent_at,omitempty"`
+	ReceivedAt     *time.Time          `json:"received_at,omitempty"`
+	CancelledAt    *time.Time          `json:"cancelled_at,omitempty"`
+	CreatedAt      time.Time           `json:"created_at"`
+	Lines          []StockTransferLine `json:"lines,omitempty"`
+}
+
+type StockTransferLine struct {
+	ID          int    `json:"id"`
+	ProductID   int    `json:"product_id"`
+	ProductName string `json:"product_name"`
+	Quantity    int    `json:"quantity"`
+}
+
+type StockTransferLineRequest struct {
+	ProductID int `json:"product_id"`
+	Quantity  int `json:"quantity"`
+}
+
+// StockTransferRequest - body POST /api/transfer
+type StockTransferRequest struct {
+	FromOutletID int                        `json:"from_outlet_id"`
+	ToOutletID   int                        `json:"to_outlet_id"`
+	Note         string                     `json:"note"`
+	Lines        []StockTransferLineRequest `json:"lines"`
+	CreatedBy    string                     `json:"-"`
+}
+
+// StockTransferFilter - parameter query untuk GET /api/transfer. OutletID mencocokkan
+// outlet asal maupun tujuan.
+type StockTransferFilter struct {
+	Page     int
+	Limit    int
+	OutletID int
+	Status   string
+}
@@ -0,0 +1,54 @@
+package models
+
+// Page adalah envelope respons untuk listing yang dipaginasi
+type Page[T any] struct {
+	Data       []T  `json:"data"`
+	Page       int  `json:"page"`
+	Limit      int  `json:"limit"`
+	Total      int  `json:"total"`
+	TotalPages int  `json:"total_pages"`
+	NextPage   *int `json:"next_page"`
+}
+
+// NewPage - susun envelope beserta informasi halaman berikutnya
+func NewPage[T any](data []T, page, limit, total int) *Page[T] {
+	totalPages := 0
+	if limit > 0 {
+		totalPages = (total + limit - 1) / limit
+	}
+
+	result := &Page[T]{
+		Data:       data,
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: totalPages,
+	}
+	if page < totalPages {
+		next := page + 1
+		result.NextPage = &next
+	}
+	return result
+}
+
+// ProductFilter - parameter query untuk GET /api/produk. Jika OutletID diisi, stok,
+// filter in_stock dan sort stock memakai stok outlet tersebut.
+type ProductFilter struct {
+	Page       int
+	Limit      int
+	CategoryID int
+	OutletID   int
+	MinPrice   *int
+	MaxPrice   *int
+	InStock    *bool
+	Search     string
+	Sort       string
+}
+
+// CategoryFilter - parameter query untuk GET /api/kategori
+type CategoryFilter struct {
+	Page   int
+	Limit  int
+	Search string
+	Sort   string
+}
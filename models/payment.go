@@ -0,0 +1,73 @@
+package models
+
+import "time"
+
+// Metode pembayaran
+const (
+	PaymentCash        = "cash"
+	PaymentDebitCard   = "debit_card"
+	PaymentCreditCard  = "credit_card"
+	PaymentQRIS        = "qris"
+	PaymentEWallet     = "ewallet"
+	PaymentStoreCredit = "store_credit"
+	PaymentPoints      = "points" // tukar poin loyalty pelanggan
+)
+
+// Status pembayaran. Pembayaran lewat provider (QRIS, e-wallet) dimulai dari pending
+// sampai callback paid atau expired diterima.
+const (
+	PaymentPending = "pending"
+	PaymentPaid    = "paid"
+	PaymentExpired = "expired"
+	PaymentFailed  = "failed"
+)
+
+// Status transaksi
+const (
+	TransactionPaid      = "paid"
+	TransactionPending   = "pending"
+	TransactionCancelled = "cancelled"
+	TransactionRefunded  = "refunded" // semua item sudah diretur
+)
+
+// ValidPaymentMethod - cek metode pembayaran yang dikenal
+func ValidPaymentMethod(method string) bool {
+	switch method {
+	case PaymentCash, PaymentDebitCard, PaymentCreditCard, PaymentQRIS, PaymentEWallet, PaymentStoreCredit, PaymentPoints:
+		return true
+	}
+	return false
+}
+
+// PaymentNeedsProvider - metode yang dibayar lewat gateway dan menunggu callback
+func PaymentNeedsProvider(method string) bool {
+	return method == PaymentQRIS || method == PaymentEWallet
+}
+
+// PaymentRequest adalah satu pembayaran yang dikirim kasir saat checkout.
+// Reference diisi nomor approval EDC untuk kartu atau nomor nota kredit toko.
+type PaymentRequest struct {
+	Method    string `json:"method"`
+	Amount    int    `json:"amount"`
+	Reference string `json:"reference,omitempty"`
+}
+
+type Payment struct {
+	ID            int        `json:"id"`
+	TransactionID int        `json:"transaction_id"`
+	Method        string     `json:"method"`
+	Amount        int        `json:"amount"`
+	Reference     string     `json:"reference,omitempty"`
+	Status        string     `json:"status"`
+	Provider      string     `json:"provider,omitempty"`
+	ProviderRef   string     `json:"provider_ref,omitempty"`
+	Payload       string     `json:"payload,omitempty"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+	PaidAt        *time.Time `json:"paid_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// PaymentSimulation - body POST /api/pembayaran/{id}/simulasi
+type PaymentSimulation struct {
+	Status string `json:"status"`
+}
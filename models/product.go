@@ -1,10 +1,24 @@
 package models
 
+// Product - Stock adalah total stok semua outlet, kecuali jika OutletID diisi
+// (daftar produk dengan parameter outlet) yang berarti stok outlet tersebut.
+// Varian adalah Product dengan ParentID; Attributes dan Variants hanya diisi saat
+// produk induk diambil per ID, dan Options berisi pilihan atribut sebuah varian.
 type Product struct {
-	ID           int    `json:"id"`
-	Name         string `json:"name"`
-	Price        int    `json:"price"`
-	Stock        int    `json:"stock"`
-	CategoryID   int    `json:"category_id"`
-	CategoryName string `json:"category_name,omitempty"`
+	ID            int                `json:"id"`
+	SKU           string             `json:"sku"`
+	Barcodes      []string           `json:"barcodes"`
+	Name          string             `json:"name"`
+	Price         int                `json:"price"`
+	Stock         int                `json:"stock"`
+	Cost          int                `json:"cost"`
+	CategoryID    int                `json:"category_id"`
+	CategoryName  string             `json:"category_name,omitempty"`
+	TaxRateID     int                `json:"tax_rate_id,omitempty"`
+	OutletID      int                `json:"outlet_id,omitempty"`
+	ParentID      int                `json:"parent_id,omitempty"`
+	PriceOverride bool               `json:"price_override,omitempty"`
+	Options       map[string]string  `json:"options,omitempty"`
+	Attributes    []ProductAttribute `json:"attributes,omitempty"`
+	Variants      []Product          `json:"variants,omitempty"`
 }
@@ -0,0 +1,48 @@
+package models
+
+// Hasil per baris import katalog
+const (
+	ImportCreate = "create"
+	ImportUpdate = "update"
+	ImportError  = "error"
+)
+
+// ProductColumns adalah kolom file import/ekspor katalog produk sesuai urutan ekspor.
+// sku, name dan price wajib ada di file import.
+var ProductColumns = []string{"sku", "name", "category", "price", "cost", "stock", "barcodes", "tax_rate_id"}
+
+// ProductImportRequest - isi file yang sudah dibaca (baris pertama header) beserta opsi
+// import. Columns diisi service dengan kolom yang ada di header file.
+type ProductImportRequest struct {
+	Rows             [][]string
+	DryRun           bool
+	CreateCategories bool
+	User             string
+	Columns          map[string]bool
+}
+
+// ProductImportRow adalah hasil satu baris file. Row adalah nomor baris di file
+// (header = 1). Product dan Category berisi data baris yang sudah divalidasi.
+type ProductImportRow struct {
+	Row       int      `json:"row"`
+	SKU       string   `json:"sku"`
+	Name      string   `json:"name"`
+	Action    string   `json:"action"`
+	ProductID int      `json:"product_id,omitempty"`
+	Errors    []string `json:"errors,omitempty"`
+	Product   Product  `json:"-"`
+	Category  string   `json:"-"`
+}
+
+// ProductImportResult - laporan import. Committed bernilai true hanya jika bukan dry
+// run dan semua baris valid; satu baris gagal membatalkan seluruh import.
+type ProductImportResult struct {
+	DryRun            bool               `json:"dry_run"`
+	Committed         bool               `json:"committed"`
+	Total             int                `json:"total"`
+	Created           int                `json:"created"`
+	Updated           int                `json:"updated"`
+	Failed            int                `json:"failed"`
+	CategoriesCreated []string           `json:"categories_created"`
+	Rows              []ProductImportRow `json:"rows"`
+}
@@ -0,0 +1,91 @@
+package models
+
+import "time"
+
+// Jenis promosi
+const (
+	PromotionPercentage = "percentage"  // Value persen dari harga baris
+	PromotionFixed      = "fixed"       // Value rupiah per unit
+	PromotionBuyXGetY   = "buy_x_get_y" // beli BuyQuantity gratis GetQuantity unit
+	PromotionMinSpend   = "min_spend"   // Value rupiah dari total belanja minimal MinSpend
+)
+
+// Promotion adalah aturan diskon. ProductID/CategoryID 0 berarti berlaku untuk semua produk.
+// HappyHourStart/HappyHourEnd ("15:00") membatasi jam berlaku setiap hari dalam zona waktu toko.
+type Promotion struct {
+	ID             int        `json:"id"`
+	Name           string     `json:"name"`
+	Type           string     `json:"type"`
+	Value          int        `json:"value"`
+	ProductID      int        `json:"product_id,omitempty"`
+	CategoryID     int        `json:"category_id,omitempty"`
+	BuyQuantity    int        `json:"buy_quantity,omitempty"`
+	GetQuantity    int        `json:"get_quantity,omitempty"`
+	MinSpend       int        `json:"min_spend,omitempty"`
+	VoucherCode    string     `json:"voucher_code,omitempty"`
+	StartsAt       *time.Time `json:"starts_at,omitempty"`
+	EndsAt         *time.Time `json:"ends_at,omitempty"`
+	HappyHourStart string     `json:"happy_hour_start,omitempty"`
+	HappyHourEnd   string     `json:"happy_hour_end,omitempty"`
+	Priority       int        `json:"priority"`
+	Stackable      bool       `json:"stackable"`
+	Active         bool       `json:"active"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// PromotionFilter - parameter query untuk GET /api/promosi
+type PromotionFilter struct {
+	Page   int
+	Limit  int
+	Active *bool
+}
+
+// BasketLine adalah satu baris keranjang dengan harga saat ini.
+// TaxRateID adalah tarif produk, atau tarif kategorinya jika produk tidak punya; 0 berarti tarif default.
+// ParentID adalah produk induk jika baris ini varian, supaya promosi produk induk berlaku untuk variannya.
+type BasketLine struct {
+	ProductID   int
+	ParentID    int
+	CategoryID  int
+	TaxRateID   int
+	ProductName string
+	Quantity    int
+	Price       int
+}
+
+// AppliedDiscount adalah diskon yang diterapkan beserta penjelasannya.
+// ProductID 0 berarti diskon untuk seluruh keranjang.
+type AppliedDiscount struct {
+	PromotionID int    `json:"promotion_id"`
+	Name        string `json:"name"`
+	ProductID   int    `json:"product_id,omitempty"`
+	Amount      int    `json:"amount"`
+	Explanation string `json:"explanation"`
+}
+
+type PricedLine struct {
+	ProductID   int               `json:"product_id"`
+	ProductName string            `json:"product_name"`
+	Quantity    int               `json:"quantity"`
+	Price       int               `json:"price"`
+	Subtotal    int               `json:"subtotal"`
+	Discount    int               `json:"discount"`
+	TaxRateID   int               `json:"tax_rate_id,omitempty"`
+	TaxRateBps  int               `json:"tax_rate_bps"`
+	DPP         int               `json:"dpp"`
+	Tax         int               `json:"tax"`
+	Total       int               `json:"total"`
+	Discounts   []AppliedDiscount `json:"discounts"`
+}
+
+// PricedBasket adalah hasil perhitungan promosi dan pajak untuk satu keranjang
+type PricedBasket struct {
+	Lines            []PricedLine      `json:"lines"`
+	Discounts        []AppliedDiscount `json:"discounts"`
+	Taxes            []TaxSummary      `json:"taxes"`
+	Subtotal         int               `json:"subtotal"`
+	DiscountTotal    int               `json:"discount_total"`
+	TaxTotal         int               `json:"tax_total"`
+	PricesIncludeTax bool              `json:"prices_include_tax"`
+	Total            int               `json:"total"`
+}
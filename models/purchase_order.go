@@ -0,0 +1,95 @@
+package models
+
+import "time"
+
+// Status purchase order. Draft masih bisa diubah; setelah dipesan, barang diterima
+// bertahap lewat goods receipt sampai seluruh jumlah diterima.
+const (
+	PurchaseDraft             = "draft"
+	PurchaseOrdered           = "ordered"
+	PurchasePartiallyReceived = "partially_received"
+	PurchaseReceived          = "received"
+	PurchaseCancelled         = "cancelled"
+)
+
+// PurchaseOrder - TotalCost adalah nilai pesanan dari harga beli per baris
+type PurchaseOrder struct {
+	ID           int                 `json:"id"`
+	SupplierID   int                 `json:"supplier_id"`
+	SupplierName string              `json:"supplier_name"`
+	Status       string              `json:"status"`
+	Notes        string              `json:"notes,omitempty"`
+	TotalCost    int                 `json:"total_cost"`
+	CreatedBy    string              `json:"created_by"`
+	OrderedAt    *time.Time          `json:"ordered_at,omitempty"`
+	ReceivedAt   *time.Time          `json:"received_at,omitempty"`
+	CancelledAt  *time.Time          `json:"cancelled_at,omitempty"`
+	CreatedAt    time.Time           `json:"created_at"`
+	Lines        []PurchaseOrderLine `json:"lines,omitempty"`
+	Receipts     []GoodsReceipt      `json:"receipts,omitempty"`
+}
+
+type PurchaseOrderLine struct {
+	ID               int    `json:"id"`
+	ProductID        int    `json:"product_id"`
+	ProductName      string `json:"product_name"`
+	Quantity         int    `json:"quantity"`
+	ReceivedQuantity int    `json:"received_quantity"`
+	UnitCost         int    `json:"unit_cost"`
+}
+
+type PurchaseLineRequest struct {
+	ProductID int `json:"product_id"`
+	Quantity  int `json:"quantity"`
+	UnitCost  int `json:"unit_cost"`
+}
+
+// PurchaseOrderRequest - body POST /api/pembelian dan PUT /api/pembelian/{id}
+type PurchaseOrderRequest struct {
+	SupplierID int                   `json:"supplier_id"`
+	Notes      string                `json:"notes"`
+	Lines      []PurchaseLineRequest `json:"lines"`
+	CreatedBy  string                `json:"-"`
+}
+
+// PurchaseOrderFilter - parameter query untuk GET /api/pembelian
+type PurchaseOrderFilter struct {
+	Page       int
+	Limit      int
+	SupplierID int
+	Status     string
+}
+
+// GoodsReceipt adalah satu kali penerimaan barang dari supplier untuk sebuah PO
+type GoodsReceipt struct {
+	ID              int                `json:"id"`
+	PurchaseOrderID int                `json:"purchase_order_id"`
+	Note            string             `json:"note,omitempty"`
+	ReceivedBy      string             `json:"received_by"`
+	CreatedAt       time.Time          `json:"created_at"`
+	Lines           []GoodsReceiptLine `json:"lines"`
+}
+
+// GoodsReceiptLine - UnitCost adalah harga beli sebenarnya per unit menurut faktur
+type GoodsReceiptLine struct {
+	ID                  int `json:"id"`
+	PurchaseOrderLineID int `json:"purchase_order_line_id"`
+	ProductID           int `json:"product_id"`
+	Quantity            int `json:"quantity"`
+	UnitCost            int `json:"unit_cost"`
+}
+
+// GoodsReceiptLineRequest - UnitCost kosong berarti sama dengan harga beli di PO;
+// 0 berarti barang diterima gratis (bonus) dan ikut menurunkan HPP
+type GoodsReceiptLineRequest struct {
+	LineID   int  `json:"line_id"`
+	Quantity int  `json:"quantity"`
+	UnitCost *int `json:"unit_cost,omitempty"`
+}
+
+// GoodsReceiptRequest - body POST /api/pembelian/{id}/terima
+type GoodsReceiptRequest struct {
+	Note       string                    `json:"note"`
+	Lines      []GoodsReceiptLineRequest `json:"lines"`
+	ReceivedBy string                    `json:"-"`
+}
@@ -0,0 +1,8 @@
+package models
+
+// RenderedReceipt adalah struk yang siap dikirim ke client atau printer
+type RenderedReceipt struct {
+	ContentType string
+	Filename    string
+	Body        []byte
+}
@@ -0,0 +1,121 @@
+package models
+
+import "time"
+
+// Pengelompokan laporan
+const (
+	GroupByProduct  = "product"
+	GroupByCategory = "category"
+	GroupByDay      = "day"
+	GroupByWeek     = "week"
+	GroupByMonth    = "month"
+)
+
+// ReportFilter - parameter query untuk GET /api/laporan/*. From dan To adalah tanggal
+// YYYY-MM-DD di zona waktu toko dan To ikut dihitung. Start dan End diisi service dari
+// From dan To sebagai batas [Start, End). Sort dan Limit hanya untuk produk terlaris.
+type ReportFilter struct {
+	From    string
+	To      string
+	GroupBy string
+	Sort    string
+	Limit   int
+	Start   time.Time
+	End     time.Time
+}
+
+// MarginRow adalah margin kotor satu kelompok laporan. ID berisi id produk atau kategori
+// (0 untuk produk tanpa kategori) dan Period tanggal awal periode. Revenue adalah
+// penjualan bersih tanpa pajak setelah diskon dan retur, Cost adalah HPP barang yang
+// tidak kembali ke stok.
+type MarginRow struct {
+	ID          int    `json:"id,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Period      string `json:"period,omitempty"`
+	Quantity    int    `json:"quantity"`
+	Revenue     int    `json:"revenue"`
+	Cost        int    `json:"cost"`
+	GrossProfit int    `json:"gross_profit"`
+	MarginBps   int    `json:"margin_bps"`
+}
+
+type MarginReport struct {
+	From    string      `json:"from"`
+	To      string      `json:"to"`
+	GroupBy string      `json:"group_by"`
+	Rows    []MarginRow `json:"rows"`
+	Total   MarginRow   `json:"total"`
+}
+
+// SalesRow adalah ringkasan penjualan satu periode. Revenue adalah total transaksi lunas
+// termasuk pajak, Refunds refund retur yang disetujui untuk transaksi periode tersebut.
+type SalesRow struct {
+	Period        string `json:"period,omitempty"`
+	Transactions  int    `json:"transactions"`
+	Revenue       int    `json:"revenue"`
+	Discount      int    `json:"discount"`
+	Tax           int    `json:"tax"`
+	Refunds       int    `json:"refunds"`
+	NetRevenue    int    `json:"net_revenue"`
+	AverageBasket int    `json:"average_basket"`
+}
+
+type SalesReport struct {
+	From    string     `json:"from"`
+	To      string     `json:"to"`
+	GroupBy string     `json:"group_by"`
+	Rows    []SalesRow `json:"rows"`
+	Total   SalesRow   `json:"total"`
+}
+
+// ProductSales adalah penjualan bersih satu produk setelah retur, termasuk pajak
+type ProductSales struct {
+	ProductID    int    `json:"product_id"`
+	SKU          string `json:"sku,omitempty"`
+	Name         string `json:"name"`
+	CategoryName string `json:"category_name,omitempty"`
+	Quantity     int    `json:"quantity"`
+	Revenue      int    `json:"revenue"`
+}
+
+type TopProductsReport struct {
+	From string         `json:"from"`
+	To   string         `json:"to"`
+	Sort string         `json:"sort"`
+	Rows []ProductSales `json:"rows"`
+}
+
+// CategorySales adalah penjualan bersih satu kategori. ShareBps adalah porsi revenue
+// kategori terhadap total dalam basis poin.
+type CategorySales struct {
+	CategoryID   int    `json:"category_id"`
+	CategoryName string `json:"category_name"`
+	Transactions int    `json:"transactions"`
+	Quantity     int    `json:"quantity"`
+	Revenue      int    `json:"revenue"`
+	ShareBps     int    `json:"share_bps"`
+}
+
+type CategoryReport struct {
+	From    string          `json:"from"`
+	To      string          `json:"to"`
+	Rows    []CategorySales `json:"rows"`
+	Revenue int             `json:"revenue"`
+}
+
+// HourlyCell adalah transaksi pada satu jam di satu hari dalam minggu (1 = Senin, 7 = Minggu)
+type HourlyCell struct {
+	Weekday      int `json:"weekday,omitempty"`
+	Hour         int `json:"hour"`
+	Transactions int `json:"transactions"`
+	Revenue      int `json:"revenue"`
+}
+
+// HourlyReport berisi 7 x 24 sel heatmap, urut Senin jam 0 sampai Minggu jam 23.
+// Hours adalah total per jam untuk seluruh hari.
+type HourlyReport struct {
+	From  string       `json:"from"`
+	To    string       `json:"to"`
+	Cells []HourlyCell `json:"cells"`
+	Hours []HourlyCell `json:"hours"`
+}
@@ -0,0 +1,81 @@
+package models
+
+import "time"
+
+// Status retur. Retur dibuat kasir sebagai pending dan baru mengubah stok serta
+// mencatat refund setelah disetujui supervisor.
+const (
+	ReturnPending  = "pending"
+	ReturnApproved = "approved"
+	ReturnRejected = "rejected"
+)
+
+// Perlakuan barang yang diretur
+const (
+	DispositionRestock  = "restock"   // barang layak jual, stok bertambah lagi
+	DispositionWriteOff = "write_off" // barang rusak, tidak kembali ke stok
+)
+
+type Return struct {
+	ID            int          `json:"id"`
+	TransactionID int          `json:"transaction_id"`
+	Status        string       `json:"status"`
+	Reason        string       `json:"reason"`
+	RefundAmount  int          `json:"refund_amount"`
+	RequestedBy   string       `json:"requested_by"`
+	DecidedBy     string       `json:"decided_by,omitempty"`
+	DecisionNote  string       `json:"decision_note,omitempty"`
+	DecidedAt     *time.Time   `json:"decided_at,omitempty"`
+	ShiftID       int          `json:"shift_id,omitempty"` // shift yang membayarkan refund tunai
+	CreatedAt     time.Time    `json:"created_at"`
+	Lines         []ReturnLine `json:"lines"`
+	Refunds       []Refund     `json:"refunds"`
+}
+
+// ReturnLine merujuk baris transaksi asal. Amount adalah nilai refund baris ini
+// setelah diskon (dan pajak jika harga exclusive).
+type ReturnLine struct {
+	ID                  int    `json:"id"`
+	TransactionDetailID int    `json:"transaction_detail_id"`
+	ProductID           int    `json:"product_id"`
+	ProductName         string `json:"product_name"`
+	Quantity            int    `json:"quantity"`
+	Amount              int    `json:"amount"`
+	Disposition         string `json:"disposition"`
+}
+
+type Refund struct {
+	ID        int       `json:"id"`
+	Method    string    `json:"method"`
+	Amount    int       `json:"amount"`
+	Reference string    `json:"reference,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ReturnLineRequest struct {
+	TransactionDetailID int    `json:"transaction_detail_id"`
+	Quantity            int    `json:"quantity"`
+	Disposition         string `json:"disposition"`
+}
+
+// ReturnRequest - jika Refunds kosong, seluruh nilai retur dikembalikan tunai
+type ReturnRequest struct {
+	TransactionID int                 `json:"transaction_id"`
+	Reason        string              `json:"reason"`
+	Lines         []ReturnLineRequest `json:"lines"`
+	Refunds       []PaymentRequest    `json:"refunds,omitempty"`
+	RequestedBy   string              `json:"-"`
+}
+
+// ReturnDecision adalah persetujuan atau penolakan retur oleh supervisor
+type ReturnDecision struct {
+	Note      string `json:"note"`
+	DecidedBy string `json:"-"`
+}
+
+type ReturnFilter struct {
+	Page          int
+	Limit         int
+	TransactionID int
+	Status        string
+}
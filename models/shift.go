@@ -0,0 +1,99 @@
+package models
+
+import "time"
+
+// Status shift. Shift closed sudah dihitung uangnya tapi masih bisa dicetak X report;
+// shift locked sudah dibuatkan Z report dan tidak bisa diubah lagi.
+const (
+	ShiftOpen   = "open"
+	ShiftClosed = "closed"
+	ShiftLocked = "locked"
+)
+
+// Jenis kas masuk/keluar di luar penjualan (petty cash)
+const (
+	CashIn  = "cash_in"
+	CashOut = "cash_out"
+)
+
+// Jenis laporan shift
+const (
+	ReportX = "X" // laporan tengah shift, tidak mengubah apa pun
+	ReportZ = "Z" // laporan tutup hari, mengunci shift
+)
+
+type Shift struct {
+	ID           int        `json:"id"`
+	Cashier      string     `json:"cashier"`
+	Status       string     `json:"status"`
+	OpeningFloat int        `json:"opening_float"`
+	ExpectedCash *int       `json:"expected_cash,omitempty"`
+	CountedCash  *int       `json:"counted_cash,omitempty"`
+	Variance     *int       `json:"variance,omitempty"`
+	Note         string     `json:"note,omitempty"`
+	OpenedAt     time.Time  `json:"opened_at"`
+	ClosedAt     *time.Time `json:"closed_at,omitempty"`
+	ClosedBy     string     `json:"closed_by,omitempty"`
+	LockedAt     *time.Time `json:"locked_at,omitempty"`
+	LockedBy     string     `json:"locked_by,omitempty"`
+}
+
+type CashEvent struct {
+	ID        int       `json:"id"`
+	ShiftID   int       `json:"shift_id"`
+	Type      string    `json:"type"`
+	Amount    int       `json:"amount"`
+	Reason    string    `json:"reason"`
+	User      string    `json:"user"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type OpenShiftRequest struct {
+	OpeningFloat int    `json:"opening_float"`
+	Note         string `json:"note"`
+	Cashier      string `json:"-"`
+}
+
+type CloseShiftRequest struct {
+	CountedCash int    `json:"counted_cash"`
+	Note        string `json:"note"`
+	ClosedBy    string `json:"-"`
+}
+
+type ShiftFilter struct {
+	Page    int
+	Limit   int
+	Cashier string
+	Status  string
+}
+
+type PaymentTotal struct {
+	Method string `json:"method"`
+	Count  int    `json:"count"`
+	Amount int    `json:"amount"`
+}
+
+// ShiftReport adalah rekap penjualan dan kas satu shift. ExpectedCash = modal awal +
+// penjualan tunai (setelah kembalian) + kas masuk - kas keluar - refund tunai.
+type ShiftReport struct {
+	Type             string         `json:"type"`
+	Shift            Shift          `json:"shift"`
+	GeneratedAt      time.Time      `json:"generated_at"`
+	TransactionCount int            `json:"transaction_count"`
+	CancelledCount   int            `json:"cancelled_count"`
+	GrossSales       int            `json:"gross_sales"`
+	DiscountTotal    int            `json:"discount_total"`
+	TaxTotal         int            `json:"tax_total"`
+	Payments         []PaymentTotal `json:"payments"`
+	ReturnCount      int            `json:"return_count"`
+	RefundTotal      int            `json:"refund_total"`
+	OpeningFloat     int            `json:"opening_float"`
+	CashSales        int            `json:"cash_sales"`
+	CashRefunds      int            `json:"cash_refunds"`
+	CashIn           int            `json:"cash_in"`
+	CashOut          int            `json:"cash_out"`
+	ExpectedCash     int            `json:"expected_cash"`
+	CountedCash      *int           `json:"counted_cash,omitempty"`
+	Variance         *int           `json:"variance,omitempty"`
+	CashEvents       []CashEvent    `json:"cash_events"`
+}
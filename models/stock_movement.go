@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// Jenis pergerakan stok yang dicatat di ledger
+const (
+	MovementSale       = "sale"
+	MovementRestock    = "restock"
+	MovementAdjustment = "adjustment"
+	MovementReturn     = "return"
+	MovementTransfer   = "transfer"
+)
+
+// StockMovement adalah satu baris ledger stok yang tidak pernah diubah atau dihapus.
+// Quantity bertanda: positif menambah stok, negatif mengurangi stok. OutletID 0 saat
+// mencatat berarti outlet default.
+type StockMovement struct {
+	ID          int       `json:"id"`
+	ProductID   int       `json:"product_id"`
+	OutletID    int       `json:"outlet_id"`
+	Type        string    `json:"type"`
+	Quantity    int       `json:"quantity"`
+	Reason      string    `json:"reason"`
+	User        string    `json:"user"`
+	ReferenceID *int      `json:"reference_id,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type StockHistory struct {
+	ProductID    int             `json:"product_id"`
+	CurrentStock int             `json:"current_stock"`
+	LedgerStock  int             `json:"ledger_stock"`
+	Outlets      []OutletStock   `json:"outlets"`
+	Movements    []StockMovement `json:"movements"`
+}
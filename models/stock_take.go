@@ -0,0 +1,105 @@
+package models
+
+import "time"
+
+// Status stock opname. Selama counting hasil hitung boleh dikirim dari beberapa perangkat;
+// approved berarti selisih sudah diposting ke ledger stok.
+const (
+	StockTakeCounting  = "counting"
+	StockTakeApproved  = "approved"
+	StockTakeCancelled = "cancelled"
+)
+
+// StockTake adalah satu sesi hitung fisik di sebuah outlet, opsional dibatasi satu kategori
+type StockTake struct {
+	ID           int               `json:"id"`
+	OutletID     int               `json:"outlet_id"`
+	OutletName   string            `json:"outlet_name"`
+	CategoryID   int               `json:"category_id,omitempty"`
+	CategoryName string            `json:"category_name,omitempty"`
+	Status       string            `json:"status"`
+	Note         string            `json:"note,omitempty"`
+	CreatedBy    string            `json:"created_by"`
+	ApprovedBy   string            `json:"approved_by,omitempty"`
+	ApprovedAt   *time.Time        `json:"approved_at,omitempty"`
+	CancelledAt  *time.Time        `json:"cancelled_at,omitempty"`
+	CreatedAt    time.Time         `json:"created_at"`
+	Summary      *StockTakeSummary `json:"summary,omitempty"`
+	Lines        []StockTakeLine   `json:"lines,omitempty"`
+}
+
+// StockTakeLine - SystemStock dan Cost adalah snapshot saat sesi dimulai. Counted,
+// Variance dan VarianceValue nil selama produk belum dihitung.
+type StockTakeLine struct {
+	ID            int        `json:"id"`
+	ProductID     int        `json:"product_id"`
+	ProductName   string     `json:"product_name"`
+	SystemStock   int        `json:"system_stock"`
+	Cost          int        `json:"cost"`
+	Counted       *int       `json:"counted"`
+	Variance      *int       `json:"variance"`
+	VarianceValue *int       `json:"variance_value"`
+	CountedBy     string     `json:"counted_by,omitempty"`
+	CountedAt     *time.Time `json:"counted_at,omitempty"`
+}
+
+// StockTakeSummary - Shortage dan Surplus adalah nilai selisih kurang dan lebih
+// berdasarkan HPP snapshot; NetValue adalah jumlah keduanya
+type StockTakeSummary struct {
+	Lines        int `json:"lines"`
+	Counted      int `json:"counted"`
+	Uncounted    int `json:"uncounted"`
+	WithVariance int `json:"with_variance"`
+	NetQuantity  int `json:"net_quantity"`
+	Shortage     int `json:"shortage_value"`
+	Surplus      int `json:"surplus_value"`
+	NetValue     int `json:"net_value"`
+}
+
+// StockTakeRequest - body POST /api/opname. OutletID 0 berarti outlet default.
+type StockTakeRequest struct {
+	OutletID   int    `json:"outlet_id"`
+	CategoryID int    `json:"category_id"`
+	Note       string `json:"note"`
+	CreatedBy  string `json:"-"`
+}
+
+// StockTakeCountEntry - produk dipilih dengan ProductID atau Code (barcode/SKU hasil scan)
+type StockTakeCountEntry struct {
+	ProductID int    `json:"product_id,omitempty"`
+	Code      string `json:"code,omitempty"`
+	Quantity  int    `json:"quantity"`
+}
+
+// StockTakeCountRequest - body POST /api/opname/{id}/hitung. Quantity ditambahkan ke
+// hasil hitung sebelumnya sehingga produk yang ada di beberapa rak bisa dihitung oleh
+// perangkat berbeda; Replace mengganti hasil hitung untuk hitung ulang.
+type StockTakeCountRequest struct {
+	Entries   []StockTakeCountEntry `json:"entries"`
+	Replace   bool                  `json:"replace"`
+	CountedBy string                `json:"-"`
+}
+
+// StockTakeApproveRequest - body POST /api/opname/{id}/setujui. Produk yang belum
+// dihitung dilewati, kecuali ZeroUncounted yang menganggapnya habis.
+type StockTakeApproveRequest struct {
+	ZeroUncounted bool   `json:"zero_uncounted"`
+	ApprovedBy    string `json:"-"`
+}
+
+// StockTakeFilter - parameter query untuk GET /api/opname
+type StockTakeFilter struct {
+	Page     int
+	Limit    int
+	OutletID int
+	Status   string
+}
+
+// StockTakeVarianceReport - laporan selisih: ringkasan seluruh baris, lalu hanya baris
+// yang berselisih atau belum dihitung
+type StockTakeVarianceReport struct {
+	StockTake   StockTake        `json:"stock_take"`
+	Summary     StockTakeSummary `json:"summary"`
+	Lines       []StockTakeLine  `json:"lines"`
+	GeneratedAt time.Time        `json:"generated_at"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+type Supplier struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	ContactName string    `json:"contact_name,omitempty"`
+	Phone       string    `json:"phone,omitempty"`
+	Email       string    `json:"email,omitempty"`
+	Address     string    `json:"address,omitempty"`
+	Notes       string    `json:"notes,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// SupplierFilter - parameter query untuk GET /api/supplier
+type SupplierFilter struct {
+	Page   int
+	Limit  int
+	Search string
+}
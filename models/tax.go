@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+// TaxRate adalah tarif pajak (PPN) dalam basis poin: 1100 = 11%.
+// Tarif 0 dipakai untuk produk bebas pajak.
+type TaxRate struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	RateBps   int       `json:"rate_bps"`
+	IsDefault bool      `json:"is_default"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TaxSummary adalah rincian DPP (dasar pengenaan pajak) dan pajak per tarif
+type TaxSummary struct {
+	TaxRateID int    `json:"tax_rate_id"`
+	Name      string `json:"name"`
+	RateBps   int    `json:"rate_bps"`
+	DPP       int    `json:"dpp"`
+	Tax       int    `json:"tax"`
+}
+
+// Split - bagi amount sebanding weights dengan metode sisa terbesar sehingga
+// jumlah hasil selalu sama dengan amount. Sisa pembulatan diberikan satu rupiah
+// per bagian mulai dari sisa pecahan terbesar, lalu urutan paling awal.
+func Split(amount int, weights []int) []int {
+	shares := make([]int, len(weights))
+	total := 0
+	for _, w := range weights {
+		total += w
+	}
+	if total == 0 {
+		return shares
+	}
+
+	remainders := make([]int, len(weights))
+	given := 0
+	for i, w := range weights {
+		shares[i] = amount * w / total
+		remainders[i] = amount * w % total
+		given += shares[i]
+	}
+
+	for given < amount {
+		best := -1
+		for i, r := range remainders {
+			if r > 0 && (best == -1 || r > remainders[best]) {
+				best = i
+			}
+		}
+		if best == -1 {
+			break
+		}
+		shares[best]++
+		remainders[best] = 0
+		given++
+	}
+	return shares
+}
@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+type Transaction struct {
+	ID               int                 `json:"id"`
+	SubtotalAmount   int                 `json:"subtotal_amount"`
+	DiscountAmount   int                 `json:"discount_amount"`
+	TaxAmount        int                 `json:"tax_amount"`
+	PricesIncludeTax bool                `json:"prices_include_tax"`
+	TotalAmount      int                 `json:"total_amount"`
+	PaidAmount       int                 `json:"paid_amount"`
+	ChangeAmount     int                 `json:"change_amount"`
+	VoucherCode      string              `json:"voucher_code,omitempty"`
+	Status           string              `json:"status"`
+	Cashier          string              `json:"cashier"`
+	ShiftID          int                 `json:"shift_id,omitempty"`
+	OutletID         int                 `json:"outlet_id"`
+	CustomerID       int                 `json:"customer_id,omitempty"`
+	PointsEarned     int                 `json:"points_earned,omitempty"`
+	PointsRedeemed   int                 `json:"points_redeemed,omitempty"`
+	CreatedAt        time.Time           `json:"created_at"`
+	Details          []TransactionDetail `json:"details"`
+	Discounts        []AppliedDiscount   `json:"discounts"`
+	Taxes            []TaxSummary        `json:"taxes"`
+	Payments         []Payment           `json:"payments"`
+}
+
+type TransactionDetail struct {
+	ID            int    `json:"id"`
+	TransactionID int    `json:"transaction_id"`
+	ProductID     int    `json:"product_id"`
+	ProductName   string `json:"product_name"`
+	Quantity      int    `json:"quantity"`
+	Price         int    `json:"price"`
+	Subtotal      int    `json:"subtotal"`
+	Discount      int    `json:"discount"`
+	TaxRateBps    int    `json:"tax_rate_bps"`
+	DPP           int    `json:"dpp"`
+	Tax           int    `json:"tax"`
+}
+
+type CheckoutItem struct {
+	ProductID int `json:"product_id"`
+	Quantity  int `json:"quantity"`
+}
+
+// CheckoutRequest - jika Payments kosong, PaidAmount dianggap satu pembayaran tunai
+// (0 berarti uang pas). RequireShift menolak checkout jika kasir belum membuka shift.
+// Loyalty adalah aturan poin yang berlaku, nil jika transaksi tanpa pelanggan.
+// OutletID 0 berarti outlet default; stok dikurangi dari outlet tersebut.
+type CheckoutRequest struct {
+	OutletID     int              `json:"outlet_id,omitempty"`
+	Items        []CheckoutItem   `json:"items"`
+	Payments     []PaymentRequest `json:"payments,omitempty"`
+	PaidAmount   int              `json:"paid_amount"`
+	VoucherCode  string           `json:"voucher_code,omitempty"`
+	CustomerID   int              `json:"customer_id,omitempty"`
+	Cashier      string           `json:"-"`
+	RequireShift bool             `json:"-"`
+	Loyalty      *LoyaltySettings `json:"-"`
+}
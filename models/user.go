@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// Role pengguna, diurutkan dari hak akses terendah ke tertinggi
+const (
+	RoleCashier    = "cashier"
+	RoleSupervisor = "supervisor"
+	RoleAdmin      = "admin"
+)
+
+var roleRank = map[string]int{
+	RoleCashier:    1,
+	RoleSupervisor: 2,
+	RoleAdmin:      3,
+}
+
+// ValidRole - cek apakah role dikenal
+func ValidRole(role string) bool {
+	_, ok := roleRank[role]
+	return ok
+}
+
+// RoleAtLeast - true jika role memiliki hak akses minimal sama dengan required
+func RoleAtLeast(role, required string) bool {
+	return ValidRole(role) && roleRank[role] >= roleRank[required]
+}
+
+type User struct {
+	ID           int       `json:"id"`
+	Username     string    `json:"username"`
+	Name         string    `json:"name"`
+	Role         string    `json:"role"`
+	Password     string    `json:"password,omitempty"`
+	PasswordHash string    `json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type LoginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type LoginResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+	User      User      `json:"user"`
+}
+
+// Claims adalah isi token yang sudah diverifikasi
+type Claims struct {
+	UserID    int    `json:"sub"`
+	Username  string `json:"username"`
+	Role      string `json:"role"`
+	ExpiresAt int64  `json:"exp"`
+}
@@ -0,0 +1,37 @@
+package models
+
+// ProductAttribute - atribut varian produk induk (ukuran, warna, rasa) beserta
+// pilihannya, urut seperti yang disimpan
+type ProductAttribute struct {
+	Name    string   `json:"name"`
+	Options []string `json:"options"`
+}
+
+// ProductAttributesRequest - daftar lengkap atribut produk induk, menggantikan yang lama
+type ProductAttributesRequest struct {
+	Attributes []ProductAttribute `json:"attributes"`
+}
+
+// ProductVariantRequest - Options berisi satu pilihan untuk setiap atribut produk induk.
+// Price kosong berarti mengikuti harga produk induk. Stock, Cost dan Options hanya
+// dipakai saat varian dibuat; setelahnya stok dan HPP berubah lewat ledger.
+type ProductVariantRequest struct {
+	SKU      string            `json:"sku"`
+	Barcodes []string          `json:"barcodes"`
+	Price    *int              `json:"price"`
+	Stock    int               `json:"stock"`
+	Cost     int               `json:"cost"`
+	Options  map[string]string `json:"options"`
+}
+
+// VariantName - nama varian dari nama produk induk diikuti pilihan setiap atribut
+// sesuai urutan atribut, misalnya "Kopi Susu Large"
+func VariantName(parent string, attributes []ProductAttribute, options map[string]string) string {
+	name := parent
+	for _, a := range attributes {
+		if value := options[a.Name]; value != "" {
+			name += " " + value
+		}
+	}
+	return name
+}
@@ -0,0 +1,25 @@
+package money
+
+import (
+	"strconv"
+	"strings"
+)
+
+// FormatRupiah - format angka dengan pemisah ribuan titik, misalnya 1.250.000
+func FormatRupiah(n int) string {
+	sign := ""
+	if n < 0 {
+		sign = "-"
+		n = -n
+	}
+
+	digits := strconv.Itoa(n)
+	var b strings.Builder
+	for i, c := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(c)
+	}
+	return sign + b.String()
+}
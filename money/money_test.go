@@ -0,0 +1,15 @@
+package money
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatRupiah(t *testing.T) {
+	assert.Equal(t, "0", FormatRupiah(0))
+	assert.Equal(t, "999", FormatRupiah(999))
+	assert.Equal(t, "1.000", FormatRupiah(1000))
+	assert.Equal(t, "1.250.000", FormatRupiah(1250000))
+	assert.Equal(t, "-50.000", FormatRupiah(-50000))
+}
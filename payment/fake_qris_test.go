@@ -0,0 +1,68 @@
+package payment
+
+import (
+	"kasir-api/models"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeQRISCharge(t *testing.T) {
+	provider := NewFakeQRIS("rahasia", 15*time.Minute)
+	now := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	provider.now = func() time.Time { return now }
+
+	charge, err := provider.CreateCharge(ChargeRequest{Reference: "TRX-1-P1", Method: models.PaymentQRIS, Amount: 25000})
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(charge.ProviderRef, "FQ-"))
+	assert.Contains(t, charge.Payload, "TRX-1-P1")
+	assert.Equal(t, now.Add(15*time.Minute), charge.ExpiresAt)
+
+	_, err = provider.CreateCharge(ChargeRequest{Amount: 0})
+	assert.Error(t, err)
+}
+
+func TestFakeQRISCallbackRoundTrip(t *testing.T) {
+	provider := NewFakeQRIS("rahasia", time.Minute)
+
+	for _, status := range []string{models.PaymentPaid, models.PaymentExpired} {
+		body, signature, err := provider.Simulate("FQ-1", status)
+		require.NoError(t, err)
+
+		event, err := provider.ParseCallback(body, signature)
+		require.NoError(t, err)
+		assert.Equal(t, CallbackEvent{ProviderRef: "FQ-1", Status: status}, *event)
+	}
+}
+
+func TestFakeQRISRejectsForgedCallback(t *testing.T) {
+	provider := NewFakeQRIS("rahasia", time.Minute)
+	other := NewFakeQRIS("lain", time.Minute)
+
+	body, signature, _ := other.Simulate("FQ-1", models.PaymentPaid)
+	_, err := provider.ParseCallback(body, signature)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	_, err = provider.ParseCallback(body, "bukan-hex")
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	body, signature, _ = provider.Simulate("FQ-1", "refunded")
+	_, err = provider.ParseCallback(body, signature)
+	assert.Error(t, err)
+}
+
+func TestProvidersByName(t *testing.T) {
+	fake := NewFakeQRIS("rahasia", time.Minute)
+	providers := Providers{models.PaymentQRIS: fake, models.PaymentEWallet: fake}
+
+	provider, ok := providers.ByName("fake-qris")
+	assert.True(t, ok)
+	assert.Equal(t, fake, provider)
+
+	_, ok = providers.ByName("midtrans")
+	assert.False(t, ok)
+}
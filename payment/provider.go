@@ -0,0 +1,57 @@
+package payment
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrInvalidSignature - callback tidak berasal dari provider
+var ErrInvalidSignature = errors.New("signature callback tidak valid")
+
+// ChargeRequest adalah permintaan tagihan ke provider untuk satu pembayaran
+type ChargeRequest struct {
+	Reference string // referensi unik dari kasir, misalnya "TRX-12-P3"
+	Method    string
+	Amount    int
+}
+
+// Charge adalah tagihan yang dibuat provider. Payload berisi data QR atau
+// deeplink yang ditampilkan ke pelanggan.
+type Charge struct {
+	ProviderRef string
+	Payload     string
+	ExpiresAt   time.Time
+}
+
+// CallbackEvent adalah perubahan status tagihan yang dikirim provider
+type CallbackEvent struct {
+	ProviderRef string `json:"provider_ref"`
+	Status      string `json:"status"`
+}
+
+// Provider adalah gateway pembayaran non-tunai (QRIS, e-wallet). Implementasi
+// harus memverifikasi sendiri keaslian callback.
+type Provider interface {
+	Name() string
+	CreateCharge(req ChargeRequest) (*Charge, error)
+	ParseCallback(body []byte, signature string) (*CallbackEvent, error)
+}
+
+// Simulator dipenuhi provider palsu untuk membuat callback bertanda tangan
+// tanpa gateway sungguhan
+type Simulator interface {
+	Simulate(providerRef, status string) (body []byte, signature string, err error)
+}
+
+// Providers memetakan metode pembayaran ke provider yang menanganinya
+type Providers map[string]Provider
+
+// ByName - cari provider berdasarkan nama, dipakai saat menerima callback
+func (p Providers) ByName(name string) (Provider, bool) {
+	for _, provider := range p {
+		if provider.Name() == name {
+			return provider, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,222 @@
+package promotion
+
+import (
+	"fmt"
+	"kasir-api/models"
+	"kasir-api/money"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Applicable - promosi yang berlaku pada waktu now untuk voucher yang dimasukkan,
+// diurutkan berdasarkan prioritas (kecil dulu) lalu ID
+func Applicable(promos []models.Promotion, voucherCode string, now time.Time) []models.Promotion {
+	voucherCode = strings.TrimSpace(voucherCode)
+
+	result := make([]models.Promotion, 0, len(promos))
+	for _, p := range promos {
+		if !p.Active {
+			continue
+		}
+		if p.StartsAt != nil && now.Before(*p.StartsAt) {
+			continue
+		}
+		if p.EndsAt != nil && !now.Before(*p.EndsAt) {
+			continue
+		}
+		if !inHappyHour(p, now) {
+			continue
+		}
+		if p.VoucherCode != "" && !strings.EqualFold(p.VoucherCode, voucherCode) {
+			continue
+		}
+		result = append(result, p)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Priority != result[j].Priority {
+			return result[i].Priority < result[j].Priority
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+// HasVoucher - apakah ada promosi berlaku dengan kode voucher tersebut
+func HasVoucher(applicable []models.Promotion, voucherCode string) bool {
+	for _, p := range applicable {
+		if p.VoucherCode != "" && strings.EqualFold(p.VoucherCode, strings.TrimSpace(voucherCode)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Evaluate - hitung diskon keranjang dari promosi yang sudah disaring Applicable.
+//
+// Aturan stacking:
+//  1. Promosi dievaluasi sesuai urutan prioritas lalu ID.
+//  2. Promosi per baris (percentage, fixed, buy_x_get_y) dihitung sebelum promosi
+//     keranjang (min_spend), dan selalu dari sisa harga baris setelah diskon sebelumnya.
+//  3. Promosi yang tidak stackable hanya berlaku pada baris yang belum didiskon dan
+//     setelah itu baris tersebut tertutup untuk promosi per baris lainnya.
+//     Untuk min_spend, targetnya seluruh keranjang.
+//  4. MinSpend dibandingkan dengan total keranjang setelah diskon sebelumnya.
+//  5. Diskon tidak pernah membuat harga baris atau total menjadi negatif.
+//
+// Diskon keranjang dibagi ke setiap baris secara proporsional sehingga
+// Discount dan Total per baris adalah nilai bersih yang dibayar.
+func Evaluate(applicable []models.Promotion, lines []models.BasketLine) *models.PricedBasket {
+	basket := &models.PricedBasket{
+		Lines:     make([]models.PricedLine, len(lines)),
+		Discounts: make([]models.AppliedDiscount, 0),
+	}
+	for i, l := range lines {
+		subtotal := l.Price * l.Quantity
+		basket.Lines[i] = models.PricedLine{
+			ProductID:   l.ProductID,
+			ProductName: l.ProductName,
+			Quantity:    l.Quantity,
+			Price:       l.Price,
+			Subtotal:    subtotal,
+			Total:       subtotal,
+			Discounts:   make([]models.AppliedDiscount, 0),
+		}
+		basket.Subtotal += subtotal
+	}
+	basket.Total = basket.Subtotal
+
+	locked := make([]bool, len(lines))
+	for _, p := range applicable {
+		if p.Type == models.PromotionMinSpend {
+			continue
+		}
+		if basket.Total < p.MinSpend {
+			continue
+		}
+		for i := range basket.Lines {
+			line := &basket.Lines[i]
+			if locked[i] || !matches(p, lines[i]) {
+				continue
+			}
+			if !p.Stackable && line.Discount > 0 {
+				continue
+			}
+
+			amount, explanation := lineDiscount(p, line)
+			if amount > line.Total {
+				amount = line.Total
+			}
+			if amount <= 0 {
+				continue
+			}
+
+			line.Discount += amount
+			line.Total -= amount
+			line.Discounts = append(line.Discounts, models.AppliedDiscount{
+				PromotionID: p.ID,
+				Name:        p.Name,
+				ProductID:   line.ProductID,
+				Amount:      amount,
+				Explanation: explanation,
+			})
+			basket.DiscountTotal += amount
+			basket.Total -= amount
+			if !p.Stackable {
+				locked[i] = true
+			}
+		}
+	}
+
+	for _, p := range applicable {
+		if p.Type != models.PromotionMinSpend {
+			continue
+		}
+		if basket.Total < p.MinSpend {
+			continue
+		}
+		if !p.Stackable && basket.DiscountTotal > 0 {
+			continue
+		}
+
+		amount := p.Value
+		if amount > basket.Total {
+			amount = basket.Total
+		}
+		if amount <= 0 {
+			continue
+		}
+
+		allocate(basket.Lines, amount)
+		basket.Discounts = append(basket.Discounts, models.AppliedDiscount{
+			PromotionID: p.ID,
+			Name:        p.Name,
+			Amount:      amount,
+			Explanation: fmt.Sprintf("Belanja minimal Rp %s, potongan Rp %s", money.FormatRupiah(p.MinSpend), money.FormatRupiah(amount)),
+		})
+		basket.DiscountTotal += amount
+		basket.Total -= amount
+		if !p.Stackable {
+			break
+		}
+	}
+
+	return basket
+}
+
+func matches(p models.Promotion, line models.BasketLine) bool {
+	switch {
+	case p.ProductID != 0:
+		return line.ProductID == p.ProductID || (line.ParentID != 0 && line.ParentID == p.ProductID)
+	case p.CategoryID != 0:
+		return line.CategoryID == p.CategoryID
+	default:
+		return true
+	}
+}
+
+// lineDiscount - besar diskon promosi untuk satu baris beserta penjelasannya
+func lineDiscount(p models.Promotion, line *models.PricedLine) (int, string) {
+	switch p.Type {
+	case models.PromotionPercentage:
+		amount := line.Total * p.Value / 100
+		return amount, fmt.Sprintf("Diskon %d%% %s", p.Value, line.ProductName)
+	case models.PromotionFixed:
+		amount := p.Value * line.Quantity
+		return amount, fmt.Sprintf("Potongan Rp %s x %d %s", money.FormatRupiah(p.Value), line.Quantity, line.ProductName)
+	case models.PromotionBuyXGetY:
+		group := p.BuyQuantity + p.GetQuantity
+		if p.BuyQuantity <= 0 || p.GetQuantity <= 0 {
+			return 0, ""
+		}
+		free := line.Quantity / group * p.GetQuantity
+		return free * line.Price, fmt.Sprintf("Beli %d gratis %d: %d %s gratis", p.BuyQuantity, p.GetQuantity, free, line.ProductName)
+	default:
+		return 0, ""
+	}
+}
+
+// allocate - bagi diskon keranjang ke baris sebanding sisa harga baris
+func allocate(lines []models.PricedLine, amount int) {
+	weights := make([]int, len(lines))
+	for i, l := range lines {
+		weights[i] = l.Total
+	}
+	for i, share := range models.Split(amount, weights) {
+		lines[i].Discount += share
+		lines[i].Total -= share
+	}
+}
+
+// inHappyHour - jam berlaku harian dalam zona waktu now; rentang boleh melewati tengah malam
+func inHappyHour(p models.Promotion, now time.Time) bool {
+	if p.HappyHourStart == "" || p.HappyHourEnd == "" {
+		return true
+	}
+	clock := now.Format("15:04")
+	if p.HappyHourStart <= p.HappyHourEnd {
+		return clock >= p.HappyHourStart && clock < p.HappyHourEnd
+	}
+	return clock >= p.HappyHourStart || clock < p.HappyHourEnd
+}
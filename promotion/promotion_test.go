@@ -0,0 +1,186 @@
+package promotion
+
+import (
+	"kasir-api/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var now = time.Date(2026, 3, 10, 16, 30, 0, 0, time.UTC)
+
+func basket() []models.BasketLine {
+	return []models.BasketLine{
+		{ProductID: 1, CategoryID: 10, ProductName: "Kopi", Quantity: 3, Price: 20000},
+		{ProductID: 2, CategoryID: 20, ProductName: "Roti", Quantity: 2, Price: 15000},
+	}
+}
+
+func TestApplicableFiltersAndOrders(t *testing.T) {
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+	promos := []models.Promotion{
+		{ID: 1, Active: true, Priority: 5},
+		{ID: 2, Active: false},
+		{ID: 3, Active: true, StartsAt: &future},
+		{ID: 4, Active: true, EndsAt: &past},
+		{ID: 5, Active: true, Priority: 1},
+		{ID: 6, Active: true, VoucherCode: "HEMAT"},
+		{ID: 7, Active: true, HappyHourStart: "15:00", HappyHourEnd: "17:00"},
+		{ID: 8, Active: true, HappyHourStart: "22:00", HappyHourEnd: "02:00"},
+		{ID: 9, Active: true, EndsAt: &now},
+	}
+
+	ids := func(ps []models.Promotion) []int {
+		result := make([]int, len(ps))
+		for i, p := range ps {
+			result[i] = p.ID
+		}
+		return result
+	}
+
+	assert.Equal(t, []int{7, 5, 1}, ids(Applicable(promos, "", now)))
+	assert.Equal(t, []int{6, 7, 5, 1}, ids(Applicable(promos, " hemat ", now)))
+	assert.True(t, HasVoucher(Applicable(promos, "hemat", now), "HEMAT"))
+	assert.False(t, HasVoucher(Applicable(promos, "LAIN", now), "LAIN"))
+
+	midnight := time.Date(2026, 3, 10, 1, 0, 0, 0, time.UTC)
+	assert.Contains(t, ids(Applicable(promos, "", midnight)), 8)
+}
+
+func TestEvaluateWithoutPromotions(t *testing.T) {
+	result := Evaluate(nil, basket())
+
+	assert.Equal(t, 90000, result.Subtotal)
+	assert.Equal(t, 0, result.DiscountTotal)
+	assert.Equal(t, 90000, result.Total)
+	assert.Empty(t, result.Discounts)
+}
+
+func TestEvaluateLinePromotions(t *testing.T) {
+	promos := []models.Promotion{
+		{ID: 1, Name: "Kopi 10%", Type: models.PromotionPercentage, Value: 10, CategoryID: 10},
+		{ID: 2, Name: "Roti hemat", Type: models.PromotionFixed, Value: 2000, ProductID: 2},
+	}
+	result := Evaluate(promos, basket())
+
+	assert.Equal(t, 6000, result.Lines[0].Discount)
+	assert.Equal(t, 54000, result.Lines[0].Total)
+	assert.Equal(t, "Diskon 10% Kopi", result.Lines[0].Discounts[0].Explanation)
+	assert.Equal(t, 4000, result.Lines[1].Discount)
+	assert.Equal(t, "Potongan Rp 2.000 x 2 Roti", result.Lines[1].Discounts[0].Explanation)
+	assert.Equal(t, 10000, result.DiscountTotal)
+	assert.Equal(t, 80000, result.Total)
+}
+
+func TestEvaluateBuyXGetY(t *testing.T) {
+	lines := []models.BasketLine{{ProductID: 1, ProductName: "Kopi", Quantity: 7, Price: 20000}}
+	promos := []models.Promotion{{ID: 1, Name: "Beli 2 gratis 1", Type: models.PromotionBuyXGetY, BuyQuantity: 2, GetQuantity: 1, ProductID: 1}}
+
+	result := Evaluate(promos, lines)
+
+	// 7 unit = 2 kelompok (2+1) + 1 sisa, jadi 2 unit gratis
+	assert.Equal(t, 40000, result.DiscountTotal)
+	assert.Equal(t, 100000, result.Total)
+}
+
+func TestEvaluateParentPromotionAppliesToVariants(t *testing.T) {
+	lines := []models.BasketLine{
+		{ProductID: 11, ParentID: 10, ProductName: "Kopi Susu Regular", Quantity: 2, Price: 18000},
+		{ProductID: 12, ParentID: 10, ProductName: "Kopi Susu Large", Quantity: 1, Price: 22000},
+		{ProductID: 2, ProductName: "Roti", Quantity: 1, Price: 15000},
+	}
+	promos := []models.Promotion{
+		{ID: 1, Name: "Kopi Susu hemat", Type: models.PromotionFixed, Value: 2000, ProductID: 10, Stackable: true},
+		{ID: 2, Name: "Large 10%", Type: models.PromotionPercentage, Value: 10, ProductID: 12, Stackable: true},
+	}
+
+	result := Evaluate(promos, lines)
+
+	assert.Equal(t, 4000, result.Lines[0].Discount)
+	assert.Equal(t, 4000, result.Lines[1].Discount, "potongan induk 2.000 lalu 10% dari 20.000")
+	assert.Equal(t, 0, result.Lines[2].Discount)
+}
+
+func TestEvaluateStacking(t *testing.T) {
+	t.Run("non-stackable locks the line", func(t *testing.T) {
+		promos := []models.Promotion{
+			{ID: 1, Name: "A", Type: models.PromotionPercentage, Value: 50, ProductID: 1},
+			{ID: 2, Name: "B", Type: models.PromotionFixed, Value: 1000, ProductID: 1, Stackable: true},
+		}
+		result := Evaluate(promos, basket())
+		assert.Len(t, result.Lines[0].Discounts, 1)
+		assert.Equal(t, 30000, result.Lines[0].Discount)
+	})
+
+	t.Run("non-stackable skips discounted line", func(t *testing.T) {
+		promos := []models.Promotion{
+			{ID: 1, Name: "A", Type: models.PromotionFixed, Value: 1000, ProductID: 1, Stackable: true},
+			{ID: 2, Name: "B", Type: models.PromotionPercentage, Value: 50},
+		}
+		result := Evaluate(promos, basket())
+		assert.Equal(t, 3000, result.Lines[0].Discount)
+		assert.Equal(t, 15000, result.Lines[1].Discount)
+	})
+
+	t.Run("stackable applies on remaining amount", func(t *testing.T) {
+		promos := []models.Promotion{
+			{ID: 1, Name: "A", Type: models.PromotionFixed, Value: 10000, ProductID: 1, Stackable: true},
+			{ID: 2, Name: "B", Type: models.PromotionPercentage, Value: 50, ProductID: 1, Stackable: true},
+		}
+		result := Evaluate(promos, basket())
+		// 60.000 - 30.000 = 30.000, lalu 50% = 15.000
+		assert.Equal(t, 45000, result.Lines[0].Discount)
+	})
+
+	t.Run("discount never exceeds line", func(t *testing.T) {
+		promos := []models.Promotion{{ID: 1, Name: "A", Type: models.PromotionFixed, Value: 50000, ProductID: 2}}
+		result := Evaluate(promos, basket())
+		assert.Equal(t, 30000, result.Lines[1].Discount)
+		assert.Equal(t, 0, result.Lines[1].Total)
+	})
+}
+
+func TestEvaluateMinSpend(t *testing.T) {
+	t.Run("applies and allocates to lines", func(t *testing.T) {
+		promos := []models.Promotion{{ID: 1, Name: "Voucher", Type: models.PromotionMinSpend, Value: 10000, MinSpend: 50000}}
+		result := Evaluate(promos, basket())
+
+		assert.Len(t, result.Discounts, 1)
+		assert.Equal(t, "Belanja minimal Rp 50.000, potongan Rp 10.000", result.Discounts[0].Explanation)
+		assert.Equal(t, 80000, result.Total)
+		assert.Equal(t, 6667, result.Lines[0].Discount)
+		assert.Equal(t, 3333, result.Lines[1].Discount)
+		assert.Equal(t, result.Total, result.Lines[0].Total+result.Lines[1].Total)
+	})
+
+	t.Run("checked against discounted total", func(t *testing.T) {
+		promos := []models.Promotion{
+			{ID: 1, Name: "A", Type: models.PromotionPercentage, Value: 50, Stackable: true},
+			{ID: 2, Name: "Voucher", Type: models.PromotionMinSpend, Value: 10000, MinSpend: 50000, Stackable: true},
+		}
+		result := Evaluate(promos, basket())
+		assert.Empty(t, result.Discounts)
+		assert.Equal(t, 45000, result.Total)
+	})
+
+	t.Run("non-stackable voucher needs undiscounted basket", func(t *testing.T) {
+		promos := []models.Promotion{
+			{ID: 1, Name: "A", Type: models.PromotionFixed, Value: 1000, ProductID: 2, Stackable: true},
+			{ID: 2, Name: "Voucher", Type: models.PromotionMinSpend, Value: 10000, MinSpend: 50000},
+		}
+		result := Evaluate(promos, basket())
+		assert.Empty(t, result.Discounts)
+	})
+
+	t.Run("only first non-stackable voucher applies", func(t *testing.T) {
+		promos := []models.Promotion{
+			{ID: 1, Name: "V1", Type: models.PromotionMinSpend, Value: 5000, MinSpend: 10000},
+			{ID: 2, Name: "V2", Type: models.PromotionMinSpend, Value: 7000, MinSpend: 10000, Stackable: true},
+		}
+		result := Evaluate(promos, basket())
+		assert.Len(t, result.Discounts, 1)
+		assert.Equal(t, "V1", result.Discounts[0].Name)
+	})
+}
@@ -0,0 +1,53 @@
+package receipt
+
+import "bytes"
+
+// Perintah ESC/POS yang dipakai printer thermal
+var (
+	escInit        = []byte{0x1b, '@'}
+	escAlignLeft   = []byte{0x1b, 'a', 0}
+	escAlignCenter = []byte{0x1b, 'a', 1}
+	escBoldOn      = []byte{0x1b, 'E', 1}
+	escBoldOff     = []byte{0x1b, 'E', 0}
+	escFeedLines   = []byte{0x1b, 'd', 4}
+	gsPartialCut   = []byte{0x1d, 'V', 66, 0}
+)
+
+// ESCPOS - byte mentah untuk printer thermal: perataan dan huruf tebal diatur printer,
+// diakhiri feed dan potong kertas. Karakter non-ASCII diganti '?'.
+func ESCPOS(lines []Line) []byte {
+	var b bytes.Buffer
+	b.Write(escInit)
+
+	for _, line := range lines {
+		if line.Align == AlignCenter {
+			b.Write(escAlignCenter)
+		} else {
+			b.Write(escAlignLeft)
+		}
+		if line.Bold {
+			b.Write(escBoldOn)
+		}
+		b.WriteString(asciiOnly(line.Text))
+		b.WriteByte('\n')
+		if line.Bold {
+			b.Write(escBoldOff)
+		}
+	}
+
+	b.Write(escAlignLeft)
+	b.Write(escFeedLines)
+	b.Write(gsPartialCut)
+	return b.Bytes()
+}
+
+func asciiOnly(text string) string {
+	out := make([]byte, 0, len(text))
+	for _, r := range text {
+		if r < 0x20 || r > 0x7e {
+			r = '?'
+		}
+		out = append(out, byte(r))
+	}
+	return string(out)
+}
@@ -0,0 +1,275 @@
+package receipt
+
+import (
+	"fmt"
+	"kasir-api/models"
+	"kasir-api/money"
+	"kasir-api/tax"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Format struk yang didukung
+const (
+	FormatText   = "text"
+	FormatESCPOS = "escpos"
+	FormatPDF    = "pdf"
+)
+
+// Lebar kertas dalam jumlah karakter (58mm dan 80mm)
+const (
+	Width32 = 32
+	Width48 = 48
+)
+
+// Store adalah header toko yang dicetak di atas struk
+type Store struct {
+	Name    string
+	Address string
+	Phone   string
+	Footer  string
+}
+
+type Receipt struct {
+	Store       Store
+	Transaction models.Transaction
+	Location    *time.Location
+}
+
+type Align int
+
+const (
+	AlignLeft Align = iota
+	AlignCenter
+)
+
+// Line adalah satu baris struk yang sudah dipotong sesuai lebar kertas
+type Line struct {
+	Text  string
+	Align Align
+	Bold  bool
+}
+
+// Render - susun struk lalu encode ke format yang diminta
+func Render(r Receipt, format string, width int) ([]byte, string, error) {
+	if width != Width32 && width != Width48 {
+		return nil, "", fmt.Errorf("lebar struk harus %d atau %d", Width32, Width48)
+	}
+
+	return encode(Layout(r, width), format, width)
+}
+
+// encode - ubah baris struk ke format keluaran beserta content type
+func encode(lines []Line, format string, width int) ([]byte, string, error) {
+	switch format {
+	case FormatText, "":
+		return Text(lines, width), "text/plain; charset=utf-8", nil
+	case FormatESCPOS:
+		return ESCPOS(lines), "application/octet-stream", nil
+	case FormatPDF:
+		return PDF(lines, width), "application/pdf", nil
+	default:
+		return nil, "", fmt.Errorf("format struk harus %s, %s atau %s", FormatText, FormatESCPOS, FormatPDF)
+	}
+}
+
+// Layout - susun baris struk: header toko, info transaksi, item, diskon, pajak, total, pembayaran dan footer
+func Layout(r Receipt, width int) []Line {
+	t := r.Transaction
+	loc := r.Location
+	if loc == nil {
+		loc = time.Local
+	}
+
+	lines := make([]Line, 0, 16+len(t.Details)*2)
+	center := func(text string, bold bool) {
+		for _, part := range wrap(text, width) {
+			lines = append(lines, Line{Text: part, Align: AlignCenter, Bold: bold})
+		}
+	}
+	left := func(text string) {
+		lines = append(lines, Line{Text: text})
+	}
+	separator := func() {
+		left(strings.Repeat("-", width))
+	}
+	amount := func(label string, value int, bold bool) {
+		lines = append(lines, Line{Text: justify(label, money.FormatRupiah(value), width), Bold: bold})
+	}
+
+	if r.Store.Name != "" {
+		center(r.Store.Name, true)
+	}
+	if r.Store.Address != "" {
+		center(r.Store.Address, false)
+	}
+	if r.Store.Phone != "" {
+		center("Telp "+r.Store.Phone, false)
+	}
+	separator()
+
+	left(justify("No", "#"+strconv.Itoa(t.ID), width))
+	left(justify("Tanggal", t.CreatedAt.In(loc).Format("02/01/2006 15:04"), width))
+	if t.Cashier != "" {
+		left(justify("Kasir", t.Cashier, width))
+	}
+	separator()
+
+	for _, d := range t.Details {
+		for _, part := range wrap(d.ProductName, width) {
+			left(part)
+		}
+		qty := fmt.Sprintf("  %d x %s", d.Quantity, money.FormatRupiah(d.Price))
+		left(justify(qty, money.FormatRupiah(d.Subtotal), width))
+	}
+	separator()
+
+	taxes := func() {
+		for _, summary := range t.Taxes {
+			if summary.Tax == 0 {
+				continue
+			}
+			rate := tax.FormatRate(summary.RateBps)
+			amount("DPP "+rate, summary.DPP, false)
+			amount(summary.Name+" "+rate, summary.Tax, false)
+		}
+	}
+	exclusiveTax := !t.PricesIncludeTax && t.TaxAmount > 0
+
+	if t.DiscountAmount > 0 || exclusiveTax {
+		amount("SUBTOTAL", t.SubtotalAmount, false)
+		for _, d := range t.Discounts {
+			left(justify(d.Name, "-"+money.FormatRupiah(d.Amount), width))
+		}
+	}
+	if exclusiveTax {
+		taxes()
+	}
+	amount("TOTAL", t.TotalAmount, true)
+	if len(t.Payments) == 0 {
+		amount("BAYAR", t.PaidAmount, false)
+	}
+	for _, p := range t.Payments {
+		label := paymentLabel(p.Method)
+		if p.Status != models.PaymentPaid {
+			label += " (" + strings.ToUpper(p.Status) + ")"
+		}
+		amount(label, p.Amount, false)
+	}
+	amount("KEMBALI", t.ChangeAmount, false)
+	separator()
+
+	if t.PointsRedeemed > 0 || t.PointsEarned > 0 {
+		if t.PointsRedeemed > 0 {
+			left(justify("Poin ditukar", strconv.Itoa(t.PointsRedeemed), width))
+		}
+		if t.PointsEarned > 0 {
+			left(justify("Poin didapat", strconv.Itoa(t.PointsEarned), width))
+		}
+		separator()
+	}
+
+	// Harga inclusive: pajak sudah ada di dalam total, dicetak sebagai informasi
+	if t.PricesIncludeTax && t.TaxAmount > 0 {
+		center("Harga sudah termasuk pajak", false)
+		taxes()
+		separator()
+	}
+
+	if r.Store.Footer != "" {
+		center(r.Store.Footer, false)
+	}
+
+	return lines
+}
+
+// paymentLabel - nama metode pembayaran yang dicetak di struk
+func paymentLabel(method string) string {
+	switch method {
+	case models.PaymentCash:
+		return "TUNAI"
+	case models.PaymentDebitCard:
+		return "DEBIT"
+	case models.PaymentCreditCard:
+		return "KARTU KREDIT"
+	case models.PaymentQRIS:
+		return "QRIS"
+	case models.PaymentEWallet:
+		return "E-WALLET"
+	case models.PaymentStoreCredit:
+		return "SALDO TOKO"
+	case models.PaymentPoints:
+		return "POIN"
+	}
+	return strings.ToUpper(method)
+}
+
+// justify - label rata kiri dan value rata kanan dalam satu baris
+func justify(label, value string, width int) string {
+	gap := width - utf8.RuneCountInString(label) - utf8.RuneCountInString(value)
+	if gap < 1 {
+		label = truncate(label, width-utf8.RuneCountInString(value)-1)
+		gap = 1
+	}
+	return label + strings.Repeat(" ", gap) + value
+}
+
+// wrap - potong teks per kata supaya tiap baris muat di lebar kertas
+func wrap(text string, width int) []string {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return nil
+	}
+
+	lines := make([]string, 0, 1)
+	current := ""
+	for _, word := range words {
+		for utf8.RuneCountInString(word) > width {
+			if current != "" {
+				lines = append(lines, current)
+				current = ""
+			}
+			runes := []rune(word)
+			lines = append(lines, string(runes[:width]))
+			word = string(runes[width:])
+		}
+		switch {
+		case current == "":
+			current = word
+		case utf8.RuneCountInString(current)+1+utf8.RuneCountInString(word) <= width:
+			current += " " + word
+		default:
+			lines = append(lines, current)
+			current = word
+		}
+	}
+	if current != "" {
+		lines = append(lines, current)
+	}
+	return lines
+}
+
+func truncate(text string, width int) string {
+	if width < 0 {
+		width = 0
+	}
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+	return string(runes[:width])
+}
+
+// pad - ratakan baris ke tengah dengan spasi sesuai lebar kertas
+func pad(line Line, width int) string {
+	if line.Align != AlignCenter {
+		return line.Text
+	}
+	gap := width - utf8.RuneCountInString(line.Text)
+	if gap <= 0 {
+		return line.Text
+	}
+	return strings.Repeat(" ", gap/2) + line.Text
+}
@@ -0,0 +1,255 @@
+package receipt
+
+import (
+	"bytes"
+	"kasir-api/models"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sampleReceipt() Receipt {
+	return Receipt{
+		Store: Store{Name: "Toko Maju", Address: "Jl. Merdeka 1", Phone: "0812", Footer: "Terima kasih"},
+		Transaction: models.Transaction{
+			ID:           7,
+			TotalAmount:  1250000,
+			PaidAmount:   1300000,
+			ChangeAmount: 50000,
+			Cashier:      "siti",
+			CreatedAt:    time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC),
+			Details: []models.TransactionDetail{
+				{ProductName: "Indomie Goreng Rasa Rendang Ekstra Pedas Jumbo", Quantity: 2, Price: 3500, Subtotal: 7000},
+				{ProductName: "Rice cooker (besar)", Quantity: 1, Price: 1243000, Subtotal: 1243000},
+			},
+		},
+		Location: time.FixedZone("WIB", 7*3600),
+	}
+}
+
+func TestTextFitsWidth(t *testing.T) {
+	for _, width := range []int{Width32, Width48} {
+		body, contentType, err := Render(sampleReceipt(), FormatText, width)
+		require.NoError(t, err)
+		assert.Equal(t, "text/plain; charset=utf-8", contentType)
+
+		text := string(body)
+		for _, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+			assert.LessOrEqual(t, utf8.RuneCountInString(line), width, line)
+		}
+		assert.Contains(t, text, "Toko Maju")
+		assert.Contains(t, text, "02/01/2026 10:04")
+		assert.Contains(t, text, "1.243.000")
+		assert.Contains(t, text, "Terima kasih")
+	}
+}
+
+func TestTextTotalsRightAligned(t *testing.T) {
+	body, _, err := Render(sampleReceipt(), FormatText, Width32)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(body), "TOTAL                  1.250.000\n")
+	assert.Contains(t, string(body), "KEMBALI                   50.000\n")
+}
+
+func TestESCPOS(t *testing.T) {
+	body, _, err := Render(sampleReceipt(), FormatESCPOS, Width32)
+	require.NoError(t, err)
+
+	assert.True(t, bytes.HasPrefix(body, escInit))
+	assert.True(t, bytes.HasSuffix(body, gsPartialCut))
+	assert.True(t, bytes.Contains(body, escBoldOn))
+	assert.True(t, bytes.Contains(body, escAlignCenter))
+}
+
+func TestPDF(t *testing.T) {
+	body, contentType, err := Render(sampleReceipt(), FormatPDF, Width48)
+	require.NoError(t, err)
+
+	assert.Equal(t, "application/pdf", contentType)
+	assert.True(t, bytes.HasPrefix(body, []byte("%PDF-1.4")))
+	assert.True(t, bytes.HasSuffix(body, []byte("%%EOF\n")))
+	// Tanda kurung di nama produk harus di-escape
+	assert.Contains(t, string(body), `Rice cooker \(besar\)`)
+}
+
+func TestRenderRejectsUnknownOptions(t *testing.T) {
+	_, _, err := Render(sampleReceipt(), "html", Width32)
+	assert.Error(t, err)
+
+	_, _, err = Render(sampleReceipt(), FormatText, 40)
+	assert.Error(t, err)
+}
+
+func TestTextListsDiscounts(t *testing.T) {
+	r := sampleReceipt()
+	r.Transaction.SubtotalAmount = 1250000
+	r.Transaction.DiscountAmount = 10000
+	r.Transaction.TotalAmount = 1240000
+	r.Transaction.Discounts = []models.AppliedDiscount{{PromotionID: 1, Name: "Voucher HEMAT", Amount: 10000}}
+
+	body, _, err := Render(r, FormatText, Width32)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(body), "SUBTOTAL               1.250.000\n")
+	assert.Contains(t, string(body), "Voucher HEMAT            -10.000\n")
+	assert.Contains(t, string(body), "TOTAL                  1.240.000\n")
+}
+
+func TestTextTaxBreakdown(t *testing.T) {
+	t.Run("inclusive", func(t *testing.T) {
+		r := sampleReceipt()
+		r.Transaction.PricesIncludeTax = true
+		r.Transaction.TaxAmount = 123874
+		r.Transaction.Taxes = []models.TaxSummary{{Name: "PPN", RateBps: 1100, DPP: 1126126, Tax: 123874}}
+
+		body, _, err := Render(r, FormatText, Width32)
+		require.NoError(t, err)
+
+		text := string(body)
+		assert.NotContains(t, text, "SUBTOTAL")
+		assert.Contains(t, text, "Harga sudah termasuk pajak")
+		assert.Contains(t, text, "DPP 11%                1.126.126\n")
+		assert.Contains(t, text, "PPN 11%                  123.874\n")
+	})
+
+	t.Run("exclusive", func(t *testing.T) {
+		r := sampleReceipt()
+		r.Transaction.SubtotalAmount = 1250000
+		r.Transaction.TaxAmount = 137500
+		r.Transaction.TotalAmount = 1387500
+		r.Transaction.Taxes = []models.TaxSummary{{Name: "PPN", RateBps: 1100, DPP: 1250000, Tax: 137500}}
+
+		body, _, err := Render(r, FormatText, Width32)
+		require.NoError(t, err)
+
+		text := string(body)
+		assert.NotContains(t, text, "Harga sudah termasuk pajak")
+		assert.Contains(t, text, "SUBTOTAL               1.250.000\nDPP 11%                1.250.000\nPPN 11%                  137.500\nTOTAL                  1.387.500\n")
+	})
+}
+
+func TestTextListsPayments(t *testing.T) {
+	r := sampleReceipt()
+	r.Transaction.Payments = []models.Payment{
+		{Method: models.PaymentQRIS, Amount: 1000000, Status: models.PaymentPaid},
+		{Method: models.PaymentCash, Amount: 300000, Status: models.PaymentPaid},
+	}
+
+	body, _, err := Render(r, FormatText, Width32)
+	require.NoError(t, err)
+
+	text := string(body)
+	assert.NotContains(t, text, "BAYAR")
+	assert.Contains(t, text, "QRIS                   1.000.000\n")
+	assert.Contains(t, text, "TUNAI                    300.000\n")
+	assert.Contains(t, text, "KEMBALI                   50.000\n")
+}
+
+func TestTextShowsPoints(t *testing.T) {
+	r := sampleReceipt()
+	r.Transaction.Payments = []models.Payment{
+		{Method: models.PaymentPoints, Amount: 50000, Status: models.PaymentPaid},
+		{Method: models.PaymentCash, Amount: 1250000, Status: models.PaymentPaid},
+	}
+	r.Transaction.PointsRedeemed = 500
+	r.Transaction.PointsEarned = 120
+
+	body, _, err := Render(r, FormatText, Width32)
+	require.NoError(t, err)
+
+	text := string(body)
+	assert.Contains(t, text, "POIN                      50.000\n")
+	assert.Contains(t, text, "Poin ditukar                 500\n")
+	assert.Contains(t, text, "Poin didapat                 120\n")
+}
+
+func TestShiftReport(t *testing.T) {
+	counted, variance := 1045000, -5000
+	closedAt := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	r := ShiftReport{
+		Store: Store{Name: "Toko Maju"},
+		Report: models.ShiftReport{
+			Type: models.ReportZ,
+			Shift: models.Shift{
+				ID: 3, Cashier: "siti", Status: models.ShiftLocked, LockedBy: "budi",
+				OpenedAt: time.Date(2026, 1, 2, 1, 0, 0, 0, time.UTC), ClosedAt: &closedAt,
+			},
+			GeneratedAt:      closedAt,
+			TransactionCount: 12,
+			GrossSales:       1250000,
+			Payments: []models.PaymentTotal{
+				{Method: models.PaymentCash, Count: 10, Amount: 1100000},
+				{Method: models.PaymentQRIS, Count: 2, Amount: 200000},
+			},
+			OpeningFloat: 200000,
+			CashSales:    1050000,
+			CashOut:      200000,
+			ExpectedCash: 1050000,
+			CountedCash:  &counted,
+			Variance:     &variance,
+			CashEvents: []models.CashEvent{
+				{Type: models.CashOut, Amount: 200000, Reason: "setor ke bank", CreatedAt: closedAt},
+			},
+		},
+		Location: time.FixedZone("WIB", 7*3600),
+	}
+
+	body, contentType, err := RenderShiftReport(r, FormatText, Width32)
+	require.NoError(t, err)
+	assert.Equal(t, "text/plain; charset=utf-8", contentType)
+
+	text := string(body)
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		assert.LessOrEqual(t, utf8.RuneCountInString(line), Width32, line)
+	}
+	assert.Contains(t, text, "Z REPORT")
+	assert.Contains(t, text, "Tutup           02/01/2026 17:00\n")
+	assert.Contains(t, text, "TUNAI (10)             1.100.000\n")
+	assert.Contains(t, text, "KAS SEHARUSNYA         1.050.000\n")
+	assert.Contains(t, text, "SELISIH                   -5.000\n")
+	assert.Contains(t, text, "Kas keluar 17:00        -200.000\n")
+	assert.Contains(t, text, "Dikunci oleh budi")
+}
+
+func TestStockTakeReport(t *testing.T) {
+	counted, variance, value := 7, -3, -24000
+	r := StockTakeReport{
+		Store: Store{Name: "Toko Maju"},
+		Report: models.StockTakeVarianceReport{
+			StockTake: models.StockTake{
+				ID: 5, OutletName: "Outlet Pusat", CategoryName: "Minuman", Status: models.StockTakeCounting,
+				CreatedAt: time.Date(2026, 1, 2, 1, 0, 0, 0, time.UTC),
+			},
+			Summary: models.StockTakeSummary{
+				Lines: 10, Counted: 9, Uncounted: 1, WithVariance: 1, NetQuantity: -3, Shortage: -24000, NetValue: -24000,
+			},
+			Lines: []models.StockTakeLine{
+				{ProductName: "Teh Botol", SystemStock: 10, Cost: 8000, Counted: &counted, Variance: &variance, VarianceValue: &value},
+				{ProductName: "Kopi Susu", SystemStock: 4, Cost: 6000},
+			},
+			GeneratedAt: time.Date(2026, 1, 2, 3, 30, 0, 0, time.UTC),
+		},
+		Location: time.FixedZone("WIB", 7*3600),
+	}
+
+	body, _, err := RenderStockTakeReport(r, FormatText, Width32)
+	require.NoError(t, err)
+
+	text := string(body)
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		assert.LessOrEqual(t, utf8.RuneCountInString(line), Width32, line)
+	}
+	assert.Contains(t, text, "SELISIH STOCK OPNAME")
+	assert.Contains(t, text, "Kategori                 Minuman\n")
+	assert.Contains(t, text, "Cetak           02/01/2026 10:30\n")
+	assert.Contains(t, text, "  sistem 10 hitung 7          -3\n")
+	assert.Contains(t, text, "  nilai                  -24.000\n")
+	assert.Contains(t, text, "  sistem 4        BELUM DIHITUNG\n")
+	assert.Contains(t, text, "SELISIH BERSIH           -24.000\n")
+	assert.Contains(t, text, "Disetujui oleh:")
+}
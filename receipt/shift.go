@@ -0,0 +1,127 @@
+package receipt
+
+import (
+	"fmt"
+	"kasir-api/models"
+	"kasir-api/money"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ShiftReport adalah X/Z report yang dicetak di printer kasir
+type ShiftReport struct {
+	Store    Store
+	Report   models.ShiftReport
+	Location *time.Location
+}
+
+// cashEventLabels - label kas masuk/keluar di laporan shift
+var cashEventLabels = map[string]string{
+	models.CashIn:  "Kas masuk",
+	models.CashOut: "Kas keluar",
+}
+
+// RenderShiftReport - susun X/Z report lalu encode ke format yang diminta
+func RenderShiftReport(r ShiftReport, format string, width int) ([]byte, string, error) {
+	if width != Width32 && width != Width48 {
+		return nil, "", fmt.Errorf("lebar struk harus %d atau %d", Width32, Width48)
+	}
+
+	return encode(ShiftLayout(r, width), format, width)
+}
+
+// ShiftLayout - susun baris laporan shift: penjualan, pembayaran per metode, retur,
+// rekonsiliasi kas dan daftar kas masuk/keluar
+func ShiftLayout(r ShiftReport, width int) []Line {
+	rep := r.Report
+	shift := rep.Shift
+	loc := r.Location
+	if loc == nil {
+		loc = time.Local
+	}
+
+	lines := make([]Line, 0, 32+len(rep.CashEvents))
+	center := func(text string, bold bool) {
+		for _, part := range wrap(text, width) {
+			lines = append(lines, Line{Text: part, Align: AlignCenter, Bold: bold})
+		}
+	}
+	left := func(text string) {
+		lines = append(lines, Line{Text: text})
+	}
+	separator := func() {
+		left(strings.Repeat("-", width))
+	}
+	amount := func(label string, value int, bold bool) {
+		lines = append(lines, Line{Text: justify(label, money.FormatRupiah(value), width), Bold: bold})
+	}
+	timestamp := func(t time.Time) string {
+		return t.In(loc).Format("02/01/2006 15:04")
+	}
+
+	if r.Store.Name != "" {
+		center(r.Store.Name, true)
+	}
+	center(rep.Type+" REPORT", true)
+	separator()
+
+	left(justify("Shift", "#"+strconv.Itoa(shift.ID), width))
+	left(justify("Kasir", shift.Cashier, width))
+	left(justify("Buka", timestamp(shift.OpenedAt), width))
+	if shift.ClosedAt != nil {
+		left(justify("Tutup", timestamp(*shift.ClosedAt), width))
+	}
+	left(justify("Cetak", timestamp(rep.GeneratedAt), width))
+	separator()
+
+	left(justify("Transaksi", strconv.Itoa(rep.TransactionCount), width))
+	if rep.CancelledCount > 0 {
+		left(justify("Batal", strconv.Itoa(rep.CancelledCount), width))
+	}
+	amount("Diskon", rep.DiscountTotal, false)
+	amount("Pajak", rep.TaxTotal, false)
+	amount("PENJUALAN", rep.GrossSales, true)
+	separator()
+
+	for _, p := range rep.Payments {
+		amount(fmt.Sprintf("%s (%d)", paymentLabel(p.Method), p.Count), p.Amount, false)
+	}
+	if rep.ReturnCount > 0 {
+		amount(fmt.Sprintf("RETUR (%d)", rep.ReturnCount), -rep.RefundTotal, false)
+	}
+	separator()
+
+	amount("Modal awal", rep.OpeningFloat, false)
+	amount("Penjualan tunai", rep.CashSales, false)
+	amount("Kas masuk", rep.CashIn, false)
+	amount("Kas keluar", -rep.CashOut, false)
+	amount("Refund tunai", -rep.CashRefunds, false)
+	amount("KAS SEHARUSNYA", rep.ExpectedCash, true)
+	if rep.CountedCash != nil {
+		amount("KAS DIHITUNG", *rep.CountedCash, true)
+	}
+	if rep.Variance != nil {
+		amount("SELISIH", *rep.Variance, true)
+	}
+
+	if len(rep.CashEvents) > 0 {
+		separator()
+		for _, e := range rep.CashEvents {
+			value := e.Amount
+			if e.Type == models.CashOut {
+				value = -value
+			}
+			amount(cashEventLabels[e.Type]+" "+e.CreatedAt.In(loc).Format("15:04"), value, false)
+			for _, part := range wrap(e.Reason, width-2) {
+				left("  " + part)
+			}
+		}
+	}
+	separator()
+
+	if rep.Type == models.ReportZ && shift.LockedBy != "" {
+		center("Dikunci oleh "+shift.LockedBy, false)
+	}
+	return lines
+}
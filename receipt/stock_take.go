@@ -0,0 +1,118 @@
+package receipt
+
+import (
+	"fmt"
+	"kasir-api/models"
+	"kasir-api/money"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StockTakeReport adalah laporan selisih stock opname yang dicetak untuk ditandatangani
+type StockTakeReport struct {
+	Store    Store
+	Report   models.StockTakeVarianceReport
+	Location *time.Location
+}
+
+// stockTakeStatusLabels - status sesi stock opname di laporan cetak
+var stockTakeStatusLabels = map[string]string{
+	models.StockTakeCounting:  "Sedang dihitung",
+	models.StockTakeApproved:  "Disetujui",
+	models.StockTakeCancelled: "Dibatalkan",
+}
+
+// RenderStockTakeReport - susun laporan selisih lalu encode ke format yang diminta
+func RenderStockTakeReport(r StockTakeReport, format string, width int) ([]byte, string, error) {
+	if width != Width32 && width != Width48 {
+		return nil, "", fmt.Errorf("lebar struk harus %d atau %d", Width32, Width48)
+	}
+
+	return encode(StockTakeLayout(r, width), format, width)
+}
+
+// StockTakeLayout - susun baris laporan selisih: info sesi, baris yang berselisih atau
+// belum dihitung, ringkasan nilai selisih dan kolom tanda tangan
+func StockTakeLayout(r StockTakeReport, width int) []Line {
+	rep := r.Report
+	st := rep.StockTake
+	loc := r.Location
+	if loc == nil {
+		loc = time.Local
+	}
+
+	lines := make([]Line, 0, 24+3*len(rep.Lines))
+	center := func(text string, bold bool) {
+		for _, part := range wrap(text, width) {
+			lines = append(lines, Line{Text: part, Align: AlignCenter, Bold: bold})
+		}
+	}
+	left := func(text string) {
+		lines = append(lines, Line{Text: text})
+	}
+	separator := func() {
+		left(strings.Repeat("-", width))
+	}
+	timestamp := func(t time.Time) string {
+		return t.In(loc).Format("02/01/2006 15:04")
+	}
+
+	if r.Store.Name != "" {
+		center(r.Store.Name, true)
+	}
+	center("SELISIH STOCK OPNAME", true)
+	separator()
+
+	left(justify("Opname", "#"+strconv.Itoa(st.ID), width))
+	left(justify("Outlet", st.OutletName, width))
+	if st.CategoryName != "" {
+		left(justify("Kategori", st.CategoryName, width))
+	}
+	left(justify("Status", stockTakeStatusLabels[st.Status], width))
+	left(justify("Mulai", timestamp(st.CreatedAt), width))
+	if st.ApprovedAt != nil {
+		left(justify("Disetujui", timestamp(*st.ApprovedAt), width))
+	}
+	left(justify("Cetak", timestamp(rep.GeneratedAt), width))
+	separator()
+
+	if len(rep.Lines) == 0 {
+		center("Tidak ada selisih", false)
+	}
+	for _, l := range rep.Lines {
+		for _, part := range wrap(l.ProductName, width) {
+			left(part)
+		}
+		if l.Counted == nil {
+			left(justify(fmt.Sprintf("  sistem %d", l.SystemStock), "BELUM DIHITUNG", width))
+			continue
+		}
+		left(justify(fmt.Sprintf("  sistem %d hitung %d", l.SystemStock, *l.Counted), fmt.Sprintf("%+d", *l.Variance), width))
+		left(justify("  nilai", money.FormatRupiah(*l.VarianceValue), width))
+	}
+	separator()
+
+	sum := rep.Summary
+	left(justify("Produk", strconv.Itoa(sum.Lines), width))
+	left(justify("Dihitung", strconv.Itoa(sum.Counted), width))
+	if sum.Uncounted > 0 {
+		left(justify("Belum dihitung", strconv.Itoa(sum.Uncounted), width))
+	}
+	left(justify("Berselisih", strconv.Itoa(sum.WithVariance), width))
+	left(justify("Selisih qty", fmt.Sprintf("%+d", sum.NetQuantity), width))
+	left(justify("Nilai kurang", money.FormatRupiah(sum.Shortage), width))
+	left(justify("Nilai lebih", money.FormatRupiah(sum.Surplus), width))
+	lines = append(lines, Line{Text: justify("SELISIH BERSIH", money.FormatRupiah(sum.NetValue), width), Bold: true})
+	separator()
+
+	if st.ApprovedBy != "" {
+		center("Disetujui oleh "+st.ApprovedBy, false)
+	} else {
+		left("")
+		left("Disetujui oleh:")
+		left("")
+		left(strings.Repeat("_", width/2))
+	}
+	return lines
+}
@@ -0,0 +1,13 @@
+package receipt
+
+import "strings"
+
+// Text - struk teks polos dengan lebar tetap
+func Text(lines []Line, width int) []byte {
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(pad(line, width))
+		b.WriteByte('\n')
+	}
+	return []byte(b.String())
+}
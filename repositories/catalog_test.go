@@ -0,0 +1,334 @@
+package repositories_test
+
+import (
+	"database/sql"
+	"errors"
+	"kasir-api/database"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"kasir-api/repositories/memory"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// catalog - repository katalog yang diuji dengan suite yang sama di setiap backend
+type catalog struct {
+	users      repositories.UserRepositoryInterface
+	taxRates   repositories.TaxRateRepositoryInterface
+	categories repositories.CategoryRepositoryInterface
+	products   repositories.ProductRepositoryInterface
+}
+
+func TestCatalogMemory(t *testing.T) {
+	runCatalogSuite(t, func(t *testing.T) catalog {
+		store := memory.NewStore()
+		return catalog{
+			users:      memory.NewUserRepository(store),
+			taxRates:   memory.NewTaxRateRepository(store),
+			categories: memory.NewCategoryRepository(store),
+			products:   memory.NewProductRepository(store),
+		}
+	})
+}
+
+// TestCatalogSQLite membutuhkan driver SQLite: go test -tags sqlite ./repositories/.
+// Tanpa tag tersebut test dilewati; dengan tag, kegagalan membuka database membuat test gagal.
+func TestCatalogSQLite(t *testing.T) {
+	if !sqliteBuilt {
+		t.Skip("driver SQLite tidak di-build, jalankan dengan -tags sqlite")
+	}
+	runCatalogSuite(t, func(t *testing.T) catalog {
+		return newCatalog(openDB(t, "sqlite:"+filepath.Join(t.TempDir(), "kasir.db")))
+	})
+}
+
+// TestCatalogPostgres berjalan jika TEST_DB_CONN diisi. Gunakan database khusus test
+// karena semua tabel dikosongkan sebelum setiap kasus.
+func TestCatalogPostgres(t *testing.T) {
+	conn := os.Getenv("TEST_DB_CONN")
+	if conn == "" {
+		t.Skip("TEST_DB_CONN tidak diisi")
+	}
+	runCatalogSuite(t, func(t *testing.T) catalog {
+		db := openDB(t, conn)
+		_, err := db.Exec(`
+			TRUNCATE categories, products, product_barcodes, stock_movements, users, tax_rates RESTART IDENTITY CASCADE;
+			INSERT INTO tax_rates (name, rate_bps, is_default) VALUES ('PPN', 1100, TRUE), ('Bebas PPN', 0, FALSE);
+		`)
+		require.NoError(t, err)
+		return newCatalog(db)
+	})
+}
+
+// openDB - buka dan migrasi database
+func openDB(t *testing.T, conn string) *sql.DB {
+	db, err := database.InitDB(conn)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	_, err = database.MigrateUp(db)
+	require.NoError(t, err)
+	return db
+}
+
+func newCatalog(db *sql.DB) catalog {
+	return catalog{
+		users:      repositories.NewUserRepository(db),
+		taxRates:   repositories.NewTaxRateRepository(db),
+		categories: repositories.NewCategoryRepository(db),
+		products:   repositories.NewProductRepository(db),
+	}
+}
+
+func runCatalogSuite(t *testing.T, open func(t *testing.T) catalog) {
+	t.Run("not found", func(t *testing.T) {
+		repos := open(t)
+
+		_, err := repos.products.GetByID(99)
+		assert.True(t, errors.Is(err, repositories.ErrNotFound))
+		assert.EqualError(t, err, "produk tidak ditemukan")
+		_, err = repos.products.GetByCode("8992761002015")
+		assert.True(t, errors.Is(err, repositories.ErrNotFound))
+		assert.True(t, errors.Is(repos.products.Update(&models.Product{ID: 99, Name: "Kopi"}), repositories.ErrNotFound))
+		assert.True(t, errors.Is(repos.products.Delete(99), repositories.ErrNotFound))
+		_, _, err = repos.products.GetAll(models.ProductFilter{Page: 1, Limit: 10, OutletID: 99})
+		assert.EqualError(t, err, "outlet tidak ditemukan")
+
+		_, err = repos.categories.GetByID(99)
+		assert.EqualError(t, err, "kategori tidak ditemukan")
+		assert.True(t, errors.Is(repos.categories.Delete(99), repositories.ErrNotFound))
+		_, err = repos.taxRates.GetByID(99)
+		assert.True(t, errors.Is(err, repositories.ErrNotFound))
+		_, err = repos.users.GetByUsername("kasir")
+		assert.True(t, errors.Is(err, repositories.ErrNotFound))
+	})
+
+	t.Run("category join", func(t *testing.T) {
+		repos := open(t)
+
+		category := &models.Category{Name: "Minuman"}
+		require.NoError(t, repos.categories.Create(category))
+		product := &models.Product{Name: "Kopi", Price: 15000, Stock: 5, Cost: 9000, CategoryID: category.ID}
+		require.NoError(t, repos.products.Create(product))
+
+		got, err := repos.products.GetByID(product.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Minuman", got.CategoryName)
+		assert.Equal(t, 5, got.Stock)
+		assert.Equal(t, 9000, got.Cost)
+		assert.Equal(t, []string{}, got.Barcodes)
+
+		category.Name = "Kopi dan Teh"
+		require.NoError(t, repos.categories.Update(category))
+		got, _ = repos.products.GetByID(product.ID)
+		assert.Equal(t, "Kopi dan Teh", got.CategoryName)
+
+		// ON DELETE SET NULL
+		require.NoError(t, repos.categories.Delete(category.ID))
+		got, _ = repos.products.GetByID(product.ID)
+		assert.Equal(t, 0, got.CategoryID)
+		assert.Equal(t, "", got.CategoryName)
+	})
+
+	t.Run("barcode before sku", func(t *testing.T) {
+		repos := open(t)
+
+		require.NoError(t, repos.products.Create(&models.Product{Name: "Kopi", SKU: "8992761002022"}))
+		require.NoError(t, repos.products.Create(&models.Product{Name: "Teh", SKU: "TH-01", Barcodes: []string{"8992761002022"}}))
+
+		p, err := repos.products.GetByCode("8992761002022")
+		require.NoError(t, err)
+		assert.Equal(t, "TH-01", p.SKU)
+	})
+
+	t.Run("constraints", func(t *testing.T) {
+		repos := open(t)
+
+		require.NoError(t, repos.products.Create(&models.Product{Name: "Kopi", SKU: "KP-01", Barcodes: []string{"8992761002015"}}))
+		err := repos.products.Create(&models.Product{Name: "Teh", SKU: "KP-01"})
+		assert.True(t, errors.Is(err, repositories.ErrConflict))
+		err = repos.products.Create(&models.Product{Name: "Teh", SKU: "TH-01", Barcodes: []string{"8992761002015"}})
+		assert.True(t, errors.Is(err, repositories.ErrConflict))
+		err = repos.products.Create(&models.Product{Name: "Teh", SKU: "TH-02", CategoryID: 42})
+		assert.True(t, errors.Is(err, repositories.ErrForeignKey))
+		err = repos.products.Create(&models.Product{Name: "Teh", SKU: "TH-03", TaxRateID: 42})
+		assert.True(t, errors.Is(err, repositories.ErrForeignKey))
+
+		p, err := repos.products.GetByCode("8992761002015")
+		require.NoError(t, err)
+		assert.Equal(t, "KP-01", p.SKU)
+		p.Stock, p.Cost = 100, 100
+		require.NoError(t, repos.products.Update(p))
+		assert.Equal(t, 0, p.Stock, "stok tidak berubah lewat update")
+		assert.Equal(t, 0, p.Cost)
+
+		require.NoError(t, repos.users.Create(&models.User{Username: "admin", PasswordHash: "hash", Role: models.RoleAdmin}))
+		assert.True(t, errors.Is(repos.users.Create(&models.User{Username: "admin", PasswordHash: "hash", Role: models.RoleAdmin}), repositories.ErrConflict))
+		count, err := repos.users.Count()
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("filter sort page", func(t *testing.T) {
+		repos := open(t)
+		for i, name := range []string{"Kopi Susu", "Teh Manis", "Kopi Hitam", "Roti"} {
+			require.NoError(t, repos.products.Create(&models.Product{Name: name, Price: (i + 1) * 1000, Stock: i % 2}))
+		}
+
+		got, total, err := repos.products.GetAll(models.ProductFilter{Page: 1, Limit: 10, Search: "kopi", Sort: "name"})
+		require.NoError(t, err)
+		assert.Equal(t, 2, total)
+		require.Len(t, got, 2)
+		assert.Equal(t, "Kopi Hitam", got[0].Name)
+
+		inStock := true
+		minPrice := 2000
+		got, total, err = repos.products.GetAll(models.ProductFilter{Page: 1, Limit: 1, InStock: &inStock, MinPrice: &minPrice, Sort: "-price"})
+		require.NoError(t, err)
+		assert.Equal(t, 2, total)
+		require.Len(t, got, 1)
+		assert.Equal(t, "Roti", got[0].Name)
+
+		got, total, err = repos.products.GetAll(models.ProductFilter{Page: 3, Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, 4, total)
+		assert.Empty(t, got)
+
+		categories, total, err := repos.categories.GetAll(models.CategoryFilter{Page: 1, Limit: 10, Search: "x"})
+		require.NoError(t, err)
+		assert.Equal(t, 0, total)
+		assert.Empty(t, categories)
+	})
+
+	t.Run("tax rates", func(t *testing.T) {
+		repos := open(t)
+
+		rates, err := repos.taxRates.GetAll()
+		require.NoError(t, err)
+		require.Len(t, rates, 2)
+		assert.True(t, rates[0].IsDefault)
+
+		rate := &models.TaxRate{Name: "PPN 12%", RateBps: 1200, IsDefault: true}
+		require.NoError(t, repos.taxRates.Create(rate))
+		old, err := repos.taxRates.GetByID(rates[0].ID)
+		require.NoError(t, err)
+		assert.False(t, old.IsDefault, "hanya satu tarif default")
+	})
+
+	t.Run("import rolls back", func(t *testing.T) {
+		repos := open(t)
+		req := &models.ProductImportRequest{CreateCategories: true, Columns: map[string]bool{"category": true}}
+		rows := []models.ProductImportRow{
+			{Row: 2, SKU: "KP-01", Product: models.Product{SKU: "KP-01", Name: "Kopi", Price: 15000, Stock: 3}, Category: "Minuman"},
+			{Row: 3, SKU: "KP-02", Product: models.Product{SKU: "KP-02", Name: "Teh", Price: 8000}, Category: "minuman"},
+		}
+		created, err := repos.products.Import(req, rows)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Minuman"}, created)
+		assert.Equal(t, models.ImportCreate, rows[1].Action)
+		got, err := repos.products.GetByCode("KP-02")
+		require.NoError(t, err)
+		assert.Equal(t, "Minuman", got.CategoryName)
+
+		req = &models.ProductImportRequest{Columns: map[string]bool{"category": true}}
+		rows = []models.ProductImportRow{
+			{Row: 2, SKU: "KP-01", Product: models.Product{SKU: "KP-01", Name: "Kopi Susu", Price: 18000}, Category: "Minuman"},
+			{Row: 3, SKU: "RT-01", Product: models.Product{SKU: "RT-01", Name: "Roti", Price: 5000}, Category: "Roti"},
+		}
+		_, err = repos.products.Import(req, rows)
+		require.NoError(t, err)
+		assert.Equal(t, models.ImportUpdate, rows[0].Action)
+		assert.Equal(t, []string{`category: kategori "Roti" tidak ditemukan`}, rows[1].Errors)
+
+		got, _ = repos.products.GetByCode("KP-01")
+		assert.Equal(t, "Kopi", got.Name, "baris yang berhasil ikut dibatalkan")
+		assert.Equal(t, 3, got.Stock)
+		_, err = repos.products.GetByCode("RT-01")
+		assert.True(t, errors.Is(err, repositories.ErrNotFound))
+	})
+	t.Run("variants", func(t *testing.T) {
+		repos := open(t)
+
+		category := &models.Category{Name: "Minuman"}
+		require.NoError(t, repos.categories.Create(category))
+		parent := &models.Product{Name: "Kopi Susu", Price: 18000, CategoryID: category.ID}
+		require.NoError(t, repos.products.Create(parent))
+
+		err := repos.products.CreateVariant(parent.ID, &models.Product{Options: map[string]string{"Ukuran": "Large"}})
+		assert.True(t, errors.Is(err, repositories.ErrValidation), "belum punya atribut")
+
+		require.NoError(t, repos.products.SetAttributes(parent.ID, []models.ProductAttribute{
+			{Name: "Ukuran", Options: []string{"Regular", "Large"}},
+			{Name: "Gula", Options: []string{"Normal", "Less"}},
+		}))
+		regular := &models.Product{SKU: "KS-RN", Stock: 5, Cost: 9000, Options: map[string]string{"Ukuran": "Regular", "Gula": "Normal"}}
+		require.NoError(t, repos.products.CreateVariant(parent.ID, regular))
+		assert.Equal(t, "Kopi Susu Regular Normal", regular.Name)
+		assert.Equal(t, 18000, regular.Price)
+		large := &models.Product{SKU: "KS-LL", Price: 22000, PriceOverride: true, Options: map[string]string{"Ukuran": "Large", "Gula": "Less"}}
+		require.NoError(t, repos.products.CreateVariant(parent.ID, large))
+
+		err = repos.products.CreateVariant(parent.ID, &models.Product{Options: map[string]string{"Ukuran": "Large", "Gula": "Less"}})
+		assert.True(t, errors.Is(err, repositories.ErrConflict), "kombinasi sama")
+		err = repos.products.CreateVariant(parent.ID, &models.Product{Options: map[string]string{"Ukuran": "Large"}})
+		assert.EqualError(t, err, "pilihan Gula wajib diisi")
+		err = repos.products.CreateVariant(parent.ID, &models.Product{Options: map[string]string{"Ukuran": "Jumbo", "Gula": "Less"}})
+		assert.True(t, errors.Is(err, repositories.ErrValidation))
+
+		got, err := repos.products.GetByID(parent.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Regular", "Large"}, got.Attributes[0].Options)
+		require.Len(t, got.Variants, 2)
+		assert.Equal(t, map[string]string{"Ukuran": "Regular", "Gula": "Normal"}, got.Variants[0].Options)
+		assert.Equal(t, 5, got.Variants[0].Stock)
+		assert.Equal(t, "Minuman", got.Variants[0].CategoryName)
+		assert.Equal(t, []string{}, got.Variants[0].Barcodes)
+
+		// Perubahan produk induk diteruskan ke varian kecuali harga yang di-override
+		parent.Name, parent.Price = "Kopi Susu Aren", 20000
+		require.NoError(t, repos.products.Update(parent))
+		v, err := repos.products.GetByCode("KS-RN")
+		require.NoError(t, err)
+		assert.Equal(t, "Kopi Susu Aren Regular Normal", v.Name)
+		assert.Equal(t, 20000, v.Price)
+		assert.Equal(t, parent.ID, v.ParentID)
+		assert.Equal(t, "Normal", v.Options["Gula"])
+		v, _ = repos.products.GetByCode("KS-LL")
+		assert.Equal(t, 22000, v.Price)
+		assert.True(t, errors.Is(repos.products.Update(v), repositories.ErrConflict), "varian tidak diubah lewat Update")
+
+		err = repos.products.SetAttributes(parent.ID, []models.ProductAttribute{{Name: "Ukuran", Options: []string{"Regular", "Large"}}})
+		assert.True(t, errors.Is(err, repositories.ErrConflict), "atribut dihapus selama ada varian")
+		err = repos.products.SetAttributes(parent.ID, []models.ProductAttribute{
+			{Name: "Ukuran", Options: []string{"Regular"}},
+			{Name: "Gula", Options: []string{"Normal", "Less"}},
+		})
+		assert.True(t, errors.Is(err, repositories.ErrConflict), "pilihan dipakai varian")
+		require.NoError(t, repos.products.SetAttributes(parent.ID, []models.ProductAttribute{
+			{Name: "Gula", Options: []string{"Less", "Normal"}},
+			{Name: "Ukuran", Options: []string{"Regular", "Medium", "Large"}},
+		}))
+		v, _ = repos.products.GetByCode("KS-RN")
+		assert.Equal(t, "Kopi Susu Aren Normal Regular", v.Name, "nama mengikuti urutan atribut")
+
+		large.PriceOverride = false
+		require.NoError(t, repos.products.UpdateVariant(large))
+		assert.Equal(t, 20000, large.Price)
+		assert.True(t, errors.Is(repos.products.UpdateVariant(&models.Product{ID: large.ID, ParentID: 999}), repositories.ErrNotFound))
+
+		assert.True(t, errors.Is(repos.products.Delete(parent.ID), repositories.ErrConflict))
+		assert.True(t, errors.Is(repos.products.Delete(regular.ID), repositories.ErrConflict), "varian punya riwayat stok")
+		require.NoError(t, repos.products.Delete(large.ID))
+		assert.True(t, errors.Is(repos.products.Delete(parent.ID), repositories.ErrConflict), "masih punya varian")
+
+		stocked := &models.Product{Name: "Kaos", Price: 50000, Stock: 3}
+		require.NoError(t, repos.products.Create(stocked))
+		require.NoError(t, repos.products.SetAttributes(stocked.ID, []models.ProductAttribute{{Name: "Warna", Options: []string{"Hitam"}}}))
+		err = repos.products.CreateVariant(stocked.ID, &models.Product{Options: map[string]string{"Warna": "Hitam"}})
+		assert.True(t, errors.Is(err, repositories.ErrConflict), "stok produk induk harus 0")
+	})
+}
@@ -2,52 +2,81 @@ package repositories
 
 import (
 	"database/sql"
-	"errors"
+	"fmt"
 	"kasir-api/models"
 )
 
 type CategoryRepository struct {
-	db *sql.DB
+	db dialectDB
 }
 
 func NewCategoryRepository(db *sql.DB) *CategoryRepository {
-	return &CategoryRepository{db: db}
+	return &CategoryRepository{db: newDialectDB(db)}
 }
 
-func (repo *CategoryRepository) GetAll() ([]models.Category, error) {
-	query := "SELECT id, name, description FROM categories"
-	rows, err := repo.db.Query(query)
+// categorySortColumns - nilai parameter sort yang diizinkan beserta klausa ORDER BY
+var categorySortColumns = map[string]string{
+	"id":    "id ASC",
+	"-id":   "id DESC",
+	"name":  "name ASC, id ASC",
+	"-name": "name DESC, id ASC",
+}
+
+// GetAll - ambil kategori sesuai filter, sort dan halaman beserta total baris yang cocok
+func (repo *CategoryRepository) GetAll(filter models.CategoryFilter) ([]models.Category, int, error) {
+	whereClause := ""
+	args := make([]interface{}, 0)
+	if filter.Search != "" {
+		args = append(args, "%"+filter.Search+"%")
+		whereClause = "WHERE name ILIKE $1"
+	}
+
+	var total int
+	err := repo.db.QueryRow("SELECT COUNT(*) FROM categories "+whereClause, args...).Scan(&total)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+
+	orderBy, ok := categorySortColumns[filter.Sort]
+	if !ok {
+		orderBy = categorySortColumns["id"]
+	}
+
+	args = append(args, filter.Limit, (filter.Page-1)*filter.Limit)
+	query := fmt.Sprintf("SELECT id, name, description, COALESCE(tax_rate_id, 0) FROM categories %s ORDER BY %s LIMIT $%d OFFSET $%d",
+		whereClause, orderBy, len(args)-1, len(args))
+	rows, err := repo.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
 	}
 	defer rows.Close()
 
 	categories := make([]models.Category, 0)
 	for rows.Next() {
 		var c models.Category
-		err := rows.Scan(&c.ID, &c.Name, &c.Description)
+		err := rows.Scan(&c.ID, &c.Name, &c.Description, &c.TaxRateID)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 		categories = append(categories, c)
 	}
 
-	return categories, nil
+	return categories, total, rows.Err()
 }
 
 func (repo *CategoryRepository) Create(category *models.Category) error {
-	query := "INSERT INTO categories (name, description) VALUES ($1, $2) RETURNING id"
-	err := repo.db.QueryRow(query, category.Name, category.Description).Scan(&category.ID)
-	return err
+	query := "INSERT INTO categories (name, description, tax_rate_id) VALUES ($1, $2, NULLIF($3, 0)) RETURNING id"
+	err := repo.db.QueryRow(query, category.Name, category.Description, category.TaxRateID).Scan(&category.ID)
+	return mapDBError(err)
 }
 
 func (repo *CategoryRepository) GetByID(id int) (*models.Category, error) {
-	query := "SELECT id, name, description FROM categories WHERE id = $1"
+	query := "SELECT id, name, description, COALESCE(tax_rate_id, 0) FROM categories WHERE id = $1"
 
 	var c models.Category
-	err := repo.db.QueryRow(query, id).Scan(&c.ID, &c.Name, &c.Description)
+	err := repo.db.QueryRow(query, id).Scan(&c.ID, &c.Name, &c.Description, &c.TaxRateID)
 	if err == sql.ErrNoRows {
-		return nil, errors.New("category tidak ditemukan")
+		return nil, NewError(ErrNotFound, "kategori tidak ditemukan")
 	}
 	if err != nil {
 		return nil, err
@@ -57,10 +86,10 @@ func (repo *CategoryRepository) GetByID(id int) (*models.Category, error) {
 }
 
 func (repo *CategoryRepository) Update(category *models.Category) error {
-	query := "UPDATE categories SET name = $1, description = $2 WHERE id = $3"
-	result, err := repo.db.Exec(query, category.Name, category.Description, category.ID)
+	query := "UPDATE categories SET name = $1, description = $2, tax_rate_id = NULLIF($3, 0) WHERE id = $4"
+	result, err := repo.db.Exec(query, category.Name, category.Description, category.TaxRateID, category.ID)
 	if err != nil {
-		return err
+		return mapDBError(err)
 	}
 
 	rows, err := result.RowsAffected()
@@ -69,7 +98,7 @@ func (repo *CategoryRepository) Update(category *models.Category) error {
 	}
 
 	if rows == 0 {
-		return errors.New("category tidak ditemukan")
+		return NewError(ErrNotFound, "kategori tidak ditemukan")
 	}
 
 	return nil
@@ -79,7 +108,7 @@ func (repo *CategoryRepository) Delete(id int) error {
 	query := "DELETE FROM categories WHERE id = $1"
 	result, err := repo.db.Exec(query, id)
 	if err != nil {
-		return err
+		return mapDBError(err)
 	}
 	rows, err := result.RowsAffected()
 	if err != nil {
@@ -87,8 +116,8 @@ func (repo *CategoryRepository) Delete(id int) error {
 	}
 
 	if rows == 0 {
-		return errors.New("category tidak ditemukan")
+		return NewError(ErrNotFound, "kategori tidak ditemukan")
 	}
 
-	return err
+	return nil
 }
@@ -0,0 +1,202 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"kasir-api/models"
+)
+
+type CustomerRepository struct {
+	db *sql.DB
+}
+
+func NewCustomerRepository(db *sql.DB) *CustomerRepository {
+	return &CustomerRepository{db: db}
+}
+
+// pointBalance - saldo poin pelanggan yang masih bisa ditukar (belum kedaluwarsa)
+const pointBalance = `(SELECT COALESCE(SUM(remaining), 0) FROM point_entries pe
+	WHERE pe.customer_id = customers.id AND pe.remaining > 0 AND (pe.expires_at IS NULL OR pe.expires_at > NOW()))`
+
+const customerColumns = "id, name, phone, email, address, notes, " + pointBalance + ", created_at"
+
+// customerSortColumns - nilai parameter sort yang diizinkan beserta klausa ORDER BY
+var customerSortColumns = map[string]string{
+	"id":    "id ASC",
+	"-id":   "id DESC",
+	"name":  "name ASC, id ASC",
+	"-name": "name DESC, id ASC",
+}
+
+func scanCustomer(row rowScanner) (models.Customer, error) {
+	var c models.Customer
+	err := row.Scan(&c.ID, &c.Name, &c.Phone, &c.Email, &c.Address, &c.Notes, &c.Points, &c.CreatedAt)
+	return c, err
+}
+
+// GetAll - cari pelanggan berdasarkan nama atau nomor telepon
+func (repo *CustomerRepository) GetAll(filter models.CustomerFilter) ([]models.Customer, int, error) {
+	whereClause := ""
+	args := make([]interface{}, 0)
+	if filter.Search != "" {
+		args = append(args, "%"+filter.Search+"%")
+		whereClause = "WHERE name ILIKE $1 OR phone LIKE $1"
+	}
+
+	var total int
+	err := repo.db.QueryRow("SELECT COUNT(*) FROM customers "+whereClause, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	orderBy, ok := customerSortColumns[filter.Sort]
+	if !ok {
+		orderBy = customerSortColumns["id"]
+	}
+
+	args = append(args, filter.Limit, (filter.Page-1)*filter.Limit)
+	query := fmt.Sprintf("SELECT %s FROM customers %s ORDER BY %s LIMIT $%d OFFSET $%d",
+		customerColumns, whereClause, orderBy, len(args)-1, len(args))
+	rows, err := repo.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	customers := make([]models.Customer, 0)
+	for rows.Next() {
+		c, err := scanCustomer(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		customers = append(customers, c)
+	}
+	return customers, total, rows.Err()
+}
+
+func (repo *CustomerRepository) GetByID(id int) (*models.Customer, error) {
+	return repo.getCustomer("id = $1", id)
+}
+
+// GetByPhone - cari pelanggan dari nomor telepon yang sudah dinormalisasi
+func (repo *CustomerRepository) GetByPhone(phone string) (*models.Customer, error) {
+	return repo.getCustomer("phone = $1", phone)
+}
+
+func (repo *CustomerRepository) getCustomer(condition string, args ...interface{}) (*models.Customer, error) {
+	c, err := scanCustomer(repo.db.QueryRow("SELECT "+customerColumns+" FROM customers WHERE "+condition, args...))
+	if err == sql.ErrNoRows {
+		return nil, NewError(ErrNotFound, "pelanggan tidak ditemukan")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (repo *CustomerRepository) Create(customer *models.Customer) error {
+	query := `
+		INSERT INTO customers (name, phone, email, address, notes)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+	err := repo.db.QueryRow(query, customer.Name, customer.Phone, customer.Email, customer.Address, customer.Notes).
+		Scan(&customer.ID, &customer.CreatedAt)
+	return phoneConflict(mapDBError(err), customer.Phone)
+}
+
+func (repo *CustomerRepository) Update(customer *models.Customer) error {
+	query := `
+		UPDATE customers SET name = $1, phone = $2, email = $3, address = $4, notes = $5
+		WHERE id = $6
+		RETURNING ` + pointBalance + `, created_at
+	`
+	err := repo.db.QueryRow(query, customer.Name, customer.Phone, customer.Email, customer.Address, customer.Notes, customer.ID).
+		Scan(&customer.Points, &customer.CreatedAt)
+	if err == sql.ErrNoRows {
+		return NewError(ErrNotFound, "pelanggan tidak ditemukan")
+	}
+	return phoneConflict(mapDBError(err), customer.Phone)
+}
+
+// phoneConflict - pesan yang lebih jelas untuk nomor telepon yang sudah terdaftar
+func phoneConflict(err error, phone string) error {
+	if errors.Is(err, ErrConflict) {
+		return NewError(ErrConflict, fmt.Sprintf("nomor telepon %s sudah terdaftar", phone))
+	}
+	return err
+}
+
+// Delete - hapus pelanggan; transaksinya tetap ada tanpa pelanggan
+func (repo *CustomerRepository) Delete(id int) error {
+	result, err := repo.db.Exec("DELETE FROM customers WHERE id = $1", id)
+	if err != nil {
+		return mapDBError(err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return NewError(ErrNotFound, "pelanggan tidak ditemukan")
+	}
+	return nil
+}
+
+// History - ringkasan belanja pelanggan dan daftar transaksinya per halaman, terbaru lebih dulu.
+// Transaksi batal tidak termasuk riwayat belanja.
+func (repo *CustomerRepository) History(customerID, page, limit int) (*models.CustomerHistory, []models.TransactionSummary, error) {
+	customer, err := repo.GetByID(customerID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	history := &models.CustomerHistory{Customer: *customer}
+	var lastVisit sql.NullTime
+	err = repo.db.QueryRow(`
+		SELECT COUNT(*), COALESCE(SUM(total_amount), 0), MAX(created_at)
+		FROM transactions
+		WHERE customer_id = $1 AND status <> 'cancelled'`, customerID,
+	).Scan(&history.TransactionCount, &history.TotalSpend, &lastVisit)
+	if err != nil {
+		return nil, nil, err
+	}
+	if lastVisit.Valid {
+		history.LastVisit = &lastVisit.Time
+	}
+
+	err = repo.db.QueryRow(`
+		SELECT COALESCE(SUM(r.refund_amount), 0)
+		FROM returns r
+		JOIN transactions t ON t.id = r.transaction_id
+		WHERE t.customer_id = $1 AND r.status = 'approved'`, customerID,
+	).Scan(&history.RefundTotal)
+	if err != nil {
+		return nil, nil, err
+	}
+	history.LifetimeSpend = history.TotalSpend - history.RefundTotal
+
+	rows, err := repo.db.Query(`
+		SELECT t.id, t.total_amount, COALESCE(SUM(d.quantity), 0), t.status, t.cashier, t.created_at
+		FROM transactions t
+		LEFT JOIN transaction_details d ON d.transaction_id = t.id
+		WHERE t.customer_id = $1 AND t.status <> 'cancelled'
+		GROUP BY t.id
+		ORDER BY t.created_at DESC, t.id DESC
+		LIMIT $2 OFFSET $3`, customerID, limit, (page-1)*limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	transactions := make([]models.TransactionSummary, 0)
+	for rows.Next() {
+		var t models.TransactionSummary
+		if err := rows.Scan(&t.ID, &t.TotalAmount, &t.ItemCount, &t.Status, &t.Cashier, &t.CreatedAt); err != nil {
+			return nil, nil, err
+		}
+		transactions = append(transactions, t)
+	}
+	return history, transactions, rows.Err()
+}
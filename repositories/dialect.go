@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"database/sql"
+	"kasir-api/database"
+)
+
+// execQueryer - queryer yang juga bisa Exec, dimiliki *sql.Tx maupun dialectTx sehingga
+// helper seperti applyMovement bisa dipakai repository PostgreSQL dan katalog SQLite
+type execQueryer interface {
+	queryer
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// dialectDB - *sql.DB untuk repository katalog yang berjalan di PostgreSQL dan SQLite.
+// Query tetap ditulis gaya PostgreSQL lalu disesuaikan dengan database.Rebind.
+type dialectDB struct {
+	db     *sql.DB
+	driver string
+}
+
+func newDialectDB(db *sql.DB) dialectDB {
+	return dialectDB{db: db, driver: database.Driver(db)}
+}
+
+func (d dialectDB) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return d.db.Exec(database.Rebind(d.driver, query), args...)
+}
+
+func (d dialectDB) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return d.db.Query(database.Rebind(d.driver, query), args...)
+}
+
+func (d dialectDB) QueryRow(query string, args ...interface{}) *sql.Row {
+	return d.db.QueryRow(database.Rebind(d.driver, query), args...)
+}
+
+func (d dialectDB) Begin() (*dialectTx, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	return &dialectTx{tx: tx, driver: d.driver}, nil
+}
+
+// dialectTx - *sql.Tx dengan penyesuaian SQL yang sama seperti dialectDB
+type dialectTx struct {
+	tx     *sql.Tx
+	driver string
+}
+
+func (t *dialectTx) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return t.tx.Exec(database.Rebind(t.driver, query), args...)
+}
+
+func (t *dialectTx) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return t.tx.Query(database.Rebind(t.driver, query), args...)
+}
+
+func (t *dialectTx) QueryRow(query string, args ...interface{}) *sql.Row {
+	return t.tx.QueryRow(database.Rebind(t.driver, query), args...)
+}
+
+func (t *dialectTx) Commit() error {
+	return t.tx.Commit()
+}
+
+func (t *dialectTx) Rollback() error {
+	return t.tx.Rollback()
+}
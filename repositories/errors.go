@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// Jenis error repository. Gunakan errors.Is untuk memeriksa jenisnya;
+// pesan spesifik dibawa oleh *Error yang membungkus salah satu sentinel ini.
+var (
+	ErrNotFound   = errors.New("data tidak ditemukan")
+	ErrConflict   = errors.New("data bentrok dengan data lain")
+	ErrValidation = errors.New("data tidak valid")
+	ErrForeignKey = errors.New("data terkait tidak ditemukan atau masih digunakan")
+)
+
+// Error adalah error dengan pesan yang aman ditampilkan ke client
+type Error struct {
+	Kind    error
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+// NewError - buat error dengan jenis sentinel dan pesan untuk client
+func NewError(kind error, message string) error {
+	return &Error{Kind: kind, Message: message}
+}
+
+// mapDBError - terjemahkan pelanggaran constraint Postgres ke error bertipe.
+// Error lain (koneksi putus, syntax, dsb.) dikembalikan apa adanya.
+func mapDBError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return mapSQLiteError(err)
+	}
+
+	switch pqErr.Code {
+	case "23505": // unique_violation
+		return NewError(ErrConflict, "data dengan nilai yang sama sudah ada")
+	case "23503": // foreign_key_violation
+		return NewError(ErrForeignKey, "data terkait tidak ditemukan atau masih digunakan")
+	case "23502", "23514": // not_null_violation, check_violation
+		return NewError(ErrValidation, "data tidak memenuhi aturan database")
+	case "22001", "22003": // string_data_right_truncation, numeric_value_out_of_range
+		return NewError(ErrValidation, "nilai terlalu panjang atau di luar batas")
+	}
+
+	return err
+}
+
+// sqliteConstraints - potongan pesan error constraint SQLite. Dicocokkan dari teks error
+// supaya package ini tidak bergantung pada driver SQLite yang hanya ada dengan -tags sqlite.
+var sqliteConstraints = []struct {
+	message string
+	err     error
+}{
+	{"UNIQUE constraint failed", NewError(ErrConflict, "data dengan nilai yang sama sudah ada")},
+	{"FOREIGN KEY constraint failed", NewError(ErrForeignKey, "data terkait tidak ditemukan atau masih digunakan")},
+	{"NOT NULL constraint failed", NewError(ErrValidation, "data tidak memenuhi aturan database")},
+	{"CHECK constraint failed", NewError(ErrValidation, "data tidak memenuhi aturan database")},
+}
+
+// mapSQLiteError - padanan mapDBError untuk pelanggaran constraint SQLite
+func mapSQLiteError(err error) error {
+	if err == nil {
+		return nil
+	}
+	for _, c := range sqliteConstraints {
+		if strings.Contains(err.Error(), c.message) {
+			return c.err
+		}
+	}
+	return err
+}
@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapSQLiteError(t *testing.T) {
+	err := mapDBError(errors.New("constraint failed: UNIQUE constraint failed: products.sku (2067)"))
+	assert.True(t, errors.Is(err, ErrConflict))
+	assert.EqualError(t, err, "data dengan nilai yang sama sudah ada")
+
+	err = mapDBError(errors.New("constraint failed: FOREIGN KEY constraint failed (787)"))
+	assert.True(t, errors.Is(err, ErrForeignKey))
+
+	err = mapDBError(errors.New("constraint failed: CHECK constraint failed: cost >= 0 (275)"))
+	assert.True(t, errors.Is(err, ErrValidation))
+
+	other := errors.New("database is locked")
+	assert.Equal(t, other, mapDBError(other))
+	assert.NoError(t, mapDBError(nil))
+}
@@ -0,0 +1,51 @@
+package repositories
+
+import "kasir-api/models"
+
+// ProductRepositoryInterface defines the storage operations for products
+type ProductRepositoryInterface interface {
+	GetAll(filter models.ProductFilter) ([]models.Product, int, error)
+	GetByID(id int) (*models.Product, error)
+	GetByCode(code string) (*models.Product, error)
+	Create(product *models.Product) error
+	Update(product *models.Product) error
+	Delete(id int) error
+	Export() ([]models.Product, error)
+	Import(req *models.ProductImportRequest, rows []models.ProductImportRow) ([]string, error)
+	SetAttributes(productID int, attributes []models.ProductAttribute) error
+	CreateVariant(parentID int, variant *models.Product) error
+	UpdateVariant(variant *models.Product) error
+}
+
+// CategoryRepositoryInterface defines the storage operations for categories
+type CategoryRepositoryInterface interface {
+	GetAll(filter models.CategoryFilter) ([]models.Category, int, error)
+	GetByID(id int) (*models.Category, error)
+	Create(category *models.Category) error
+	Update(category *models.Category) error
+	Delete(id int) error
+}
+
+// TaxRateRepositoryInterface defines the storage operations for tax rates
+type TaxRateRepositoryInterface interface {
+	GetAll() ([]models.TaxRate, error)
+	GetByID(id int) (*models.TaxRate, error)
+	Create(rate *models.TaxRate) error
+	Update(rate *models.TaxRate) error
+	Delete(id int) error
+}
+
+// UserRepositoryInterface defines the storage operations for users
+type UserRepositoryInterface interface {
+	GetAll() ([]models.User, error)
+	GetByUsername(username string) (*models.User, error)
+	Create(user *models.User) error
+	Count() (int, error)
+}
+
+var (
+	_ ProductRepositoryInterface  = (*ProductRepository)(nil)
+	_ CategoryRepositoryInterface = (*CategoryRepository)(nil)
+	_ TaxRateRepositoryInterface  = (*TaxRateRepository)(nil)
+	_ UserRepositoryInterface     = (*UserRepository)(nil)
+)
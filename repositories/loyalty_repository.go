@@ -0,0 +1,517 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"kasir-api/loyalty"
+	"kasir-api/models"
+	"time"
+)
+
+type LoyaltyRepository struct {
+	db *sql.DB
+}
+
+func NewLoyaltyRepository(db *sql.DB) *LoyaltyRepository {
+	return &LoyaltyRepository{db: db}
+}
+
+func (repo *LoyaltyRepository) GetSettings() (*models.LoyaltySettings, error) {
+	return getLoyaltySettings(repo.db)
+}
+
+func getLoyaltySettings(q queryer) (*models.LoyaltySettings, error) {
+	var s models.LoyaltySettings
+	err := q.QueryRow("SELECT active, earn_amount, point_value, expiry_days, updated_at FROM loyalty_settings WHERE id = 1").
+		Scan(&s.Active, &s.EarnAmount, &s.PointValue, &s.ExpiryDays, &s.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := q.Query("SELECT category_id, multiplier_percent, excluded FROM loyalty_category_rules ORDER BY category_id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	s.CategoryRules = make([]models.LoyaltyCategoryRule, 0)
+	for rows.Next() {
+		var r models.LoyaltyCategoryRule
+		if err := rows.Scan(&r.CategoryID, &r.MultiplierPercent, &r.Excluded); err != nil {
+			return nil, err
+		}
+		s.CategoryRules = append(s.CategoryRules, r)
+	}
+	return &s, rows.Err()
+}
+
+// UpdateSettings - simpan aturan poin. Aturan kategori diganti seluruhnya. Perubahan
+// hanya berlaku untuk transaksi berikutnya, poin yang sudah diperoleh tidak dihitung ulang.
+func (repo *LoyaltyRepository) UpdateSettings(settings *models.LoyaltySettings) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(`
+		UPDATE loyalty_settings SET active = $1, earn_amount = $2, point_value = $3, expiry_days = $4, updated_at = NOW()
+		WHERE id = 1 RETURNING updated_at`,
+		settings.Active, settings.EarnAmount, settings.PointValue, settings.ExpiryDays,
+	).Scan(&settings.UpdatedAt)
+	if err != nil {
+		return mapDBError(err)
+	}
+
+	if _, err := tx.Exec("DELETE FROM loyalty_category_rules"); err != nil {
+		return err
+	}
+	for _, r := range settings.CategoryRules {
+		_, err := tx.Exec(
+			"INSERT INTO loyalty_category_rules (category_id, multiplier_percent, excluded) VALUES ($1, $2, $3)",
+			r.CategoryID, r.MultiplierPercent, r.Excluded,
+		)
+		if err != nil {
+			return mapDBError(err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Ledger - saldo poin pelanggan dan entri ledgernya per halaman, terbaru lebih dulu
+func (repo *LoyaltyRepository) Ledger(customerID, page, limit int) (*models.PointLedger, []models.PointEntry, int, error) {
+	var exists bool
+	err := repo.db.QueryRow("SELECT EXISTS (SELECT 1 FROM customers WHERE id = $1)", customerID).Scan(&exists)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	if !exists {
+		return nil, nil, 0, NewError(ErrNotFound, "pelanggan tidak ditemukan")
+	}
+
+	ledger := &models.PointLedger{CustomerID: customerID}
+	err = repo.db.QueryRow(`
+		SELECT COALESCE(SUM(remaining), 0) FROM point_entries
+		WHERE customer_id = $1 AND remaining > 0 AND (expires_at IS NULL OR expires_at > NOW())`, customerID,
+	).Scan(&ledger.Balance)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	var nextExpiry sql.NullTime
+	var nextPoints int
+	err = repo.db.QueryRow(`
+		SELECT expires_at, SUM(remaining) FROM point_entries
+		WHERE customer_id = $1 AND remaining > 0 AND expires_at > NOW()
+		GROUP BY expires_at ORDER BY expires_at LIMIT 1`, customerID,
+	).Scan(&nextExpiry, &nextPoints)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, nil, 0, err
+	}
+	if nextExpiry.Valid {
+		ledger.NextExpiry = &nextExpiry.Time
+		ledger.NextExpiryPoints = nextPoints
+	}
+
+	var total int
+	err = repo.db.QueryRow("SELECT COUNT(*) FROM point_entries WHERE customer_id = $1", customerID).Scan(&total)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	rows, err := repo.db.Query(`
+		SELECT `+pointEntryColumns+` FROM point_entries
+		WHERE customer_id = $1
+		ORDER BY id DESC LIMIT $2 OFFSET $3`,
+		customerID, limit, (page-1)*limit,
+	)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	defer rows.Close()
+
+	entries := make([]models.PointEntry, 0)
+	for rows.Next() {
+		e, err := scanPointEntry(rows)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		entries = append(entries, e)
+	}
+	return ledger, entries, total, rows.Err()
+}
+
+const pointEntryColumns = `
+	id, customer_id, type, points, remaining, expires_at, COALESCE(transaction_id, 0), COALESCE(return_id, 0),
+	note, username, created_at`
+
+func scanPointEntry(row rowScanner) (models.PointEntry, error) {
+	var e models.PointEntry
+	var expiresAt sql.NullTime
+	err := row.Scan(&e.ID, &e.CustomerID, &e.Type, &e.Points, &e.Remaining, &expiresAt, &e.TransactionID, &e.ReturnID,
+		&e.Note, &e.User, &e.CreatedAt)
+	if expiresAt.Valid {
+		e.ExpiresAt = &expiresAt.Time
+	}
+	return e, err
+}
+
+// Adjust - koreksi saldo manual. Penambahan mengikuti masa berlaku poin saat ini,
+// pengurangan tidak boleh melebihi saldo.
+func (repo *LoyaltyRepository) Adjust(customerID int, adj models.PointAdjustment) (*models.PointEntry, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := lockCustomer(tx, customerID); err != nil {
+		return nil, err
+	}
+
+	entry := &models.PointEntry{
+		CustomerID: customerID,
+		Type:       models.PointAdjust,
+		Points:     adj.Points,
+		Note:       adj.Note,
+		User:       adj.User,
+	}
+	if adj.Points > 0 {
+		settings, err := getLoyaltySettings(tx)
+		if err != nil {
+			return nil, err
+		}
+		entry.ExpiresAt = loyalty.ExpiresAt(*settings, time.Now())
+		err = creditPoints(tx, entry)
+	} else {
+		err = debitPoints(tx, entry, false)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// ExpireBefore - hanguskan sisa poin yang masa berlakunya lewat sebelum at. Setiap
+// pelanggan diproses dalam database transaction sendiri. Mengembalikan jumlah entri expire.
+func (repo *LoyaltyRepository) ExpireBefore(at time.Time) (int, error) {
+	rows, err := repo.db.Query("SELECT DISTINCT customer_id FROM point_entries WHERE remaining > 0 AND expires_at <= $1", at)
+	if err != nil {
+		return 0, err
+	}
+	customerIDs := make([]int, 0)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		customerIDs = append(customerIDs, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, customerID := range customerIDs {
+		ok, err := repo.expireCustomer(customerID, at)
+		if err != nil {
+			return expired, err
+		}
+		if ok {
+			expired++
+		}
+	}
+	return expired, nil
+}
+
+func (repo *LoyaltyRepository) expireCustomer(customerID int, at time.Time) (bool, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if err := lockCustomer(tx, customerID); err != nil {
+		return false, err
+	}
+
+	var points int
+	err = tx.QueryRow(`
+		WITH expired AS (
+			UPDATE point_entries p SET remaining = 0
+			FROM (SELECT id, remaining FROM point_entries
+				WHERE customer_id = $1 AND remaining > 0 AND expires_at <= $2) old
+			WHERE p.id = old.id
+			RETURNING old.remaining
+		)
+		SELECT COALESCE(SUM(remaining), 0) FROM expired`, customerID, at,
+	).Scan(&points)
+	if err != nil {
+		return false, err
+	}
+	if points == 0 {
+		return false, nil
+	}
+
+	_, err = tx.Exec(
+		"INSERT INTO point_entries (customer_id, type, points, note) VALUES ($1, $2, $3, $4)",
+		customerID, models.PointExpire, -points, "poin kedaluwarsa",
+	)
+	if err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// lockCustomer - kunci pelanggan FOR UPDATE sebelum mengubah ledger poinnya sehingga
+// saldo tidak bisa dipakai dua kali oleh checkout paralel
+func lockCustomer(tx *sql.Tx, customerID int) error {
+	var locked int
+	err := tx.QueryRow("SELECT id FROM customers WHERE id = $1 FOR UPDATE", customerID).Scan(&locked)
+	if err == sql.ErrNoRows {
+		return NewError(ErrNotFound, "pelanggan tidak ditemukan")
+	}
+	return err
+}
+
+// creditPoints - tambah entri positif, seluruh poinnya masih tersisa untuk ditukar
+func creditPoints(tx *sql.Tx, entry *models.PointEntry) error {
+	entry.Remaining = entry.Points
+	return insertPointEntry(tx, entry)
+}
+
+// debitPoints - kurangi saldo dengan memakai sisa poin yang paling cepat kedaluwarsa
+// lebih dulu. Jika partial, pengurangan dibatasi sampai saldo yang ada; jika tidak,
+// saldo yang kurang ditolak. entry.Points negatif dan disesuaikan dengan yang terpakai.
+func debitPoints(tx *sql.Tx, entry *models.PointEntry, partial bool) error {
+	rows, err := tx.Query(`
+		SELECT id, remaining FROM point_entries
+		WHERE customer_id = $1 AND remaining > 0 AND (expires_at IS NULL OR expires_at > NOW())
+		ORDER BY expires_at NULLS LAST, id
+		FOR UPDATE`, entry.CustomerID)
+	if err != nil {
+		return err
+	}
+	type open struct{ id, remaining int }
+	opens := make([]open, 0)
+	available := 0
+	for rows.Next() {
+		var o open
+		if err := rows.Scan(&o.id, &o.remaining); err != nil {
+			rows.Close()
+			return err
+		}
+		opens = append(opens, o)
+		available += o.remaining
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	need := -entry.Points
+	if need > available {
+		if !partial {
+			return NewError(ErrConflict, fmt.Sprintf("poin tidak mencukupi, saldo %d", available))
+		}
+		need = available
+	}
+	if need == 0 {
+		return nil
+	}
+	entry.Points = -need
+
+	for _, o := range opens {
+		if need == 0 {
+			break
+		}
+		used := min(o.remaining, need)
+		if _, err := tx.Exec("UPDATE point_entries SET remaining = remaining - $1 WHERE id = $2", used, o.id); err != nil {
+			return err
+		}
+		need -= used
+	}
+
+	entry.Remaining = 0
+	return insertPointEntry(tx, entry)
+}
+
+func insertPointEntry(tx *sql.Tx, entry *models.PointEntry) error {
+	return tx.QueryRow(`
+		INSERT INTO point_entries (customer_id, type, points, remaining, expires_at, transaction_id, return_id, note, username)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, 0), NULLIF($7, 0), $8, $9)
+		RETURNING id, created_at`,
+		entry.CustomerID, entry.Type, entry.Points, entry.Remaining, entry.ExpiresAt,
+		entry.TransactionID, entry.ReturnID, entry.Note, entry.User,
+	).Scan(&entry.ID, &entry.CreatedAt)
+}
+
+// transactionPoints adalah data poin yang disimpan pada transaksi saat checkout
+type transactionPoints struct {
+	customerID int
+	total      int
+	earned     int
+	redeemed   int
+	pointValue int
+	expiresAt  *time.Time
+}
+
+func getTransactionPoints(tx *sql.Tx, transactionID int) (*transactionPoints, error) {
+	var p transactionPoints
+	var expiresAt sql.NullTime
+	err := tx.QueryRow(`
+		SELECT COALESCE(customer_id, 0), total_amount, points_earned, points_redeemed, point_value, points_expire_at
+		FROM transactions WHERE id = $1`, transactionID,
+	).Scan(&p.customerID, &p.total, &p.earned, &p.redeemed, &p.pointValue, &expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	if expiresAt.Valid {
+		p.expiresAt = &expiresAt.Time
+	}
+	return &p, nil
+}
+
+// creditEarnedPoints - kreditkan poin belanja ketika transaksi lunas
+func creditEarnedPoints(tx *sql.Tx, transactionID int) error {
+	p, err := getTransactionPoints(tx, transactionID)
+	if err != nil {
+		return err
+	}
+	if p.customerID == 0 || p.earned == 0 {
+		return nil
+	}
+	if err := lockCustomer(tx, p.customerID); err != nil {
+		return err
+	}
+	return creditPoints(tx, &models.PointEntry{
+		CustomerID:    p.customerID,
+		Type:          models.PointEarn,
+		Points:        p.earned,
+		ExpiresAt:     p.expiresAt,
+		TransactionID: transactionID,
+		Note:          fmt.Sprintf("belanja transaksi #%d", transactionID),
+	})
+}
+
+// restoreExpiry - poin yang dikembalikan mendapat masa berlaku baru sesuai aturan
+// saat ini, supaya refund lama tidak langsung hangus
+func restoreExpiry(tx *sql.Tx) (*time.Time, error) {
+	settings, err := getLoyaltySettings(tx)
+	if err != nil {
+		return nil, err
+	}
+	return loyalty.ExpiresAt(*settings, time.Now()), nil
+}
+
+// restoreRedeemedPoints - kembalikan poin yang dipakai membayar transaksi yang dibatalkan
+func restoreRedeemedPoints(tx *sql.Tx, transactionID int, user, reason string) error {
+	p, err := getTransactionPoints(tx, transactionID)
+	if err != nil {
+		return err
+	}
+	if p.customerID == 0 || p.redeemed == 0 {
+		return nil
+	}
+	if err := lockCustomer(tx, p.customerID); err != nil {
+		return err
+	}
+	expiresAt, err := restoreExpiry(tx)
+	if err != nil {
+		return err
+	}
+	return creditPoints(tx, &models.PointEntry{
+		CustomerID:    p.customerID,
+		Type:          models.PointRestore,
+		Points:        p.redeemed,
+		ExpiresAt:     expiresAt,
+		TransactionID: transactionID,
+		Note:          reason,
+		User:          user,
+	})
+}
+
+// reverseReturnPoints - saat retur disetujui, tarik poin belanja sebanding nilai yang
+// sudah direfund dan kembalikan refund dengan metode poin ke saldo pelanggan. Penarikan
+// dihitung kumulatif dari semua retur yang disetujui sehingga pembulatan tidak menumpuk;
+// jika saldo tidak cukup, yang ditarik hanya sebesar saldo.
+func reverseReturnPoints(tx *sql.Tx, ret *models.Return) error {
+	p, err := getTransactionPoints(tx, ret.TransactionID)
+	if err != nil {
+		return err
+	}
+	if p.customerID == 0 {
+		return nil
+	}
+
+	refundedPoints := 0
+	for _, r := range ret.Refunds {
+		if r.Method == models.PaymentPoints && p.pointValue > 0 {
+			refundedPoints += r.Amount / p.pointValue
+		}
+	}
+	if p.earned == 0 && refundedPoints == 0 {
+		return nil
+	}
+	if err := lockCustomer(tx, p.customerID); err != nil {
+		return err
+	}
+
+	if p.earned > 0 {
+		var refunded, reversed int
+		err := tx.QueryRow("SELECT COALESCE(SUM(refund_amount), 0) FROM returns WHERE transaction_id = $1 AND status = 'approved'", ret.TransactionID).
+			Scan(&refunded)
+		if err != nil {
+			return err
+		}
+		err = tx.QueryRow("SELECT COALESCE(-SUM(points), 0) FROM point_entries WHERE transaction_id = $1 AND type = $2", ret.TransactionID, models.PointReversal).
+			Scan(&reversed)
+		if err != nil {
+			return err
+		}
+
+		target := p.earned
+		if refunded < p.total {
+			target = p.earned * refunded / p.total
+		}
+		if target > reversed {
+			err := debitPoints(tx, &models.PointEntry{
+				CustomerID:    p.customerID,
+				Type:          models.PointReversal,
+				Points:        reversed - target,
+				TransactionID: ret.TransactionID,
+				ReturnID:      ret.ID,
+				Note:          fmt.Sprintf("retur #%d transaksi #%d", ret.ID, ret.TransactionID),
+				User:          ret.DecidedBy,
+			}, true)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if refundedPoints > 0 {
+		expiresAt, err := restoreExpiry(tx)
+		if err != nil {
+			return err
+		}
+		return creditPoints(tx, &models.PointEntry{
+			CustomerID:    p.customerID,
+			Type:          models.PointRestore,
+			Points:        refundedPoints,
+			ExpiresAt:     expiresAt,
+			TransactionID: ret.TransactionID,
+			ReturnID:      ret.ID,
+			Note:          fmt.Sprintf("refund poin retur #%d", ret.ID),
+			User:          ret.DecidedBy,
+		})
+	}
+	return nil
+}
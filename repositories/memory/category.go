@@ -0,0 +1,108 @@
+package memory
+
+import (
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"sort"
+	"strings"
+)
+
+type CategoryRepository struct {
+	store *Store
+}
+
+func NewCategoryRepository(store *Store) *CategoryRepository {
+	return &CategoryRepository{store: store}
+}
+
+// GetAll - kategori sesuai filter, sort dan halaman seperti versi PostgreSQL
+func (repo *CategoryRepository) GetAll(filter models.CategoryFilter) ([]models.Category, int, error) {
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	search := strings.ToLower(filter.Search)
+	matched := make([]models.Category, 0)
+	for _, c := range repo.store.categories {
+		if search == "" || strings.Contains(strings.ToLower(c.Name), search) {
+			matched = append(matched, c)
+		}
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		a, b := matched[i], matched[j]
+		switch filter.Sort {
+		case "-id":
+			return a.ID > b.ID
+		case "name", "-name":
+			if an, bn := strings.ToLower(a.Name), strings.ToLower(b.Name); an != bn {
+				return (an < bn) == (filter.Sort == "name")
+			}
+		}
+		return a.ID < b.ID
+	})
+	return paginate(matched, filter.Page, filter.Limit), len(matched), nil
+}
+
+func (repo *CategoryRepository) GetByID(id int) (*models.Category, error) {
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	c, ok := repo.store.categories[id]
+	if !ok {
+		return nil, repositories.NewError(repositories.ErrNotFound, "kategori tidak ditemukan")
+	}
+	return &c, nil
+}
+
+func (repo *CategoryRepository) Create(category *models.Category) error {
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	if err := repo.store.checkRefs(0, category.TaxRateID); err != nil {
+		return err
+	}
+	category.ID = repo.store.newID("categories")
+	repo.store.categories[category.ID] = *category
+	return nil
+}
+
+func (repo *CategoryRepository) Update(category *models.Category) error {
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	if _, ok := repo.store.categories[category.ID]; !ok {
+		return repositories.NewError(repositories.ErrNotFound, "kategori tidak ditemukan")
+	}
+	if err := repo.store.checkRefs(0, category.TaxRateID); err != nil {
+		return err
+	}
+	repo.store.categories[category.ID] = *category
+	return nil
+}
+
+// Delete - hapus kategori; produk di kategori tersebut menjadi tanpa kategori
+func (repo *CategoryRepository) Delete(id int) error {
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	if _, ok := repo.store.categories[id]; !ok {
+		return repositories.NewError(repositories.ErrNotFound, "kategori tidak ditemukan")
+	}
+	delete(repo.store.categories, id)
+	for pid, p := range repo.store.products {
+		if p.CategoryID == id {
+			p.CategoryID = 0
+			repo.store.products[pid] = p
+		}
+	}
+	return nil
+}
+
+// paginate - potong hasil sesuai halaman (mulai 1) dan limit seperti LIMIT/OFFSET
+func paginate[T any](items []T, page, limit int) []T {
+	offset := (page - 1) * limit
+	if limit <= 0 || offset < 0 || offset >= len(items) {
+		return make([]T, 0)
+	}
+	return items[offset:min(offset+limit, len(items))]
+}
@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"errors"
+	"fmt"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConcurrentCreate(t *testing.T) {
+	products := NewProductRepository(NewStore())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			assert.NoError(t, products.Create(&models.Product{Name: fmt.Sprintf("Produk %d", i)}))
+			_, _, _ = products.GetAll(models.ProductFilter{Page: 1, Limit: 10})
+		}(i)
+	}
+	wg.Wait()
+
+	_, total, _ := products.GetAll(models.ProductFilter{Page: 1, Limit: 10})
+	assert.Equal(t, 50, total)
+}
+
+func TestConcurrentCreateSameSKU(t *testing.T) {
+	products := NewProductRepository(NewStore())
+
+	var wg sync.WaitGroup
+	results := make([]error, 20)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = products.Create(&models.Product{Name: "Kopi", SKU: "KP-01"})
+		}(i)
+	}
+	wg.Wait()
+
+	created := 0
+	for _, err := range results {
+		if err == nil {
+			created++
+			continue
+		}
+		assert.True(t, errors.Is(err, repositories.ErrConflict))
+	}
+	assert.Equal(t, 1, created)
+
+	p, err := products.GetByCode("KP-01")
+	require.NoError(t, err)
+	assert.Equal(t, "Kopi", p.Name)
+}
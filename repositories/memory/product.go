@@ -0,0 +1,322 @@
+package memory
+
+import (
+	"fmt"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+)
+
+type ProductRepository struct {
+	store *Store
+}
+
+func NewProductRepository(store *Store) *ProductRepository {
+	return &ProductRepository{store: store}
+}
+
+// GetAll - produk sesuai filter, sort dan halaman beserta nama kategorinya. Penyimpanan
+// memori tidak mengenal outlet, sehingga filter outlet selalu tidak ditemukan.
+func (repo *ProductRepository) GetAll(filter models.ProductFilter) ([]models.Product, int, error) {
+	if filter.OutletID != 0 {
+		return nil, 0, repositories.NewError(repositories.ErrNotFound, "outlet tidak ditemukan")
+	}
+
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	search := strings.ToLower(filter.Search)
+	matched := make([]models.Product, 0)
+	for _, p := range repo.store.products {
+		switch {
+		case filter.CategoryID != 0 && p.CategoryID != filter.CategoryID:
+		case filter.MinPrice != nil && p.Price < *filter.MinPrice:
+		case filter.MaxPrice != nil && p.Price > *filter.MaxPrice:
+		case filter.InStock != nil && *filter.InStock != (p.Stock > 0):
+		case search != "" && !strings.Contains(strings.ToLower(p.Name), search):
+		default:
+			matched = append(matched, repo.withCategory(p))
+		}
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		a, b := matched[i], matched[j]
+		desc := strings.HasPrefix(filter.Sort, "-")
+		switch strings.TrimPrefix(filter.Sort, "-") {
+		case "id":
+			if desc {
+				return a.ID > b.ID
+			}
+		case "name":
+			if an, bn := strings.ToLower(a.Name), strings.ToLower(b.Name); an != bn {
+				return (an < bn) != desc
+			}
+		case "price":
+			if a.Price != b.Price {
+				return (a.Price < b.Price) != desc
+			}
+		case "stock":
+			if a.Stock != b.Stock {
+				return (a.Stock < b.Stock) != desc
+			}
+		}
+		return a.ID < b.ID
+	})
+	return paginate(matched, filter.Page, filter.Limit), len(matched), nil
+}
+
+func (repo *ProductRepository) GetByID(id int) (*models.Product, error) {
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	p, ok := repo.store.products[id]
+	if !ok {
+		return nil, repositories.NewError(repositories.ErrNotFound, "produk tidak ditemukan")
+	}
+	p = repo.withVariants(repo.withCategory(p))
+	return &p, nil
+}
+
+// GetByCode - produk berdasarkan barcode, atau SKU jika tidak ada barcode yang cocok
+func (repo *ProductRepository) GetByCode(code string) (*models.Product, error) {
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	var bySKU *models.Product
+	for _, p := range repo.store.products {
+		if slices.Contains(p.Barcodes, code) {
+			p = repo.withVariants(repo.withCategory(p))
+			return &p, nil
+		}
+		if p.SKU != "" && p.SKU == code {
+			bySKU = &p
+		}
+	}
+	if bySKU == nil {
+		return nil, repositories.NewError(repositories.ErrNotFound, "produk tidak ditemukan")
+	}
+	p := repo.withVariants(repo.withCategory(*bySKU))
+	return &p, nil
+}
+
+// Create - simpan produk baru; product.Stock menjadi stok awal dan product.Cost HPP awal
+func (repo *ProductRepository) Create(product *models.Product) error {
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	product.ID, product.ParentID, product.PriceOverride, product.Options = 0, 0, false, nil
+	if err := repo.check(*product); err != nil {
+		return err
+	}
+	product.ID = repo.store.newID("products")
+	repo.save(*product)
+	return nil
+}
+
+// Update - ubah data produk; stok dan HPP tetap dan diisi ke product. Perubahan produk
+// induk diteruskan ke variannya, varian sendiri diubah lewat UpdateVariant.
+func (repo *ProductRepository) Update(product *models.Product) error {
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	existing, ok := repo.store.products[product.ID]
+	if !ok {
+		return repositories.NewError(repositories.ErrNotFound, "produk tidak ditemukan")
+	}
+	if existing.ParentID != 0 {
+		return repositories.NewError(repositories.ErrConflict, msgVariantUpdate)
+	}
+	if err := repo.check(*product); err != nil {
+		return err
+	}
+	product.Stock, product.Cost = existing.Stock, existing.Cost
+	product.ParentID, product.PriceOverride, product.Options = 0, false, nil
+	repo.save(*product)
+	repo.syncVariants(product.ID)
+	return nil
+}
+
+// Delete - hapus produk beserta atributnya; produk induk yang masih punya varian dan
+// produk yang sudah punya riwayat stok ditolak
+func (repo *ProductRepository) Delete(id int) error {
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	p, ok := repo.store.products[id]
+	if !ok {
+		return repositories.NewError(repositories.ErrNotFound, "produk tidak ditemukan")
+	}
+	if len(repo.variants(id)) > 0 {
+		return repositories.NewError(repositories.ErrConflict, msgHasVariants)
+	}
+	// Tanpa ledger stok, stok awal yang tidak nol adalah satu-satunya riwayat stok
+	if p.Stock != 0 {
+		return repositories.NewError(repositories.ErrConflict, msgHasMovements)
+	}
+	delete(repo.store.products, id)
+	delete(repo.store.attributes, id)
+	return nil
+}
+
+// Export - semua produk urut ID beserta nama kategori dan barcode
+func (repo *ProductRepository) Export() ([]models.Product, error) {
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	products := make([]models.Product, 0, len(repo.store.products))
+	for _, p := range repo.store.products {
+		products = append(products, repo.withCategory(p))
+	}
+	sort.Slice(products, func(i, j int) bool { return products[i].ID < products[j].ID })
+	return products, nil
+}
+
+// Import - upsert produk berdasarkan SKU dengan aturan yang sama seperti versi
+// PostgreSQL. Perubahan dikembalikan ke kondisi awal jika dry run atau ada baris gagal.
+func (repo *ProductRepository) Import(req *models.ProductImportRequest, rows []models.ProductImportRow) ([]string, error) {
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	products := maps.Clone(repo.store.products)
+	categories := maps.Clone(repo.store.categories)
+
+	categoryIDs := make(map[string]int)
+	for _, c := range sortedCategories(categories) {
+		if _, ok := categoryIDs[strings.ToLower(c.Name)]; !ok {
+			categoryIDs[strings.ToLower(c.Name)] = c.ID
+		}
+	}
+
+	created := make([]string, 0)
+	failed := false
+	for i := range rows {
+		row := &rows[i]
+		if len(row.Errors) > 0 {
+			failed = true
+			continue
+		}
+
+		if req.Columns["category"] && row.Category != "" {
+			key := strings.ToLower(row.Category)
+			id, ok := categoryIDs[key]
+			if !ok && req.CreateCategories {
+				id = repo.store.newID("categories")
+				repo.store.categories[id] = models.Category{ID: id, Name: row.Category}
+				categoryIDs[key] = id
+				created = append(created, row.Category)
+			} else if !ok {
+				row.Action = models.ImportError
+				row.Errors = append(row.Errors, fmt.Sprintf("category: kategori %q tidak ditemukan", row.Category))
+				failed = true
+				continue
+			}
+			row.Product.CategoryID = id
+		}
+
+		if err := repo.importProduct(req, row); err != nil {
+			row.Action = models.ImportError
+			row.Errors = append(row.Errors, err.Error())
+			failed = true
+		}
+	}
+
+	if failed || req.DryRun {
+		repo.store.products = products
+		repo.store.categories = categories
+	}
+	return created, nil
+}
+
+func (repo *ProductRepository) importProduct(req *models.ProductImportRequest, row *models.ProductImportRow) error {
+	p := row.Product
+	var existing *models.Product
+	for _, candidate := range repo.store.products {
+		if candidate.SKU == p.SKU {
+			existing = &candidate
+			break
+		}
+	}
+
+	if existing == nil {
+		p.ParentID, p.PriceOverride, p.Options = 0, false, nil
+		if err := repo.check(p); err != nil {
+			return err
+		}
+		p.ID = repo.store.newID("products")
+		repo.save(p)
+		row.Action, row.ProductID = models.ImportCreate, p.ID
+		return nil
+	}
+
+	if existing.ParentID != 0 {
+		return repositories.NewError(repositories.ErrConflict, msgVariantUpdate)
+	}
+	updated := *existing
+	updated.Name, updated.Price = p.Name, p.Price
+	if req.Columns["category"] {
+		updated.CategoryID = p.CategoryID
+	}
+	if req.Columns["tax_rate_id"] {
+		updated.TaxRateID = p.TaxRateID
+	}
+	if req.Columns["barcodes"] {
+		updated.Barcodes = p.Barcodes
+	}
+	if err := repo.check(updated); err != nil {
+		return err
+	}
+	repo.save(updated)
+	repo.syncVariants(updated.ID)
+	row.Action, row.ProductID = models.ImportUpdate, updated.ID
+	return nil
+}
+
+// check - SKU dan barcode unik serta kategori dan tarif pajak yang dirujuk ada
+func (repo *ProductRepository) check(product models.Product) error {
+	for _, p := range repo.store.products {
+		if p.ID == product.ID {
+			continue
+		}
+		if product.SKU != "" && p.SKU == product.SKU {
+			return repositories.NewError(repositories.ErrConflict, msgDuplicate)
+		}
+		for _, code := range product.Barcodes {
+			if slices.Contains(p.Barcodes, code) {
+				return repositories.NewError(repositories.ErrConflict, msgDuplicate)
+			}
+		}
+	}
+	return repo.store.checkRefs(product.CategoryID, product.TaxRateID)
+}
+
+// save - simpan salinan produk tanpa nama kategori; nama diambil saat dibaca. Pilihan
+// atribut varian ikut disimpan, atribut dan daftar varian disimpan terpisah.
+func (repo *ProductRepository) save(product models.Product) {
+	product.CategoryName = ""
+	product.Options = maps.Clone(product.Options)
+	product.Attributes, product.Variants = nil, nil
+	product.Barcodes = slices.Clone(product.Barcodes)
+	if product.Barcodes == nil {
+		product.Barcodes = make([]string, 0)
+	}
+	repo.store.products[product.ID] = product
+}
+
+// withCategory - salinan produk dengan nama kategori, seperti LEFT JOIN categories.
+// Pilihan atribut varian hanya diisi withVariants.
+func (repo *ProductRepository) withCategory(p models.Product) models.Product {
+	p.CategoryName = repo.store.categories[p.CategoryID].Name
+	p.Options = nil
+	p.Barcodes = slices.Clone(p.Barcodes)
+	sort.Strings(p.Barcodes)
+	return p
+}
+
+func sortedCategories(categories map[int]models.Category) []models.Category {
+	result := slices.Collect(maps.Values(categories))
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
@@ -0,0 +1,72 @@
+// Package memory berisi implementasi repository katalog dan user yang menyimpan data
+// di memori. Dipakai untuk menjalankan API tanpa database (STORAGE=memory) dan untuk
+// menguji service tanpa PostgreSQL. Pesan error mengikuti repository PostgreSQL.
+package memory
+
+import (
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"sync"
+	"time"
+)
+
+// Pesan error yang sama dengan hasil mapDBError untuk pelanggaran constraint
+const (
+	msgDuplicate  = "data dengan nilai yang sama sudah ada"
+	msgForeignKey = "data terkait tidak ditemukan atau masih digunakan"
+)
+
+// Store menyimpan semua tabel. Repository yang dibuat dari Store yang sama berbagi data
+// dan satu mutex, sehingga relasi antar tabel (nama kategori, ON DELETE SET NULL) tetap
+// konsisten dan aman dipakai dari banyak goroutine.
+type Store struct {
+	mu         sync.RWMutex
+	categories map[int]models.Category
+	products   map[int]models.Product
+	attributes map[int][]models.ProductAttribute
+	taxRates   map[int]models.TaxRate
+	users      map[int]models.User
+	nextID     map[string]int
+}
+
+// NewStore - store kosong dengan tarif pajak awal yang sama seperti migrasi database
+func NewStore() *Store {
+	s := &Store{
+		categories: make(map[int]models.Category),
+		products:   make(map[int]models.Product),
+		attributes: make(map[int][]models.ProductAttribute),
+		taxRates:   make(map[int]models.TaxRate),
+		users:      make(map[int]models.User),
+		nextID:     make(map[string]int),
+	}
+	for _, r := range []models.TaxRate{{Name: "PPN", RateBps: 1100, IsDefault: true}, {Name: "Bebas PPN"}} {
+		r.ID = s.newID("tax_rates")
+		r.CreatedAt = time.Now()
+		s.taxRates[r.ID] = r
+	}
+	return s
+}
+
+// newID - ID berikutnya untuk tabel, seperti SERIAL (ID tidak dipakai ulang)
+func (s *Store) newID(table string) int {
+	s.nextID[table]++
+	return s.nextID[table]
+}
+
+// checkRefs - kategori dan tarif pajak yang dirujuk harus ada, seperti foreign key
+func (s *Store) checkRefs(categoryID, taxRateID int) error {
+	if _, ok := s.categories[categoryID]; categoryID != 0 && !ok {
+		return repositories.NewError(repositories.ErrForeignKey, msgForeignKey)
+	}
+	if _, ok := s.taxRates[taxRateID]; taxRateID != 0 && !ok {
+		return repositories.NewError(repositories.ErrForeignKey, msgForeignKey)
+	}
+	return nil
+}
+
+var (
+	_ repositories.ProductRepositoryInterface  = (*ProductRepository)(nil)
+	_ repositories.CategoryRepositoryInterface = (*CategoryRepository)(nil)
+	_ repositories.TaxRateRepositoryInterface  = (*TaxRateRepository)(nil)
+	_ repositories.UserRepositoryInterface     = (*UserRepository)(nil)
+)
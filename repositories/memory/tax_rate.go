@@ -0,0 +1,103 @@
+package memory
+
+import (
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"sort"
+	"time"
+)
+
+type TaxRateRepository struct {
+	store *Store
+}
+
+func NewTaxRateRepository(store *Store) *TaxRateRepository {
+	return &TaxRateRepository{store: store}
+}
+
+func (repo *TaxRateRepository) GetAll() ([]models.TaxRate, error) {
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	rates := make([]models.TaxRate, 0, len(repo.store.taxRates))
+	for _, r := range repo.store.taxRates {
+		rates = append(rates, r)
+	}
+	sort.Slice(rates, func(i, j int) bool { return rates[i].ID < rates[j].ID })
+	return rates, nil
+}
+
+func (repo *TaxRateRepository) GetByID(id int) (*models.TaxRate, error) {
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	r, ok := repo.store.taxRates[id]
+	if !ok {
+		return nil, repositories.NewError(repositories.ErrNotFound, "tarif pajak tidak ditemukan")
+	}
+	return &r, nil
+}
+
+// Create - simpan tarif baru; jika default, tarif default sebelumnya dilepas
+func (repo *TaxRateRepository) Create(rate *models.TaxRate) error {
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	if rate.IsDefault {
+		repo.clearDefault(0)
+	}
+	rate.ID = repo.store.newID("tax_rates")
+	rate.CreatedAt = time.Now()
+	repo.store.taxRates[rate.ID] = *rate
+	return nil
+}
+
+// Update - ubah tarif; jika dijadikan default, tarif default lain dilepas
+func (repo *TaxRateRepository) Update(rate *models.TaxRate) error {
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	existing, ok := repo.store.taxRates[rate.ID]
+	if !ok {
+		return repositories.NewError(repositories.ErrNotFound, "tarif pajak tidak ditemukan")
+	}
+	if rate.IsDefault {
+		repo.clearDefault(rate.ID)
+	}
+	rate.CreatedAt = existing.CreatedAt
+	repo.store.taxRates[rate.ID] = *rate
+	return nil
+}
+
+// Delete - hapus tarif; produk dan kategori yang memakainya kembali ke tarif default
+func (repo *TaxRateRepository) Delete(id int) error {
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	if _, ok := repo.store.taxRates[id]; !ok {
+		return repositories.NewError(repositories.ErrNotFound, "tarif pajak tidak ditemukan")
+	}
+	delete(repo.store.taxRates, id)
+	for cid, c := range repo.store.categories {
+		if c.TaxRateID == id {
+			c.TaxRateID = 0
+			repo.store.categories[cid] = c
+		}
+	}
+	for pid, p := range repo.store.products {
+		if p.TaxRateID == id {
+			p.TaxRateID = 0
+			repo.store.products[pid] = p
+		}
+	}
+	return nil
+}
+
+func (repo *TaxRateRepository) clearDefault(except int) {
+	for id, r := range repo.store.taxRates {
+		if r.IsDefault && id != except {
+			r.IsDefault = false
+			repo.store.taxRates[id] = r
+		}
+	}
+}
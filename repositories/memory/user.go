@@ -0,0 +1,65 @@
+package memory
+
+import (
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"sort"
+	"time"
+)
+
+type UserRepository struct {
+	store *Store
+}
+
+func NewUserRepository(store *Store) *UserRepository {
+	return &UserRepository{store: store}
+}
+
+// GetAll - semua user tanpa hash password, seperti versi PostgreSQL
+func (repo *UserRepository) GetAll() ([]models.User, error) {
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	users := make([]models.User, 0, len(repo.store.users))
+	for _, u := range repo.store.users {
+		u.PasswordHash = ""
+		users = append(users, u)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	return users, nil
+}
+
+func (repo *UserRepository) Create(user *models.User) error {
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	for _, u := range repo.store.users {
+		if u.Username == user.Username {
+			return repositories.NewError(repositories.ErrConflict, msgDuplicate)
+		}
+	}
+	user.ID = repo.store.newID("users")
+	user.CreatedAt = time.Now()
+	stored := *user
+	stored.Password = ""
+	repo.store.users[user.ID] = stored
+	return nil
+}
+
+func (repo *UserRepository) GetByUsername(username string) (*models.User, error) {
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	for _, u := range repo.store.users {
+		if u.Username == username {
+			return &u, nil
+		}
+	}
+	return nil, repositories.NewError(repositories.ErrNotFound, "user tidak ditemukan")
+}
+
+func (repo *UserRepository) Count() (int, error) {
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+	return len(repo.store.users), nil
+}
@@ -0,0 +1,176 @@
+package memory
+
+import (
+	"fmt"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"maps"
+	"slices"
+	"sort"
+)
+
+// Pesan error yang sama dengan repository PostgreSQL untuk varian produk
+const (
+	msgVariantUpdate = "produk ini varian, ubah lewat endpoint varian produk induk"
+	msgHasVariants   = "produk masih punya varian"
+	msgHasMovements  = "produk sudah punya riwayat stok dan tidak bisa dihapus"
+)
+
+// SetAttributes - ganti atribut produk induk dengan aturan yang sama seperti versi
+// PostgreSQL: selama produk punya varian atribut tidak bisa ditambah atau dihapus dan
+// pilihan yang dipakai varian tidak bisa dihapus
+func (repo *ProductRepository) SetAttributes(productID int, attributes []models.ProductAttribute) error {
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	p, ok := repo.store.products[productID]
+	if !ok {
+		return repositories.NewError(repositories.ErrNotFound, "produk tidak ditemukan")
+	}
+	if p.ParentID != 0 {
+		return repositories.NewError(repositories.ErrValidation, "varian tidak bisa punya atribut")
+	}
+
+	variants := repo.variants(productID)
+	if len(variants) > 0 {
+		existing := repo.store.attributes[productID]
+		same := len(attributes) == len(existing)
+		for _, a := range attributes {
+			same = same && slices.ContainsFunc(existing, func(e models.ProductAttribute) bool { return e.Name == a.Name })
+		}
+		if !same {
+			return repositories.NewError(repositories.ErrConflict, "atribut tidak bisa ditambah atau dihapus selama produk punya varian")
+		}
+		for _, a := range attributes {
+			for _, v := range variants {
+				if value := v.Options[a.Name]; !slices.Contains(a.Options, value) {
+					return repositories.NewError(repositories.ErrConflict, fmt.Sprintf("pilihan %s %s masih dipakai varian", a.Name, value))
+				}
+			}
+		}
+	}
+
+	saved := make([]models.ProductAttribute, len(attributes))
+	for i, a := range attributes {
+		saved[i] = models.ProductAttribute{Name: a.Name, Options: slices.Clone(a.Options)}
+	}
+	repo.store.attributes[productID] = saved
+	repo.syncVariants(productID)
+	return nil
+}
+
+// CreateVariant - buat varian di bawah produk induk; nama, kategori, tarif pajak dan
+// harga (kecuali PriceOverride) mengikuti produk induk
+func (repo *ProductRepository) CreateVariant(parentID int, variant *models.Product) error {
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	parent, ok := repo.store.products[parentID]
+	if !ok {
+		return repositories.NewError(repositories.ErrNotFound, "produk tidak ditemukan")
+	}
+	if parent.ParentID != 0 {
+		return repositories.NewError(repositories.ErrValidation, "varian tidak bisa punya varian")
+	}
+	if parent.Stock != 0 {
+		return repositories.NewError(repositories.ErrConflict, "stok produk induk harus 0 sebelum punya varian")
+	}
+
+	attributes := repo.store.attributes[parentID]
+	if err := repositories.CheckVariantOptions(attributes, variant.Options); err != nil {
+		return err
+	}
+	for _, v := range repo.variants(parentID) {
+		if maps.Equal(v.Options, variant.Options) {
+			return repositories.NewError(repositories.ErrConflict, "varian dengan pilihan yang sama sudah ada")
+		}
+	}
+
+	variant.ID, variant.ParentID = 0, parentID
+	variant.Name = models.VariantName(parent.Name, attributes, variant.Options)
+	variant.CategoryID, variant.TaxRateID = parent.CategoryID, parent.TaxRateID
+	if !variant.PriceOverride {
+		variant.Price = parent.Price
+	}
+	if err := repo.check(*variant); err != nil {
+		return err
+	}
+	variant.ID = repo.store.newID("products")
+	repo.save(*variant)
+	return nil
+}
+
+// UpdateVariant - ubah SKU, barcode dan harga varian
+func (repo *ProductRepository) UpdateVariant(variant *models.Product) error {
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	existing, ok := repo.store.products[variant.ID]
+	if !ok || existing.ParentID == 0 || existing.ParentID != variant.ParentID {
+		return repositories.NewError(repositories.ErrNotFound, "varian tidak ditemukan")
+	}
+	if !variant.PriceOverride {
+		variant.Price = repo.store.products[existing.ParentID].Price
+	}
+
+	updated := existing
+	updated.SKU, updated.Barcodes = variant.SKU, variant.Barcodes
+	updated.Price, updated.PriceOverride = variant.Price, variant.PriceOverride
+	if err := repo.check(updated); err != nil {
+		return err
+	}
+	repo.save(updated)
+	return nil
+}
+
+// withVariants - salinan produk dengan atribut dan varian jika produk induk, atau
+// pilihan atributnya jika varian
+func (repo *ProductRepository) withVariants(p models.Product) models.Product {
+	if p.ParentID != 0 {
+		p.Options = maps.Clone(repo.store.products[p.ID].Options)
+		return p
+	}
+
+	attributes := repo.store.attributes[p.ID]
+	if len(attributes) == 0 {
+		return p
+	}
+	p.Attributes = make([]models.ProductAttribute, len(attributes))
+	for i, a := range attributes {
+		p.Attributes[i] = models.ProductAttribute{Name: a.Name, Options: slices.Clone(a.Options)}
+	}
+	p.Variants = make([]models.Product, 0)
+	for _, v := range repo.variants(p.ID) {
+		options := v.Options
+		v = repo.withCategory(v)
+		v.Options = maps.Clone(options)
+		p.Variants = append(p.Variants, v)
+	}
+	return p
+}
+
+// variants - varian produk induk urut ID
+func (repo *ProductRepository) variants(parentID int) []models.Product {
+	result := make([]models.Product, 0)
+	for _, p := range repo.store.products {
+		if p.ParentID == parentID && parentID != 0 {
+			result = append(result, p)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+// syncVariants - teruskan kategori, tarif pajak, harga dan nama produk induk ke variannya
+func (repo *ProductRepository) syncVariants(parentID int) {
+	parent := repo.store.products[parentID]
+	attributes := repo.store.attributes[parentID]
+	for _, v := range repo.variants(parentID) {
+		v.CategoryID, v.TaxRateID = parent.CategoryID, parent.TaxRateID
+		if !v.PriceOverride {
+			v.Price = parent.Price
+		}
+		v.Name = models.VariantName(parent.Name, attributes, v.Options)
+		repo.store.products[v.ID] = v
+	}
+}
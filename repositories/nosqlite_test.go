@@ -0,0 +1,5 @@
+//go:build !sqlite
+
+package repositories_test
+
+const sqliteBuilt = false
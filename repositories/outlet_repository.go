@@ -0,0 +1,206 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"kasir-api/models"
+	"strings"
+)
+
+type OutletRepository struct {
+	db *sql.DB
+}
+
+func NewOutletRepository(db *sql.DB) *OutletRepository {
+	return &OutletRepository{db: db}
+}
+
+const outletColumns = "id, code, name, type, address, is_default, active, created_at"
+
+func scanOutlet(row rowScanner) (models.Outlet, error) {
+	var o models.Outlet
+	err := row.Scan(&o.ID, &o.Code, &o.Name, &o.Type, &o.Address, &o.IsDefault, &o.Active, &o.CreatedAt)
+	return o, err
+}
+
+// GetAll - cari outlet berdasarkan kode atau nama, outlet default lebih dulu lalu urut nama
+func (repo *OutletRepository) GetAll(filter models.OutletFilter) ([]models.Outlet, int, error) {
+	conditions := make([]string, 0, 2)
+	args := make([]interface{}, 0, 4)
+	if filter.Search != "" {
+		args = append(args, "%"+filter.Search+"%")
+		conditions = append(conditions, fmt.Sprintf("(code ILIKE $%d OR name ILIKE $%d)", len(args), len(args)))
+	}
+	if filter.Active != nil {
+		args = append(args, *filter.Active)
+		conditions = append(conditions, fmt.Sprintf("active = $%d", len(args)))
+	}
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	err := repo.db.QueryRow("SELECT COUNT(*) FROM outlets "+whereClause, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, filter.Limit, (filter.Page-1)*filter.Limit)
+	query := fmt.Sprintf("SELECT %s FROM outlets %s ORDER BY is_default DESC, name, id LIMIT $%d OFFSET $%d",
+		outletColumns, whereClause, len(args)-1, len(args))
+	rows, err := repo.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	outlets := make([]models.Outlet, 0)
+	for rows.Next() {
+		o, err := scanOutlet(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		outlets = append(outlets, o)
+	}
+	return outlets, total, rows.Err()
+}
+
+func (repo *OutletRepository) GetByID(id int) (*models.Outlet, error) {
+	o, err := scanOutlet(repo.db.QueryRow("SELECT "+outletColumns+" FROM outlets WHERE id = $1", id))
+	if err == sql.ErrNoRows {
+		return nil, NewError(ErrNotFound, "outlet tidak ditemukan")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+// Create - simpan outlet baru. Jika outlet ini dijadikan default, outlet default
+// sebelumnya dilepas dalam transaksi yang sama.
+func (repo *OutletRepository) Create(outlet *models.Outlet) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if outlet.IsDefault {
+		if _, err := tx.Exec("UPDATE outlets SET is_default = FALSE WHERE is_default"); err != nil {
+			return err
+		}
+	}
+	err = tx.QueryRow(
+		"INSERT INTO outlets (code, name, type, address, is_default, active) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at",
+		outlet.Code, outlet.Name, outlet.Type, outlet.Address, outlet.IsDefault, outlet.Active,
+	).Scan(&outlet.ID, &outlet.CreatedAt)
+	if err != nil {
+		return mapDBError(err)
+	}
+	return tx.Commit()
+}
+
+// Update - ubah data outlet. Outlet default hanya bisa dilepas dengan menjadikan
+// outlet lain default, supaya selalu ada tepat satu outlet default.
+func (repo *OutletRepository) Update(outlet *models.Outlet) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var wasDefault bool
+	err = tx.QueryRow("SELECT is_default FROM outlets WHERE id = $1 FOR UPDATE", outlet.ID).Scan(&wasDefault)
+	if err == sql.ErrNoRows {
+		return NewError(ErrNotFound, "outlet tidak ditemukan")
+	}
+	if err != nil {
+		return err
+	}
+	if wasDefault && !outlet.IsDefault {
+		return NewError(ErrConflict, "jadikan outlet lain default terlebih dahulu")
+	}
+
+	if outlet.IsDefault && !wasDefault {
+		if _, err := tx.Exec("UPDATE outlets SET is_default = FALSE WHERE is_default"); err != nil {
+			return err
+		}
+	}
+	err = tx.QueryRow(
+		"UPDATE outlets SET code = $1, name = $2, type = $3, address = $4, is_default = $5, active = $6 WHERE id = $7 RETURNING created_at",
+		outlet.Code, outlet.Name, outlet.Type, outlet.Address, outlet.IsDefault, outlet.Active, outlet.ID,
+	).Scan(&outlet.CreatedAt)
+	if err != nil {
+		return mapDBError(err)
+	}
+	return tx.Commit()
+}
+
+// Delete - hapus outlet yang bukan default dan belum pernah punya stok, transaksi
+// atau transfer
+func (repo *OutletRepository) Delete(id int) error {
+	var isDefault bool
+	err := repo.db.QueryRow("SELECT is_default FROM outlets WHERE id = $1", id).Scan(&isDefault)
+	if err == sql.ErrNoRows {
+		return NewError(ErrNotFound, "outlet tidak ditemukan")
+	}
+	if err != nil {
+		return err
+	}
+	if isDefault {
+		return NewError(ErrConflict, "outlet default tidak bisa dihapus")
+	}
+
+	result, err := repo.db.Exec("DELETE FROM outlets WHERE id = $1", id)
+	if err != nil {
+		return mapDBError(err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return NewError(ErrNotFound, "outlet tidak ditemukan")
+	}
+	return nil
+}
+
+// defaultOutletID - outlet tujuan pergerakan stok yang tidak menyebut outlet
+func defaultOutletID(q queryer) (int, error) {
+	var id int
+	err := q.QueryRow("SELECT id FROM outlets WHERE is_default").Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, NewError(ErrConflict, "belum ada outlet default")
+	}
+	return id, err
+}
+
+// activeOutlet - outlet untuk checkout atau transfer; 0 berarti outlet default.
+// Outlet yang tidak ada atau sudah nonaktif ditolak.
+func activeOutlet(q queryer, id int) (int, error) {
+	if id == 0 {
+		return defaultOutletID(q)
+	}
+	var active bool
+	var name string
+	err := q.QueryRow("SELECT active, name FROM outlets WHERE id = $1", id).Scan(&active, &name)
+	if err == sql.ErrNoRows {
+		return 0, NewError(ErrValidation, fmt.Sprintf("outlet id %d tidak ditemukan", id))
+	}
+	if err != nil {
+		return 0, err
+	}
+	if !active {
+		return 0, NewError(ErrConflict, fmt.Sprintf("outlet %s tidak aktif", name))
+	}
+	return id, nil
+}
+
+// outletStock - stok produk di sebuah outlet. Baris outlet_stocks baru ada setelah
+// movement pertama, sehingga produk yang belum pernah ada di outlet berstok 0.
+func outletStock(q queryer, outletID, productID int) (int, error) {
+	var stock int
+	err := q.QueryRow("SELECT COALESCE(SUM(stock), 0) FROM outlet_stocks WHERE outlet_id = $1 AND product_id = $2", outletID, productID).Scan(&stock)
+	return stock, err
+}
@@ -0,0 +1,236 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"kasir-api/models"
+	"time"
+)
+
+type PaymentRepository struct {
+	db *sql.DB
+}
+
+func NewPaymentRepository(db *sql.DB) *PaymentRepository {
+	return &PaymentRepository{db: db}
+}
+
+// queryer dipenuhi *sql.DB dan *sql.Tx
+type queryer interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+func getPayments(q queryer, condition string, args ...interface{}) ([]models.Payment, error) {
+	query := `
+		SELECT id, transaction_id, method, amount, reference, status, provider, COALESCE(provider_ref, ''),
+			payload, expires_at, paid_at, created_at
+		FROM payments
+		WHERE ` + condition + `
+		ORDER BY id
+	`
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	payments := make([]models.Payment, 0)
+	for rows.Next() {
+		var p models.Payment
+		var expiresAt, paidAt sql.NullTime
+		err := rows.Scan(&p.ID, &p.TransactionID, &p.Method, &p.Amount, &p.Reference, &p.Status, &p.Provider,
+			&p.ProviderRef, &p.Payload, &expiresAt, &paidAt, &p.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if expiresAt.Valid {
+			p.ExpiresAt = &expiresAt.Time
+		}
+		if paidAt.Valid {
+			p.PaidAt = &paidAt.Time
+		}
+		payments = append(payments, p)
+	}
+	return payments, rows.Err()
+}
+
+func getPayment(q queryer, condition string, args ...interface{}) (*models.Payment, error) {
+	payments, err := getPayments(q, condition, args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(payments) == 0 {
+		return nil, NewError(ErrNotFound, "pembayaran tidak ditemukan")
+	}
+	return &payments[0], nil
+}
+
+func (repo *PaymentRepository) GetByID(id int) (*models.Payment, error) {
+	return getPayment(repo.db, "id = $1", id)
+}
+
+// GetByProviderRef - cari pembayaran dari referensi tagihan provider
+func (repo *PaymentRepository) GetByProviderRef(provider, providerRef string) (*models.Payment, error) {
+	return getPayment(repo.db, "provider = $1 AND provider_ref = $2", provider, providerRef)
+}
+
+// SetCharge - simpan tagihan yang dibuat provider untuk pembayaran pending
+func (repo *PaymentRepository) SetCharge(payment *models.Payment) error {
+	query := `
+		UPDATE payments SET provider = $1, provider_ref = $2, payload = $3, expires_at = $4
+		WHERE id = $5 AND status = 'pending'
+	`
+	result, err := repo.db.Exec(query, payment.Provider, payment.ProviderRef, payment.Payload, payment.ExpiresAt, payment.ID)
+	if err != nil {
+		return mapDBError(err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return NewError(ErrConflict, "pembayaran tidak lagi menunggu")
+	}
+	return nil
+}
+
+// UpdateStatus - ubah status pembayaran pending dan sesuaikan transaksinya dalam satu
+// database transaction. Jika semua pembayaran lunas, transaksi menjadi paid. Jika
+// pembayaran expired atau failed, transaksi dibatalkan, pembayaran pending lainnya ikut
+// ditutup, stok dikembalikan lewat ledger dan poin yang dipakai dikembalikan. Status
+// yang sama diabaikan supaya callback yang dikirim ulang aman. Poin belanja dikreditkan
+// saat transaksi menjadi paid.
+func (repo *PaymentRepository) UpdateStatus(paymentID int, status string) (*models.Payment, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var locked int
+	err = tx.QueryRow("SELECT id FROM payments WHERE id = $1 FOR UPDATE", paymentID).Scan(&locked)
+	if err == sql.ErrNoRows {
+		return nil, NewError(ErrNotFound, "pembayaran tidak ditemukan")
+	}
+	if err != nil {
+		return nil, err
+	}
+	payment, err := getPayment(tx, "id = $1", paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if payment.Status == status {
+		return payment, nil
+	}
+	if payment.Status != models.PaymentPending {
+		return nil, NewError(ErrConflict, fmt.Sprintf("pembayaran sudah %s", payment.Status))
+	}
+
+	var transactionStatus, cashier string
+	err = tx.QueryRow("SELECT status, cashier FROM transactions WHERE id = $1 FOR UPDATE", payment.TransactionID).
+		Scan(&transactionStatus, &cashier)
+	if err != nil {
+		return nil, err
+	}
+
+	if status == models.PaymentPaid {
+		err = tx.QueryRow(
+			"UPDATE payments SET status = $1, paid_at = NOW() WHERE id = $2 RETURNING paid_at",
+			status, payment.ID,
+		).Scan(&payment.PaidAt)
+		if err != nil {
+			return nil, err
+		}
+
+		var unpaid int
+		err = tx.QueryRow("SELECT COUNT(*) FROM payments WHERE transaction_id = $1 AND status <> 'paid'", payment.TransactionID).Scan(&unpaid)
+		if err != nil {
+			return nil, err
+		}
+		if unpaid == 0 && transactionStatus == models.TransactionPending {
+			if _, err := tx.Exec("UPDATE transactions SET status = $1 WHERE id = $2", models.TransactionPaid, payment.TransactionID); err != nil {
+				return nil, err
+			}
+			if err := creditEarnedPoints(tx, payment.TransactionID); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		_, err = tx.Exec("UPDATE payments SET status = $1 WHERE transaction_id = $2 AND status = 'pending'", status, payment.TransactionID)
+		if err != nil {
+			return nil, err
+		}
+		if transactionStatus != models.TransactionCancelled {
+			reason := fmt.Sprintf("pembatalan transaksi #%d: pembayaran %s %s", payment.TransactionID, payment.Method, status)
+			if err := cancelTransaction(tx, payment.TransactionID, cashier, reason); err != nil {
+				return nil, err
+			}
+		}
+	}
+	payment.Status = status
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+// cancelTransaction - tandai transaksi batal, kembalikan stok yang sudah terjual ke
+// outlet transaksi dan poin yang dipakai membayar
+func cancelTransaction(tx *sql.Tx, transactionID int, user, reason string) error {
+	var outletID int
+	err := tx.QueryRow("UPDATE transactions SET status = $1 WHERE id = $2 RETURNING outlet_id", models.TransactionCancelled, transactionID).Scan(&outletID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := tx.Query(`
+		SELECT product_id, SUM(quantity) FROM transaction_details
+		WHERE transaction_id = $1
+		GROUP BY product_id
+		ORDER BY product_id`, transactionID)
+	if err != nil {
+		return err
+	}
+	quantities := make(map[int]int)
+	productIDs := make([]int, 0)
+	for rows.Next() {
+		var productID, quantity int
+		if err := rows.Scan(&productID, &quantity); err != nil {
+			rows.Close()
+			return err
+		}
+		quantities[productID] = quantity
+		productIDs = append(productIDs, productID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// Produk dikunci berurutan berdasarkan ID, sama seperti checkout
+	for _, productID := range productIDs {
+		if _, err := tx.Exec("SELECT 1 FROM products WHERE id = $1 FOR UPDATE", productID); err != nil {
+			return err
+		}
+		err := applyMovement(tx, &models.StockMovement{
+			ProductID:   productID,
+			OutletID:    outletID,
+			Type:        models.MovementReturn,
+			Quantity:    quantities[productID],
+			Reason:      reason,
+			User:        user,
+			ReferenceID: &transactionID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return restoreRedeemedPoints(tx, transactionID, user, reason)
+}
+
+// ExpiresBefore - pembayaran pending yang tagihannya sudah lewat waktu
+func (repo *PaymentRepository) ExpiresBefore(at time.Time) ([]models.Payment, error) {
+	return getPayments(repo.db, "status = 'pending' AND expires_at < $1", at)
+}
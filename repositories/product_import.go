@@ -0,0 +1,212 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"kasir-api/models"
+	"strings"
+)
+
+// Export - semua produk untuk ekspor katalog, urut ID, beserta nama kategori dan barcode
+func (repo *ProductRepository) Export() ([]models.Product, error) {
+	rows, err := repo.db.Query(`
+		SELECT ` + productColumns + `
+		FROM products p
+		LEFT JOIN categories c ON p.category_id = c.id
+		ORDER BY p.id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := make([]models.Product, 0)
+	index := make(map[int]int)
+	for rows.Next() {
+		p, err := scanProduct(rows)
+		if err != nil {
+			return nil, err
+		}
+		p.Barcodes = make([]string, 0)
+		index[p.ID] = len(products)
+		products = append(products, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	barcodes, err := repo.db.Query("SELECT product_id, code FROM product_barcodes ORDER BY product_id, code")
+	if err != nil {
+		return nil, err
+	}
+	defer barcodes.Close()
+	for barcodes.Next() {
+		var productID int
+		var code string
+		if err := barcodes.Scan(&productID, &code); err != nil {
+			return nil, err
+		}
+		if i, ok := index[productID]; ok {
+			products[i].Barcodes = append(products[i].Barcodes, code)
+		}
+	}
+	return products, barcodes.Err()
+}
+
+// Import - upsert produk berdasarkan SKU dalam satu transaksi. Setiap baris dijalankan
+// di savepoint sendiri sehingga pelanggaran constraint (misalnya barcode milik produk
+// lain) menjadi error baris tersebut dan baris lain tetap diperiksa. Baris yang sudah
+// punya error dari validasi dilewati. Transaksi hanya di-commit jika bukan dry run dan
+// tidak ada baris yang gagal. Kembalian berisi nama kategori yang dibuat.
+func (repo *ProductRepository) Import(req *models.ProductImportRequest, rows []models.ProductImportRow) ([]string, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	categories, err := categoryIDsByName(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	created := make([]string, 0)
+	failed := false
+	for i := range rows {
+		row := &rows[i]
+		if len(row.Errors) > 0 {
+			failed = true
+			continue
+		}
+
+		if req.Columns["category"] && row.Category != "" {
+			key := strings.ToLower(row.Category)
+			id, ok := categories[key]
+			if !ok && req.CreateCategories {
+				if err := tx.QueryRow("INSERT INTO categories (name) VALUES ($1) RETURNING id", row.Category).Scan(&id); err != nil {
+					return nil, err
+				}
+				categories[key] = id
+				created = append(created, row.Category)
+			} else if !ok {
+				row.Action = models.ImportError
+				row.Errors = append(row.Errors, fmt.Sprintf("category: kategori %q tidak ditemukan", row.Category))
+				failed = true
+				continue
+			}
+			row.Product.CategoryID = id
+		}
+
+		if _, err := tx.Exec("SAVEPOINT import_row"); err != nil {
+			return nil, err
+		}
+		if err := importProduct(tx, req, row); err != nil {
+			var repoErr *Error
+			if !errors.As(err, &repoErr) {
+				return nil, err
+			}
+			if _, err := tx.Exec("ROLLBACK TO SAVEPOINT import_row"); err != nil {
+				return nil, err
+			}
+			row.Action = models.ImportError
+			row.Errors = append(row.Errors, repoErr.Message)
+			failed = true
+			continue
+		}
+		if _, err := tx.Exec("RELEASE SAVEPOINT import_row"); err != nil {
+			return nil, err
+		}
+	}
+
+	if failed || req.DryRun {
+		return created, nil
+	}
+	return created, tx.Commit()
+}
+
+// importProduct - buat produk baru atau ubah produk dengan SKU yang sama. Produk baru
+// mendapat stok awal lewat ledger dan HPP awal dari file; untuk produk yang sudah ada
+// stok dan HPP tidak diubah, sama seperti Update. Kategori, barcode dan tarif pajak
+// hanya diubah jika kolomnya ada di file. SKU milik varian ditolak.
+func importProduct(tx execQueryer, req *models.ProductImportRequest, row *models.ProductImportRow) error {
+	p := &row.Product
+	var parentID int
+	err := tx.QueryRow("SELECT id, COALESCE(parent_id, 0) FROM products WHERE sku = $1 FOR UPDATE", p.SKU).Scan(&p.ID, &parentID)
+	if err == sql.ErrNoRows {
+		err = tx.QueryRow(
+			"INSERT INTO products (sku, name, price, stock, cost, category_id, tax_rate_id) VALUES ($1, $2, $3, 0, $4, NULLIF($5, 0), NULLIF($6, 0)) RETURNING id",
+			p.SKU, p.Name, p.Price, p.Cost, p.CategoryID, p.TaxRateID,
+		).Scan(&p.ID)
+		if err != nil {
+			return mapDBError(err)
+		}
+		if err := replaceBarcodes(tx, p.ID, p.Barcodes); err != nil {
+			return mapDBError(err)
+		}
+		if p.Stock != 0 {
+			err = applyMovement(tx, &models.StockMovement{
+				ProductID: p.ID,
+				Type:      models.MovementAdjustment,
+				Quantity:  p.Stock,
+				Reason:    "stok awal (import)",
+				User:      req.User,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		row.Action, row.ProductID = models.ImportCreate, p.ID
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if parentID != 0 {
+		return NewError(ErrConflict, msgVariantUpdate)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE products SET name = $1, price = $2,
+			category_id = CASE WHEN $3 THEN NULLIF($4, 0) ELSE category_id END,
+			tax_rate_id = CASE WHEN $5 THEN NULLIF($6, 0) ELSE tax_rate_id END
+		WHERE id = $7`,
+		p.Name, p.Price, req.Columns["category"], p.CategoryID, req.Columns["tax_rate_id"], p.TaxRateID, p.ID,
+	)
+	if err != nil {
+		return mapDBError(err)
+	}
+	if req.Columns["barcodes"] {
+		if err := replaceBarcodes(tx, p.ID, p.Barcodes); err != nil {
+			return mapDBError(err)
+		}
+	}
+	if err := syncVariants(tx, p.ID); err != nil {
+		return err
+	}
+	row.Action, row.ProductID = models.ImportUpdate, p.ID
+	return nil
+}
+
+// categoryIDsByName - id kategori berdasarkan nama tanpa membedakan huruf besar/kecil.
+// Jika ada nama kembar dipakai kategori dengan ID terkecil.
+func categoryIDsByName(tx execQueryer) (map[string]int, error) {
+	rows, err := tx.Query("SELECT id, name FROM categories ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[string]int)
+	for rows.Next() {
+		var id int
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return nil, err
+		}
+		if _, ok := result[strings.ToLower(name)]; !ok {
+			result[strings.ToLower(name)] = id
+		}
+	}
+	return result, rows.Err()
+}
@@ -2,94 +2,280 @@ package repositories
 
 import (
 	"database/sql"
-	"errors"
+	"fmt"
 	"kasir-api/models"
+	"strings"
 )
 
 type ProductRepository struct {
-	db *sql.DB
+	db dialectDB
 }
 
 func NewProductRepository(db *sql.DB) *ProductRepository {
-	return &ProductRepository{db: db}
+	return &ProductRepository{db: newDialectDB(db)}
 }
 
-func (repo *ProductRepository) GetAll() ([]models.Product, error) {
-	query := `
-		SELECT p.id, p.name, p.price, p.stock, p.category_id, COALESCE(c.name, '') as category_name
+// productColumns - kolom produk dengan urutan yang dibaca scanProduct
+const productColumns = `p.id, COALESCE(p.sku, ''), p.name, p.price, p.stock, p.cost, COALESCE(p.category_id, 0), COALESCE(c.name, '') as category_name,
+	COALESCE(p.tax_rate_id, 0), COALESCE(p.parent_id, 0), p.price_override`
+
+// productIDByCode - ID produk untuk kode $1. Barcode didahulukan: SKU satu produk bisa
+// sama dengan barcode produk lain, dan barcode yang dipindai harus menang.
+const productIDByCode = `COALESCE((SELECT product_id FROM product_barcodes WHERE code = $1), (SELECT id FROM products WHERE sku = $1))`
+
+func scanProduct(row rowScanner) (models.Product, error) {
+	var p models.Product
+	err := row.Scan(&p.ID, &p.SKU, &p.Name, &p.Price, &p.Stock, &p.Cost, &p.CategoryID, &p.CategoryName, &p.TaxRateID, &p.ParentID, &p.PriceOverride)
+	return p, err
+}
+
+// productSortColumns - nilai parameter sort yang diizinkan beserta klausa ORDER BY
+var productSortColumns = map[string]string{
+	"id":     "p.id ASC",
+	"-id":    "p.id DESC",
+	"name":   "p.name ASC, p.id ASC",
+	"-name":  "p.name DESC, p.id ASC",
+	"price":  "p.price ASC, p.id ASC",
+	"-price": "p.price DESC, p.id ASC",
+	"stock":  "p.stock ASC, p.id ASC",
+	"-stock": "p.stock DESC, p.id ASC",
+}
+
+// GetAll - ambil produk sesuai filter, sort dan halaman beserta total baris yang cocok.
+// Dengan filter.OutletID, stok yang dibaca, difilter dan diurutkan adalah stok outlet tersebut.
+func (repo *ProductRepository) GetAll(filter models.ProductFilter) ([]models.Product, int, error) {
+	conditions := make([]string, 0)
+	args := make([]interface{}, 0)
+	where := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(cond, len(args)))
+	}
+
+	stock, join := "p.stock", ""
+	if filter.OutletID != 0 {
+		var exists bool
+		err := repo.db.QueryRow("SELECT EXISTS (SELECT 1 FROM outlets WHERE id = $1)", filter.OutletID).Scan(&exists)
+		if err != nil {
+			return nil, 0, err
+		}
+		if !exists {
+			return nil, 0, NewError(ErrNotFound, "outlet tidak ditemukan")
+		}
+		args = append(args, filter.OutletID)
+		stock, join = "COALESCE(os.stock, 0)", "LEFT JOIN outlet_stocks os ON os.product_id = p.id AND os.outlet_id = $1"
+	}
+
+	if filter.CategoryID != 0 {
+		where("p.category_id = $%d", filter.CategoryID)
+	}
+	if filter.MinPrice != nil {
+		where("p.price >= $%d", *filter.MinPrice)
+	}
+	if filter.MaxPrice != nil {
+		where("p.price <= $%d", *filter.MaxPrice)
+	}
+	if filter.InStock != nil {
+		if *filter.InStock {
+			conditions = append(conditions, stock+" > 0")
+		} else {
+			conditions = append(conditions, stock+" <= 0")
+		}
+	}
+	if filter.Search != "" {
+		where("p.name ILIKE $%d", "%"+filter.Search+"%")
+	}
+
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	err := repo.db.QueryRow("SELECT COUNT(*) FROM products p "+join+" "+whereClause, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	orderBy, ok := productSortColumns[filter.Sort]
+	if !ok {
+		orderBy = productSortColumns["id"]
+	}
+	orderBy = strings.ReplaceAll(orderBy, "p.stock", stock)
+
+	args = append(args, filter.Limit, (filter.Page-1)*filter.Limit)
+	query := fmt.Sprintf(`
+		SELECT %s
 		FROM products p
 		LEFT JOIN categories c ON p.category_id = c.id
-	`
-	rows, err := repo.db.Query(query)
+		%s
+		%s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d
+	`, strings.Replace(productColumns, "p.stock", stock, 1), join, whereClause, orderBy, len(args)-1, len(args))
+
+	rows, err := repo.db.Query(query, args...)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 
 	products := make([]models.Product, 0)
+	ids := make([]int, 0)
 	for rows.Next() {
-		var p models.Product
-		err := rows.Scan(&p.ID, &p.Name, &p.Price, &p.Stock, &p.CategoryID, &p.CategoryName)
+		p, err := scanProduct(rows)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
+		p.OutletID = filter.OutletID
 		products = append(products, p)
+		ids = append(ids, p.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
 	}
 
-	return products, nil
+	barcodes, err := repo.loadBarcodes(ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range products {
+		products[i].Barcodes = barcodes[products[i].ID]
+	}
+
+	return products, total, nil
 }
 
+// Create - simpan produk dengan stok 0 lalu catat stok awal sebagai movement di ledger.
+// product.Cost menjadi HPP awal untuk stok tersebut.
 func (repo *ProductRepository) Create(product *models.Product) error {
-	query := "INSERT INTO products (name, price, stock, category_id) VALUES ($1, $2, $3, $4) RETURNING id"
-	err := repo.db.QueryRow(query, product.Name, product.Price, product.Stock, product.CategoryID).Scan(&product.ID)
-	return err
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := "INSERT INTO products (sku, name, price, stock, cost, category_id, tax_rate_id) VALUES (NULLIF($1, ''), $2, $3, 0, $4, NULLIF($5, 0), NULLIF($6, 0)) RETURNING id"
+	err = tx.QueryRow(query, product.SKU, product.Name, product.Price, product.Cost, product.CategoryID, product.TaxRateID).Scan(&product.ID)
+	if err != nil {
+		return mapDBError(err)
+	}
+
+	if err := replaceBarcodes(tx, product.ID, product.Barcodes); err != nil {
+		return mapDBError(err)
+	}
+
+	if product.Stock != 0 {
+		err = applyMovement(tx, &models.StockMovement{
+			ProductID: product.ID,
+			Type:      models.MovementAdjustment,
+			Quantity:  product.Stock,
+			Reason:    "stok awal",
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
 
-// GetByID - ambil produk by ID dengan JOIN ke categories
+// GetByID - ambil produk by ID dengan JOIN ke categories, beserta atribut dan varian
+// jika produk induk atau pilihan atributnya jika varian
 func (repo *ProductRepository) GetByID(id int) (*models.Product, error) {
+	return repo.getOne("p.id = $1", id)
+}
+
+// GetByCode - ambil produk berdasarkan barcode, atau SKU jika tidak ada barcode yang cocok.
+// Jika kode cocok dengan barcode satu produk dan SKU produk lain, produk barcode yang dipakai.
+func (repo *ProductRepository) GetByCode(code string) (*models.Product, error) {
+	return repo.getOne("p.id = "+productIDByCode, code)
+}
+
+func (repo *ProductRepository) getOne(condition string, arg interface{}) (*models.Product, error) {
 	query := `
-		SELECT p.id, p.name, p.price, p.stock, p.category_id, COALESCE(c.name, '') as category_name
+		SELECT ` + productColumns + `
 		FROM products p
 		LEFT JOIN categories c ON p.category_id = c.id
-		WHERE p.id = $1
+		WHERE ` + condition + `
+		LIMIT 1
 	`
 
-	var p models.Product
-	err := repo.db.QueryRow(query, id).Scan(&p.ID, &p.Name, &p.Price, &p.Stock, &p.CategoryID, &p.CategoryName)
+	p, err := scanProduct(repo.db.QueryRow(query, arg))
 	if err == sql.ErrNoRows {
-		return nil, errors.New("produk tidak ditemukan")
+		return nil, NewError(ErrNotFound, "produk tidak ditemukan")
 	}
 	if err != nil {
 		return nil, err
 	}
 
+	barcodes, err := repo.loadBarcodes([]int{p.ID})
+	if err != nil {
+		return nil, err
+	}
+	p.Barcodes = barcodes[p.ID]
+
+	if err := repo.loadVariants(&p); err != nil {
+		return nil, err
+	}
 	return &p, nil
 }
 
+// Update - ubah data produk. Stok dan HPP tidak ikut diubah; perubahan stok harus lewat
+// stock movement supaya tercatat di ledger dan HPP dihitung ulang saat penerimaan barang.
+// product.Stock dan product.Cost diisi dengan nilai aktual. Nama, harga, kategori dan
+// tarif pajak produk induk diteruskan ke variannya; varian diubah lewat UpdateVariant.
 func (repo *ProductRepository) Update(product *models.Product) error {
-	query := "UPDATE products SET name = $1, price = $2, stock = $3, category_id = $4 WHERE id = $5"
-	result, err := repo.db.Exec(query, product.Name, product.Price, product.Stock, product.CategoryID, product.ID)
+	tx, err := repo.db.Begin()
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
-	rows, err := result.RowsAffected()
+	var parentID int
+	query := "UPDATE products SET sku = NULLIF($1, ''), name = $2, price = $3, category_id = NULLIF($4, 0), tax_rate_id = NULLIF($5, 0) WHERE id = $6 RETURNING stock, cost, COALESCE(parent_id, 0)"
+	err = tx.QueryRow(query, product.SKU, product.Name, product.Price, product.CategoryID, product.TaxRateID, product.ID).Scan(&product.Stock, &product.Cost, &parentID)
+	if err == sql.ErrNoRows {
+		return NewError(ErrNotFound, "produk tidak ditemukan")
+	}
 	if err != nil {
-		return err
+		return mapDBError(err)
+	}
+	if parentID != 0 {
+		return NewError(ErrConflict, msgVariantUpdate)
 	}
 
-	if rows == 0 {
-		return errors.New("produk tidak ditemukan")
+	if err := replaceBarcodes(tx, product.ID, product.Barcodes); err != nil {
+		return mapDBError(err)
+	}
+	if err := syncVariants(tx, product.ID); err != nil {
+		return err
 	}
 
-	return nil
+	return tx.Commit()
 }
 
+// Delete - hapus produk; produk induk yang masih punya varian dan produk yang sudah
+// punya riwayat stok ditolak supaya ledger tidak hilang
 func (repo *ProductRepository) Delete(id int) error {
+	var hasVariants, hasMovements bool
+	err := repo.db.QueryRow(
+		"SELECT EXISTS (SELECT 1 FROM products WHERE parent_id = $1), EXISTS (SELECT 1 FROM stock_movements WHERE product_id = $1)",
+		id,
+	).Scan(&hasVariants, &hasMovements)
+	if err != nil {
+		return err
+	}
+	if hasVariants {
+		return NewError(ErrConflict, msgHasVariants)
+	}
+	if hasMovements {
+		return NewError(ErrConflict, msgHasMovements)
+	}
+
 	query := "DELETE FROM products WHERE id = $1"
 	result, err := repo.db.Exec(query, id)
 	if err != nil {
-		return err
+		return mapDBError(err)
 	}
 	rows, err := result.RowsAffected()
 	if err != nil {
@@ -97,8 +283,59 @@ func (repo *ProductRepository) Delete(id int) error {
 	}
 
 	if rows == 0 {
-		return errors.New("produk tidak ditemukan")
+		return NewError(ErrNotFound, "produk tidak ditemukan")
 	}
 
-	return err
+	return nil
+}
+
+// loadBarcodes - ambil barcode untuk sekumpulan produk, dikelompokkan per product_id
+func (repo *ProductRepository) loadBarcodes(ids []int) (map[int][]string, error) {
+	result := make(map[int][]string, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	placeholders := make([]string, len(ids))
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = id
+		result[id] = make([]string, 0)
+	}
+
+	query := "SELECT product_id, code FROM product_barcodes WHERE product_id IN (" + strings.Join(placeholders, ", ") + ") ORDER BY product_id, code"
+	rows, err := repo.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var productID int
+		var code string
+		if err := rows.Scan(&productID, &code); err != nil {
+			return nil, err
+		}
+		result[productID] = append(result[productID], code)
+	}
+
+	return result, rows.Err()
+}
+
+// replaceBarcodes - ganti seluruh barcode produk dengan daftar baru
+func replaceBarcodes(tx execQueryer, productID int, barcodes []string) error {
+	_, err := tx.Exec("DELETE FROM product_barcodes WHERE product_id = $1", productID)
+	if err != nil {
+		return err
+	}
+
+	for _, code := range barcodes {
+		_, err := tx.Exec("INSERT INTO product_barcodes (code, product_id) VALUES ($1, $2)", code, productID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
@@ -0,0 +1,480 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"kasir-api/models"
+	"maps"
+)
+
+const (
+	msgVariantUpdate = "produk ini varian, ubah lewat endpoint varian produk induk"
+	msgHasVariants   = "produk masih punya varian"
+	msgHasMovements  = "produk sudah punya riwayat stok dan tidak bisa dihapus"
+)
+
+// variantAttribute - atribut produk induk beserta ID atribut dan pilihannya
+type variantAttribute struct {
+	id      int
+	name    string
+	options []variantOption
+}
+
+type variantOption struct {
+	id    int
+	value string
+}
+
+// CheckVariantOptions - options harus berisi tepat satu pilihan yang ada untuk setiap
+// atribut produk induk
+func CheckVariantOptions(attributes []models.ProductAttribute, options map[string]string) error {
+	if len(attributes) == 0 {
+		return NewError(ErrValidation, "produk belum punya atribut varian")
+	}
+	for name := range options {
+		found := false
+		for _, a := range attributes {
+			found = found || a.Name == name
+		}
+		if !found {
+			return NewError(ErrValidation, fmt.Sprintf("atribut %s tidak ada di produk induk", name))
+		}
+	}
+	for _, a := range attributes {
+		value, ok := options[a.Name]
+		if !ok {
+			return NewError(ErrValidation, fmt.Sprintf("pilihan %s wajib diisi", a.Name))
+		}
+		valid := false
+		for _, o := range a.Options {
+			valid = valid || o == value
+		}
+		if !valid {
+			return NewError(ErrValidation, fmt.Sprintf("%s %s tidak ada di pilihan atribut", a.Name, value))
+		}
+	}
+	return nil
+}
+
+// loadVariants - isi Attributes dan Variants produk induk, atau Options jika p varian
+func (repo *ProductRepository) loadVariants(p *models.Product) error {
+	if p.ParentID != 0 {
+		options, err := variantOptions(repo.db, "vo.product_id = $1", p.ID)
+		if err != nil {
+			return err
+		}
+		p.Options = options[p.ID]
+		return nil
+	}
+
+	attributes, err := loadAttributes(repo.db, p.ID)
+	if err != nil || len(attributes) == 0 {
+		return err
+	}
+	p.Attributes = productAttributes(attributes)
+
+	rows, err := repo.db.Query(`
+		SELECT `+productColumns+`
+		FROM products p
+		LEFT JOIN categories c ON p.category_id = c.id
+		WHERE p.parent_id = $1
+		ORDER BY p.id
+	`, p.ID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	p.Variants = make([]models.Product, 0)
+	ids := make([]int, 0)
+	for rows.Next() {
+		v, err := scanProduct(rows)
+		if err != nil {
+			return err
+		}
+		p.Variants = append(p.Variants, v)
+		ids = append(ids, v.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	barcodes, err := repo.loadBarcodes(ids)
+	if err != nil {
+		return err
+	}
+	options, err := variantOptions(repo.db, "a.product_id = $1", p.ID)
+	if err != nil {
+		return err
+	}
+	for i := range p.Variants {
+		p.Variants[i].Barcodes = barcodes[p.Variants[i].ID]
+		p.Variants[i].Options = options[p.Variants[i].ID]
+	}
+	return nil
+}
+
+// SetAttributes - ganti atribut produk induk. Atribut dan pilihan dicocokkan berdasarkan
+// nama sehingga varian yang ada tetap terhubung. Selama produk punya varian, atribut
+// tidak bisa ditambah atau dihapus dan pilihan yang dipakai varian tidak bisa dihapus.
+// Nama varian disusun ulang mengikuti urutan atribut yang baru.
+func (repo *ProductRepository) SetAttributes(productID int, attributes []models.ProductAttribute) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var parentID int
+	var hasVariants bool
+	err = tx.QueryRow(
+		"SELECT COALESCE(parent_id, 0), EXISTS (SELECT 1 FROM products v WHERE v.parent_id = p.id) FROM products p WHERE p.id = $1 FOR UPDATE",
+		productID,
+	).Scan(&parentID, &hasVariants)
+	if err == sql.ErrNoRows {
+		return NewError(ErrNotFound, "produk tidak ditemukan")
+	}
+	if err != nil {
+		return err
+	}
+	if parentID != 0 {
+		return NewError(ErrValidation, "varian tidak bisa punya atribut")
+	}
+
+	existing, err := loadAttributes(tx, productID)
+	if err != nil {
+		return err
+	}
+	current := make(map[string]variantAttribute, len(existing))
+	for _, a := range existing {
+		current[a.name] = a
+	}
+
+	if hasVariants {
+		same := len(attributes) == len(existing)
+		for _, a := range attributes {
+			_, ok := current[a.Name]
+			same = same && ok
+		}
+		if !same {
+			return NewError(ErrConflict, "atribut tidak bisa ditambah atau dihapus selama produk punya varian")
+		}
+	}
+
+	keep := make(map[string]bool, len(attributes))
+	for _, a := range attributes {
+		keep[a.Name] = true
+	}
+	for _, a := range existing {
+		if !keep[a.name] {
+			if _, err := tx.Exec("DELETE FROM product_attributes WHERE id = $1", a.id); err != nil {
+				return err
+			}
+		}
+	}
+
+	for i, a := range attributes {
+		attr, ok := current[a.Name]
+		if ok {
+			if _, err := tx.Exec("UPDATE product_attributes SET position = $1 WHERE id = $2", i, attr.id); err != nil {
+				return err
+			}
+		} else {
+			err := tx.QueryRow(
+				"INSERT INTO product_attributes (product_id, name, position) VALUES ($1, $2, $3) RETURNING id",
+				productID, a.Name, i,
+			).Scan(&attr.id)
+			if err != nil {
+				return mapDBError(err)
+			}
+		}
+		if err := setAttributeOptions(tx, attr, a.Options); err != nil {
+			return err
+		}
+	}
+
+	if err := syncVariants(tx, productID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// setAttributeOptions - ganti pilihan satu atribut, pilihan yang sudah ada dicocokkan
+// berdasarkan nilainya
+func setAttributeOptions(tx execQueryer, attr variantAttribute, values []string) error {
+	current := make(map[string]int, len(attr.options))
+	for _, o := range attr.options {
+		current[o.value] = o.id
+	}
+	keep := make(map[string]bool, len(values))
+	for _, v := range values {
+		keep[v] = true
+	}
+
+	for _, o := range attr.options {
+		if keep[o.value] {
+			continue
+		}
+		var used bool
+		err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM product_variant_options WHERE option_id = $1)", o.id).Scan(&used)
+		if err != nil {
+			return err
+		}
+		if used {
+			return NewError(ErrConflict, fmt.Sprintf("pilihan %s %s masih dipakai varian", attr.name, o.value))
+		}
+		if _, err := tx.Exec("DELETE FROM product_attribute_options WHERE id = $1", o.id); err != nil {
+			return err
+		}
+	}
+
+	for i, v := range values {
+		var err error
+		if id, ok := current[v]; ok {
+			_, err = tx.Exec("UPDATE product_attribute_options SET position = $1 WHERE id = $2", i, id)
+		} else {
+			_, err = tx.Exec("INSERT INTO product_attribute_options (attribute_id, value, position) VALUES ($1, $2, $3)", attr.id, v, i)
+		}
+		if err != nil {
+			return mapDBError(err)
+		}
+	}
+	return nil
+}
+
+// CreateVariant - buat varian di bawah produk induk. Nama, kategori dan tarif pajak
+// mengikuti produk induk, harga juga kecuali variant.PriceOverride. variant.Stock
+// dicatat sebagai stok awal di ledger. Produk induk yang masih punya stok ditolak
+// karena stok hanya dicatat per varian dan produk induk tidak bisa dijual.
+func (repo *ProductRepository) CreateVariant(parentID int, variant *models.Product) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var parent models.Product
+	err = tx.QueryRow(
+		"SELECT id, name, price, stock, COALESCE(category_id, 0), COALESCE(tax_rate_id, 0), COALESCE(parent_id, 0) FROM products WHERE id = $1 FOR UPDATE",
+		parentID,
+	).Scan(&parent.ID, &parent.Name, &parent.Price, &parent.Stock, &parent.CategoryID, &parent.TaxRateID, &parent.ParentID)
+	if err == sql.ErrNoRows {
+		return NewError(ErrNotFound, "produk tidak ditemukan")
+	}
+	if err != nil {
+		return err
+	}
+	if parent.ParentID != 0 {
+		return NewError(ErrValidation, "varian tidak bisa punya varian")
+	}
+	if parent.Stock != 0 {
+		return NewError(ErrConflict, "stok produk induk harus 0 sebelum punya varian")
+	}
+
+	existing, err := loadAttributes(tx, parentID)
+	if err != nil {
+		return err
+	}
+	attributes := productAttributes(existing)
+	if err := CheckVariantOptions(attributes, variant.Options); err != nil {
+		return err
+	}
+	siblings, err := variantOptions(tx, "a.product_id = $1", parentID)
+	if err != nil {
+		return err
+	}
+	for _, options := range siblings {
+		if maps.Equal(options, variant.Options) {
+			return NewError(ErrConflict, "varian dengan pilihan yang sama sudah ada")
+		}
+	}
+
+	variant.ParentID = parentID
+	variant.Name = models.VariantName(parent.Name, attributes, variant.Options)
+	variant.CategoryID, variant.TaxRateID = parent.CategoryID, parent.TaxRateID
+	if !variant.PriceOverride {
+		variant.Price = parent.Price
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO products (sku, name, price, stock, cost, category_id, tax_rate_id, parent_id, price_override)
+		VALUES (NULLIF($1, ''), $2, $3, 0, $4, NULLIF($5, 0), NULLIF($6, 0), $7, $8) RETURNING id`,
+		variant.SKU, variant.Name, variant.Price, variant.Cost, variant.CategoryID, variant.TaxRateID, parentID, variant.PriceOverride,
+	).Scan(&variant.ID)
+	if err != nil {
+		return mapDBError(err)
+	}
+	if err := replaceBarcodes(tx, variant.ID, variant.Barcodes); err != nil {
+		return mapDBError(err)
+	}
+
+	for _, a := range existing {
+		for _, o := range a.options {
+			if o.value != variant.Options[a.name] {
+				continue
+			}
+			if _, err := tx.Exec("INSERT INTO product_variant_options (product_id, option_id) VALUES ($1, $2)", variant.ID, o.id); err != nil {
+				return err
+			}
+		}
+	}
+
+	if variant.Stock != 0 {
+		err = applyMovement(tx, &models.StockMovement{
+			ProductID: variant.ID,
+			Type:      models.MovementAdjustment,
+			Quantity:  variant.Stock,
+			Reason:    "stok awal",
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// UpdateVariant - ubah SKU, barcode dan harga varian. Tanpa PriceOverride harga kembali
+// mengikuti produk induk. variant.Price diisi dengan harga yang berlaku.
+func (repo *ProductRepository) UpdateVariant(variant *models.Product) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var parentPrice int
+	err = tx.QueryRow(
+		"SELECT parent.price FROM products p JOIN products parent ON parent.id = p.parent_id WHERE p.id = $1 AND p.parent_id = $2 FOR UPDATE",
+		variant.ID, variant.ParentID,
+	).Scan(&parentPrice)
+	if err == sql.ErrNoRows {
+		return NewError(ErrNotFound, "varian tidak ditemukan")
+	}
+	if err != nil {
+		return err
+	}
+	if !variant.PriceOverride {
+		variant.Price = parentPrice
+	}
+
+	_, err = tx.Exec("UPDATE products SET sku = NULLIF($1, ''), price = $2, price_override = $3 WHERE id = $4",
+		variant.SKU, variant.Price, variant.PriceOverride, variant.ID)
+	if err != nil {
+		return mapDBError(err)
+	}
+	if err := replaceBarcodes(tx, variant.ID, variant.Barcodes); err != nil {
+		return mapDBError(err)
+	}
+
+	return tx.Commit()
+}
+
+// syncVariants - teruskan kategori, tarif pajak dan harga (kecuali price_override)
+// produk induk ke variannya lalu susun ulang nama varian
+func syncVariants(tx execQueryer, parentID int) error {
+	var parent models.Product
+	err := tx.QueryRow(
+		"SELECT name, price, COALESCE(category_id, 0), COALESCE(tax_rate_id, 0) FROM products WHERE id = $1", parentID,
+	).Scan(&parent.Name, &parent.Price, &parent.CategoryID, &parent.TaxRateID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE products SET category_id = NULLIF($1, 0), tax_rate_id = NULLIF($2, 0),
+			price = CASE WHEN price_override THEN price ELSE $3 END
+		WHERE parent_id = $4`,
+		parent.CategoryID, parent.TaxRateID, parent.Price, parentID,
+	)
+	if err != nil {
+		return err
+	}
+
+	options, err := variantOptions(tx, "a.product_id = $1", parentID)
+	if err != nil || len(options) == 0 {
+		return err
+	}
+	attributes, err := loadAttributes(tx, parentID)
+	if err != nil {
+		return err
+	}
+	for id, opts := range options {
+		name := models.VariantName(parent.Name, productAttributes(attributes), opts)
+		if _, err := tx.Exec("UPDATE products SET name = $1 WHERE id = $2", name, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadAttributes - atribut produk beserta pilihannya, urut position
+func loadAttributes(q queryer, productID int) ([]variantAttribute, error) {
+	rows, err := q.Query(`
+		SELECT a.id, a.name, o.id, o.value
+		FROM product_attributes a
+		JOIN product_attribute_options o ON o.attribute_id = a.id
+		WHERE a.product_id = $1
+		ORDER BY a.position, a.id, o.position, o.id
+	`, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attributes := make([]variantAttribute, 0)
+	for rows.Next() {
+		var attrID int
+		var name string
+		var o variantOption
+		if err := rows.Scan(&attrID, &name, &o.id, &o.value); err != nil {
+			return nil, err
+		}
+		if n := len(attributes); n == 0 || attributes[n-1].id != attrID {
+			attributes = append(attributes, variantAttribute{id: attrID, name: name})
+		}
+		last := &attributes[len(attributes)-1]
+		last.options = append(last.options, o)
+	}
+	return attributes, rows.Err()
+}
+
+func productAttributes(attributes []variantAttribute) []models.ProductAttribute {
+	result := make([]models.ProductAttribute, len(attributes))
+	for i, a := range attributes {
+		result[i] = models.ProductAttribute{Name: a.name, Options: make([]string, len(a.options))}
+		for j, o := range a.options {
+			result[i].Options[j] = o.value
+		}
+	}
+	return result
+}
+
+// variantOptions - pilihan atribut setiap varian yang cocok dengan condition,
+// dikelompokkan per ID varian
+func variantOptions(q queryer, condition string, arg interface{}) (map[int]map[string]string, error) {
+	rows, err := q.Query(`
+		SELECT vo.product_id, a.name, o.value
+		FROM product_variant_options vo
+		JOIN product_attribute_options o ON o.id = vo.option_id
+		JOIN product_attributes a ON a.id = o.attribute_id
+		WHERE `+condition, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[int]map[string]string)
+	for rows.Next() {
+		var id int
+		var name, value string
+		if err := rows.Scan(&id, &name, &value); err != nil {
+			return nil, err
+		}
+		if result[id] == nil {
+			result[id] = make(map[string]string)
+		}
+		result[id][name] = value
+	}
+	return result, rows.Err()
+}
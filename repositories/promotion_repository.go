@@ -0,0 +1,157 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"kasir-api/models"
+	"time"
+)
+
+type PromotionRepository struct {
+	db *sql.DB
+}
+
+func NewPromotionRepository(db *sql.DB) *PromotionRepository {
+	return &PromotionRepository{db: db}
+}
+
+const promotionColumns = `
+	id, name, type, value, COALESCE(product_id, 0), COALESCE(category_id, 0),
+	buy_quantity, get_quantity, min_spend, COALESCE(voucher_code, ''), starts_at, ends_at,
+	happy_hour_start, happy_hour_end, priority, stackable, active, created_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanPromotion(row rowScanner) (models.Promotion, error) {
+	var p models.Promotion
+	var startsAt, endsAt sql.NullTime
+	err := row.Scan(
+		&p.ID, &p.Name, &p.Type, &p.Value, &p.ProductID, &p.CategoryID,
+		&p.BuyQuantity, &p.GetQuantity, &p.MinSpend, &p.VoucherCode, &startsAt, &endsAt,
+		&p.HappyHourStart, &p.HappyHourEnd, &p.Priority, &p.Stackable, &p.Active, &p.CreatedAt,
+	)
+	if startsAt.Valid {
+		p.StartsAt = &startsAt.Time
+	}
+	if endsAt.Valid {
+		p.EndsAt = &endsAt.Time
+	}
+	return p, err
+}
+
+func (repo *PromotionRepository) queryPromotions(query string, args ...interface{}) ([]models.Promotion, error) {
+	rows, err := repo.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	promotions := make([]models.Promotion, 0)
+	for rows.Next() {
+		p, err := scanPromotion(rows)
+		if err != nil {
+			return nil, err
+		}
+		promotions = append(promotions, p)
+	}
+	return promotions, rows.Err()
+}
+
+// GetAll - ambil promosi per halaman, terbaru lebih dulu
+func (repo *PromotionRepository) GetAll(filter models.PromotionFilter) ([]models.Promotion, int, error) {
+	whereClause := ""
+	args := make([]interface{}, 0)
+	if filter.Active != nil {
+		args = append(args, *filter.Active)
+		whereClause = "WHERE active = $1"
+	}
+
+	var total int
+	err := repo.db.QueryRow("SELECT COUNT(*) FROM promotions "+whereClause, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, filter.Limit, (filter.Page-1)*filter.Limit)
+	query := fmt.Sprintf("SELECT %s FROM promotions %s ORDER BY id DESC LIMIT $%d OFFSET $%d",
+		promotionColumns, whereClause, len(args)-1, len(args))
+	promotions, err := repo.queryPromotions(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return promotions, total, nil
+}
+
+// GetActive - promosi aktif yang periodenya mencakup waktu at. Jam happy hour dan
+// kode voucher dicek oleh engine promosi.
+func (repo *PromotionRepository) GetActive(at time.Time) ([]models.Promotion, error) {
+	query := fmt.Sprintf(`
+		SELECT %s FROM promotions
+		WHERE active AND (starts_at IS NULL OR starts_at <= $1) AND (ends_at IS NULL OR ends_at > $1)
+		ORDER BY priority, id
+	`, promotionColumns)
+	return repo.queryPromotions(query, at)
+}
+
+func (repo *PromotionRepository) GetByID(id int) (*models.Promotion, error) {
+	row := repo.db.QueryRow(fmt.Sprintf("SELECT %s FROM promotions WHERE id = $1", promotionColumns), id)
+	p, err := scanPromotion(row)
+	if err == sql.ErrNoRows {
+		return nil, NewError(ErrNotFound, "promosi tidak ditemukan")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (repo *PromotionRepository) Create(p *models.Promotion) error {
+	query := `
+		INSERT INTO promotions (name, type, value, product_id, category_id, buy_quantity, get_quantity, min_spend,
+			voucher_code, starts_at, ends_at, happy_hour_start, happy_hour_end, priority, stackable, active)
+		VALUES ($1, $2, $3, NULLIF($4, 0), NULLIF($5, 0), $6, $7, $8, NULLIF($9, ''), $10, $11, $12, $13, $14, $15, $16)
+		RETURNING id, created_at
+	`
+	err := repo.db.QueryRow(query,
+		p.Name, p.Type, p.Value, p.ProductID, p.CategoryID, p.BuyQuantity, p.GetQuantity, p.MinSpend,
+		p.VoucherCode, p.StartsAt, p.EndsAt, p.HappyHourStart, p.HappyHourEnd, p.Priority, p.Stackable, p.Active,
+	).Scan(&p.ID, &p.CreatedAt)
+	return mapDBError(err)
+}
+
+func (repo *PromotionRepository) Update(p *models.Promotion) error {
+	query := `
+		UPDATE promotions SET name = $1, type = $2, value = $3, product_id = NULLIF($4, 0), category_id = NULLIF($5, 0),
+			buy_quantity = $6, get_quantity = $7, min_spend = $8, voucher_code = NULLIF($9, ''), starts_at = $10,
+			ends_at = $11, happy_hour_start = $12, happy_hour_end = $13, priority = $14, stackable = $15, active = $16
+		WHERE id = $17
+		RETURNING created_at
+	`
+	err := repo.db.QueryRow(query,
+		p.Name, p.Type, p.Value, p.ProductID, p.CategoryID, p.BuyQuantity, p.GetQuantity, p.MinSpend,
+		p.VoucherCode, p.StartsAt, p.EndsAt, p.HappyHourStart, p.HappyHourEnd, p.Priority, p.Stackable, p.Active, p.ID,
+	).Scan(&p.CreatedAt)
+	if err == sql.ErrNoRows {
+		return NewError(ErrNotFound, "promosi tidak ditemukan")
+	}
+	return mapDBError(err)
+}
+
+func (repo *PromotionRepository) Delete(id int) error {
+	result, err := repo.db.Exec("DELETE FROM promotions WHERE id = $1", id)
+	if err != nil {
+		return mapDBError(err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return NewError(ErrNotFound, "promosi tidak ditemukan")
+	}
+
+	return nil
+}
@@ -0,0 +1,430 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"kasir-api/costing"
+	"kasir-api/models"
+	"sort"
+	"strings"
+)
+
+type PurchaseOrderRepository struct {
+	db *sql.DB
+}
+
+func NewPurchaseOrderRepository(db *sql.DB) *PurchaseOrderRepository {
+	return &PurchaseOrderRepository{db: db}
+}
+
+const purchaseOrderColumns = `
+	po.id, po.supplier_id, s.name, po.status, po.notes, po.total_cost, po.created_by,
+	po.ordered_at, po.received_at, po.cancelled_at, po.created_at`
+
+func scanPurchaseOrder(row rowScanner) (models.PurchaseOrder, error) {
+	var po models.PurchaseOrder
+	var orderedAt, receivedAt, cancelledAt sql.NullTime
+	err := row.Scan(&po.ID, &po.SupplierID, &po.SupplierName, &po.Status, &po.Notes, &po.TotalCost, &po.CreatedBy,
+		&orderedAt, &receivedAt, &cancelledAt, &po.CreatedAt)
+	if orderedAt.Valid {
+		po.OrderedAt = &orderedAt.Time
+	}
+	if receivedAt.Valid {
+		po.ReceivedAt = &receivedAt.Time
+	}
+	if cancelledAt.Valid {
+		po.CancelledAt = &cancelledAt.Time
+	}
+	return po, err
+}
+
+// GetAll - purchase order per halaman, terbaru lebih dulu. Baris dan penerimaan tidak ikut dimuat.
+func (repo *PurchaseOrderRepository) GetAll(filter models.PurchaseOrderFilter) ([]models.PurchaseOrder, int, error) {
+	conditions := make([]string, 0, 2)
+	args := make([]interface{}, 0, 4)
+	if filter.SupplierID > 0 {
+		args = append(args, filter.SupplierID)
+		conditions = append(conditions, fmt.Sprintf("po.supplier_id = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("po.status = $%d", len(args)))
+	}
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	err := repo.db.QueryRow("SELECT COUNT(*) FROM purchase_orders po "+whereClause, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, filter.Limit, (filter.Page-1)*filter.Limit)
+	query := fmt.Sprintf(`
+		SELECT %s FROM purchase_orders po
+		JOIN suppliers s ON s.id = po.supplier_id
+		%s ORDER BY po.id DESC LIMIT $%d OFFSET $%d`,
+		purchaseOrderColumns, whereClause, len(args)-1, len(args))
+	rows, err := repo.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	orders := make([]models.PurchaseOrder, 0)
+	for rows.Next() {
+		po, err := scanPurchaseOrder(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		orders = append(orders, po)
+	}
+	return orders, total, rows.Err()
+}
+
+func (repo *PurchaseOrderRepository) GetByID(id int) (*models.PurchaseOrder, error) {
+	return getPurchaseOrder(repo.db, id)
+}
+
+// getPurchaseOrder - purchase order beserta baris dan riwayat penerimaannya
+func getPurchaseOrder(q queryer, id int) (*models.PurchaseOrder, error) {
+	query := "SELECT " + purchaseOrderColumns + " FROM purchase_orders po JOIN suppliers s ON s.id = po.supplier_id WHERE po.id = $1"
+	po, err := scanPurchaseOrder(q.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, NewError(ErrNotFound, "purchase order tidak ditemukan")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	po.Lines, err = getPurchaseOrderLines(q, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := q.Query(`
+		SELECT g.id, g.note, g.received_by, g.created_at, l.id, l.purchase_order_line_id, l.product_id, l.quantity, l.unit_cost
+		FROM goods_receipts g
+		JOIN goods_receipt_lines l ON l.goods_receipt_id = g.id
+		WHERE g.purchase_order_id = $1
+		ORDER BY g.id, l.id`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	po.Receipts = make([]models.GoodsReceipt, 0)
+	for rows.Next() {
+		var g models.GoodsReceipt
+		var l models.GoodsReceiptLine
+		err := rows.Scan(&g.ID, &g.Note, &g.ReceivedBy, &g.CreatedAt, &l.ID, &l.PurchaseOrderLineID, &l.ProductID, &l.Quantity, &l.UnitCost)
+		if err != nil {
+			return nil, err
+		}
+		if n := len(po.Receipts); n == 0 || po.Receipts[n-1].ID != g.ID {
+			g.PurchaseOrderID = id
+			g.Lines = make([]models.GoodsReceiptLine, 0, 1)
+			po.Receipts = append(po.Receipts, g)
+		}
+		last := &po.Receipts[len(po.Receipts)-1]
+		last.Lines = append(last.Lines, l)
+	}
+	return &po, rows.Err()
+}
+
+func getPurchaseOrderLines(q queryer, purchaseOrderID int) ([]models.PurchaseOrderLine, error) {
+	rows, err := q.Query(`
+		SELECT id, product_id, product_name, quantity, received_quantity, unit_cost
+		FROM purchase_order_lines WHERE purchase_order_id = $1 ORDER BY id`, purchaseOrderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lines := make([]models.PurchaseOrderLine, 0)
+	for rows.Next() {
+		var l models.PurchaseOrderLine
+		if err := rows.Scan(&l.ID, &l.ProductID, &l.ProductName, &l.Quantity, &l.ReceivedQuantity, &l.UnitCost); err != nil {
+			return nil, err
+		}
+		lines = append(lines, l)
+	}
+	return lines, rows.Err()
+}
+
+// Create - simpan purchase order baru sebagai draft
+func (repo *PurchaseOrderRepository) Create(req *models.PurchaseOrderRequest) (*models.PurchaseOrder, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var id int
+	err = tx.QueryRow(
+		"INSERT INTO purchase_orders (supplier_id, status, notes, created_by) VALUES ($1, $2, $3, $4) RETURNING id",
+		req.SupplierID, models.PurchaseDraft, req.Notes, req.CreatedBy,
+	).Scan(&id)
+	if err != nil {
+		return nil, mapDBError(err)
+	}
+	if err := insertPurchaseLines(tx, id, req.Lines); err != nil {
+		return nil, err
+	}
+
+	po, err := getPurchaseOrder(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return po, nil
+}
+
+// Update - ganti supplier, catatan dan seluruh baris purchase order yang masih draft
+func (repo *PurchaseOrderRepository) Update(id int, req *models.PurchaseOrderRequest) (*models.PurchaseOrder, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	status, err := lockPurchaseOrder(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if status != models.PurchaseDraft {
+		return nil, NewError(ErrConflict, fmt.Sprintf("purchase order %s tidak bisa diubah", status))
+	}
+
+	_, err = tx.Exec("UPDATE purchase_orders SET supplier_id = $1, notes = $2 WHERE id = $3", req.SupplierID, req.Notes, id)
+	if err != nil {
+		return nil, mapDBError(err)
+	}
+	if _, err := tx.Exec("DELETE FROM purchase_order_lines WHERE purchase_order_id = $1", id); err != nil {
+		return nil, err
+	}
+	if err := insertPurchaseLines(tx, id, req.Lines); err != nil {
+		return nil, err
+	}
+
+	po, err := getPurchaseOrder(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return po, nil
+}
+
+// insertPurchaseLines - simpan baris dengan nama produk saat ini dan hitung ulang total_cost
+func insertPurchaseLines(tx *sql.Tx, purchaseOrderID int, lines []models.PurchaseLineRequest) error {
+	total := 0
+	for _, l := range lines {
+		var name string
+		err := tx.QueryRow("SELECT name FROM products WHERE id = $1", l.ProductID).Scan(&name)
+		if err == sql.ErrNoRows {
+			return NewError(ErrValidation, fmt.Sprintf("produk id %d tidak ditemukan", l.ProductID))
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`
+			INSERT INTO purchase_order_lines (purchase_order_id, product_id, product_name, quantity, unit_cost)
+			VALUES ($1, $2, $3, $4, $5)`,
+			purchaseOrderID, l.ProductID, name, l.Quantity, l.UnitCost,
+		)
+		if err != nil {
+			return mapDBError(err)
+		}
+		total += l.Quantity * l.UnitCost
+	}
+
+	_, err := tx.Exec("UPDATE purchase_orders SET total_cost = $1 WHERE id = $2", total, purchaseOrderID)
+	return mapDBError(err)
+}
+
+func lockPurchaseOrder(tx *sql.Tx, id int) (string, error) {
+	var status string
+	err := tx.QueryRow("SELECT status FROM purchase_orders WHERE id = $1 FOR UPDATE", id).Scan(&status)
+	if err == sql.ErrNoRows {
+		return "", NewError(ErrNotFound, "purchase order tidak ditemukan")
+	}
+	return status, err
+}
+
+// Order - kirim purchase order draft ke supplier, setelah itu baris tidak bisa diubah
+func (repo *PurchaseOrderRepository) Order(id int) (*models.PurchaseOrder, error) {
+	return repo.transition(id, models.PurchaseOrdered, "ordered_at", models.PurchaseDraft)
+}
+
+// Cancel - batalkan purchase order yang belum diterima penuh. Barang yang sudah
+// diterima tetap masuk stok; sisa pesanan tidak lagi ditunggu.
+func (repo *PurchaseOrderRepository) Cancel(id int) (*models.PurchaseOrder, error) {
+	return repo.transition(id, models.PurchaseCancelled, "cancelled_at",
+		models.PurchaseDraft, models.PurchaseOrdered, models.PurchasePartiallyReceived)
+}
+
+func (repo *PurchaseOrderRepository) transition(id int, status, timestampColumn string, from ...string) (*models.PurchaseOrder, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	current, err := lockPurchaseOrder(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	allowed := false
+	for _, f := range from {
+		allowed = allowed || current == f
+	}
+	if !allowed {
+		return nil, NewError(ErrConflict, fmt.Sprintf("purchase order sudah %s", current))
+	}
+
+	_, err = tx.Exec("UPDATE purchase_orders SET status = $1, "+timestampColumn+" = NOW() WHERE id = $2", status, id)
+	if err != nil {
+		return nil, err
+	}
+
+	po, err := getPurchaseOrder(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return po, nil
+}
+
+// Receive - catat penerimaan barang untuk purchase order yang sudah dipesan. Stok
+// bertambah lewat movement restock di ledger, harga beli per unit disimpan di baris
+// penerimaan dan HPP produk diperbarui dengan rata-rata bergerak. Penerimaan tidak boleh melebihi sisa pesanan per baris; PO menjadi
+// received jika semua baris sudah diterima penuh.
+func (repo *PurchaseOrderRepository) Receive(id int, req *models.GoodsReceiptRequest) (*models.PurchaseOrder, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	status, err := lockPurchaseOrder(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if status != models.PurchaseOrdered && status != models.PurchasePartiallyReceived {
+		return nil, NewError(ErrConflict, fmt.Sprintf("purchase order berstatus %s tidak bisa diterima", status))
+	}
+
+	orderLines, err := getPurchaseOrderLines(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int]*models.PurchaseOrderLine, len(orderLines))
+	for i := range orderLines {
+		byID[orderLines[i].ID] = &orderLines[i]
+	}
+
+	lines := make([]models.GoodsReceiptLine, 0, len(req.Lines))
+	for _, r := range req.Lines {
+		line, ok := byID[r.LineID]
+		if !ok {
+			return nil, NewError(ErrValidation, fmt.Sprintf("baris id %d bukan bagian dari purchase order #%d", r.LineID, id))
+		}
+		remaining := line.Quantity - line.ReceivedQuantity
+		if r.Quantity > remaining {
+			return nil, NewError(ErrValidation, fmt.Sprintf("penerimaan %s melebihi sisa pesanan %d", line.ProductName, remaining))
+		}
+		line.ReceivedQuantity += r.Quantity
+
+		unitCost := line.UnitCost
+		if r.UnitCost != nil {
+			unitCost = *r.UnitCost
+		}
+		lines = append(lines, models.GoodsReceiptLine{
+			PurchaseOrderLineID: line.ID,
+			ProductID:           line.ProductID,
+			Quantity:            r.Quantity,
+			UnitCost:            unitCost,
+		})
+	}
+
+	var receiptID int
+	err = tx.QueryRow(
+		"INSERT INTO goods_receipts (purchase_order_id, note, received_by) VALUES ($1, $2, $3) RETURNING id",
+		id, req.Note, req.ReceivedBy,
+	).Scan(&receiptID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, l := range lines {
+		_, err := tx.Exec(`
+			INSERT INTO goods_receipt_lines (goods_receipt_id, purchase_order_line_id, product_id, quantity, unit_cost)
+			VALUES ($1, $2, $3, $4, $5)`,
+			receiptID, l.PurchaseOrderLineID, l.ProductID, l.Quantity, l.UnitCost,
+		)
+		if err != nil {
+			return nil, err
+		}
+		_, err = tx.Exec("UPDATE purchase_order_lines SET received_quantity = received_quantity + $1 WHERE id = $2", l.Quantity, l.PurchaseOrderLineID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Produk dikunci berurutan berdasarkan ID, sama seperti checkout. HPP dihitung ulang
+	// dengan rata-rata bergerak dari stok sebelum barang masuk.
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].ProductID < lines[j].ProductID })
+	for _, l := range lines {
+		var stock, cost int
+		err := tx.QueryRow("SELECT stock, cost FROM products WHERE id = $1 FOR UPDATE", l.ProductID).Scan(&stock, &cost)
+		if err != nil {
+			return nil, err
+		}
+		cost = costing.MovingAverage(stock, cost, l.Quantity, l.UnitCost)
+		if _, err := tx.Exec("UPDATE products SET cost = $1 WHERE id = $2", cost, l.ProductID); err != nil {
+			return nil, err
+		}
+		err = applyMovement(tx, &models.StockMovement{
+			ProductID:   l.ProductID,
+			Type:        models.MovementRestock,
+			Quantity:    l.Quantity,
+			Reason:      fmt.Sprintf("penerimaan PO #%d", id),
+			User:        req.ReceivedBy,
+			ReferenceID: &id,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	complete := true
+	for _, l := range orderLines {
+		complete = complete && l.ReceivedQuantity == l.Quantity
+	}
+	if complete {
+		_, err = tx.Exec("UPDATE purchase_orders SET status = $1, received_at = NOW() WHERE id = $2", models.PurchaseReceived, id)
+	} else {
+		_, err = tx.Exec("UPDATE purchase_orders SET status = $1 WHERE id = $2", models.PurchasePartiallyReceived, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	po, err := getPurchaseOrder(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return po, nil
+}
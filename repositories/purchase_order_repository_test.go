@@ -0,0 +1,59 @@
+package repositories_test
+
+import (
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReceiveZeroCost berjalan jika TEST_DB_CONN diisi: barang bonus yang diterima
+// dengan unit_cost 0 menurunkan HPP, sedangkan unit_cost kosong memakai harga di PO.
+func TestReceiveZeroCost(t *testing.T) {
+	conn := os.Getenv("TEST_DB_CONN")
+	if conn == "" {
+		t.Skip("TEST_DB_CONN tidak diisi")
+	}
+	db := openDB(t, conn)
+	_, err := db.Exec("TRUNCATE products, suppliers, purchase_orders RESTART IDENTITY CASCADE")
+	require.NoError(t, err)
+
+	products := repositories.NewProductRepository(db)
+	suppliers := repositories.NewSupplierRepository(db)
+	orders := repositories.NewPurchaseOrderRepository(db)
+
+	product := &models.Product{Name: "Kopi", SKU: "KP-01", Price: 5000, Stock: 10, Cost: 1000}
+	require.NoError(t, products.Create(product))
+	supplier := &models.Supplier{Name: "PT Kopi"}
+	require.NoError(t, suppliers.Create(supplier))
+	po, err := orders.Create(&models.PurchaseOrderRequest{
+		SupplierID: supplier.ID,
+		Lines:      []models.PurchaseLineRequest{{ProductID: product.ID, Quantity: 20, UnitCost: 1000}},
+		CreatedBy:  "siti",
+	})
+	require.NoError(t, err)
+	_, err = orders.Order(po.ID)
+	require.NoError(t, err)
+	lineID := po.Lines[0].ID
+
+	free := 0
+	po, err = orders.Receive(po.ID, &models.GoodsReceiptRequest{
+		Lines:      []models.GoodsReceiptLineRequest{{LineID: lineID, Quantity: 10, UnitCost: &free}},
+		ReceivedBy: "siti",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0, po.Receipts[0].Lines[0].UnitCost)
+	got, err := products.GetByID(product.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 500, got.Cost, "(10 x 1.000 + 10 x 0) / 20")
+
+	po, err = orders.Receive(po.ID, &models.GoodsReceiptRequest{
+		Lines:      []models.GoodsReceiptLineRequest{{LineID: lineID, Quantity: 10}},
+		ReceivedBy: "siti",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1000, po.Receipts[1].Lines[0].UnitCost, "harga beli di PO")
+}
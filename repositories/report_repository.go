@@ -0,0 +1,279 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"kasir-api/models"
+	"time"
+)
+
+// ReportRepository - timezone adalah nama zona IANA toko untuk AT TIME ZONE
+type ReportRepository struct {
+	db       *sql.DB
+	timezone string
+}
+
+func NewReportRepository(db *sql.DB, timezone string) *ReportRepository {
+	return &ReportRepository{db: db, timezone: timezone}
+}
+
+// CheckTimezone - pastikan PostgreSQL mengenal zona waktu toko, supaya TIMEZONE yang
+// salah ketahuan saat server mulai dan bukan saat laporan pertama dibuka
+func (repo *ReportRepository) CheckTimezone() error {
+	var now time.Time
+	if err := repo.db.QueryRow("SELECT NOW() AT TIME ZONE $1", repo.timezone).Scan(&now); err != nil {
+		return fmt.Errorf("zona waktu %q tidak dikenal database: %w", repo.timezone, err)
+	}
+	return nil
+}
+
+// soldLines - baris penjualan transaksi lunas (termasuk yang sudah diretur) dalam
+// rentang [$1, $2) beserta jumlah dan nilai yang diretur lewat retur yang disetujui.
+// restocked hanya menghitung barang yang kembali ke stok; barang write-off tetap menjadi HPP.
+const soldLines = `
+	SELECT d.id, d.transaction_id, d.product_id, d.product_name, d.quantity, d.dpp, d.tax, d.cost, t.created_at,
+		COALESCE(r.returned, 0) AS returned, COALESCE(r.restocked, 0) AS restocked,
+		COALESCE(r.returned_amount, 0) AS returned_amount
+	FROM transaction_details d
+	JOIN transactions t ON t.id = d.transaction_id
+	LEFT JOIN (
+		SELECT rl.transaction_detail_id, SUM(rl.quantity) AS returned,
+			SUM(rl.quantity) FILTER (WHERE rl.disposition = 'restock') AS restocked,
+			SUM(rl.amount) AS returned_amount
+		FROM return_lines rl
+		JOIN returns rt ON rt.id = rl.return_id
+		WHERE rt.status = 'approved'
+		GROUP BY rl.transaction_detail_id
+	) r ON r.transaction_detail_id = d.id
+	WHERE t.status IN ('paid', 'refunded') AND t.created_at >= $1 AND t.created_at < $2`
+
+// paidTransactions - transaksi lunas dalam rentang [$1, $2) beserta total refund dari
+// retur yang disetujui
+const paidTransactions = `
+	SELECT t.id, t.created_at, t.total_amount, t.discount_amount, t.tax_amount,
+		COALESCE(r.refunded, 0) AS refunded
+	FROM transactions t
+	LEFT JOIN (
+		SELECT transaction_id, SUM(refund_amount) AS refunded
+		FROM returns
+		WHERE status = 'approved'
+		GROUP BY transaction_id
+	) r ON r.transaction_id = t.id
+	WHERE t.status IN ('paid', 'refunded') AND t.created_at >= $1 AND t.created_at < $2`
+
+// reportGroup - kolom id, nama dan periode beserta klausa GROUP BY dan ORDER BY untuk
+// satu pengelompokan. Pengelompokan per periode memakai zona waktu toko sebagai $3.
+type reportGroup struct {
+	columns, groupBy, orderBy string
+	zoned                     bool
+}
+
+var marginGroups = map[string]reportGroup{
+	models.GroupByProduct: {
+		columns: "l.product_id, COALESCE(MAX(p.name), MAX(l.product_name)), ''",
+		groupBy: "l.product_id",
+		orderBy: "revenue DESC, 1",
+	},
+	models.GroupByCategory: {
+		columns: "COALESCE(p.category_id, 0), COALESCE(MAX(c.name), 'Tanpa kategori'), ''",
+		groupBy: "COALESCE(p.category_id, 0)",
+		orderBy: "revenue DESC, 1",
+	},
+	models.GroupByDay:   periodGroup("day"),
+	models.GroupByWeek:  periodGroup("week"),
+	models.GroupByMonth: periodGroup("month"),
+}
+
+func periodGroup(unit string) reportGroup {
+	period := periodColumn("l", unit)
+	return reportGroup{columns: "0, '', " + period, groupBy: period, orderBy: "3", zoned: true}
+}
+
+// periodColumn - tanggal awal periode day, week (mulai Senin) atau month dari created_at
+// di zona waktu toko ($3)
+func periodColumn(alias, unit string) string {
+	return fmt.Sprintf("to_char(date_trunc('%s', %s.created_at AT TIME ZONE $3), 'YYYY-MM-DD')", unit, alias)
+}
+
+// Margin - pendapatan, HPP dan margin kotor per kelompok. Retur dihitung pada periode
+// penjualan asalnya supaya margin periode lama ikut terkoreksi.
+func (repo *ReportRepository) Margin(filter models.ReportFilter) ([]models.MarginRow, error) {
+	group, ok := marginGroups[filter.GroupBy]
+	if !ok {
+		return nil, NewError(ErrValidation, "group_by tidak dikenal")
+	}
+
+	query := fmt.Sprintf(`
+		WITH l AS (%s)
+		SELECT %s,
+			SUM(l.quantity - l.returned),
+			SUM(l.dpp * (l.quantity - l.returned) / l.quantity) AS revenue,
+			SUM(l.cost * (l.quantity - l.restocked))
+		FROM l
+		LEFT JOIN products p ON p.id = l.product_id
+		LEFT JOIN categories c ON c.id = p.category_id
+		GROUP BY %s
+		ORDER BY %s
+	`, soldLines, group.columns, group.groupBy, group.orderBy)
+
+	args := []interface{}{filter.Start, filter.End}
+	if group.zoned {
+		args = append(args, repo.timezone)
+	}
+	rows, err := repo.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]models.MarginRow, 0)
+	for rows.Next() {
+		var m models.MarginRow
+		if err := rows.Scan(&m.ID, &m.Name, &m.Period, &m.Quantity, &m.Revenue, &m.Cost); err != nil {
+			return nil, err
+		}
+		result = append(result, m)
+	}
+	return result, rows.Err()
+}
+
+// Sales - jumlah transaksi, penjualan, diskon, pajak dan refund per periode. Hanya
+// periode yang punya transaksi yang dikembalikan.
+func (repo *ReportRepository) Sales(filter models.ReportFilter) ([]models.SalesRow, error) {
+	if filter.GroupBy != models.GroupByDay && filter.GroupBy != models.GroupByWeek && filter.GroupBy != models.GroupByMonth {
+		return nil, NewError(ErrValidation, "group_by tidak dikenal")
+	}
+	period := periodColumn("t", filter.GroupBy)
+	query := fmt.Sprintf(`
+		WITH t AS (%s)
+		SELECT %s AS period, COUNT(*), SUM(t.total_amount), SUM(t.discount_amount),
+			SUM(t.tax_amount), SUM(t.refunded)
+		FROM t
+		GROUP BY period
+		ORDER BY period
+	`, paidTransactions, period)
+
+	rows, err := repo.db.Query(query, filter.Start, filter.End, repo.timezone)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]models.SalesRow, 0)
+	for rows.Next() {
+		var r models.SalesRow
+		if err := rows.Scan(&r.Period, &r.Transactions, &r.Revenue, &r.Discount, &r.Tax, &r.Refunds); err != nil {
+			return nil, err
+		}
+		result = append(result, r)
+	}
+	return result, rows.Err()
+}
+
+// topProductOrder - urutan produk terlaris berdasarkan jumlah terjual atau nilai penjualan
+var topProductOrder = map[string]string{
+	"quantity": "quantity DESC, revenue DESC, l.product_id",
+	"revenue":  "revenue DESC, quantity DESC, l.product_id",
+}
+
+// TopProducts - produk dengan penjualan bersih (setelah retur) terbanyak
+func (repo *ReportRepository) TopProducts(filter models.ReportFilter) ([]models.ProductSales, error) {
+	orderBy, ok := topProductOrder[filter.Sort]
+	if !ok {
+		return nil, NewError(ErrValidation, "sort tidak dikenal")
+	}
+
+	query := fmt.Sprintf(`
+		WITH l AS (%s)
+		SELECT l.product_id, COALESCE(MAX(p.sku), ''), COALESCE(MAX(p.name), MAX(l.product_name)),
+			COALESCE(MAX(c.name), ''),
+			SUM(l.quantity - l.returned) AS quantity,
+			SUM(l.dpp + l.tax - l.returned_amount) AS revenue
+		FROM l
+		LEFT JOIN products p ON p.id = l.product_id
+		LEFT JOIN categories c ON p.category_id = c.id
+		GROUP BY l.product_id
+		HAVING SUM(l.quantity - l.returned) > 0
+		ORDER BY %s
+		LIMIT $3
+	`, soldLines, orderBy)
+
+	rows, err := repo.db.Query(query, filter.Start, filter.End, filter.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]models.ProductSales, 0)
+	for rows.Next() {
+		var r models.ProductSales
+		if err := rows.Scan(&r.ProductID, &r.SKU, &r.Name, &r.CategoryName, &r.Quantity, &r.Revenue); err != nil {
+			return nil, err
+		}
+		result = append(result, r)
+	}
+	return result, rows.Err()
+}
+
+// Categories - penjualan bersih per kategori produk saat ini, produk tanpa kategori
+// dikumpulkan dengan category_id 0
+func (repo *ReportRepository) Categories(filter models.ReportFilter) ([]models.CategorySales, error) {
+	query := fmt.Sprintf(`
+		WITH l AS (%s)
+		SELECT COALESCE(p.category_id, 0), COALESCE(MAX(c.name), 'Tanpa kategori'),
+			COUNT(DISTINCT l.transaction_id),
+			SUM(l.quantity - l.returned),
+			SUM(l.dpp + l.tax - l.returned_amount) AS revenue
+		FROM l
+		LEFT JOIN products p ON p.id = l.product_id
+		LEFT JOIN categories c ON p.category_id = c.id
+		GROUP BY COALESCE(p.category_id, 0)
+		ORDER BY revenue DESC, 1
+	`, soldLines)
+
+	rows, err := repo.db.Query(query, filter.Start, filter.End)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]models.CategorySales, 0)
+	for rows.Next() {
+		var r models.CategorySales
+		if err := rows.Scan(&r.CategoryID, &r.CategoryName, &r.Transactions, &r.Quantity, &r.Revenue); err != nil {
+			return nil, err
+		}
+		result = append(result, r)
+	}
+	return result, rows.Err()
+}
+
+// Hourly - jumlah transaksi dan penjualan per hari dalam minggu (1 = Senin) dan jam
+// di zona waktu toko. Hanya sel yang punya transaksi yang dikembalikan.
+func (repo *ReportRepository) Hourly(filter models.ReportFilter) ([]models.HourlyCell, error) {
+	query := fmt.Sprintf(`
+		WITH t AS (%s)
+		SELECT EXTRACT(ISODOW FROM t.created_at AT TIME ZONE $3)::int AS weekday,
+			EXTRACT(HOUR FROM t.created_at AT TIME ZONE $3)::int AS hour,
+			COUNT(*), SUM(t.total_amount)
+		FROM t
+		GROUP BY weekday, hour
+		ORDER BY weekday, hour
+	`, paidTransactions)
+
+	rows, err := repo.db.Query(query, filter.Start, filter.End, repo.timezone)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]models.HourlyCell, 0)
+	for rows.Next() {
+		var c models.HourlyCell
+		if err := rows.Scan(&c.Weekday, &c.Hour, &c.Transactions, &c.Revenue); err != nil {
+			return nil, err
+		}
+		result = append(result, c)
+	}
+	return result, rows.Err()
+}
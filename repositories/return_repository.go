@@ -0,0 +1,487 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"kasir-api/models"
+	"sort"
+	"strings"
+)
+
+type ReturnRepository struct {
+	db *sql.DB
+}
+
+func NewReturnRepository(db *sql.DB) *ReturnRepository {
+	return &ReturnRepository{db: db}
+}
+
+// returnableLine adalah baris transaksi beserta jumlah yang sudah diretur (pending
+// atau approved), dipakai untuk menjamin retur tidak melebihi yang terjual
+type returnableLine struct {
+	detail         models.TransactionDetail
+	net            int // nilai baris yang dibayar pelanggan
+	returned       int
+	returnedAmount int
+}
+
+// Create - simpan retur pending. Transaksi dikunci FOR UPDATE sehingga dua retur
+// paralel untuk transaksi yang sama dihitung berurutan dan tidak bisa bersama-sama
+// melebihi jumlah terjual. Retur pending ikut dihitung supaya jumlahnya tercadang.
+func (repo *ReturnRepository) Create(req *models.ReturnRequest) (*models.Return, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var status string
+	var pricesIncludeTax bool
+	var pointValue, pointsRedeemed int
+	err = tx.QueryRow("SELECT status, prices_include_tax, point_value, points_redeemed FROM transactions WHERE id = $1 FOR UPDATE", req.TransactionID).
+		Scan(&status, &pricesIncludeTax, &pointValue, &pointsRedeemed)
+	if err == sql.ErrNoRows {
+		return nil, NewError(ErrNotFound, "transaksi tidak ditemukan")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if status != models.TransactionPaid {
+		return nil, NewError(ErrConflict, fmt.Sprintf("transaksi berstatus %s tidak bisa diretur", status))
+	}
+
+	lines, err := returnableLines(tx, req.TransactionID, pricesIncludeTax)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := models.Return{
+		TransactionID: req.TransactionID,
+		Status:        models.ReturnPending,
+		Reason:        req.Reason,
+		RequestedBy:   req.RequestedBy,
+		Lines:         make([]models.ReturnLine, 0, len(req.Lines)),
+	}
+	for _, l := range req.Lines {
+		line, ok := lines[l.TransactionDetailID]
+		if !ok {
+			return nil, NewError(ErrValidation, fmt.Sprintf("baris transaksi id %d bukan bagian dari transaksi #%d", l.TransactionDetailID, req.TransactionID))
+		}
+		remaining := line.detail.Quantity - line.returned
+		if l.Quantity > remaining {
+			return nil, NewError(ErrValidation, fmt.Sprintf("retur %s melebihi jumlah terjual, sisa %d", line.detail.ProductName, remaining))
+		}
+
+		// Sisa terakhir mengambil sisa nilai supaya total refund tepat sama dengan
+		// nilai baris meskipun diretur bertahap
+		amount := line.net * l.Quantity / line.detail.Quantity
+		if l.Quantity == remaining {
+			amount = line.net - line.returnedAmount
+		}
+		line.returned += l.Quantity
+		line.returnedAmount += amount
+
+		ret.RefundAmount += amount
+		ret.Lines = append(ret.Lines, models.ReturnLine{
+			TransactionDetailID: l.TransactionDetailID,
+			ProductID:           line.detail.ProductID,
+			ProductName:         line.detail.ProductName,
+			Quantity:            l.Quantity,
+			Amount:              amount,
+			Disposition:         l.Disposition,
+		})
+	}
+
+	ret.Refunds, err = settleRefunds(req.Refunds, ret.RefundAmount)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPointRefunds(tx, req.TransactionID, ret.Refunds, pointValue, pointsRedeemed); err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO returns (transaction_id, status, reason, refund_amount, requested_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+	err = tx.QueryRow(query, ret.TransactionID, ret.Status, ret.Reason, ret.RefundAmount, ret.RequestedBy).
+		Scan(&ret.ID, &ret.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range ret.Lines {
+		l := &ret.Lines[i]
+		err = tx.QueryRow(`
+			INSERT INTO return_lines (return_id, transaction_detail_id, product_id, product_name, quantity, amount, disposition)
+			VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`,
+			ret.ID, l.TransactionDetailID, l.ProductID, l.ProductName, l.Quantity, l.Amount, l.Disposition,
+		).Scan(&l.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range ret.Refunds {
+		r := &ret.Refunds[i]
+		err = tx.QueryRow(
+			"INSERT INTO refunds (return_id, method, amount, reference) VALUES ($1, $2, $3, $4) RETURNING id, created_at",
+			ret.ID, r.Method, r.Amount, r.Reference,
+		).Scan(&r.ID, &r.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// returnableLines - baris transaksi beserta jumlah dan nilai yang sudah diretur
+func returnableLines(tx *sql.Tx, transactionID int, pricesIncludeTax bool) (map[int]*returnableLine, error) {
+	query := `
+		SELECT d.id, d.product_id, d.product_name, d.quantity, d.subtotal, d.discount, d.tax,
+			COALESCE(SUM(rl.quantity), 0), COALESCE(SUM(rl.amount), 0)
+		FROM transaction_details d
+		LEFT JOIN return_lines rl ON rl.transaction_detail_id = d.id
+			AND rl.return_id IN (SELECT id FROM returns WHERE status <> 'rejected')
+		WHERE d.transaction_id = $1
+		GROUP BY d.id
+	`
+	rows, err := tx.Query(query, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lines := make(map[int]*returnableLine)
+	for rows.Next() {
+		var l returnableLine
+		d := &l.detail
+		err := rows.Scan(&d.ID, &d.ProductID, &d.ProductName, &d.Quantity, &d.Subtotal, &d.Discount, &d.Tax,
+			&l.returned, &l.returnedAmount)
+		if err != nil {
+			return nil, err
+		}
+		l.net = d.Subtotal - d.Discount
+		if !pricesIncludeTax {
+			l.net += d.Tax
+		}
+		lines[d.ID] = &l
+	}
+	return lines, rows.Err()
+}
+
+// settleRefunds - tanpa rincian, seluruh nilai retur dikembalikan tunai. Jika dirinci,
+// jumlahnya harus tepat sama dengan nilai retur.
+func settleRefunds(requests []models.PaymentRequest, total int) ([]models.Refund, error) {
+	refunds := make([]models.Refund, 0, len(requests))
+	if len(requests) == 0 {
+		if total > 0 {
+			refunds = append(refunds, models.Refund{Method: models.PaymentCash, Amount: total})
+		}
+		return refunds, nil
+	}
+
+	sum := 0
+	for _, r := range requests {
+		sum += r.Amount
+		refunds = append(refunds, models.Refund{Method: r.Method, Amount: r.Amount, Reference: r.Reference})
+	}
+	if sum != total {
+		return nil, NewError(ErrValidation, fmt.Sprintf("jumlah refund %d harus sama dengan nilai retur %d", sum, total))
+	}
+	return refunds, nil
+}
+
+// checkPointRefunds - refund dengan metode poin hanya untuk transaksi yang dibayar
+// dengan poin, dalam kelipatan nilai poin saat checkout, dan totalnya (termasuk retur
+// lain yang belum ditolak) tidak melebihi poin yang dipakai
+func checkPointRefunds(tx *sql.Tx, transactionID int, refunds []models.Refund, pointValue, pointsRedeemed int) error {
+	amount := 0
+	for _, r := range refunds {
+		if r.Method == models.PaymentPoints {
+			amount += r.Amount
+		}
+	}
+	if amount == 0 {
+		return nil
+	}
+	if pointsRedeemed == 0 {
+		return NewError(ErrValidation, "transaksi tidak dibayar dengan poin")
+	}
+	if amount%pointValue != 0 {
+		return NewError(ErrValidation, fmt.Sprintf("refund poin harus kelipatan Rp %d", pointValue))
+	}
+
+	var refunded int
+	err := tx.QueryRow(`
+		SELECT COALESCE(SUM(f.amount), 0) FROM refunds f
+		JOIN returns r ON r.id = f.return_id
+		WHERE r.transaction_id = $1 AND r.status <> 'rejected' AND f.method = $2`,
+		transactionID, models.PaymentPoints,
+	).Scan(&refunded)
+	if err != nil {
+		return err
+	}
+	if remaining := pointsRedeemed*pointValue - refunded; amount > remaining {
+		return NewError(ErrValidation, fmt.Sprintf("refund poin melebihi pembayaran poin, sisa Rp %d", remaining))
+	}
+	return nil
+}
+
+// Approve - setujui retur pending: barang restock dikembalikan ke stok lewat ledger
+// transaksi ditandai refunded jika semua itemnya sudah diretur, dan poin pelanggan
+// disesuaikan (poin belanja ditarik, refund poin dikembalikan)
+func (repo *ReturnRepository) Approve(id int, decision models.ReturnDecision) (*models.Return, error) {
+	return repo.decide(id, models.ReturnApproved, decision)
+}
+
+// Reject - tolak retur pending, jumlah yang dicadangkan kembali bisa diretur
+func (repo *ReturnRepository) Reject(id int, decision models.ReturnDecision) (*models.Return, error) {
+	return repo.decide(id, models.ReturnRejected, decision)
+}
+
+func (repo *ReturnRepository) decide(id int, status string, decision models.ReturnDecision) (*models.Return, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Transaksi dikunci lebih dulu, urutan yang sama dengan Create
+	var transactionID int
+	err = tx.QueryRow("SELECT transaction_id FROM returns WHERE id = $1", id).Scan(&transactionID)
+	if err == sql.ErrNoRows {
+		return nil, NewError(ErrNotFound, "retur tidak ditemukan")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec("SELECT 1 FROM transactions WHERE id = $1 FOR UPDATE", transactionID); err != nil {
+		return nil, err
+	}
+
+	ret, err := getReturn(tx, id, true)
+	if err != nil {
+		return nil, err
+	}
+	if ret.Status != models.ReturnPending {
+		return nil, NewError(ErrConflict, fmt.Sprintf("retur sudah %s", ret.Status))
+	}
+
+	err = tx.QueryRow(`
+		UPDATE returns SET status = $1, decided_by = $2, decision_note = $3, decided_at = NOW()
+		WHERE id = $4 RETURNING decided_at`,
+		status, decision.DecidedBy, decision.Note, id,
+	).Scan(&ret.DecidedAt)
+	if err != nil {
+		return nil, err
+	}
+	ret.Status = status
+	ret.DecidedBy = decision.DecidedBy
+	ret.DecisionNote = decision.Note
+
+	if status == models.ReturnApproved {
+		// Refund tunai keluar dari laci kasir yang mengajukan retur, jika shiftnya masih terbuka
+		ret.ShiftID, err = openShiftID(tx, ret.RequestedBy)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec("UPDATE returns SET shift_id = NULLIF($1, 0) WHERE id = $2", ret.ShiftID, id); err != nil {
+			return nil, err
+		}
+
+		if err := restockReturn(tx, ret); err != nil {
+			return nil, err
+		}
+		if err := reverseReturnPoints(tx, ret); err != nil {
+			return nil, err
+		}
+
+		var open int
+		err = tx.QueryRow(`
+			SELECT COUNT(*) FROM transaction_details d
+			WHERE d.transaction_id = $1 AND d.quantity > (
+				SELECT COALESCE(SUM(rl.quantity), 0) FROM return_lines rl
+				JOIN returns r ON r.id = rl.return_id
+				WHERE rl.transaction_detail_id = d.id AND r.status = 'approved'
+			)`, transactionID,
+		).Scan(&open)
+		if err != nil {
+			return nil, err
+		}
+		if open == 0 {
+			if _, err := tx.Exec("UPDATE transactions SET status = $1 WHERE id = $2", models.TransactionRefunded, transactionID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// restockReturn - catat movement return untuk baris restock di outlet transaksi asal.
+// Barang write-off tidak menambah stok karena tidak bisa dijual lagi.
+func restockReturn(tx *sql.Tx, ret *models.Return) error {
+	var outletID int
+	if err := tx.QueryRow("SELECT outlet_id FROM transactions WHERE id = $1", ret.TransactionID).Scan(&outletID); err != nil {
+		return err
+	}
+
+	quantities := make(map[int]int)
+	for _, l := range ret.Lines {
+		if l.Disposition == models.DispositionRestock {
+			quantities[l.ProductID] += l.Quantity
+		}
+	}
+	productIDs := make([]int, 0, len(quantities))
+	for id := range quantities {
+		productIDs = append(productIDs, id)
+	}
+	sort.Ints(productIDs)
+
+	for _, productID := range productIDs {
+		var locked int
+		err := tx.QueryRow("SELECT id FROM products WHERE id = $1 FOR UPDATE", productID).Scan(&locked)
+		if err == sql.ErrNoRows {
+			// Produk sudah dihapus, tidak ada stok yang bisa dikembalikan
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		err = applyMovement(tx, &models.StockMovement{
+			ProductID:   productID,
+			OutletID:    outletID,
+			Type:        models.MovementReturn,
+			Quantity:    quantities[productID],
+			Reason:      fmt.Sprintf("retur #%d transaksi #%d", ret.ID, ret.TransactionID),
+			User:        ret.DecidedBy,
+			ReferenceID: &ret.TransactionID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (repo *ReturnRepository) GetByID(id int) (*models.Return, error) {
+	return getReturn(repo.db, id, false)
+}
+
+// GetAll - ambil retur per halaman, terbaru lebih dulu. Baris dan refund tidak ikut dimuat.
+func (repo *ReturnRepository) GetAll(filter models.ReturnFilter) ([]models.Return, int, error) {
+	conditions := make([]string, 0, 2)
+	args := make([]interface{}, 0, 4)
+	if filter.TransactionID > 0 {
+		args = append(args, filter.TransactionID)
+		conditions = append(conditions, fmt.Sprintf("transaction_id = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	err := repo.db.QueryRow("SELECT COUNT(*) FROM returns "+whereClause, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, filter.Limit, (filter.Page-1)*filter.Limit)
+	query := fmt.Sprintf("SELECT %s FROM returns %s ORDER BY id DESC LIMIT $%d OFFSET $%d",
+		returnColumns, whereClause, len(args)-1, len(args))
+	rows, err := repo.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	returns := make([]models.Return, 0)
+	for rows.Next() {
+		r, err := scanReturn(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		returns = append(returns, r)
+	}
+	return returns, total, rows.Err()
+}
+
+const returnColumns = `
+	id, transaction_id, status, reason, refund_amount, requested_by, decided_by, decision_note, decided_at,
+	COALESCE(shift_id, 0), created_at`
+
+func scanReturn(row rowScanner) (models.Return, error) {
+	var r models.Return
+	var decidedAt sql.NullTime
+	err := row.Scan(&r.ID, &r.TransactionID, &r.Status, &r.Reason, &r.RefundAmount, &r.RequestedBy,
+		&r.DecidedBy, &r.DecisionNote, &decidedAt, &r.ShiftID, &r.CreatedAt)
+	if decidedAt.Valid {
+		r.DecidedAt = &decidedAt.Time
+	}
+	return r, err
+}
+
+// getReturn - retur beserta baris dan refund, opsional dikunci FOR UPDATE
+func getReturn(q queryer, id int, lock bool) (*models.Return, error) {
+	query := "SELECT " + returnColumns + " FROM returns WHERE id = $1"
+	if lock {
+		query += " FOR UPDATE"
+	}
+	r, err := scanReturn(q.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, NewError(ErrNotFound, "retur tidak ditemukan")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := q.Query(`
+		SELECT id, transaction_detail_id, product_id, product_name, quantity, amount, disposition
+		FROM return_lines WHERE return_id = $1 ORDER BY id`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	r.Lines = make([]models.ReturnLine, 0)
+	for rows.Next() {
+		var l models.ReturnLine
+		if err := rows.Scan(&l.ID, &l.TransactionDetailID, &l.ProductID, &l.ProductName, &l.Quantity, &l.Amount, &l.Disposition); err != nil {
+			return nil, err
+		}
+		r.Lines = append(r.Lines, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	refunds, err := q.Query("SELECT id, method, amount, reference, created_at FROM refunds WHERE return_id = $1 ORDER BY id", id)
+	if err != nil {
+		return nil, err
+	}
+	defer refunds.Close()
+
+	r.Refunds = make([]models.Refund, 0)
+	for refunds.Next() {
+		var f models.Refund
+		if err := refunds.Scan(&f.ID, &f.Method, &f.Amount, &f.Reference, &f.CreatedAt); err != nil {
+			return nil, err
+		}
+		r.Refunds = append(r.Refunds, f)
+	}
+	return &r, refunds.Err()
+}
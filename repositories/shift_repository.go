@@ -0,0 +1,405 @@
+package repositories
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"kasir-api/models"
+	"strings"
+	"time"
+)
+
+type ShiftRepository struct {
+	db *sql.DB
+}
+
+func NewShiftRepository(db *sql.DB) *ShiftRepository {
+	return &ShiftRepository{db: db}
+}
+
+const shiftColumns = `
+	id, cashier, status, opening_float, expected_cash, counted_cash, variance, note,
+	opened_at, closed_at, closed_by, locked_at, locked_by`
+
+func scanShift(row rowScanner) (models.Shift, error) {
+	var s models.Shift
+	var expected, counted, variance sql.NullInt64
+	var closedAt, lockedAt sql.NullTime
+	err := row.Scan(&s.ID, &s.Cashier, &s.Status, &s.OpeningFloat, &expected, &counted, &variance, &s.Note,
+		&s.OpenedAt, &closedAt, &s.ClosedBy, &lockedAt, &s.LockedBy)
+	s.ExpectedCash = nullInt(expected)
+	s.CountedCash = nullInt(counted)
+	s.Variance = nullInt(variance)
+	if closedAt.Valid {
+		s.ClosedAt = &closedAt.Time
+	}
+	if lockedAt.Valid {
+		s.LockedAt = &lockedAt.Time
+	}
+	return s, err
+}
+
+func nullInt(n sql.NullInt64) *int {
+	if !n.Valid {
+		return nil
+	}
+	v := int(n.Int64)
+	return &v
+}
+
+func getShift(q queryer, condition string, args ...interface{}) (*models.Shift, error) {
+	s, err := scanShift(q.QueryRow("SELECT "+shiftColumns+" FROM shifts WHERE "+condition, args...))
+	if err == sql.ErrNoRows {
+		return nil, NewError(ErrNotFound, "shift tidak ditemukan")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// openShiftID - shift terbuka milik kasir, 0 jika tidak ada. Baris shift dikunci
+// FOR SHARE supaya tidak ditutup selagi transaksi yang menempel padanya disimpan.
+func openShiftID(tx *sql.Tx, cashier string) (int, error) {
+	var id int
+	err := tx.QueryRow("SELECT id FROM shifts WHERE cashier = $1 AND status = 'open' FOR SHARE", cashier).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return id, err
+}
+
+// Open - buka shift baru, satu kasir hanya boleh punya satu shift terbuka
+func (repo *ShiftRepository) Open(shift *models.Shift) error {
+	query := `
+		INSERT INTO shifts (cashier, status, opening_float, note)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, opened_at
+	`
+	err := repo.db.QueryRow(query, shift.Cashier, models.ShiftOpen, shift.OpeningFloat, shift.Note).
+		Scan(&shift.ID, &shift.OpenedAt)
+	err = mapDBError(err)
+	if errors.Is(err, ErrConflict) {
+		return NewError(ErrConflict, fmt.Sprintf("kasir %s masih punya shift terbuka", shift.Cashier))
+	}
+	if err != nil {
+		return err
+	}
+	shift.Status = models.ShiftOpen
+	return nil
+}
+
+func (repo *ShiftRepository) GetByID(id int) (*models.Shift, error) {
+	return getShift(repo.db, "id = $1", id)
+}
+
+// GetOpen - shift yang sedang terbuka milik kasir
+func (repo *ShiftRepository) GetOpen(cashier string) (*models.Shift, error) {
+	shift, err := getShift(repo.db, "cashier = $1 AND status = 'open'", cashier)
+	if errors.Is(err, ErrNotFound) {
+		return nil, NewError(ErrNotFound, "tidak ada shift terbuka")
+	}
+	return shift, err
+}
+
+// GetAll - ambil shift per halaman, terbaru lebih dulu
+func (repo *ShiftRepository) GetAll(filter models.ShiftFilter) ([]models.Shift, int, error) {
+	conditions := make([]string, 0, 2)
+	args := make([]interface{}, 0, 4)
+	if filter.Cashier != "" {
+		args = append(args, filter.Cashier)
+		conditions = append(conditions, fmt.Sprintf("cashier = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	err := repo.db.QueryRow("SELECT COUNT(*) FROM shifts "+whereClause, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, filter.Limit, (filter.Page-1)*filter.Limit)
+	query := fmt.Sprintf("SELECT %s FROM shifts %s ORDER BY id DESC LIMIT $%d OFFSET $%d",
+		shiftColumns, whereClause, len(args)-1, len(args))
+	rows, err := repo.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	shifts := make([]models.Shift, 0)
+	for rows.Next() {
+		s, err := scanShift(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		shifts = append(shifts, s)
+	}
+	return shifts, total, rows.Err()
+}
+
+// lockShift - kunci shift FOR UPDATE dan pastikan statusnya sesuai
+func lockShift(tx *sql.Tx, id int, status string) (*models.Shift, error) {
+	shift, err := getShift(tx, "id = $1 FOR UPDATE", id)
+	if err != nil {
+		return nil, err
+	}
+	if shift.Status != status {
+		return nil, NewError(ErrConflict, fmt.Sprintf("shift sudah %s", shift.Status))
+	}
+	return shift, nil
+}
+
+// AddCashEvent - catat kas masuk/keluar pada shift yang masih terbuka
+func (repo *ShiftRepository) AddCashEvent(event *models.CashEvent) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := lockShift(tx, event.ShiftID, models.ShiftOpen); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO shift_cash_events (shift_id, type, amount, reason, username)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+	err = tx.QueryRow(query, event.ShiftID, event.Type, event.Amount, event.Reason, event.User).
+		Scan(&event.ID, &event.CreatedAt)
+	if err != nil {
+		return mapDBError(err)
+	}
+
+	return tx.Commit()
+}
+
+// Close - tutup shift dengan uang hasil hitung. Kas seharusnya dihitung saat itu juga
+// dan disimpan bersama selisihnya; setelah ditutup tidak ada transaksi baru yang masuk.
+func (repo *ShiftRepository) Close(id int, req *models.CloseShiftRequest) (*models.Shift, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	shift, err := lockShift(tx, id, models.ShiftOpen)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := summarizeShift(tx, shift)
+	if err != nil {
+		return nil, err
+	}
+	variance := req.CountedCash - report.ExpectedCash
+
+	note := shift.Note
+	if req.Note != "" {
+		note = req.Note
+	}
+	query := `
+		UPDATE shifts SET status = $1, expected_cash = $2, counted_cash = $3, variance = $4, note = $5,
+			closed_at = NOW(), closed_by = $6
+		WHERE id = $7
+	`
+	_, err = tx.Exec(query, models.ShiftClosed, report.ExpectedCash, req.CountedCash, variance, note, req.ClosedBy, id)
+	if err != nil {
+		return nil, mapDBError(err)
+	}
+
+	shift, err = getShift(tx, "id = $1", id)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return shift, nil
+}
+
+// Report - X report dari data terkini. Shift yang sudah locked mengembalikan Z report tersimpan.
+func (repo *ShiftRepository) Report(id int) (*models.ShiftReport, error) {
+	shift, err := repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if shift.Status == models.ShiftLocked {
+		return repo.storedZReport(id)
+	}
+
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	report, err := summarizeShift(tx, shift)
+	if err != nil {
+		return nil, err
+	}
+	report.Type = models.ReportX
+	return report, tx.Commit()
+}
+
+// Lock - buat Z report untuk shift yang sudah ditutup lalu kunci shift. Laporan
+// disimpan apa adanya sehingga cetak ulang selalu sama meskipun data berubah.
+// Shift yang sudah locked mengembalikan laporan tersimpan.
+func (repo *ShiftRepository) Lock(id int, user string) (*models.ShiftReport, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	shift, err := getShift(tx, "id = $1 FOR UPDATE", id)
+	if err != nil {
+		return nil, err
+	}
+	switch shift.Status {
+	case models.ShiftLocked:
+		return repo.storedZReport(id)
+	case models.ShiftOpen:
+		return nil, NewError(ErrConflict, "shift harus ditutup sebelum Z report")
+	}
+
+	lockedAt := time.Now()
+	shift.Status = models.ShiftLocked
+	shift.LockedAt = &lockedAt
+	shift.LockedBy = user
+
+	report, err := summarizeShift(tx, shift)
+	if err != nil {
+		return nil, err
+	}
+	report.Type = models.ReportZ
+	report.GeneratedAt = lockedAt
+
+	body, err := json.Marshal(report)
+	if err != nil {
+		return nil, err
+	}
+	query := "UPDATE shifts SET status = $1, locked_at = $2, locked_by = $3, z_report = $4 WHERE id = $5"
+	if _, err := tx.Exec(query, models.ShiftLocked, lockedAt, user, body, id); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (repo *ShiftRepository) storedZReport(id int) (*models.ShiftReport, error) {
+	var body []byte
+	if err := repo.db.QueryRow("SELECT z_report FROM shifts WHERE id = $1", id).Scan(&body); err != nil {
+		return nil, err
+	}
+	var report models.ShiftReport
+	if err := json.Unmarshal(body, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// summarizeShift - hitung rekap penjualan dan kas shift. Transaksi batal tidak dihitung;
+// penjualan tunai adalah pembayaran tunai dikurangi kembalian.
+func summarizeShift(tx *sql.Tx, shift *models.Shift) (*models.ShiftReport, error) {
+	report := &models.ShiftReport{
+		Shift:        *shift,
+		GeneratedAt:  time.Now(),
+		OpeningFloat: shift.OpeningFloat,
+		CountedCash:  shift.CountedCash,
+		Payments:     make([]models.PaymentTotal, 0),
+		CashEvents:   make([]models.CashEvent, 0),
+	}
+
+	var change int
+	err := tx.QueryRow(`
+		SELECT COUNT(*) FILTER (WHERE status <> 'cancelled'),
+			COUNT(*) FILTER (WHERE status = 'cancelled'),
+			COALESCE(SUM(total_amount) FILTER (WHERE status <> 'cancelled'), 0),
+			COALESCE(SUM(discount_amount) FILTER (WHERE status <> 'cancelled'), 0),
+			COALESCE(SUM(tax_amount) FILTER (WHERE status <> 'cancelled'), 0),
+			COALESCE(SUM(change_amount) FILTER (WHERE status <> 'cancelled'), 0)
+		FROM transactions WHERE shift_id = $1`, shift.ID,
+	).Scan(&report.TransactionCount, &report.CancelledCount, &report.GrossSales, &report.DiscountTotal, &report.TaxTotal, &change)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(`
+		SELECT p.method, COUNT(*), SUM(p.amount)
+		FROM payments p
+		JOIN transactions t ON t.id = p.transaction_id
+		WHERE t.shift_id = $1 AND t.status <> 'cancelled' AND p.status = 'paid'
+		GROUP BY p.method
+		ORDER BY p.method`, shift.ID)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var p models.PaymentTotal
+		if err := rows.Scan(&p.Method, &p.Count, &p.Amount); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if p.Method == models.PaymentCash {
+			report.CashSales = p.Amount - change
+		}
+		report.Payments = append(report.Payments, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRow(`
+		SELECT COUNT(*), COALESCE(SUM(refund_amount), 0),
+			COALESCE(SUM((SELECT SUM(f.amount) FROM refunds f WHERE f.return_id = r.id AND f.method = 'cash')), 0)
+		FROM returns r WHERE r.shift_id = $1 AND r.status = 'approved'`, shift.ID,
+	).Scan(&report.ReturnCount, &report.RefundTotal, &report.CashRefunds)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err = tx.Query(`
+		SELECT id, shift_id, type, amount, reason, username, created_at
+		FROM shift_cash_events WHERE shift_id = $1 ORDER BY id`, shift.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var e models.CashEvent
+		if err := rows.Scan(&e.ID, &e.ShiftID, &e.Type, &e.Amount, &e.Reason, &e.User, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		if e.Type == models.CashIn {
+			report.CashIn += e.Amount
+		} else {
+			report.CashOut += e.Amount
+		}
+		report.CashEvents = append(report.CashEvents, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	report.ExpectedCash = report.OpeningFloat + report.CashSales + report.CashIn - report.CashOut - report.CashRefunds
+	if report.CountedCash != nil {
+		variance := *report.CountedCash - report.ExpectedCash
+		report.Variance = &variance
+	}
+	return report, nil
+}
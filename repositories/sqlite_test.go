@@ -0,0 +1,6 @@
+//go:build sqlite
+
+package repositories_test
+
+// sqliteBuilt - driver SQLite ikut di-build, jadi TestCatalogSQLite wajib jalan
+const sqliteBuilt = true
@@ -0,0 +1,183 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"kasir-api/models"
+)
+
+type StockMovementRepository struct {
+	db *sql.DB
+}
+
+func NewStockMovementRepository(db *sql.DB) *StockMovementRepository {
+	return &StockMovementRepository{db: db}
+}
+
+// Create - catat pergerakan stok dan sesuaikan stok produk serta stok outletnya dalam
+// satu transaction. Stok outlet tidak boleh menjadi negatif.
+func (repo *StockMovementRepository) Create(movement *models.StockMovement) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var locked int
+	err = tx.QueryRow("SELECT id FROM products WHERE id = $1 FOR UPDATE", movement.ProductID).Scan(&locked)
+	if err == sql.ErrNoRows {
+		return NewError(ErrNotFound, "produk tidak ditemukan")
+	}
+	if err != nil {
+		return err
+	}
+
+	if movement.OutletID == 0 {
+		if movement.OutletID, err = defaultOutletID(tx); err != nil {
+			return err
+		}
+	} else {
+		var exists bool
+		err = tx.QueryRow("SELECT EXISTS (SELECT 1 FROM outlets WHERE id = $1)", movement.OutletID).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return NewError(ErrValidation, fmt.Sprintf("outlet id %d tidak ditemukan", movement.OutletID))
+		}
+	}
+	stock, err := outletStock(tx, movement.OutletID, movement.ProductID)
+	if err != nil {
+		return err
+	}
+	if stock+movement.Quantity < 0 {
+		return NewError(ErrConflict, "stok outlet tidak boleh kurang dari 0")
+	}
+
+	if err := applyMovement(tx, movement); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetByProductID - riwayat pergerakan stok sebuah produk, terbaru dulu
+func (repo *StockMovementRepository) GetByProductID(productID int) ([]models.StockMovement, error) {
+	query := `
+		SELECT id, product_id, outlet_id, type, quantity, reason, username, reference_id, created_at
+		FROM stock_movements
+		WHERE product_id = $1
+		ORDER BY created_at DESC, id DESC
+	`
+	rows, err := repo.db.Query(query, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	movements := make([]models.StockMovement, 0)
+	for rows.Next() {
+		var m models.StockMovement
+		var ref sql.NullInt64
+		err := rows.Scan(&m.ID, &m.ProductID, &m.OutletID, &m.Type, &m.Quantity, &m.Reason, &m.User, &ref, &m.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if ref.Valid {
+			id := int(ref.Int64)
+			m.ReferenceID = &id
+		}
+		movements = append(movements, m)
+	}
+
+	return movements, rows.Err()
+}
+
+// GetLedgerStock - hitung stok dari total seluruh pergerakan di ledger
+func (repo *StockMovementRepository) GetLedgerStock(productID int) (int, error) {
+	var stock int
+	err := repo.db.QueryRow("SELECT COALESCE(SUM(quantity), 0) FROM stock_movements WHERE product_id = $1", productID).Scan(&stock)
+	return stock, err
+}
+
+// GetOutletStocks - stok produk per outlet beserta barang yang sedang dikirim ke outlet
+// tersebut. Outlet nonaktif hanya ditampilkan jika masih punya stok atau kiriman.
+func (repo *StockMovementRepository) GetOutletStocks(productID int) ([]models.OutletStock, error) {
+	query := `
+		SELECT o.id, o.code, o.name, COALESCE(s.stock, 0), COALESCE(t.quantity, 0)
+		FROM outlets o
+		LEFT JOIN outlet_stocks s ON s.outlet_id = o.id AND s.product_id = $1
+		LEFT JOIN (
+			SELECT tr.to_outlet_id, SUM(l.quantity) AS quantity
+			FROM stock_transfers tr
+			JOIN stock_transfer_lines l ON l.transfer_id = tr.id
+			WHERE tr.status = $2 AND l.product_id = $1
+			GROUP BY tr.to_outlet_id
+		) t ON t.to_outlet_id = o.id
+		WHERE o.active OR COALESCE(s.stock, 0) <> 0 OR COALESCE(t.quantity, 0) > 0
+		ORDER BY o.is_default DESC, o.name, o.id
+	`
+	rows, err := repo.db.Query(query, productID, models.TransferSent)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stocks := make([]models.OutletStock, 0)
+	for rows.Next() {
+		var s models.OutletStock
+		if err := rows.Scan(&s.OutletID, &s.OutletCode, &s.OutletName, &s.Stock, &s.InTransit); err != nil {
+			return nil, err
+		}
+		stocks = append(stocks, s)
+	}
+	return stocks, rows.Err()
+}
+
+// applyMovement - sisipkan baris ledger dan perbarui stok produk serta stok outletnya di
+// dalam tx yang sedang berjalan. Movement tanpa outlet dicatat di outlet default.
+// Pemanggil bertanggung jawab mengunci baris produk terlebih dahulu. Produk induk yang
+// punya varian ditolak karena stoknya dicatat per varian.
+func applyMovement(tx execQueryer, movement *models.StockMovement) error {
+	if movement.OutletID == 0 {
+		id, err := defaultOutletID(tx)
+		if err != nil {
+			return err
+		}
+		movement.OutletID = id
+	}
+
+	var name string
+	err := tx.QueryRow(
+		"SELECT name FROM products p WHERE id = $1 AND EXISTS (SELECT 1 FROM products v WHERE v.parent_id = p.id)",
+		movement.ProductID,
+	).Scan(&name)
+	if err == nil {
+		return NewError(ErrValidation, fmt.Sprintf("%s punya varian, pilih variannya", name))
+	}
+	if err != sql.ErrNoRows {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE products SET stock = stock + $1 WHERE id = $2", movement.Quantity, movement.ProductID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`
+		INSERT INTO outlet_stocks (outlet_id, product_id, stock) VALUES ($1, $2, $3)
+		ON CONFLICT (outlet_id, product_id) DO UPDATE SET stock = outlet_stocks.stock + excluded.stock`,
+		movement.OutletID, movement.ProductID, movement.Quantity,
+	)
+	if err != nil {
+		return mapDBError(err)
+	}
+
+	query := `
+		INSERT INTO stock_movements (product_id, outlet_id, type, quantity, reason, username, reference_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`
+	return tx.QueryRow(query,
+		movement.ProductID, movement.OutletID, movement.Type, movement.Quantity, movement.Reason, movement.User, movement.ReferenceID,
+	).Scan(&movement.ID, &movement.CreatedAt)
+}
@@ -0,0 +1,403 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"kasir-api/models"
+	"sort"
+	"strings"
+)
+
+type StockTakeRepository struct {
+	db *sql.DB
+}
+
+func NewStockTakeRepository(db *sql.DB) *StockTakeRepository {
+	return &StockTakeRepository{db: db}
+}
+
+const stockTakeColumns = `
+	st.id, st.outlet_id, o.name, COALESCE(st.category_id, 0), COALESCE(c.name, ''), st.status, st.note,
+	st.created_by, st.approved_by, st.approved_at, st.cancelled_at, st.created_at`
+
+const stockTakeFrom = `
+	FROM stock_takes st
+	JOIN outlets o ON o.id = st.outlet_id
+	LEFT JOIN categories c ON c.id = st.category_id`
+
+func scanStockTake(row rowScanner) (models.StockTake, error) {
+	var st models.StockTake
+	var approvedAt, cancelledAt sql.NullTime
+	err := row.Scan(&st.ID, &st.OutletID, &st.OutletName, &st.CategoryID, &st.CategoryName, &st.Status, &st.Note,
+		&st.CreatedBy, &st.ApprovedBy, &approvedAt, &cancelledAt, &st.CreatedAt)
+	if approvedAt.Valid {
+		st.ApprovedAt = &approvedAt.Time
+	}
+	if cancelledAt.Valid {
+		st.CancelledAt = &cancelledAt.Time
+	}
+	return st, err
+}
+
+const stockTakeLineColumns = "id, product_id, product_name, system_stock, cost, counted, counted_by, counted_at"
+
+// scanStockTakeLine - baca baris beserta selisihnya jika sudah dihitung
+func scanStockTakeLine(row rowScanner) (models.StockTakeLine, error) {
+	var l models.StockTakeLine
+	var counted sql.NullInt64
+	var countedAt sql.NullTime
+	err := row.Scan(&l.ID, &l.ProductID, &l.ProductName, &l.SystemStock, &l.Cost, &counted, &l.CountedBy, &countedAt)
+	if counted.Valid {
+		c := int(counted.Int64)
+		variance := c - l.SystemStock
+		value := variance * l.Cost
+		l.Counted, l.Variance, l.VarianceValue = &c, &variance, &value
+	}
+	if countedAt.Valid {
+		l.CountedAt = &countedAt.Time
+	}
+	return l, err
+}
+
+// GetAll - sesi stock opname per halaman, terbaru lebih dulu. Baris tidak ikut dimuat.
+func (repo *StockTakeRepository) GetAll(filter models.StockTakeFilter) ([]models.StockTake, int, error) {
+	conditions := make([]string, 0, 2)
+	args := make([]interface{}, 0, 4)
+	if filter.OutletID > 0 {
+		args = append(args, filter.OutletID)
+		conditions = append(conditions, fmt.Sprintf("st.outlet_id = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("st.status = $%d", len(args)))
+	}
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	err := repo.db.QueryRow("SELECT COUNT(*) FROM stock_takes st "+whereClause, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, filter.Limit, (filter.Page-1)*filter.Limit)
+	query := fmt.Sprintf("SELECT %s %s %s ORDER BY st.id DESC LIMIT $%d OFFSET $%d",
+		stockTakeColumns, stockTakeFrom, whereClause, len(args)-1, len(args))
+	rows, err := repo.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	takes := make([]models.StockTake, 0)
+	for rows.Next() {
+		st, err := scanStockTake(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		takes = append(takes, st)
+	}
+	return takes, total, rows.Err()
+}
+
+func (repo *StockTakeRepository) GetByID(id int) (*models.StockTake, error) {
+	return getStockTake(repo.db, id)
+}
+
+// getStockTake - sesi beserta seluruh barisnya, urut nama produk
+func getStockTake(q queryer, id int) (*models.StockTake, error) {
+	st, err := scanStockTake(q.QueryRow("SELECT "+stockTakeColumns+stockTakeFrom+" WHERE st.id = $1", id))
+	if err == sql.ErrNoRows {
+		return nil, NewError(ErrNotFound, "stock opname tidak ditemukan")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := q.Query("SELECT "+stockTakeLineColumns+" FROM stock_take_lines WHERE stock_take_id = $1 ORDER BY product_name, product_id", id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	st.Lines = make([]models.StockTakeLine, 0)
+	for rows.Next() {
+		l, err := scanStockTakeLine(rows)
+		if err != nil {
+			return nil, err
+		}
+		st.Lines = append(st.Lines, l)
+	}
+	return &st, rows.Err()
+}
+
+// Create - mulai sesi stock opname dan snapshot stok outlet seluruh produk (atau satu
+// kategori) dalam satu statement sehingga snapshot konsisten. Produk induk dilewati
+// karena stoknya dihitung per varian. Outlet dikunci supaya
+// dua sesi yang produknya tumpang tindih tidak bisa berjalan bersamaan di outlet yang sama.
+func (repo *StockTakeRepository) Create(req *models.StockTakeRequest) (*models.StockTake, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	outletID, err := activeOutlet(tx, req.OutletID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec("SELECT 1 FROM outlets WHERE id = $1 FOR UPDATE", outletID); err != nil {
+		return nil, err
+	}
+
+	var running int
+	err = tx.QueryRow(`
+		SELECT COUNT(*) FROM stock_takes
+		WHERE outlet_id = $1 AND status = $2 AND (category_id IS NULL OR $3 = 0 OR category_id = $3)`,
+		outletID, models.StockTakeCounting, req.CategoryID,
+	).Scan(&running)
+	if err != nil {
+		return nil, err
+	}
+	if running > 0 {
+		return nil, NewError(ErrConflict, "masih ada stock opname berjalan untuk produk yang sama di outlet ini")
+	}
+
+	var id int
+	err = tx.QueryRow(
+		"INSERT INTO stock_takes (outlet_id, category_id, status, note, created_by) VALUES ($1, NULLIF($2, 0), $3, $4, $5) RETURNING id",
+		outletID, req.CategoryID, models.StockTakeCounting, req.Note, req.CreatedBy,
+	).Scan(&id)
+	if err != nil {
+		return nil, mapDBError(err)
+	}
+
+	result, err := tx.Exec(`
+		INSERT INTO stock_take_lines (stock_take_id, product_id, product_name, system_stock, cost)
+		SELECT $1, p.id, p.name, COALESCE(os.stock, 0), p.cost
+		FROM products p
+		LEFT JOIN outlet_stocks os ON os.product_id = p.id AND os.outlet_id = $2
+		WHERE ($3 = 0 OR p.category_id = $3) AND NOT EXISTS (SELECT 1 FROM products v WHERE v.parent_id = p.id)`,
+		id, outletID, req.CategoryID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return nil, err
+	} else if n == 0 {
+		return nil, NewError(ErrValidation, "tidak ada produk untuk dihitung")
+	}
+
+	st, err := getStockTake(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return st, nil
+}
+
+// Count - simpan hasil hitung dari satu perangkat. Sesi dikunci FOR SHARE sehingga
+// beberapa perangkat bisa mengirim bersamaan, sedangkan persetujuan (FOR UPDATE)
+// menunggu kiriman yang sedang berjalan. Baris dikunci berurutan berdasarkan produk
+// supaya kiriman paralel tidak deadlock. Kembalian berisi baris yang berubah.
+func (repo *StockTakeRepository) Count(id int, req *models.StockTakeCountRequest) ([]models.StockTakeLine, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var status string
+	err = tx.QueryRow("SELECT status FROM stock_takes WHERE id = $1 FOR SHARE", id).Scan(&status)
+	if err == sql.ErrNoRows {
+		return nil, NewError(ErrNotFound, "stock opname tidak ditemukan")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if status != models.StockTakeCounting {
+		return nil, NewError(ErrConflict, fmt.Sprintf("stock opname sudah %s", status))
+	}
+
+	entries := make([]models.StockTakeCountEntry, len(req.Entries))
+	copy(entries, req.Entries)
+	for i := range entries {
+		if entries[i].ProductID != 0 {
+			continue
+		}
+		err := tx.QueryRow(
+			"SELECT id FROM products WHERE id = "+productIDByCode,
+			entries[i].Code,
+		).Scan(&entries[i].ProductID)
+		if err == sql.ErrNoRows {
+			return nil, NewError(ErrValidation, fmt.Sprintf("produk dengan kode %s tidak ditemukan", entries[i].Code))
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].ProductID < entries[j].ProductID })
+
+	changed := make(map[int]models.StockTakeLine, len(entries))
+	for _, e := range entries {
+		var lineID int
+		var counted sql.NullInt64
+		var name string
+		err := tx.QueryRow(
+			"SELECT id, counted, product_name FROM stock_take_lines WHERE stock_take_id = $1 AND product_id = $2 FOR UPDATE",
+			id, e.ProductID,
+		).Scan(&lineID, &counted, &name)
+		if err == sql.ErrNoRows {
+			return nil, NewError(ErrValidation, fmt.Sprintf("produk id %d tidak termasuk stock opname ini", e.ProductID))
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		value := e.Quantity
+		if !req.Replace {
+			value += int(counted.Int64)
+		}
+		if value < 0 {
+			return nil, NewError(ErrValidation, fmt.Sprintf("hasil hitung %s tidak boleh kurang dari 0", name))
+		}
+
+		line, err := scanStockTakeLine(tx.QueryRow(
+			"UPDATE stock_take_lines SET counted = $1, counted_by = $2, counted_at = NOW() WHERE id = $3 RETURNING "+stockTakeLineColumns,
+			value, req.CountedBy, lineID,
+		))
+		if err != nil {
+			return nil, err
+		}
+		changed[line.ProductID] = line
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	lines := make([]models.StockTakeLine, 0, len(changed))
+	for _, e := range entries {
+		if line, ok := changed[e.ProductID]; ok {
+			lines = append(lines, line)
+			delete(changed, e.ProductID)
+		}
+	}
+	return lines, nil
+}
+
+// Approve - setujui hasil hitung dan posting selisih sebagai movement adjustment di
+// outlet sesi. Selisih dihitung terhadap snapshot, sehingga penjualan yang terjadi
+// selama penghitungan tetap tercatat. Baris yang belum dihitung dilewati, atau
+// dianggap 0 jika req.ZeroUncounted.
+func (repo *StockTakeRepository) Approve(id int, req *models.StockTakeApproveRequest) (*models.StockTake, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := lockStockTake(tx, id); err != nil {
+		return nil, err
+	}
+	if req.ZeroUncounted {
+		_, err := tx.Exec(
+			"UPDATE stock_take_lines SET counted = 0, counted_by = $1, counted_at = NOW() WHERE stock_take_id = $2 AND counted IS NULL",
+			req.ApprovedBy, id,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	st, err := getStockTake(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Produk dikunci berurutan berdasarkan ID, sama seperti checkout
+	lines := st.Lines
+	sort.Slice(lines, func(i, j int) bool { return lines[i].ProductID < lines[j].ProductID })
+	for _, l := range lines {
+		if l.Variance == nil || *l.Variance == 0 {
+			continue
+		}
+		var locked int
+		if err := tx.QueryRow("SELECT id FROM products WHERE id = $1 FOR UPDATE", l.ProductID).Scan(&locked); err != nil {
+			return nil, err
+		}
+		err := applyMovement(tx, &models.StockMovement{
+			ProductID:   l.ProductID,
+			OutletID:    st.OutletID,
+			Type:        models.MovementAdjustment,
+			Quantity:    *l.Variance,
+			Reason:      fmt.Sprintf("stock opname #%d", id),
+			User:        req.ApprovedBy,
+			ReferenceID: &id,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = tx.Exec("UPDATE stock_takes SET status = $1, approved_by = $2, approved_at = NOW() WHERE id = $3",
+		models.StockTakeApproved, req.ApprovedBy, id)
+	if err != nil {
+		return nil, err
+	}
+
+	st, err = getStockTake(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return st, nil
+}
+
+// Cancel - batalkan sesi yang masih counting tanpa mengubah stok
+func (repo *StockTakeRepository) Cancel(id int) (*models.StockTake, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := lockStockTake(tx, id); err != nil {
+		return nil, err
+	}
+	_, err = tx.Exec("UPDATE stock_takes SET status = $1, cancelled_at = NOW() WHERE id = $2", models.StockTakeCancelled, id)
+	if err != nil {
+		return nil, err
+	}
+
+	st, err := getStockTake(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return st, nil
+}
+
+// lockStockTake - kunci sesi FOR UPDATE dan pastikan masih counting
+func lockStockTake(tx *sql.Tx, id int) error {
+	var status string
+	err := tx.QueryRow("SELECT status FROM stock_takes WHERE id = $1 FOR UPDATE", id).Scan(&status)
+	if err == sql.ErrNoRows {
+		return NewError(ErrNotFound, "stock opname tidak ditemukan")
+	}
+	if err != nil {
+		return err
+	}
+	if status != models.StockTakeCounting {
+		return NewError(ErrConflict, fmt.Sprintf("stock opname sudah %s", status))
+	}
+	return nil
+}
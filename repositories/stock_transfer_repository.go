@@ -0,0 +1,309 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"kasir-api/models"
+	"sort"
+	"strings"
+)
+
+type StockTransferRepository struct {
+	db *sql.DB
+}
+
+func NewStockTransferRepository(db *sql.DB) *StockTransferRepository {
+	return &StockTransferRepository{db: db}
+}
+
+const stockTransferColumns = `
+	t.id, t.from_outlet_id, f.name, t.to_outlet_id, d.name, t.status, t.note, t.created_by, t.sent_by, t.received_by,
+	t.sent_at, t.received_at, t.cancelled_at, t.created_at`
+
+const stockTransferFrom = `
+	FROM stock_transfers t
+	JOIN outlets f ON f.id = t.from_outlet_id
+	JOIN outlets d ON d.id = t.to_outlet_id`
+
+func scanStockTransfer(row rowScanner) (models.StockTransfer, error) {
+	var t models.StockTransfer
+	var sentAt, receivedAt, cancelledAt sql.NullTime
+	err := row.Scan(&t.ID, &t.FromOutletID, &t.FromOutletName, &t.ToOutletID, &t.ToOutletName, &t.Status, &t.Note,
+		&t.CreatedBy, &t.SentBy, &t.ReceivedBy, &sentAt, &receivedAt, &cancelledAt, &t.CreatedAt)
+	if sentAt.Valid {
+		t.SentAt = &sentAt.Time
+	}
+	if receivedAt.Valid {
+		t.ReceivedAt = &receivedAt.Time
+	}
+	if cancelledAt.Valid {
+		t.CancelledAt = &cancelledAt.Time
+	}
+	return t, err
+}
+
+// GetAll - transfer per halaman, terbaru lebih dulu. Baris tidak ikut dimuat.
+func (repo *StockTransferRepository) GetAll(filter models.StockTransferFilter) ([]models.StockTransfer, int, error) {
+	conditions := make([]string, 0, 2)
+	args := make([]interface{}, 0, 4)
+	if filter.OutletID > 0 {
+		args = append(args, filter.OutletID)
+		conditions = append(conditions, fmt.Sprintf("(t.from_outlet_id = $%d OR t.to_outlet_id = $%d)", len(args), len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("t.status = $%d", len(args)))
+	}
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	err := repo.db.QueryRow("SELECT COUNT(*) FROM stock_transfers t "+whereClause, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, filter.Limit, (filter.Page-1)*filter.Limit)
+	query := fmt.Sprintf("SELECT %s %s %s ORDER BY t.id DESC LIMIT $%d OFFSET $%d",
+		stockTransferColumns, stockTransferFrom, whereClause, len(args)-1, len(args))
+	rows, err := repo.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	transfers := make([]models.StockTransfer, 0)
+	for rows.Next() {
+		t, err := scanStockTransfer(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		transfers = append(transfers, t)
+	}
+	return transfers, total, rows.Err()
+}
+
+func (repo *StockTransferRepository) GetByID(id int) (*models.StockTransfer, error) {
+	return getStockTransfer(repo.db, id)
+}
+
+// getStockTransfer - transfer beserta barisnya
+func getStockTransfer(q queryer, id int) (*models.StockTransfer, error) {
+	t, err := scanStockTransfer(q.QueryRow("SELECT "+stockTransferColumns+stockTransferFrom+" WHERE t.id = $1", id))
+	if err == sql.ErrNoRows {
+		return nil, NewError(ErrNotFound, "transfer tidak ditemukan")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := q.Query("SELECT id, product_id, product_name, quantity FROM stock_transfer_lines WHERE transfer_id = $1 ORDER BY id", id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	t.Lines = make([]models.StockTransferLine, 0)
+	for rows.Next() {
+		var l models.StockTransferLine
+		if err := rows.Scan(&l.ID, &l.ProductID, &l.ProductName, &l.Quantity); err != nil {
+			return nil, err
+		}
+		t.Lines = append(t.Lines, l)
+	}
+	return &t, rows.Err()
+}
+
+// Create - simpan transfer baru sebagai draft. Stok belum berpindah sampai transfer dikirim.
+func (repo *StockTransferRepository) Create(req *models.StockTransferRequest) (*models.StockTransfer, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	for _, outletID := range []int{req.FromOutletID, req.ToOutletID} {
+		if _, err := activeOutlet(tx, outletID); err != nil {
+			return nil, err
+		}
+	}
+
+	var id int
+	err = tx.QueryRow(
+		"INSERT INTO stock_transfers (from_outlet_id, to_outlet_id, status, note, created_by) VALUES ($1, $2, $3, $4, $5) RETURNING id",
+		req.FromOutletID, req.ToOutletID, models.TransferDraft, req.Note, req.CreatedBy,
+	).Scan(&id)
+	if err != nil {
+		return nil, mapDBError(err)
+	}
+
+	for _, l := range req.Lines {
+		var name string
+		err := tx.QueryRow("SELECT name FROM products WHERE id = $1", l.ProductID).Scan(&name)
+		if err == sql.ErrNoRows {
+			return nil, NewError(ErrValidation, fmt.Sprintf("produk id %d tidak ditemukan", l.ProductID))
+		}
+		if err != nil {
+			return nil, err
+		}
+		_, err = tx.Exec(
+			"INSERT INTO stock_transfer_lines (transfer_id, product_id, product_name, quantity) VALUES ($1, $2, $3, $4)",
+			id, l.ProductID, name, l.Quantity,
+		)
+		if err != nil {
+			return nil, mapDBError(err)
+		}
+	}
+
+	t, err := getStockTransfer(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// lockStockTransfer - kunci transfer FOR UPDATE dan pastikan statusnya sesuai
+func lockStockTransfer(tx *sql.Tx, id int, status string) (*models.StockTransfer, error) {
+	var current string
+	err := tx.QueryRow("SELECT status FROM stock_transfers WHERE id = $1 FOR UPDATE", id).Scan(&current)
+	if err == sql.ErrNoRows {
+		return nil, NewError(ErrNotFound, "transfer tidak ditemukan")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if current != status {
+		return nil, NewError(ErrConflict, fmt.Sprintf("transfer sudah %s", current))
+	}
+	return getStockTransfer(tx, id)
+}
+
+// Send - kirim transfer draft. Stok keluar dari outlet asal lewat movement transfer
+// dan tercatat dalam perjalanan sampai diterima; total stok produk ikut berkurang.
+func (repo *StockTransferRepository) Send(id int, user string) (*models.StockTransfer, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	t, err := lockStockTransfer(tx, id, models.TransferDraft)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := activeOutlet(tx, t.FromOutletID); err != nil {
+		return nil, err
+	}
+
+	// Produk dikunci berurutan berdasarkan ID, sama seperti checkout
+	lines := t.Lines
+	sort.Slice(lines, func(i, j int) bool { return lines[i].ProductID < lines[j].ProductID })
+	for _, l := range lines {
+		var locked int
+		if err := tx.QueryRow("SELECT id FROM products WHERE id = $1 FOR UPDATE", l.ProductID).Scan(&locked); err != nil {
+			return nil, err
+		}
+		stock, err := outletStock(tx, t.FromOutletID, l.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		if stock < l.Quantity {
+			return nil, NewError(ErrConflict, fmt.Sprintf("stok %s di %s tidak mencukupi", l.ProductName, t.FromOutletName))
+		}
+		err = applyMovement(tx, &models.StockMovement{
+			ProductID:   l.ProductID,
+			OutletID:    t.FromOutletID,
+			Type:        models.MovementTransfer,
+			Quantity:    -l.Quantity,
+			Reason:      fmt.Sprintf("transfer #%d ke %s", id, t.ToOutletName),
+			User:        user,
+			ReferenceID: &id,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = tx.Exec("UPDATE stock_transfers SET status = $1, sent_by = $2, sent_at = NOW() WHERE id = $3", models.TransferSent, user, id)
+	if err != nil {
+		return nil, err
+	}
+	return repo.commit(tx, id)
+}
+
+// Receive - terima transfer yang sudah dikirim. Seluruh barang masuk ke outlet tujuan;
+// outlet tujuan yang sudah nonaktif tetap bisa menerima supaya barang tidak tertahan.
+func (repo *StockTransferRepository) Receive(id int, user string) (*models.StockTransfer, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	t, err := lockStockTransfer(tx, id, models.TransferSent)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := t.Lines
+	sort.Slice(lines, func(i, j int) bool { return lines[i].ProductID < lines[j].ProductID })
+	for _, l := range lines {
+		var locked int
+		if err := tx.QueryRow("SELECT id FROM products WHERE id = $1 FOR UPDATE", l.ProductID).Scan(&locked); err != nil {
+			return nil, err
+		}
+		err := applyMovement(tx, &models.StockMovement{
+			ProductID:   l.ProductID,
+			OutletID:    t.ToOutletID,
+			Type:        models.MovementTransfer,
+			Quantity:    l.Quantity,
+			Reason:      fmt.Sprintf("transfer #%d dari %s", id, t.FromOutletName),
+			User:        user,
+			ReferenceID: &id,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = tx.Exec("UPDATE stock_transfers SET status = $1, received_by = $2, received_at = NOW() WHERE id = $3", models.TransferReceived, user, id)
+	if err != nil {
+		return nil, err
+	}
+	return repo.commit(tx, id)
+}
+
+// Cancel - batalkan transfer yang belum dikirim. Transfer yang sudah dikirim harus
+// diterima lalu dikirim balik dengan transfer baru.
+func (repo *StockTransferRepository) Cancel(id int) (*models.StockTransfer, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := lockStockTransfer(tx, id, models.TransferDraft); err != nil {
+		return nil, err
+	}
+	_, err = tx.Exec("UPDATE stock_transfers SET status = $1, cancelled_at = NOW() WHERE id = $2", models.TransferCancelled, id)
+	if err != nil {
+		return nil, err
+	}
+	return repo.commit(tx, id)
+}
+
+func (repo *StockTransferRepository) commit(tx *sql.Tx, id int) (*models.StockTransfer, error) {
+	t, err := getStockTransfer(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
@@ -0,0 +1,110 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"kasir-api/models"
+)
+
+type SupplierRepository struct {
+	db *sql.DB
+}
+
+func NewSupplierRepository(db *sql.DB) *SupplierRepository {
+	return &SupplierRepository{db: db}
+}
+
+const supplierColumns = "id, name, contact_name, phone, email, address, notes, created_at"
+
+func scanSupplier(row rowScanner) (models.Supplier, error) {
+	var s models.Supplier
+	err := row.Scan(&s.ID, &s.Name, &s.ContactName, &s.Phone, &s.Email, &s.Address, &s.Notes, &s.CreatedAt)
+	return s, err
+}
+
+// GetAll - cari supplier berdasarkan nama atau nama kontak, urut nama
+func (repo *SupplierRepository) GetAll(filter models.SupplierFilter) ([]models.Supplier, int, error) {
+	whereClause := ""
+	args := make([]interface{}, 0)
+	if filter.Search != "" {
+		args = append(args, "%"+filter.Search+"%")
+		whereClause = "WHERE name ILIKE $1 OR contact_name ILIKE $1"
+	}
+
+	var total int
+	err := repo.db.QueryRow("SELECT COUNT(*) FROM suppliers "+whereClause, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, filter.Limit, (filter.Page-1)*filter.Limit)
+	query := fmt.Sprintf("SELECT %s FROM suppliers %s ORDER BY name, id LIMIT $%d OFFSET $%d",
+		supplierColumns, whereClause, len(args)-1, len(args))
+	rows, err := repo.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	suppliers := make([]models.Supplier, 0)
+	for rows.Next() {
+		s, err := scanSupplier(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		suppliers = append(suppliers, s)
+	}
+	return suppliers, total, rows.Err()
+}
+
+func (repo *SupplierRepository) GetByID(id int) (*models.Supplier, error) {
+	s, err := scanSupplier(repo.db.QueryRow("SELECT "+supplierColumns+" FROM suppliers WHERE id = $1", id))
+	if err == sql.ErrNoRows {
+		return nil, NewError(ErrNotFound, "supplier tidak ditemukan")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (repo *SupplierRepository) Create(supplier *models.Supplier) error {
+	query := `
+		INSERT INTO suppliers (name, contact_name, phone, email, address, notes)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+	err := repo.db.QueryRow(query, supplier.Name, supplier.ContactName, supplier.Phone, supplier.Email, supplier.Address, supplier.Notes).
+		Scan(&supplier.ID, &supplier.CreatedAt)
+	return mapDBError(err)
+}
+
+func (repo *SupplierRepository) Update(supplier *models.Supplier) error {
+	query := `
+		UPDATE suppliers SET name = $1, contact_name = $2, phone = $3, email = $4, address = $5, notes = $6
+		WHERE id = $7
+		RETURNING created_at
+	`
+	err := repo.db.QueryRow(query, supplier.Name, supplier.ContactName, supplier.Phone, supplier.Email, supplier.Address, supplier.Notes, supplier.ID).
+		Scan(&supplier.CreatedAt)
+	if err == sql.ErrNoRows {
+		return NewError(ErrNotFound, "supplier tidak ditemukan")
+	}
+	return mapDBError(err)
+}
+
+// Delete - hapus supplier yang belum pernah dipakai di purchase order
+func (repo *SupplierRepository) Delete(id int) error {
+	result, err := repo.db.Exec("DELETE FROM suppliers WHERE id = $1", id)
+	if err != nil {
+		return mapDBError(err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return NewError(ErrNotFound, "supplier tidak ditemukan")
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"database/sql"
+	"kasir-api/models"
+)
+
+type TaxRateRepository struct {
+	db dialectDB
+}
+
+func NewTaxRateRepository(db *sql.DB) *TaxRateRepository {
+	return &TaxRateRepository{db: newDialectDB(db)}
+}
+
+// GetAll - semua tarif pajak, jumlahnya sedikit sehingga tidak dipaginasi
+func (repo *TaxRateRepository) GetAll() ([]models.TaxRate, error) {
+	rows, err := repo.db.Query("SELECT id, name, rate_bps, is_default, created_at FROM tax_rates ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rates := make([]models.TaxRate, 0)
+	for rows.Next() {
+		var r models.TaxRate
+		if err := rows.Scan(&r.ID, &r.Name, &r.RateBps, &r.IsDefault, &r.CreatedAt); err != nil {
+			return nil, err
+		}
+		rates = append(rates, r)
+	}
+	return rates, rows.Err()
+}
+
+func (repo *TaxRateRepository) GetByID(id int) (*models.TaxRate, error) {
+	var r models.TaxRate
+	err := repo.db.QueryRow("SELECT id, name, rate_bps, is_default, created_at FROM tax_rates WHERE id = $1", id).
+		Scan(&r.ID, &r.Name, &r.RateBps, &r.IsDefault, &r.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, NewError(ErrNotFound, "tarif pajak tidak ditemukan")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// Create - simpan tarif baru; jika default, tarif default sebelumnya dilepas
+func (repo *TaxRateRepository) Create(rate *models.TaxRate) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if rate.IsDefault {
+		if _, err := tx.Exec("UPDATE tax_rates SET is_default = FALSE WHERE is_default"); err != nil {
+			return err
+		}
+	}
+
+	query := "INSERT INTO tax_rates (name, rate_bps, is_default) VALUES ($1, $2, $3) RETURNING id, created_at"
+	err = tx.QueryRow(query, rate.Name, rate.RateBps, rate.IsDefault).Scan(&rate.ID, &rate.CreatedAt)
+	if err != nil {
+		return mapDBError(err)
+	}
+
+	return tx.Commit()
+}
+
+// Update - ubah tarif; jika dijadikan default, tarif default lain dilepas
+func (repo *TaxRateRepository) Update(rate *models.TaxRate) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if rate.IsDefault {
+		if _, err := tx.Exec("UPDATE tax_rates SET is_default = FALSE WHERE is_default AND id <> $1", rate.ID); err != nil {
+			return err
+		}
+	}
+
+	query := "UPDATE tax_rates SET name = $1, rate_bps = $2, is_default = $3 WHERE id = $4 RETURNING created_at"
+	err = tx.QueryRow(query, rate.Name, rate.RateBps, rate.IsDefault, rate.ID).Scan(&rate.CreatedAt)
+	if err == sql.ErrNoRows {
+		return NewError(ErrNotFound, "tarif pajak tidak ditemukan")
+	}
+	if err != nil {
+		return mapDBError(err)
+	}
+
+	return tx.Commit()
+}
+
+// Delete - hapus tarif; produk dan kategori yang memakainya kembali ke tarif default
+func (repo *TaxRateRepository) Delete(id int) error {
+	result, err := repo.db.Exec("DELETE FROM tax_rates WHERE id = $1", id)
+	if err != nil {
+		return mapDBError(err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return NewError(ErrNotFound, "tarif pajak tidak ditemukan")
+	}
+
+	return nil
+}
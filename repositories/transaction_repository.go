@@ -0,0 +1,437 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"kasir-api/loyalty"
+	"kasir-api/models"
+	"sort"
+	"time"
+)
+
+type TransactionRepository struct {
+	db *sql.DB
+}
+
+func NewTransactionRepository(db *sql.DB) *TransactionRepository {
+	return &TransactionRepository{db: db}
+}
+
+// Pricer - hitung harga bersih keranjang (diskon promosi dan pajak) dari harga produk yang sudah dikunci
+type Pricer func(lines []models.BasketLine) *models.PricedBasket
+
+// CreateTransaction - kurangi stok dan simpan transaksi dalam satu database transaction.
+// Pengurangan stok dicatat sebagai movement "sale" di ledger. Setiap baris produk
+// dikunci dengan SELECT ... FOR UPDATE sehingga dua kasir yang menjual unit terakhir
+// tidak bisa sama-sama berhasil. Diskon dihitung oleh price dari harga yang terkunci.
+// PaidAmount 0 berarti dibayar pas. Transaksi ditempelkan ke shift kasir yang sedang terbuka.
+// Stok diperiksa dan dikurangi di outlet transaksi, outlet default jika tidak disebut.
+// Pembayaran poin langsung mengurangi saldo pelanggan; poin belanja baru dikreditkan
+// setelah transaksi lunas.
+func (repo *TransactionRepository) CreateTransaction(req *models.CheckoutRequest, price Pricer) (*models.Transaction, error) {
+	items := req.Items
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	shiftID, err := openShiftID(tx, req.Cashier)
+	if err != nil {
+		return nil, err
+	}
+	if shiftID == 0 && req.RequireShift {
+		return nil, NewError(ErrConflict, "buka shift terlebih dahulu sebelum checkout")
+	}
+
+	outletID, err := activeOutlet(tx, req.OutletID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Kunci produk berurutan berdasarkan ID supaya checkout paralel tidak deadlock
+	locked := make([]models.CheckoutItem, len(items))
+	copy(locked, items)
+	sort.Slice(locked, func(i, j int) bool { return locked[i].ProductID < locked[j].ProductID })
+
+	products := make(map[int]models.Product, len(locked))
+	for _, item := range locked {
+		var p models.Product
+		// Tarif pajak produk, atau tarif kategorinya jika produk tidak punya
+		query := `
+			SELECT p.id, COALESCE(p.parent_id, 0), p.name, p.price, p.stock, p.cost, COALESCE(p.category_id, 0), COALESCE(p.tax_rate_id, c.tax_rate_id, 0)
+			FROM products p
+			LEFT JOIN categories c ON p.category_id = c.id
+			WHERE p.id = $1
+			FOR UPDATE OF p
+		`
+		err := tx.QueryRow(query, item.ProductID).Scan(&p.ID, &p.ParentID, &p.Name, &p.Price, &p.Stock, &p.Cost, &p.CategoryID, &p.TaxRateID)
+		if err == sql.ErrNoRows {
+			return nil, NewError(ErrValidation, fmt.Sprintf("produk id %d tidak ditemukan", item.ProductID))
+		}
+		if err != nil {
+			return nil, err
+		}
+		stock, err := outletStock(tx, outletID, p.ID)
+		if err != nil {
+			return nil, err
+		}
+		if stock < item.Quantity {
+			return nil, NewError(ErrConflict, fmt.Sprintf("stok produk %s tidak mencukupi", p.Name))
+		}
+		products[p.ID] = p
+	}
+
+	lines := make([]models.BasketLine, 0, len(items))
+	for _, item := range items {
+		p := products[item.ProductID]
+		lines = append(lines, models.BasketLine{
+			ProductID:   p.ID,
+			ParentID:    p.ParentID,
+			CategoryID:  p.CategoryID,
+			TaxRateID:   p.TaxRateID,
+			ProductName: p.Name,
+			Quantity:    item.Quantity,
+			Price:       p.Price,
+		})
+	}
+	basket := price(lines)
+
+	transaction := models.Transaction{
+		SubtotalAmount:   basket.Subtotal,
+		DiscountAmount:   basket.DiscountTotal,
+		TaxAmount:        basket.TaxTotal,
+		PricesIncludeTax: basket.PricesIncludeTax,
+		TotalAmount:      basket.Total,
+		VoucherCode:      req.VoucherCode,
+		Cashier:          req.Cashier,
+		ShiftID:          shiftID,
+		OutletID:         outletID,
+		CustomerID:       req.CustomerID,
+		Details:          make([]models.TransactionDetail, 0, len(basket.Lines)),
+		Discounts:        make([]models.AppliedDiscount, 0),
+		Taxes:            basket.Taxes,
+	}
+	for _, line := range basket.Lines {
+		transaction.Details = append(transaction.Details, models.TransactionDetail{
+			ProductID:   line.ProductID,
+			ProductName: line.ProductName,
+			Quantity:    line.Quantity,
+			Price:       line.Price,
+			Subtotal:    line.Subtotal,
+			Discount:    line.Discount,
+			TaxRateBps:  line.TaxRateBps,
+			DPP:         line.DPP,
+			Tax:         line.Tax,
+		})
+		transaction.Discounts = append(transaction.Discounts, line.Discounts...)
+	}
+	transaction.Discounts = append(transaction.Discounts, basket.Discounts...)
+
+	transaction.Payments, transaction.ChangeAmount, err = settlePayments(req, transaction.TotalAmount)
+	if err != nil {
+		return nil, err
+	}
+	transaction.Status = models.TransactionPaid
+	redeemedAmount := 0
+	for _, p := range transaction.Payments {
+		transaction.PaidAmount += p.Amount
+		if p.Status == models.PaymentPending {
+			transaction.Status = models.TransactionPending
+		}
+		if p.Method == models.PaymentPoints {
+			redeemedAmount += p.Amount
+		}
+	}
+
+	pointValue := 0
+	var pointsExpireAt *time.Time
+	if req.Loyalty != nil && req.CustomerID > 0 {
+		settings := *req.Loyalty
+		if redeemedAmount > 0 {
+			transaction.PointsRedeemed, err = loyalty.Redeem(settings, redeemedAmount)
+			if err != nil {
+				return nil, NewError(ErrValidation, err.Error())
+			}
+		}
+		earnLines := make([]loyalty.Line, len(basket.Lines))
+		for i, line := range basket.Lines {
+			earnLines[i] = loyalty.Line{CategoryID: products[line.ProductID].CategoryID, Amount: line.Total}
+		}
+		transaction.PointsEarned = loyalty.Earn(settings, earnLines, redeemedAmount)
+		pointValue = settings.PointValue
+		pointsExpireAt = loyalty.ExpiresAt(settings, time.Now())
+	} else if redeemedAmount > 0 {
+		return nil, NewError(ErrValidation, "pembayaran poin membutuhkan pelanggan")
+	}
+
+	query := `
+		INSERT INTO transactions (subtotal_amount, discount_amount, tax_amount, prices_include_tax, total_amount,
+			paid_amount, change_amount, voucher_code, status, cashier, shift_id, customer_id,
+			points_earned, points_redeemed, point_value, points_expire_at, outlet_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NULLIF($11, 0), NULLIF($12, 0), $13, $14, $15, $16, $17)
+		RETURNING id, created_at
+	`
+	err = tx.QueryRow(query,
+		transaction.SubtotalAmount, transaction.DiscountAmount, transaction.TaxAmount, transaction.PricesIncludeTax, transaction.TotalAmount,
+		transaction.PaidAmount, transaction.ChangeAmount, transaction.VoucherCode, transaction.Status, transaction.Cashier, transaction.ShiftID, transaction.CustomerID,
+		transaction.PointsEarned, transaction.PointsRedeemed, pointValue, pointsExpireAt, transaction.OutletID,
+	).Scan(&transaction.ID, &transaction.CreatedAt)
+	if err != nil {
+		return nil, mapDBError(err)
+	}
+
+	// Pelanggan dikunci setelah produk, urutan yang sama dengan persetujuan retur
+	if transaction.PointsRedeemed > 0 {
+		if err := lockCustomer(tx, transaction.CustomerID); err != nil {
+			return nil, err
+		}
+		err = debitPoints(tx, &models.PointEntry{
+			CustomerID:    transaction.CustomerID,
+			Type:          models.PointRedeem,
+			Points:        -transaction.PointsRedeemed,
+			TransactionID: transaction.ID,
+			Note:          fmt.Sprintf("pembayaran transaksi #%d", transaction.ID),
+			User:          transaction.Cashier,
+		}, false)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if transaction.Status == models.TransactionPaid {
+		if err := creditEarnedPoints(tx, transaction.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range transaction.Details {
+		d := &transaction.Details[i]
+		d.TransactionID = transaction.ID
+		// HPP saat terjual disimpan supaya laporan margin tidak berubah oleh penerimaan berikutnya
+		err = tx.QueryRow(
+			`INSERT INTO transaction_details (transaction_id, product_id, product_name, quantity, price, subtotal, discount, tax_rate_bps, dpp, tax, cost)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id`,
+			d.TransactionID, d.ProductID, d.ProductName, d.Quantity, d.Price, d.Subtotal, d.Discount, d.TaxRateBps, d.DPP, d.Tax,
+			products[d.ProductID].Cost,
+		).Scan(&d.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		err = applyMovement(tx, &models.StockMovement{
+			ProductID:   d.ProductID,
+			OutletID:    transaction.OutletID,
+			Type:        models.MovementSale,
+			Quantity:    -d.Quantity,
+			Reason:      fmt.Sprintf("penjualan transaksi #%d", transaction.ID),
+			User:        transaction.Cashier,
+			ReferenceID: &transaction.ID,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, d := range transaction.Discounts {
+		_, err = tx.Exec(
+			"INSERT INTO transaction_discounts (transaction_id, promotion_id, product_id, name, amount, explanation) VALUES ($1, $2, NULLIF($3, 0), $4, $5, $6)",
+			transaction.ID, d.PromotionID, d.ProductID, d.Name, d.Amount, d.Explanation,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range transaction.Payments {
+		p := &transaction.Payments[i]
+		p.TransactionID = transaction.ID
+		// paid_at dihitung di Go: $5 yang dipakai sebagai kolom VARCHAR dan dibandingkan
+		// dengan literal membuat PostgreSQL menyimpulkan dua tipe untuk parameter yang sama
+		var paidAt *time.Time
+		if p.Status == models.PaymentPaid {
+			now := time.Now()
+			paidAt = &now
+		}
+		err = tx.QueryRow(`
+			INSERT INTO payments (transaction_id, method, amount, reference, status, paid_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id, paid_at, created_at`,
+			p.TransactionID, p.Method, p.Amount, p.Reference, p.Status, paidAt,
+		).Scan(&p.ID, &p.PaidAt, &p.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, t := range transaction.Taxes {
+		_, err = tx.Exec(
+			"INSERT INTO transaction_taxes (transaction_id, tax_rate_id, name, rate_bps, dpp, tax) VALUES ($1, $2, $3, $4, $5, $6)",
+			transaction.ID, t.TaxRateID, t.Name, t.RateBps, t.DPP, t.Tax,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &transaction, nil
+}
+
+// settlePayments - susun pembayaran dan kembalian. Tanpa Payments, PaidAmount dianggap
+// satu pembayaran tunai (0 berarti uang pas). Pembayaran non-tunai tidak boleh melebihi
+// total; kelebihan bayar hanya dari tunai dan dikembalikan sebagai kembalian.
+func settlePayments(req *models.CheckoutRequest, total int) ([]models.Payment, int, error) {
+	requests := req.Payments
+	if len(requests) == 0 {
+		amount := req.PaidAmount
+		if amount == 0 {
+			amount = total
+		}
+		requests = make([]models.PaymentRequest, 0, 1)
+		if amount > 0 {
+			requests = append(requests, models.PaymentRequest{Method: models.PaymentCash, Amount: amount})
+		}
+	}
+
+	payments := make([]models.Payment, 0, len(requests))
+	paid, nonCash := 0, 0
+	for _, r := range requests {
+		paid += r.Amount
+		if r.Method != models.PaymentCash {
+			nonCash += r.Amount
+		}
+
+		status := models.PaymentPaid
+		if models.PaymentNeedsProvider(r.Method) {
+			status = models.PaymentPending
+		}
+		payments = append(payments, models.Payment{Method: r.Method, Amount: r.Amount, Reference: r.Reference, Status: status})
+	}
+
+	if nonCash > total {
+		return nil, 0, NewError(ErrValidation, fmt.Sprintf("pembayaran non-tunai melebihi total %d", total))
+	}
+	if paid < total {
+		return nil, 0, NewError(ErrValidation, fmt.Sprintf("pembayaran kurang dari total %d", total))
+	}
+	return payments, paid - total, nil
+}
+
+// GetByID - ambil transaksi beserta detail item
+func (repo *TransactionRepository) GetByID(id int) (*models.Transaction, error) {
+	var t models.Transaction
+	query := `
+		SELECT id, subtotal_amount, discount_amount, tax_amount, prices_include_tax, total_amount,
+			paid_amount, change_amount, voucher_code, status, cashier, COALESCE(shift_id, 0), outlet_id, COALESCE(customer_id, 0),
+			points_earned, points_redeemed, created_at
+		FROM transactions WHERE id = $1
+	`
+	err := repo.db.QueryRow(query, id).Scan(
+		&t.ID, &t.SubtotalAmount, &t.DiscountAmount, &t.TaxAmount, &t.PricesIncludeTax, &t.TotalAmount,
+		&t.PaidAmount, &t.ChangeAmount, &t.VoucherCode, &t.Status, &t.Cashier, &t.ShiftID, &t.OutletID, &t.CustomerID,
+		&t.PointsEarned, &t.PointsRedeemed, &t.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, NewError(ErrNotFound, "transaksi tidak ditemukan")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	query = `
+		SELECT id, transaction_id, product_id, product_name, quantity, price, subtotal, discount, tax_rate_bps, dpp, tax
+		FROM transaction_details
+		WHERE transaction_id = $1
+		ORDER BY id
+	`
+	rows, err := repo.db.Query(query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	t.Details = make([]models.TransactionDetail, 0)
+	for rows.Next() {
+		var d models.TransactionDetail
+		err := rows.Scan(&d.ID, &d.TransactionID, &d.ProductID, &d.ProductName, &d.Quantity, &d.Price, &d.Subtotal, &d.Discount, &d.TaxRateBps, &d.DPP, &d.Tax)
+		if err != nil {
+			return nil, err
+		}
+		t.Details = append(t.Details, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	t.Discounts, err = repo.getDiscounts(id)
+	if err != nil {
+		return nil, err
+	}
+
+	t.Taxes, err = repo.getTaxes(id)
+	if err != nil {
+		return nil, err
+	}
+
+	t.Payments, err = getPayments(repo.db, "transaction_id = $1", id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+// getDiscounts - diskon yang diterapkan pada transaksi, diskon per baris lebih dulu
+func (repo *TransactionRepository) getDiscounts(transactionID int) ([]models.AppliedDiscount, error) {
+	query := `
+		SELECT COALESCE(promotion_id, 0), name, COALESCE(product_id, 0), amount, explanation
+		FROM transaction_discounts
+		WHERE transaction_id = $1
+		ORDER BY id
+	`
+	rows, err := repo.db.Query(query, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	discounts := make([]models.AppliedDiscount, 0)
+	for rows.Next() {
+		var d models.AppliedDiscount
+		if err := rows.Scan(&d.PromotionID, &d.Name, &d.ProductID, &d.Amount, &d.Explanation); err != nil {
+			return nil, err
+		}
+		discounts = append(discounts, d)
+	}
+
+	return discounts, rows.Err()
+}
+
+// getTaxes - rincian DPP dan pajak per tarif pada transaksi
+func (repo *TransactionRepository) getTaxes(transactionID int) ([]models.TaxSummary, error) {
+	query := `
+		SELECT COALESCE(tax_rate_id, 0), name, rate_bps, dpp, tax
+		FROM transaction_taxes
+		WHERE transaction_id = $1
+		ORDER BY id
+	`
+	rows, err := repo.db.Query(query, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	taxes := make([]models.TaxSummary, 0)
+	for rows.Next() {
+		var t models.TaxSummary
+		if err := rows.Scan(&t.TaxRateID, &t.Name, &t.RateBps, &t.DPP, &t.Tax); err != nil {
+			return nil, err
+		}
+		taxes = append(taxes, t)
+	}
+
+	return taxes, rows.Err()
+}
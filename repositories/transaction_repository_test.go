@@ -0,0 +1,67 @@
+package repositories_test
+
+import (
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCheckoutOutletStock berjalan jika TEST_DB_CONN diisi: penjualan di outlet selain
+// default mengurangi stok outlet tersebut, bukan stok outlet default.
+func TestCheckoutOutletStock(t *testing.T) {
+	conn := os.Getenv("TEST_DB_CONN")
+	if conn == "" {
+		t.Skip("TEST_DB_CONN tidak diisi")
+	}
+	db := openDB(t, conn)
+	_, err := db.Exec(`
+		TRUNCATE products, stock_transfers, outlets RESTART IDENTITY CASCADE;
+		INSERT INTO outlets (code, name, is_default) VALUES ('PUSAT', 'Outlet Pusat', TRUE);
+	`)
+	require.NoError(t, err)
+
+	products := repositories.NewProductRepository(db)
+	outlets := repositories.NewOutletRepository(db)
+	movements := repositories.NewStockMovementRepository(db)
+	transactions := repositories.NewTransactionRepository(db)
+
+	product := &models.Product{Name: "Kopi", SKU: "KP-01", Price: 5000}
+	require.NoError(t, products.Create(product))
+	branch := &models.Outlet{Code: "CBG", Name: "Cabang", Type: models.OutletStore, Active: true}
+	require.NoError(t, outlets.Create(branch))
+	require.NoError(t, movements.Create(&models.StockMovement{ProductID: product.ID, Type: models.MovementRestock, Quantity: 5, User: "siti"}))
+	require.NoError(t, movements.Create(&models.StockMovement{ProductID: product.ID, OutletID: branch.ID, Type: models.MovementRestock, Quantity: 4, User: "siti"}))
+
+	price := func(lines []models.BasketLine) *models.PricedBasket {
+		basket := &models.PricedBasket{Discounts: []models.AppliedDiscount{}, Taxes: []models.TaxSummary{}}
+		for _, l := range lines {
+			subtotal := l.Price * l.Quantity
+			basket.Lines = append(basket.Lines, models.PricedLine{
+				ProductID: l.ProductID, ProductName: l.ProductName, Quantity: l.Quantity,
+				Price: l.Price, Subtotal: subtotal, Total: subtotal,
+			})
+			basket.Subtotal += subtotal
+		}
+		basket.Total = basket.Subtotal
+		return basket
+	}
+	transaction, err := transactions.CreateTransaction(&models.CheckoutRequest{
+		OutletID: branch.ID,
+		Items:    []models.CheckoutItem{{ProductID: product.ID, Quantity: 3}},
+		Cashier:  "siti",
+	}, price)
+	require.NoError(t, err)
+	assert.Equal(t, branch.ID, transaction.OutletID)
+
+	stocks, err := movements.GetOutletStocks(product.ID)
+	require.NoError(t, err)
+	byOutlet := map[int]int{}
+	for _, s := range stocks {
+		byOutlet[s.OutletID] = s.Stock
+	}
+	assert.Equal(t, map[int]int{1: 5, branch.ID: 1}, byOutlet)
+}
@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"database/sql"
+	"kasir-api/models"
+)
+
+type UserRepository struct {
+	db dialectDB
+}
+
+func NewUserRepository(db *sql.DB) *UserRepository {
+	return &UserRepository{db: newDialectDB(db)}
+}
+
+func (repo *UserRepository) GetAll() ([]models.User, error) {
+	rows, err := repo.db.Query("SELECT id, username, name, role, created_at FROM users ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make([]models.User, 0)
+	for rows.Next() {
+		var u models.User
+		err := rows.Scan(&u.ID, &u.Username, &u.Name, &u.Role, &u.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+
+	return users, rows.Err()
+}
+
+func (repo *UserRepository) Create(user *models.User) error {
+	query := "INSERT INTO users (username, name, role, password_hash) VALUES ($1, $2, $3, $4) RETURNING id, created_at"
+	err := repo.db.QueryRow(query, user.Username, user.Name, user.Role, user.PasswordHash).Scan(&user.ID, &user.CreatedAt)
+	return mapDBError(err)
+}
+
+func (repo *UserRepository) GetByUsername(username string) (*models.User, error) {
+	query := "SELECT id, username, name, role, password_hash, created_at FROM users WHERE username = $1"
+
+	var u models.User
+	err := repo.db.QueryRow(query, username).Scan(&u.ID, &u.Username, &u.Name, &u.Role, &u.PasswordHash, &u.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, NewError(ErrNotFound, "user tidak ditemukan")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &u, nil
+}
+
+func (repo *UserRepository) Count() (int, error) {
+	var count int
+	err := repo.db.QueryRow("SELECT COUNT(*) FROM users").Scan(&count)
+	return count, err
+}
@@ -0,0 +1,64 @@
+package response
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// Kode error yang stabil untuk dipakai client. Jangan ubah nilai yang sudah ada.
+const (
+	CodeInvalidBody        = "invalid_body"
+	CodeInvalidID          = "invalid_id"
+	CodeInvalidQuery       = "invalid_query"
+	CodeValidation         = "validation_error"
+	CodeNotFound           = "not_found"
+	CodeConflict           = "conflict"
+	CodeForeignKey         = "foreign_key_violation"
+	CodeUnauthorized       = "unauthorized"
+	CodeInvalidCredentials = "invalid_credentials"
+	CodeForbidden          = "forbidden"
+	CodeMethodNotAllowed   = "method_not_allowed"
+	CodeNotImplemented     = "not_implemented"
+	CodeInternal           = "internal_error"
+)
+
+// ErrorBody adalah format JSON untuk semua respons error
+type ErrorBody struct {
+	Code      string      `json:"code"`
+	Message   string      `json:"message"`
+	Details   interface{} `json:"details,omitempty"`
+	RequestID string      `json:"request_id,omitempty"`
+}
+
+type contextKey string
+
+const requestIDKey contextKey = "request_id"
+
+// WithRequestID - simpan request ID ke context
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// RequestID - ambil request ID dari context, kosong jika tidak ada
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// JSON - tulis respons JSON dengan status tertentu
+func JSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// Error - tulis respons error JSON beserta request ID
+func Error(w http.ResponseWriter, r *http.Request, status int, code, message string, details interface{}) {
+	JSON(w, status, ErrorBody{
+		Code:      code,
+		Message:   message,
+		Details:   details,
+		RequestID: RequestID(r.Context()),
+	})
+}
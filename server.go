@@ -0,0 +1,421 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"kasir-api/database"
+	"kasir-api/handlers"
+	"kasir-api/middleware"
+	"kasir-api/models"
+	"kasir-api/payment"
+	"kasir-api/receipt"
+	"kasir-api/repositories"
+	"kasir-api/repositories/memory"
+	"kasir-api/response"
+	"kasir-api/services"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// runServer - susun repository, service dan handler lalu jalankan HTTP server. Tanpa db
+// (STORAGE=memory) atau dengan SQLite hanya auth, produk, kategori dan pajak yang tersedia;
+// endpoint modul lain dijawab 501 not_implemented.
+func runServer(config Config, db *sql.DB) {
+	if config.JWTSecret == "" {
+		log.Fatal("JWT_SECRET wajib diisi")
+	}
+
+	location, err := loadTimezone(config.Timezone)
+	if err != nil {
+		log.Fatal("Invalid TIMEZONE: ", err)
+	}
+
+	repos := newCatalog(db)
+	authService := services.NewAuthService(repos.users, config.JWTSecret, config.TokenTTL)
+	authHandler := handlers.NewAuthHandler(authService)
+	auth := middleware.NewAuth(authService)
+
+	if err := authService.EnsureAdmin(config.AdminUsername, config.AdminPassword); err != nil {
+		log.Fatal("Failed to create admin user:", err)
+	}
+
+	taxRateService := services.NewTaxRateService(repos.taxRates)
+	taxRateHandler := handlers.NewTaxRateHandler(taxRateService)
+
+	categoryService := services.NewCategoryService(repos.categories, repos.taxRates)
+	categoryHandler := handlers.NewCategoryHandler(categoryService)
+
+	productService := services.NewProductService(repos.products, repos.categories, repos.taxRates)
+	productHandler := handlers.NewProductHandler(productService)
+
+	// Setup routes
+	// Kasir boleh membaca, supervisor mengubah harga/stok, admin menghapus kategori dan mengatur pajak
+	productRules := map[string]string{
+		http.MethodGet:    models.RoleCashier,
+		http.MethodPost:   models.RoleSupervisor,
+		http.MethodPut:    models.RoleSupervisor,
+		http.MethodDelete: models.RoleSupervisor,
+	}
+	categoryRules := map[string]string{
+		http.MethodGet:    models.RoleCashier,
+		http.MethodPost:   models.RoleSupervisor,
+		http.MethodPut:    models.RoleSupervisor,
+		http.MethodDelete: models.RoleAdmin,
+	}
+	taxRateRules := map[string]string{
+		http.MethodGet:    models.RoleCashier,
+		http.MethodPost:   models.RoleAdmin,
+		http.MethodPut:    models.RoleAdmin,
+		http.MethodDelete: models.RoleAdmin,
+	}
+
+	var productStock http.HandlerFunc
+	switch {
+	case db == nil:
+		productStock = registerUnavailable("STORAGE=memory")
+	case database.Driver(db) == database.SQLite:
+		productStock = registerUnavailable("SQLite")
+	default:
+		productStock = registerOperations(config, db, auth, repos, productRules, location)
+	}
+
+	http.HandleFunc("/api/auth/login", authHandler.HandleLogin)
+	http.HandleFunc("/api/auth/me", auth.RequireRole(models.RoleCashier, authHandler.HandleMe))
+	http.HandleFunc("/api/users", auth.RequireRole(models.RoleAdmin, authHandler.HandleUsers))
+
+	http.HandleFunc("/api/produk", auth.Require(productRules, productHandler.HandleProducts))
+	http.HandleFunc("/api/produk/barcode/", auth.Require(productRules, productHandler.HandleProductByBarcode))
+	http.HandleFunc("/api/produk/export", auth.RequireRole(models.RoleSupervisor, productHandler.HandleExport))
+	http.HandleFunc("/api/produk/import", auth.RequireRole(models.RoleSupervisor, productHandler.HandleImport))
+	http.HandleFunc("/api/produk/", auth.Require(productRules, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/stok") {
+			productStock(w, r)
+			return
+		}
+		productHandler.HandleProductByID(w, r)
+	}))
+
+	http.HandleFunc("/api/kategori", auth.Require(categoryRules, categoryHandler.HandleCategories))
+	http.HandleFunc("/api/kategori/", auth.Require(categoryRules, categoryHandler.HandleCategoryByID))
+
+	http.HandleFunc("/api/pajak", auth.Require(taxRateRules, taxRateHandler.HandleTaxRates))
+	http.HandleFunc("/api/pajak/", auth.Require(taxRateRules, taxRateHandler.HandleTaxRateByID))
+
+	// localhost:8080/health
+	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{
+			"status":  "OK",
+			"message": "API Running",
+		})
+	})
+	fmt.Println("Server running di localhost:" + config.Port)
+
+	err = http.ListenAndServe(":"+config.Port, middleware.RequestID(http.DefaultServeMux))
+	if err != nil {
+		fmt.Println("gagal running server")
+	}
+}
+
+// loadTimezone - zona waktu toko dari TIMEZONE. Namanya dipakai apa adanya di query
+// laporan (AT TIME ZONE), jadi harus nama zona IANA; "Local" dan kosong ditolak karena
+// tidak dikenal PostgreSQL.
+func loadTimezone(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || strings.EqualFold(name, "Local") {
+		return nil, fmt.Errorf("isi dengan nama zona IANA seperti Asia/Jakarta, bukan %q", name)
+	}
+	return time.LoadLocation(name)
+}
+
+// catalog - repository yang bisa berjalan tanpa database
+type catalog struct {
+	users      repositories.UserRepositoryInterface
+	taxRates   repositories.TaxRateRepositoryInterface
+	categories repositories.CategoryRepositoryInterface
+	products   repositories.ProductRepositoryInterface
+}
+
+// newCatalog - repository SQL (PostgreSQL atau SQLite), atau penyimpanan memori jika db nil
+func newCatalog(db *sql.DB) catalog {
+	if db == nil {
+		store := memory.NewStore()
+		return catalog{
+			users:      memory.NewUserRepository(store),
+			taxRates:   memory.NewTaxRateRepository(store),
+			categories: memory.NewCategoryRepository(store),
+			products:   memory.NewProductRepository(store),
+		}
+	}
+	return catalog{
+		users:      repositories.NewUserRepository(db),
+		taxRates:   repositories.NewTaxRateRepository(db),
+		categories: repositories.NewCategoryRepository(db),
+		products:   repositories.NewProductRepository(db),
+	}
+}
+
+// operationRoutes - pola route yang didaftarkan registerOperations, ditambah riwayat stok
+// produk (/api/produk/{id}/stok). Perbarui daftar ini saat menambah modul PostgreSQL.
+var operationRoutes = []string{
+	"/api/promosi", "/api/promosi/",
+	"/api/transaksi", "/api/transaksi/",
+	"/api/pelanggan", "/api/pelanggan/",
+	"/api/poin/",
+	"/api/shift", "/api/shift/",
+	"/api/supplier", "/api/supplier/",
+	"/api/pembelian", "/api/pembelian/",
+	"/api/outlet", "/api/outlet/",
+	"/api/transfer", "/api/transfer/",
+	"/api/opname", "/api/opname/",
+	"/api/laporan/",
+	"/api/retur", "/api/retur/",
+	"/api/pembayaran/",
+}
+
+// registerUnavailable - tanpa PostgreSQL modul operasional dijawab 501 dengan penjelasan,
+// bukan 404, dan daftarnya dicatat saat server mulai. Mengembalikan handler riwayat stok.
+func registerUnavailable(mode string) http.HandlerFunc {
+	log.Printf("%s: hanya endpoint auth, produk, kategori dan pajak yang tersedia", mode)
+	log.Printf("%s: endpoint berikut menjawab 501 karena membutuhkan PostgreSQL: %s, /api/produk/{id}/stok", mode, strings.Join(operationRoutes, ", "))
+
+	unavailable := func(w http.ResponseWriter, r *http.Request) {
+		response.Error(w, r, http.StatusNotImplemented, response.CodeNotImplemented,
+			fmt.Sprintf("endpoint ini membutuhkan PostgreSQL dan tidak tersedia dengan %s", mode), nil)
+	}
+	for _, pattern := range operationRoutes {
+		http.HandleFunc(pattern, unavailable)
+	}
+	return unavailable
+}
+
+// registerOperations - daftarkan modul yang membutuhkan PostgreSQL (stok, transaksi,
+// pembayaran, pelanggan, pembelian, outlet, laporan) dan kembalikan handler riwayat stok produk
+func registerOperations(config Config, db *sql.DB, auth *middleware.Auth, repos catalog, productRules map[string]string, location *time.Location) http.HandlerFunc {
+	productRepo, categoryRepo, taxRateRepo := repos.products, repos.categories, repos.taxRates
+
+	stockMovementRepo := repositories.NewStockMovementRepository(db)
+	stockMovementService := services.NewStockMovementService(stockMovementRepo, productRepo)
+	stockMovementHandler := handlers.NewStockMovementHandler(stockMovementService)
+
+	promotionRepo := repositories.NewPromotionRepository(db)
+	pricing := services.NewPricing(promotionRepo, taxRateRepo, location, config.PricesIncludeTax)
+	promotionService := services.NewPromotionService(promotionRepo, productRepo, categoryRepo, pricing)
+	promotionHandler := handlers.NewPromotionHandler(promotionService)
+
+	// Provider QRIS palsu sampai gateway sungguhan dipasang; e-wallet memakai alur yang sama
+	paymentSecret := config.PaymentSecret
+	if paymentSecret == "" {
+		paymentSecret = config.JWTSecret
+	}
+	fakeQRIS := payment.NewFakeQRIS(paymentSecret, config.QRISTTL)
+	providers := payment.Providers{
+		models.PaymentQRIS:    fakeQRIS,
+		models.PaymentEWallet: fakeQRIS,
+	}
+
+	paymentRepo := repositories.NewPaymentRepository(db)
+	paymentService := services.NewPaymentService(paymentRepo, providers)
+	paymentHandler := handlers.NewPaymentHandler(paymentService)
+	go expirePayments(paymentService, time.Minute)
+
+	customerRepo := repositories.NewCustomerRepository(db)
+	customerService := services.NewCustomerService(customerRepo)
+	customerHandler := handlers.NewCustomerHandler(customerService)
+
+	loyaltyRepo := repositories.NewLoyaltyRepository(db)
+	loyaltyService := services.NewLoyaltyService(loyaltyRepo)
+	loyaltyHandler := handlers.NewLoyaltyHandler(loyaltyService)
+	go expirePoints(loyaltyService, time.Hour)
+
+	transactionRepo := repositories.NewTransactionRepository(db)
+	transactionService := services.NewTransactionService(transactionRepo, paymentRepo, customerRepo, loyaltyRepo, pricing, providers, config.RequireShift)
+	transactionHandler := handlers.NewTransactionHandler(transactionService)
+
+	returnRepo := repositories.NewReturnRepository(db)
+	returnService := services.NewReturnService(returnRepo)
+	returnHandler := handlers.NewReturnHandler(returnService)
+
+	supplierRepo := repositories.NewSupplierRepository(db)
+	supplierService := services.NewSupplierService(supplierRepo)
+	supplierHandler := handlers.NewSupplierHandler(supplierService)
+
+	purchaseOrderRepo := repositories.NewPurchaseOrderRepository(db)
+	purchaseOrderService := services.NewPurchaseOrderService(purchaseOrderRepo, supplierRepo)
+	purchaseOrderHandler := handlers.NewPurchaseOrderHandler(purchaseOrderService)
+
+	outletRepo := repositories.NewOutletRepository(db)
+	outletService := services.NewOutletService(outletRepo)
+	outletHandler := handlers.NewOutletHandler(outletService)
+
+	stockTransferRepo := repositories.NewStockTransferRepository(db)
+	stockTransferService := services.NewStockTransferService(stockTransferRepo)
+	stockTransferHandler := handlers.NewStockTransferHandler(stockTransferService)
+
+	store := receipt.Store{
+		Name:    config.StoreName,
+		Address: config.StoreAddress,
+		Phone:   config.StorePhone,
+		Footer:  config.ReceiptFooter,
+	}
+	receiptService := services.NewReceiptService(transactionRepo, store, config.ReceiptWidth, location)
+	receiptHandler := handlers.NewReceiptHandler(receiptService)
+
+	shiftRepo := repositories.NewShiftRepository(db)
+	shiftService := services.NewShiftService(shiftRepo, store, config.ReceiptWidth, location)
+	shiftHandler := handlers.NewShiftHandler(shiftService)
+
+	stockTakeRepo := repositories.NewStockTakeRepository(db)
+	stockTakeService := services.NewStockTakeService(stockTakeRepo, categoryRepo, store, config.ReceiptWidth, location)
+	stockTakeHandler := handlers.NewStockTakeHandler(stockTakeService)
+
+	reportRepo := repositories.NewReportRepository(db, location.String())
+	if err := reportRepo.CheckTimezone(); err != nil {
+		log.Fatal("Invalid TIMEZONE: ", err)
+	}
+	reportService := services.NewReportService(reportRepo, location)
+	reportHandler := handlers.NewReportHandler(reportService)
+
+	// Setup routes
+	paymentRules := map[string]string{
+		http.MethodGet:  models.RoleCashier,
+		http.MethodPost: models.RoleSupervisor,
+	}
+	// Kasir mendaftarkan dan mengubah data pelanggan di kasir, supervisor menghapus
+	customerRules := map[string]string{
+		http.MethodGet:    models.RoleCashier,
+		http.MethodPost:   models.RoleCashier,
+		http.MethodPut:    models.RoleCashier,
+		http.MethodDelete: models.RoleSupervisor,
+	}
+	// Kasir melihat aturan poin, hanya admin yang mengubahnya
+	loyaltyRules := map[string]string{
+		http.MethodGet: models.RoleCashier,
+		http.MethodPut: models.RoleAdmin,
+	}
+	// Kasir mengajukan retur, supervisor menyetujui atau menolak
+	returnRules := map[string]string{
+		http.MethodGet:  models.RoleCashier,
+		http.MethodPost: models.RoleSupervisor,
+	}
+	// Kasir membuka shift sendiri, daftar semua shift hanya untuk supervisor
+	shiftRules := map[string]string{
+		http.MethodGet:  models.RoleSupervisor,
+		http.MethodPost: models.RoleCashier,
+	}
+	// Pembelian ke supplier dikelola supervisor, hanya admin yang menghapus supplier
+	purchasingRules := map[string]string{
+		http.MethodGet:    models.RoleSupervisor,
+		http.MethodPost:   models.RoleSupervisor,
+		http.MethodPut:    models.RoleSupervisor,
+		http.MethodDelete: models.RoleAdmin,
+	}
+	// Kasir memilih outlet saat checkout, hanya admin yang mengelola outlet
+	outletRules := map[string]string{
+		http.MethodGet:    models.RoleCashier,
+		http.MethodPost:   models.RoleAdmin,
+		http.MethodPut:    models.RoleAdmin,
+		http.MethodDelete: models.RoleAdmin,
+	}
+
+	http.HandleFunc("/api/promosi", auth.Require(productRules, promotionHandler.HandlePromotions))
+	http.HandleFunc("/api/promosi/preview", auth.RequireRole(models.RoleCashier, promotionHandler.HandlePreview))
+	http.HandleFunc("/api/promosi/", auth.Require(productRules, promotionHandler.HandlePromotionByID))
+
+	http.HandleFunc("/api/transaksi", auth.RequireRole(models.RoleCashier, transactionHandler.HandleTransactions))
+	http.HandleFunc("/api/transaksi/", auth.RequireRole(models.RoleCashier, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/struk") {
+			receiptHandler.HandleReceipt(w, r)
+			return
+		}
+		transactionHandler.HandleTransactionByID(w, r)
+	}))
+
+	http.HandleFunc("/api/pelanggan", auth.Require(customerRules, customerHandler.HandleCustomers))
+	http.HandleFunc("/api/pelanggan/telepon/", auth.RequireRole(models.RoleCashier, customerHandler.HandleCustomerByPhone))
+	http.HandleFunc("/api/pelanggan/", auth.Require(customerRules, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/poin") {
+			// Koreksi saldo poin manual hanya oleh supervisor
+			if r.Method == http.MethodPost {
+				auth.RequireRole(models.RoleSupervisor, loyaltyHandler.HandleCustomerPoints)(w, r)
+				return
+			}
+			loyaltyHandler.HandleCustomerPoints(w, r)
+			return
+		}
+		customerHandler.HandleCustomerByID(w, r)
+	}))
+	http.HandleFunc("/api/poin/aturan", auth.Require(loyaltyRules, loyaltyHandler.HandleSettings))
+
+	http.HandleFunc("/api/shift", auth.Require(shiftRules, shiftHandler.HandleShifts))
+	http.HandleFunc("/api/shift/current", auth.RequireRole(models.RoleCashier, shiftHandler.HandleCurrent))
+	http.HandleFunc("/api/shift/", auth.RequireRole(models.RoleCashier, func(w http.ResponseWriter, r *http.Request) {
+		// Z report mengunci shift sehingga hanya supervisor yang boleh membuatnya
+		if strings.HasSuffix(r.URL.Path, "/z") {
+			auth.RequireRole(models.RoleSupervisor, shiftHandler.HandleShiftByID)(w, r)
+			return
+		}
+		shiftHandler.HandleShiftByID(w, r)
+	}))
+
+	http.HandleFunc("/api/supplier", auth.Require(purchasingRules, supplierHandler.HandleSuppliers))
+	http.HandleFunc("/api/supplier/", auth.Require(purchasingRules, supplierHandler.HandleSupplierByID))
+
+	http.HandleFunc("/api/pembelian", auth.Require(purchasingRules, purchaseOrderHandler.HandlePurchaseOrders))
+	http.HandleFunc("/api/pembelian/", auth.Require(purchasingRules, purchaseOrderHandler.HandlePurchaseOrderByID))
+
+	http.HandleFunc("/api/outlet", auth.Require(outletRules, outletHandler.HandleOutlets))
+	http.HandleFunc("/api/outlet/", auth.Require(outletRules, outletHandler.HandleOutletByID))
+
+	http.HandleFunc("/api/transfer", auth.RequireRole(models.RoleSupervisor, stockTransferHandler.HandleTransfers))
+	http.HandleFunc("/api/transfer/", auth.RequireRole(models.RoleSupervisor, stockTransferHandler.HandleTransferByID))
+
+	http.HandleFunc("/api/opname", auth.RequireRole(models.RoleSupervisor, stockTakeHandler.HandleStockTakes))
+	http.HandleFunc("/api/opname/", auth.RequireRole(models.RoleCashier, func(w http.ResponseWriter, r *http.Request) {
+		// Petugas hitung cukup kasir dan tidak melihat stok sistem; sisanya oleh supervisor
+		if strings.HasSuffix(r.URL.Path, "/hitung") {
+			stockTakeHandler.HandleStockTakeByID(w, r)
+			return
+		}
+		auth.RequireRole(models.RoleSupervisor, stockTakeHandler.HandleStockTakeByID)(w, r)
+	}))
+
+	http.HandleFunc("/api/laporan/penjualan", auth.RequireRole(models.RoleSupervisor, reportHandler.HandleSales))
+	http.HandleFunc("/api/laporan/produk-terlaris", auth.RequireRole(models.RoleSupervisor, reportHandler.HandleTopProducts))
+	http.HandleFunc("/api/laporan/kategori", auth.RequireRole(models.RoleSupervisor, reportHandler.HandleCategories))
+	http.HandleFunc("/api/laporan/jam", auth.RequireRole(models.RoleSupervisor, reportHandler.HandleHourly))
+	http.HandleFunc("/api/laporan/margin", auth.RequireRole(models.RoleSupervisor, reportHandler.HandleMargin))
+
+	http.HandleFunc("/api/retur", auth.RequireRole(models.RoleCashier, returnHandler.HandleReturns))
+	http.HandleFunc("/api/retur/", auth.Require(returnRules, returnHandler.HandleReturnByID))
+
+	http.HandleFunc("/api/pembayaran/", auth.Require(paymentRules, paymentHandler.HandlePaymentByID))
+	http.HandleFunc("/api/pembayaran/callback/", paymentHandler.HandleCallback)
+
+	return stockMovementHandler.HandleProductStock
+}
+
+// expirePayments - batalkan tagihan QRIS yang kedaluwarsa tanpa callback secara berkala
+func expirePayments(service *services.PaymentService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		if err := service.ExpireOverdue(now); err != nil {
+			log.Println("expire payments:", err)
+		}
+	}
+}
+
+// expirePoints - hanguskan poin pelanggan yang sudah lewat masa berlakunya secara berkala
+func expirePoints(service *services.LoyaltyService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		if err := service.ExpireOverdue(now); err != nil {
+			log.Println("expire points:", err)
+		}
+	}
+}
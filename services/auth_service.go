@@ -0,0 +1,102 @@
+package services
+
+import (
+	"errors"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"log"
+	"strings"
+	"time"
+)
+
+type AuthService struct {
+	repo     repositories.UserRepositoryInterface
+	secret   []byte
+	tokenTTL time.Duration
+}
+
+func NewAuthService(repo repositories.UserRepositoryInterface, secret string, tokenTTL time.Duration) *AuthService {
+	return &AuthService{repo: repo, secret: []byte(secret), tokenTTL: tokenTTL}
+}
+
+// Login - verifikasi username dan password lalu terbitkan token
+func (s *AuthService) Login(username, password string) (*models.LoginResponse, error) {
+	user, err := s.repo.GetByUsername(strings.TrimSpace(username))
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckPassword(user.PasswordHash, password); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	expiresAt := time.Now().Add(s.tokenTTL)
+	token, err := SignToken(models.Claims{
+		UserID:    user.ID,
+		Username:  user.Username,
+		Role:      user.Role,
+		ExpiresAt: expiresAt.Unix(),
+	}, s.secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.LoginResponse{Token: token, ExpiresAt: expiresAt, User: *user}, nil
+}
+
+// ParseToken - verifikasi token bearer
+func (s *AuthService) ParseToken(token string) (*models.Claims, error) {
+	return ParseToken(token, s.secret, time.Now())
+}
+
+func (s *AuthService) GetUsers() ([]models.User, error) {
+	return s.repo.GetAll()
+}
+
+// CreateUser - buat akun baru dengan password yang di-hash
+func (s *AuthService) CreateUser(user *models.User) error {
+	v := &validator{}
+	user.Username = strings.TrimSpace(user.Username)
+	user.Name = strings.TrimSpace(user.Name)
+	v.required(user.Username, "username", 100)
+	v.maxLength(user.Name, "name", 255)
+	v.check(len(user.Password) >= 8, "password", "minimal 8 karakter")
+	v.check(models.ValidRole(user.Role), "role", "harus cashier, supervisor atau admin")
+	if err := v.err(); err != nil {
+		return err
+	}
+
+	hash, err := HashPassword(user.Password)
+	if err != nil {
+		return err
+	}
+	user.PasswordHash = hash
+	user.Password = ""
+
+	return s.repo.Create(user)
+}
+
+// EnsureAdmin - buat akun admin awal jika belum ada user sama sekali
+func (s *AuthService) EnsureAdmin(username, password string) error {
+	if username == "" || password == "" {
+		return nil
+	}
+
+	count, err := s.repo.Count()
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	err = s.CreateUser(&models.User{Username: username, Name: "Administrator", Role: models.RoleAdmin, Password: password})
+	if err != nil {
+		return err
+	}
+
+	log.Println("Admin awal dibuat:", username)
+	return nil
+}
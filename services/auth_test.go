@@ -0,0 +1,40 @@
+package services
+
+import (
+	"kasir-api/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashPassword(t *testing.T) {
+	hash, err := HashPassword("rahasia123")
+	assert.NoError(t, err)
+	assert.NotContains(t, hash, "rahasia123")
+
+	assert.NoError(t, CheckPassword(hash, "rahasia123"))
+	assert.Error(t, CheckPassword(hash, "rahasia124"))
+}
+
+func TestToken(t *testing.T) {
+	secret := []byte("test-secret")
+	now := time.Now()
+	claims := models.Claims{UserID: 1, Username: "siti", Role: models.RoleCashier, ExpiresAt: now.Add(time.Hour).Unix()}
+
+	token, err := SignToken(claims, secret)
+	assert.NoError(t, err)
+
+	parsed, err := ParseToken(token, secret, now)
+	assert.NoError(t, err)
+	assert.Equal(t, claims, *parsed)
+
+	_, err = ParseToken(token, []byte("other-secret"), now)
+	assert.Error(t, err)
+
+	_, err = ParseToken(token, secret, now.Add(2*time.Hour))
+	assert.Error(t, err)
+
+	_, err = ParseToken(token+"x", secret, now)
+	assert.Error(t, err)
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidateBarcode - cek barcode EAN-13 (13 digit) atau UPC-A (12 digit) beserta check digit-nya
+func ValidateBarcode(code string) error {
+	if len(code) != 12 && len(code) != 13 {
+		return invalid("barcode %s harus 12 digit (UPC-A) atau 13 digit (EAN-13)", code)
+	}
+
+	digits := make([]int, len(code))
+	for i, r := range code {
+		if r < '0' || r > '9' {
+			return invalid("barcode %s hanya boleh berisi angka", code)
+		}
+		digits[i] = int(r - '0')
+	}
+
+	// Bobot GTIN dihitung dari kanan: digit tepat sebelum check digit berbobot 3, lalu 1, 3, ...
+	sum := 0
+	for i := len(digits) - 2; i >= 0; i-- {
+		if (len(digits)-2-i)%2 == 0 {
+			sum += digits[i] * 3
+		} else {
+			sum += digits[i]
+		}
+	}
+	check := (10 - sum%10) % 10
+	if check != digits[len(digits)-1] {
+		return invalid("check digit barcode %s tidak valid", code)
+	}
+
+	return nil
+}
+
+// normalizeCodes - rapikan SKU dan barcode produk lalu validasi setiap barcode
+func normalizeCodes(v *validator, sku string, barcodes []string) (string, []string) {
+	sku = strings.TrimSpace(sku)
+	v.maxLength(sku, "sku", 64)
+
+	seen := make(map[string]bool, len(barcodes))
+	normalized := make([]string, 0, len(barcodes))
+	for i, code := range barcodes {
+		code = strings.TrimSpace(code)
+		if code == "" || seen[code] {
+			continue
+		}
+		if err := ValidateBarcode(code); err != nil {
+			v.add(fmt.Sprintf("barcodes[%d]", i), err.Error())
+			continue
+		}
+		seen[code] = true
+		normalized = append(normalized, code)
+	}
+
+	return sku, normalized
+}
@@ -0,0 +1,40 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateBarcode(t *testing.T) {
+	valid := []string{
+		"8992761002015", // EAN-13
+		"4006381333931", // EAN-13
+		"036000291452",  // UPC-A
+	}
+	for _, code := range valid {
+		assert.NoError(t, ValidateBarcode(code), code)
+	}
+
+	invalid := []string{
+		"8992761002016", // check digit salah
+		"036000291453",  // check digit salah
+		"12345",         // panjang salah
+		"89927610020AB", // bukan angka
+	}
+	for _, code := range invalid {
+		assert.Error(t, ValidateBarcode(code), code)
+	}
+}
+
+func TestNormalizeCodes(t *testing.T) {
+	v := &validator{}
+	sku, barcodes := normalizeCodes(v, "  KOPI-001 ", []string{" 8992761002015", "8992761002015", ""})
+	assert.NoError(t, v.err())
+	assert.Equal(t, "KOPI-001", sku)
+	assert.Equal(t, []string{"8992761002015"}, barcodes)
+
+	v = &validator{}
+	normalizeCodes(v, "KOPI-001", []string{"8992761002015", "8992761002016"})
+	assert.Equal(t, []FieldError{{Field: "barcodes[1]", Message: "check digit barcode 8992761002016 tidak valid"}}, v.fields)
+}
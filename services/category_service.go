@@ -3,21 +3,31 @@ package services
 import (
 	"kasir-api/models"
 	"kasir-api/repositories"
+	"strings"
 )
 
 type CategoryService struct {
-	repo *repositories.CategoryRepository
+	repo        repositories.CategoryRepositoryInterface
+	taxRateRepo repositories.TaxRateRepositoryInterface
 }
 
-func NewCategoryService(repo *repositories.CategoryRepository) *CategoryService {
-	return &CategoryService{repo: repo}
+func NewCategoryService(repo repositories.CategoryRepositoryInterface, taxRateRepo repositories.TaxRateRepositoryInterface) *CategoryService {
+	return &CategoryService{repo: repo, taxRateRepo: taxRateRepo}
 }
 
-func (s *CategoryService) GetAll() ([]models.Category, error) {
-	return s.repo.GetAll()
+func (s *CategoryService) GetAll(filter models.CategoryFilter) (*models.Page[models.Category], error) {
+	filter.Page, filter.Limit = normalizePage(filter.Page, filter.Limit)
+	categories, total, err := s.repo.GetAll(filter)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewPage(categories, filter.Page, filter.Limit, total), nil
 }
 
 func (s *CategoryService) Create(data *models.Category) error {
+	if err := s.validate(data); err != nil {
+		return err
+	}
 	return s.repo.Create(data)
 }
 
@@ -25,10 +35,33 @@ func (s *CategoryService) GetByID(id int) (*models.Category, error) {
 	return s.repo.GetByID(id)
 }
 
-func (s *CategoryService) Update(product *models.Category) error {
-	return s.repo.Update(product)
+func (s *CategoryService) Update(category *models.Category) error {
+	if err := s.validate(category); err != nil {
+		return err
+	}
+	return s.repo.Update(category)
 }
 
 func (s *CategoryService) Delete(id int) error {
 	return s.repo.Delete(id)
 }
+
+func (s *CategoryService) validate(category *models.Category) error {
+	if err := validateCategory(category); err != nil {
+		return err
+	}
+	v := &validator{}
+	if err := checkTaxRate(v, s.taxRateRepo, category.TaxRateID); err != nil {
+		return err
+	}
+	return v.err()
+}
+
+func validateCategory(category *models.Category) error {
+	v := &validator{}
+	category.Name = strings.TrimSpace(category.Name)
+	category.Description = strings.TrimSpace(category.Description)
+	v.required(category.Name, "name", 255)
+	v.maxLength(category.Description, "description", 1000)
+	return v.err()
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"net/mail"
+	"strings"
+)
+
+type CustomerService struct {
+	repo *repositories.CustomerRepository
+}
+
+func NewCustomerService(repo *repositories.CustomerRepository) *CustomerService {
+	return &CustomerService{repo: repo}
+}
+
+func (s *CustomerService) GetAll(filter models.CustomerFilter) (*models.Page[models.Customer], error) {
+	filter.Page, filter.Limit = normalizePage(filter.Page, filter.Limit)
+	// Pencarian dengan format +62 tetap cocok dengan nomor yang disimpan sebagai 08...
+	if phone, err := NormalizePhone(filter.Search); err == nil {
+		filter.Search = phone
+	}
+	customers, total, err := s.repo.GetAll(filter)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewPage(customers, filter.Page, filter.Limit, total), nil
+}
+
+func (s *CustomerService) GetByID(id int) (*models.Customer, error) {
+	return s.repo.GetByID(id)
+}
+
+// GetByPhone - cari pelanggan di kasir dari nomor telepon dalam format apa pun
+func (s *CustomerService) GetByPhone(phone string) (*models.Customer, error) {
+	normalized, err := NormalizePhone(phone)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetByPhone(normalized)
+}
+
+func (s *CustomerService) Create(customer *models.Customer) error {
+	if err := validateCustomer(customer); err != nil {
+		return err
+	}
+	return s.repo.Create(customer)
+}
+
+func (s *CustomerService) Update(customer *models.Customer) error {
+	if err := validateCustomer(customer); err != nil {
+		return err
+	}
+	return s.repo.Update(customer)
+}
+
+func (s *CustomerService) Delete(id int) error {
+	return s.repo.Delete(id)
+}
+
+// History - ringkasan belanja dan daftar transaksi pelanggan
+func (s *CustomerService) History(customerID, page, limit int) (*models.CustomerHistory, error) {
+	page, limit = normalizePage(page, limit)
+	history, transactions, err := s.repo.History(customerID, page, limit)
+	if err != nil {
+		return nil, err
+	}
+	history.Transactions = models.NewPage(transactions, page, limit, history.TransactionCount)
+	return history, nil
+}
+
+func validateCustomer(customer *models.Customer) error {
+	v := &validator{}
+	customer.Name = strings.TrimSpace(customer.Name)
+	customer.Email = strings.TrimSpace(customer.Email)
+	customer.Address = strings.TrimSpace(customer.Address)
+	customer.Notes = strings.TrimSpace(customer.Notes)
+	v.required(customer.Name, "name", 255)
+	v.maxLength(customer.Address, "address", 1000)
+	v.maxLength(customer.Notes, "notes", 1000)
+
+	if strings.TrimSpace(customer.Phone) == "" {
+		v.add("phone", "wajib diisi")
+	} else if phone, err := NormalizePhone(customer.Phone); err != nil {
+		v.add("phone", err.Error())
+	} else {
+		customer.Phone = phone
+	}
+
+	if customer.Email != "" {
+		_, err := mail.ParseAddress(customer.Email)
+		v.check(err == nil, "email", "format email tidak valid")
+		v.maxLength(customer.Email, "email", 255)
+	}
+	return v.err()
+}
+
+// checkCustomer - pastikan pelanggan yang ditempelkan ke transaksi ada
+func checkCustomer(v *validator, repo *repositories.CustomerRepository, id int) error {
+	if id == 0 {
+		return nil
+	}
+	if id < 0 {
+		v.add("customer_id", "tidak valid")
+		return nil
+	}
+	_, err := repo.GetByID(id)
+	return v.exists(err, "customer_id", "pelanggan tidak ditemukan")
+}
@@ -0,0 +1,18 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"kasir-api/repositories"
+)
+
+// ErrInvalidCredentials - login gagal karena username atau password salah
+var ErrInvalidCredentials = errors.New("username atau password salah")
+
+// ErrForbidden - user login tapi tidak boleh mengakses data milik user lain
+var ErrForbidden = errors.New("tidak memiliki akses ke data ini")
+
+// invalid - buat error validasi dengan pesan untuk client
+func invalid(format string, args ...interface{}) error {
+	return repositories.NewError(repositories.ErrValidation, fmt.Sprintf(format, args...))
+}
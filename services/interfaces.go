@@ -4,18 +4,176 @@ import "kasir-api/models"
 
 // ProductServiceInterface defines the interface for product service operations
 type ProductServiceInterface interface {
-	GetAll() ([]models.Product, error)
+	GetAll(filter models.ProductFilter) (*models.Page[models.Product], error)
 	GetByID(id int) (*models.Product, error)
+	GetByCode(code string) (*models.Product, error)
 	Create(product *models.Product) error
 	Update(product *models.Product) error
+	Export() ([][]string, error)
+	Import(req *models.ProductImportRequest) (*models.ProductImportResult, error)
 	Delete(id int) error
+	SetAttributes(id int, req *models.ProductAttributesRequest) (*models.Product, error)
+	CreateVariant(parentID int, req *models.ProductVariantRequest) (*models.Product, error)
+	UpdateVariant(parentID, id int, req *models.ProductVariantRequest) (*models.Product, error)
 }
 
 // CategoryServiceInterface defines the interface for category service operations
 type CategoryServiceInterface interface {
-	GetAll() ([]models.Category, error)
+	GetAll(filter models.CategoryFilter) (*models.Page[models.Category], error)
 	GetByID(id int) (*models.Category, error)
 	Create(category *models.Category) error
 	Update(category *models.Category) error
 	Delete(id int) error
 }
+
+// TransactionServiceInterface defines the interface for checkout operations
+type TransactionServiceInterface interface {
+	Checkout(req *models.CheckoutRequest) (*models.Transaction, error)
+	GetByID(id int) (*models.Transaction, error)
+}
+
+// StockMovementServiceInterface defines the interface for stock ledger operations
+type StockMovementServiceInterface interface {
+	Record(movement *models.StockMovement) error
+	History(productID int) (*models.StockHistory, error)
+}
+
+// AuthServiceInterface defines the interface for login and user account operations
+type AuthServiceInterface interface {
+	Login(username, password string) (*models.LoginResponse, error)
+	ParseToken(token string) (*models.Claims, error)
+	GetUsers() ([]models.User, error)
+	CreateUser(user *models.User) error
+}
+
+// ReceiptServiceInterface defines the interface for printable receipt rendering
+type ReceiptServiceInterface interface {
+	Render(transactionID int, format string, width int) (*models.RenderedReceipt, error)
+}
+
+// PromotionServiceInterface defines the interface for promotion management and basket preview
+type PromotionServiceInterface interface {
+	GetAll(filter models.PromotionFilter) (*models.Page[models.Promotion], error)
+	GetByID(id int) (*models.Promotion, error)
+	Create(promotion *models.Promotion) error
+	Update(promotion *models.Promotion) error
+	Delete(id int) error
+	Preview(req *models.CheckoutRequest) (*models.PricedBasket, error)
+}
+
+// TaxRateServiceInterface defines the interface for tax rate operations
+type TaxRateServiceInterface interface {
+	GetAll() ([]models.TaxRate, error)
+	GetByID(id int) (*models.TaxRate, error)
+	Create(rate *models.TaxRate) error
+	Update(rate *models.TaxRate) error
+	Delete(id int) error
+}
+
+// PaymentServiceInterface defines the interface for payment status and provider callbacks
+type PaymentServiceInterface interface {
+	GetByID(id int) (*models.Payment, error)
+	HandleCallback(provider string, body []byte, signature string) (*models.Payment, error)
+	Simulate(paymentID int, status string) (*models.Payment, error)
+}
+
+// ReturnServiceInterface defines the interface for returns and refunds
+type ReturnServiceInterface interface {
+	GetAll(filter models.ReturnFilter) (*models.Page[models.Return], error)
+	GetByID(id int) (*models.Return, error)
+	Create(req *models.ReturnRequest) (*models.Return, error)
+	Approve(id int, decision *models.ReturnDecision) (*models.Return, error)
+	Reject(id int, decision *models.ReturnDecision) (*models.Return, error)
+}
+
+// ShiftServiceInterface defines the interface for cashier shifts and X/Z reports
+type ShiftServiceInterface interface {
+	GetAll(filter models.ShiftFilter) (*models.Page[models.Shift], error)
+	GetByID(id int, actor *models.Claims) (*models.Shift, error)
+	GetCurrent(cashier string) (*models.Shift, error)
+	Open(req *models.OpenShiftRequest) (*models.Shift, error)
+	AddCashEvent(event *models.CashEvent, actor *models.Claims) error
+	Close(id int, req *models.CloseShiftRequest, actor *models.Claims) (*models.Shift, error)
+	XReport(id int, actor *models.Claims) (*models.ShiftReport, error)
+	ZReport(id int, user string) (*models.ShiftReport, error)
+	RenderReport(report *models.ShiftReport, format string, width int) (*models.RenderedReceipt, error)
+}
+
+// CustomerServiceInterface defines the interface for customer service
+type CustomerServiceInterface interface {
+	GetAll(filter models.CustomerFilter) (*models.Page[models.Customer], error)
+	GetByID(id int) (*models.Customer, error)
+	GetByPhone(phone string) (*models.Customer, error)
+	Create(customer *models.Customer) error
+	Update(customer *models.Customer) error
+	Delete(id int) error
+	History(customerID, page, limit int) (*models.CustomerHistory, error)
+}
+
+// LoyaltyServiceInterface defines the interface for loyalty rules and point ledgers
+type LoyaltyServiceInterface interface {
+	GetSettings() (*models.LoyaltySettings, error)
+	UpdateSettings(settings *models.LoyaltySettings) error
+	Ledger(customerID, page, limit int) (*models.PointLedger, error)
+	Adjust(customerID int, adj *models.PointAdjustment) (*models.PointEntry, error)
+}
+
+// SupplierServiceInterface defines the interface for supplier service
+type SupplierServiceInterface interface {
+	GetAll(filter models.SupplierFilter) (*models.Page[models.Supplier], error)
+	GetByID(id int) (*models.Supplier, error)
+	Create(supplier *models.Supplier) error
+	Update(supplier *models.Supplier) error
+	Delete(id int) error
+}
+
+// PurchaseOrderServiceInterface defines the interface for purchase orders and goods receipts
+type PurchaseOrderServiceInterface interface {
+	GetAll(filter models.PurchaseOrderFilter) (*models.Page[models.PurchaseOrder], error)
+	GetByID(id int) (*models.PurchaseOrder, error)
+	Create(req *models.PurchaseOrderRequest) (*models.PurchaseOrder, error)
+	Update(id int, req *models.PurchaseOrderRequest) (*models.PurchaseOrder, error)
+	Order(id int) (*models.PurchaseOrder, error)
+	Cancel(id int) (*models.PurchaseOrder, error)
+	Receive(id int, req *models.GoodsReceiptRequest) (*models.PurchaseOrder, error)
+}
+
+// ReportServiceInterface defines the interface for sales and margin reports
+type ReportServiceInterface interface {
+	Margin(filter models.ReportFilter) (*models.MarginReport, error)
+	Sales(filter models.ReportFilter) (*models.SalesReport, error)
+	TopProducts(filter models.ReportFilter) (*models.TopProductsReport, error)
+	Categories(filter models.ReportFilter) (*models.CategoryReport, error)
+	Hourly(filter models.ReportFilter) (*models.HourlyReport, error)
+}
+
+// OutletServiceInterface defines the interface for outlet service
+type OutletServiceInterface interface {
+	GetAll(filter models.OutletFilter) (*models.Page[models.Outlet], error)
+	GetByID(id int) (*models.Outlet, error)
+	Create(outlet *models.Outlet) error
+	Update(outlet *models.Outlet) error
+	Delete(id int) error
+}
+
+// StockTransferServiceInterface defines the interface for stock transfers between outlets
+type StockTransferServiceInterface interface {
+	GetAll(filter models.StockTransferFilter) (*models.Page[models.StockTransfer], error)
+	GetByID(id int) (*models.StockTransfer, error)
+	Create(req *models.StockTransferRequest) (*models.StockTransfer, error)
+	Send(id int, user string) (*models.StockTransfer, error)
+	Receive(id int, user string) (*models.StockTransfer, error)
+	Cancel(id int) (*models.StockTransfer, error)
+}
+
+// StockTakeServiceInterface defines the interface for stock opname sessions
+type StockTakeServiceInterface interface {
+	GetAll(filter models.StockTakeFilter) (*models.Page[models.StockTake], error)
+	GetByID(id int) (*models.StockTake, error)
+	Create(req *models.StockTakeRequest) (*models.StockTake, error)
+	Count(id int, req *models.StockTakeCountRequest) ([]models.StockTakeLine, error)
+	Approve(id int, req *models.StockTakeApproveRequest) (*models.StockTake, error)
+	Cancel(id int) (*models.StockTake, error)
+	VarianceReport(id int) (*models.StockTakeVarianceReport, error)
+	RenderVarianceReport(report *models.StockTakeVarianceReport, format string, width int) (*models.RenderedReceipt, error)
+}
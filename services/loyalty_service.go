@@ -0,0 +1,72 @@
+package services
+
+import (
+	"fmt"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"strings"
+	"time"
+)
+
+type LoyaltyService struct {
+	repo *repositories.LoyaltyRepository
+}
+
+func NewLoyaltyService(repo *repositories.LoyaltyRepository) *LoyaltyService {
+	return &LoyaltyService{repo: repo}
+}
+
+func (s *LoyaltyService) GetSettings() (*models.LoyaltySettings, error) {
+	return s.repo.GetSettings()
+}
+
+func (s *LoyaltyService) UpdateSettings(settings *models.LoyaltySettings) error {
+	v := &validator{}
+	v.check(settings.EarnAmount > 0, "earn_amount", "harus lebih dari 0")
+	v.check(settings.PointValue > 0, "point_value", "harus lebih dari 0")
+	v.check(settings.ExpiryDays >= 0 && settings.ExpiryDays <= 3650, "expiry_days", "harus antara 0 dan 3650")
+	seen := make(map[int]bool, len(settings.CategoryRules))
+	for i, r := range settings.CategoryRules {
+		field := fmt.Sprintf("category_rules[%d]", i)
+		v.check(r.CategoryID > 0, field+".category_id", "wajib diisi")
+		v.check(!seen[r.CategoryID], field+".category_id", "kategori duplikat")
+		v.check(r.MultiplierPercent >= 0 && r.MultiplierPercent <= 1000, field+".multiplier_percent", "harus antara 0 dan 1000")
+		seen[r.CategoryID] = true
+	}
+	if settings.CategoryRules == nil {
+		settings.CategoryRules = make([]models.LoyaltyCategoryRule, 0)
+	}
+	if err := v.err(); err != nil {
+		return err
+	}
+	return s.repo.UpdateSettings(settings)
+}
+
+// Ledger - saldo dan riwayat poin pelanggan
+func (s *LoyaltyService) Ledger(customerID, page, limit int) (*models.PointLedger, error) {
+	page, limit = normalizePage(page, limit)
+	ledger, entries, total, err := s.repo.Ledger(customerID, page, limit)
+	if err != nil {
+		return nil, err
+	}
+	ledger.Entries = models.NewPage(entries, page, limit, total)
+	return ledger, nil
+}
+
+// Adjust - koreksi poin manual, alasan wajib diisi supaya ledger bisa diaudit
+func (s *LoyaltyService) Adjust(customerID int, adj *models.PointAdjustment) (*models.PointEntry, error) {
+	adj.Note = strings.TrimSpace(adj.Note)
+	v := &validator{}
+	v.check(adj.Points != 0, "points", "tidak boleh 0")
+	v.required(adj.Note, "note", 255)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	return s.repo.Adjust(customerID, *adj)
+}
+
+// ExpireOverdue - hanguskan sisa poin yang sudah lewat masa berlakunya
+func (s *LoyaltyService) ExpireOverdue(now time.Time) error {
+	_, err := s.repo.ExpireBefore(now)
+	return err
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"strings"
+)
+
+type OutletService struct {
+	repo *repositories.OutletRepository
+}
+
+func NewOutletService(repo *repositories.OutletRepository) *OutletService {
+	return &OutletService{repo: repo}
+}
+
+func (s *OutletService) GetAll(filter models.OutletFilter) (*models.Page[models.Outlet], error) {
+	filter.Page, filter.Limit = normalizePage(filter.Page, filter.Limit)
+	filter.Search = strings.TrimSpace(filter.Search)
+	outlets, total, err := s.repo.GetAll(filter)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewPage(outlets, filter.Page, filter.Limit, total), nil
+}
+
+func (s *OutletService) GetByID(id int) (*models.Outlet, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *OutletService) Create(outlet *models.Outlet) error {
+	if err := validateOutlet(outlet); err != nil {
+		return err
+	}
+	return s.repo.Create(outlet)
+}
+
+func (s *OutletService) Update(outlet *models.Outlet) error {
+	if err := validateOutlet(outlet); err != nil {
+		return err
+	}
+	return s.repo.Update(outlet)
+}
+
+func (s *OutletService) Delete(id int) error {
+	return s.repo.Delete(id)
+}
+
+// validateOutlet - kode outlet disimpan huruf besar supaya unik tanpa membedakan
+// huruf; type kosong berarti toko
+func validateOutlet(outlet *models.Outlet) error {
+	v := &validator{}
+	outlet.Code = strings.ToUpper(strings.TrimSpace(outlet.Code))
+	outlet.Name = strings.TrimSpace(outlet.Name)
+	outlet.Address = strings.TrimSpace(outlet.Address)
+	if outlet.Type == "" {
+		outlet.Type = models.OutletStore
+	}
+	v.required(outlet.Code, "code", 20)
+	v.check(!strings.ContainsAny(outlet.Code, " \t"), "code", "tidak boleh mengandung spasi")
+	v.required(outlet.Name, "name", 255)
+	v.maxLength(outlet.Address, "address", 1000)
+	v.check(outlet.Type == models.OutletStore || outlet.Type == models.OutletWarehouse, "type", "harus store atau warehouse")
+	v.check(!outlet.IsDefault || outlet.Active, "active", "outlet default harus aktif")
+	return v.err()
+}
@@ -0,0 +1,20 @@
+package services
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+// normalizePage - isi default page/limit dan batasi limit maksimum
+func normalizePage(page, limit int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = DefaultPageLimit
+	}
+	if limit > MaxPageLimit {
+		limit = MaxPageLimit
+	}
+	return page, limit
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	passwordIterations = 210000
+	passwordKeyLength  = 32
+	passwordSaltLength = 16
+)
+
+// HashPassword - hash password dengan PBKDF2-SHA256 dan salt acak.
+// Format: pbkdf2-sha256$<iterasi>$<salt base64>$<hash base64>
+func HashPassword(password string) (string, error) {
+	salt := make([]byte, passwordSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key, err := pbkdf2.Key(sha256.New, password, salt, passwordIterations, passwordKeyLength)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("pbkdf2-sha256$%d$%s$%s",
+		passwordIterations,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// CheckPassword - bandingkan password dengan hash tersimpan dalam waktu konstan
+func CheckPassword(hash, password string) error {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 4 || parts[0] != "pbkdf2-sha256" {
+		return errors.New("format hash password tidak dikenal")
+	}
+
+	iterations, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return err
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return err
+	}
+	expected, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return err
+	}
+
+	key, err := pbkdf2.Key(sha256.New, password, salt, iterations, len(expected))
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare(key, expected) != 1 {
+		return errors.New("password salah")
+	}
+
+	return nil
+}
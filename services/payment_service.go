@@ -0,0 +1,90 @@
+package services
+
+import (
+	"errors"
+	"kasir-api/models"
+	"kasir-api/payment"
+	"kasir-api/repositories"
+	"time"
+)
+
+type PaymentService struct {
+	repo      *repositories.PaymentRepository
+	providers payment.Providers
+}
+
+func NewPaymentService(repo *repositories.PaymentRepository, providers payment.Providers) *PaymentService {
+	return &PaymentService{repo: repo, providers: providers}
+}
+
+func (s *PaymentService) GetByID(id int) (*models.Payment, error) {
+	return s.repo.GetByID(id)
+}
+
+// HandleCallback - terima perubahan status dari provider. Signature diverifikasi oleh
+// provider; payment.ErrInvalidSignature diteruskan supaya handler membalas 401.
+func (s *PaymentService) HandleCallback(providerName string, body []byte, signature string) (*models.Payment, error) {
+	provider, ok := s.providers.ByName(providerName)
+	if !ok {
+		return nil, repositories.NewError(repositories.ErrNotFound, "provider pembayaran tidak dikenal")
+	}
+
+	event, err := provider.ParseCallback(body, signature)
+	if errors.Is(err, payment.ErrInvalidSignature) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, invalid("%v", err)
+	}
+
+	p, err := s.repo.GetByProviderRef(provider.Name(), event.ProviderRef)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.UpdateStatus(p.ID, event.Status)
+}
+
+// Simulate - kirim callback palsu untuk pembayaran yang ditangani provider simulasi,
+// supaya alur QRIS bisa dicoba tanpa gateway sungguhan
+func (s *PaymentService) Simulate(paymentID int, status string) (*models.Payment, error) {
+	v := &validator{}
+	v.check(status == models.PaymentPaid || status == models.PaymentExpired, "status", "harus paid atau expired")
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	p, err := s.repo.GetByID(paymentID)
+	if err != nil {
+		return nil, err
+	}
+	provider, ok := s.providers.ByName(p.Provider)
+	if !ok {
+		return nil, invalid("pembayaran %s tidak melalui provider", p.Method)
+	}
+	simulator, ok := provider.(payment.Simulator)
+	if !ok {
+		return nil, invalid("provider %s tidak mendukung simulasi", provider.Name())
+	}
+
+	body, signature, err := simulator.Simulate(p.ProviderRef, status)
+	if err != nil {
+		return nil, err
+	}
+	return s.HandleCallback(provider.Name(), body, signature)
+}
+
+// ExpireOverdue - tutup tagihan pending yang sudah lewat waktu tanpa callback dari
+// provider, sehingga transaksinya batal dan stok kembali
+func (s *PaymentService) ExpireOverdue(now time.Time) error {
+	payments, err := s.repo.ExpiresBefore(now)
+	if err != nil {
+		return err
+	}
+	for _, p := range payments {
+		_, err := s.repo.UpdateStatus(p.ID, models.PaymentExpired)
+		if err != nil && !errors.Is(err, repositories.ErrConflict) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package services
+
+import "strings"
+
+// NormalizePhone - ubah nomor telepon ke format nasional, misalnya "+62 812-3456-7890"
+// menjadi "081234567890". Spasi, strip, titik dan kurung diabaikan.
+func NormalizePhone(phone string) (string, error) {
+	cleaned := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '.', '(', ')':
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(phone))
+
+	switch {
+	case strings.HasPrefix(cleaned, "+62"):
+		cleaned = "0" + cleaned[3:]
+	case strings.HasPrefix(cleaned, "62"):
+		cleaned = "0" + cleaned[2:]
+	}
+
+	for _, r := range cleaned {
+		if r < '0' || r > '9' {
+			return "", invalid("nomor telepon %s hanya boleh berisi angka", phone)
+		}
+	}
+	if !strings.HasPrefix(cleaned, "0") || len(cleaned) < 9 || len(cleaned) > 15 {
+		return "", invalid("nomor telepon %s harus diawali 0 atau +62 dan terdiri dari 9-15 digit", phone)
+	}
+	return cleaned, nil
+}
@@ -0,0 +1,33 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizePhone(t *testing.T) {
+	valid := map[string]string{
+		"081234567890":      "081234567890",
+		"+62 812-3456-7890": "081234567890",
+		"62812.3456.7890":   "081234567890",
+		"(022) 1234567":     "0221234567",
+	}
+	for input, expected := range valid {
+		phone, err := NormalizePhone(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, phone, input)
+	}
+
+	invalid := []string{
+		"",
+		"0812abc",
+		"812345678",        // tanpa awalan 0
+		"0812",             // terlalu pendek
+		"0812345678901234", // terlalu panjang
+	}
+	for _, input := range invalid {
+		_, err := NormalizePhone(input)
+		assert.Error(t, err, input)
+	}
+}
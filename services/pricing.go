@@ -0,0 +1,56 @@
+package services
+
+import (
+	"kasir-api/models"
+	"kasir-api/promotion"
+	"kasir-api/repositories"
+	"kasir-api/tax"
+	"strings"
+	"time"
+)
+
+// Pricing menghitung harga keranjang yang sama untuk checkout dan preview:
+// promosi yang berlaku lebih dulu, lalu pajak dari harga setelah diskon
+type Pricing struct {
+	promotionRepo    *repositories.PromotionRepository
+	taxRateRepo      repositories.TaxRateRepositoryInterface
+	location         *time.Location
+	pricesIncludeTax bool
+}
+
+func NewPricing(promotionRepo *repositories.PromotionRepository, taxRateRepo repositories.TaxRateRepositoryInterface, location *time.Location, pricesIncludeTax bool) *Pricing {
+	return &Pricing{
+		promotionRepo:    promotionRepo,
+		taxRateRepo:      taxRateRepo,
+		location:         location,
+		pricesIncludeTax: pricesIncludeTax,
+	}
+}
+
+// Pricer - muat promosi dan tarif pajak yang berlaku sekarang. Kode voucher yang
+// tidak dikenal atau sudah tidak berlaku ditolak supaya kasir tahu.
+func (p *Pricing) Pricer(voucherCode string) (repositories.Pricer, error) {
+	now := time.Now().In(p.location)
+	promos, err := p.promotionRepo.GetActive(now)
+	if err != nil {
+		return nil, err
+	}
+
+	applicable := promotion.Applicable(promos, voucherCode, now)
+	if strings.TrimSpace(voucherCode) != "" && !promotion.HasVoucher(applicable, voucherCode) {
+		v := &validator{}
+		v.add("voucher_code", "tidak valid atau sudah tidak berlaku")
+		return nil, v.err()
+	}
+
+	rates, err := p.taxRateRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(lines []models.BasketLine) *models.PricedBasket {
+		basket := promotion.Evaluate(applicable, lines)
+		tax.Apply(basket, lines, rates, p.pricesIncludeTax)
+		return basket
+	}, nil
+}
@@ -0,0 +1,223 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"kasir-api/models"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// maxImportRows membatasi jumlah baris produk dalam satu file import
+const maxImportRows = 5000
+
+// Export - katalog produk sebagai tabel dengan header models.ProductColumns. Barcode
+// dipisahkan titik koma.
+func (s *ProductService) Export() ([][]string, error) {
+	products, err := s.repo.Export()
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([][]string, 0, len(products)+1)
+	rows = append(rows, models.ProductColumns)
+	for _, p := range products {
+		taxRate := ""
+		if p.TaxRateID > 0 {
+			taxRate = strconv.Itoa(p.TaxRateID)
+		}
+		rows = append(rows, []string{
+			p.SKU,
+			p.Name,
+			p.CategoryName,
+			strconv.Itoa(p.Price),
+			strconv.Itoa(p.Cost),
+			strconv.Itoa(p.Stock),
+			strings.Join(p.Barcodes, ";"),
+			taxRate,
+		})
+	}
+	return rows, nil
+}
+
+// Import - validasi setiap baris file lalu upsert produk berdasarkan SKU. Kolom dikenali
+// dari header tanpa membedakan huruf besar/kecil dan kolom lain diabaikan. Error validasi
+// dan error database dilaporkan per baris; satu baris gagal membatalkan seluruh import.
+func (s *ProductService) Import(req *models.ProductImportRequest) (*models.ProductImportResult, error) {
+	if len(req.Rows) == 0 {
+		return nil, invalid("file kosong")
+	}
+
+	columns := make(map[string]int)
+	for i, name := range req.Rows[0] {
+		key := strings.ToLower(strings.TrimSpace(name))
+		if !knownProductColumn(key) {
+			continue
+		}
+		if _, ok := columns[key]; ok {
+			return nil, invalid("kolom %s muncul lebih dari sekali", key)
+		}
+		columns[key] = i
+	}
+	for _, required := range []string{"sku", "name", "price"} {
+		if _, ok := columns[required]; !ok {
+			return nil, invalid("kolom %s wajib ada di baris pertama", required)
+		}
+	}
+	req.Columns = make(map[string]bool, len(columns))
+	for name := range columns {
+		req.Columns[name] = true
+	}
+
+	importer := &productImporter{service: s, columns: columns, skus: map[string]int{}, taxRates: map[int]bool{}}
+	rows := make([]models.ProductImportRow, 0, len(req.Rows)-1)
+	for i, record := range req.Rows[1:] {
+		if blankRecord(record) {
+			continue
+		}
+		if len(rows) == maxImportRows {
+			return nil, invalid("maksimal %d produk per file", maxImportRows)
+		}
+		row, err := importer.row(i+2, record)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		return nil, invalid("file tidak berisi produk")
+	}
+
+	created, err := s.repo.Import(req, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.ProductImportResult{DryRun: req.DryRun, Total: len(rows), CategoriesCreated: created, Rows: rows}
+	for _, row := range rows {
+		switch row.Action {
+		case models.ImportCreate:
+			result.Created++
+		case models.ImportUpdate:
+			result.Updated++
+		default:
+			result.Failed++
+		}
+	}
+	result.Committed = !req.DryRun && result.Failed == 0
+	if !result.Committed {
+		// ID produk baru ikut di-rollback
+		for i := range result.Rows {
+			if result.Rows[i].Action == models.ImportCreate {
+				result.Rows[i].ProductID = 0
+			}
+		}
+	}
+	return result, nil
+}
+
+// productImporter - validasi baris import; menyimpan SKU yang sudah muncul dan tarif
+// pajak yang sudah dicek supaya tidak query ulang untuk setiap baris
+type productImporter struct {
+	service  *ProductService
+	columns  map[string]int
+	skus     map[string]int
+	taxRates map[int]bool
+}
+
+func (imp *productImporter) row(number int, record []string) (models.ProductImportRow, error) {
+	cell := func(name string) string {
+		i, ok := imp.columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	v := &validator{}
+	p := models.Product{Name: cell("name")}
+	v.required(p.Name, "name", 255)
+	p.Price = importInt(v, cell("price"), "price", true)
+	p.Cost = importInt(v, cell("cost"), "cost", false)
+	p.Stock = importInt(v, cell("stock"), "stock", false)
+	p.TaxRateID = importInt(v, cell("tax_rate_id"), "tax_rate_id", false)
+
+	barcodes := strings.FieldsFunc(cell("barcodes"), func(r rune) bool {
+		return r == ';' || r == ',' || unicode.IsSpace(r)
+	})
+	p.SKU, p.Barcodes = normalizeCodes(v, cell("sku"), barcodes)
+	if p.SKU == "" {
+		v.add("sku", "wajib diisi")
+	} else if prev, ok := imp.skus[strings.ToLower(p.SKU)]; ok {
+		v.add("sku", fmt.Sprintf("sama dengan baris %d", prev))
+	} else {
+		imp.skus[strings.ToLower(p.SKU)] = number
+	}
+
+	category := cell("category")
+	v.maxLength(category, "category", 255)
+
+	if p.TaxRateID > 0 {
+		found, checked := imp.taxRates[p.TaxRateID]
+		if !checked {
+			_, err := imp.service.taxRateRepo.GetByID(p.TaxRateID)
+			if err := v.exists(err, "tax_rate_id", "tarif pajak tidak ditemukan"); err != nil {
+				return models.ProductImportRow{}, err
+			}
+			found = err == nil
+			imp.taxRates[p.TaxRateID] = found
+		} else if !found {
+			v.add("tax_rate_id", "tarif pajak tidak ditemukan")
+		}
+	}
+
+	row := models.ProductImportRow{Row: number, SKU: p.SKU, Name: p.Name, Product: p, Category: category}
+	var verr *ValidationError
+	if errors.As(v.err(), &verr) {
+		row.Action = models.ImportError
+		for _, f := range verr.Fields {
+			row.Errors = append(row.Errors, f.Field+": "+f.Message)
+		}
+	}
+	return row, nil
+}
+
+// importInt - baca angka bulat tidak negatif dari sel. Angka dari Excel seperti
+// "15000.0" diterima selama tidak punya pecahan.
+func importInt(v *validator, value, field string, required bool) int {
+	if value == "" {
+		v.check(!required, field, "wajib diisi")
+		return 0
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		f, ferr := strconv.ParseFloat(value, 64)
+		if ferr != nil || f != math.Trunc(f) || math.Abs(f) > math.MaxInt32 {
+			v.add(field, "harus angka bulat")
+			return 0
+		}
+		n = int(f)
+	}
+	v.check(n >= 0, field, "tidak boleh negatif")
+	return n
+}
+
+func knownProductColumn(name string) bool {
+	for _, c := range models.ProductColumns {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
+
+func blankRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
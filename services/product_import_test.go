@@ -0,0 +1,42 @@
+package services
+
+import (
+	"kasir-api/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportInt(t *testing.T) {
+	v := &validator{}
+	assert.Equal(t, 15000, importInt(v, "15000", "price", true))
+	assert.Equal(t, 15000, importInt(v, "15000.0", "price", true))
+	assert.Equal(t, 0, importInt(v, "", "cost", false))
+	assert.NoError(t, v.err())
+
+	for _, value := range []string{"", "12.5", "abc", "-1", "15.000,00"} {
+		v := &validator{}
+		importInt(v, value, "price", true)
+		assert.Error(t, v.err(), value)
+	}
+}
+
+func TestImportRowValidation(t *testing.T) {
+	columns := map[string]int{"sku": 0, "name": 1, "price": 2, "barcodes": 3, "category": 4}
+	imp := &productImporter{columns: columns, skus: map[string]int{}, taxRates: map[int]bool{}}
+
+	row, err := imp.row(2, []string{" KP-01 ", "Kopi Susu", "18000", "8992761002015; 012345678905", "Minuman"})
+	assert.NoError(t, err)
+	assert.Empty(t, row.Errors)
+	assert.Equal(t, "KP-01", row.Product.SKU)
+	assert.Equal(t, []string{"8992761002015", "012345678905"}, row.Product.Barcodes)
+	assert.Equal(t, "Minuman", row.Category)
+
+	row, err = imp.row(3, []string{"kp-01", "", "-5", "123"})
+	assert.NoError(t, err)
+	assert.Equal(t, models.ImportError, row.Action)
+	assert.Contains(t, row.Errors, "name: wajib diisi")
+	assert.Contains(t, row.Errors, "price: tidak boleh negatif")
+	assert.Contains(t, row.Errors, "sku: sama dengan baris 2")
+	assert.Len(t, row.Errors, 4) // termasuk barcode tidak valid
+}
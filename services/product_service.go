@@ -1,23 +1,38 @@
 package services
 
 import (
+	"fmt"
 	"kasir-api/models"
 	"kasir-api/repositories"
+	"strings"
 )
 
 type ProductService struct {
-	repo *repositories.ProductRepository
+	repo         repositories.ProductRepositoryInterface
+	categoryRepo repositories.CategoryRepositoryInterface
+	taxRateRepo  repositories.TaxRateRepositoryInterface
 }
 
-func NewProductService(repo *repositories.ProductRepository) *ProductService {
-	return &ProductService{repo: repo}
+func NewProductService(repo repositories.ProductRepositoryInterface, categoryRepo repositories.CategoryRepositoryInterface, taxRateRepo repositories.TaxRateRepositoryInterface) *ProductService {
+	return &ProductService{repo: repo, categoryRepo: categoryRepo, taxRateRepo: taxRateRepo}
 }
 
-func (s *ProductService) GetAll() ([]models.Product, error) {
-	return s.repo.GetAll()
+func (s *ProductService) GetAll(filter models.ProductFilter) (*models.Page[models.Product], error) {
+	filter.Page, filter.Limit = normalizePage(filter.Page, filter.Limit)
+	products, total, err := s.repo.GetAll(filter)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewPage(products, filter.Page, filter.Limit, total), nil
 }
 
 func (s *ProductService) Create(data *models.Product) error {
+	v := &validator{}
+	v.check(data.Stock >= 0, "stock", "tidak boleh negatif")
+	v.check(data.Cost >= 0, "cost", "tidak boleh negatif")
+	if err := s.validate(v, data); err != nil {
+		return err
+	}
 	return s.repo.Create(data)
 }
 
@@ -25,10 +40,123 @@ func (s *ProductService) GetByID(id int) (*models.Product, error) {
 	return s.repo.GetByID(id)
 }
 
+// GetByCode - cari produk dari hasil scan barcode atau SKU
+func (s *ProductService) GetByCode(code string) (*models.Product, error) {
+	return s.repo.GetByCode(strings.TrimSpace(code))
+}
+
+// Update - stok dan HPP diabaikan karena hanya bisa diubah lewat stock movement dan
+// penerimaan barang
 func (s *ProductService) Update(product *models.Product) error {
+	if err := s.validate(&validator{}, product); err != nil {
+		return err
+	}
 	return s.repo.Update(product)
 }
 
 func (s *ProductService) Delete(id int) error {
 	return s.repo.Delete(id)
 }
+
+// SetAttributes - ganti atribut varian produk induk, misalnya ukuran dan rasa beserta
+// pilihannya. Kembalian berisi produk induk dengan matriks variannya.
+func (s *ProductService) SetAttributes(id int, req *models.ProductAttributesRequest) (*models.Product, error) {
+	v := &validator{}
+	names := make(map[string]bool)
+	for i := range req.Attributes {
+		a := &req.Attributes[i]
+		field := fmt.Sprintf("attributes[%d]", i)
+		a.Name = strings.TrimSpace(a.Name)
+		v.required(a.Name, field+".name", 100)
+		v.check(!names[strings.ToLower(a.Name)], field+".name", "tidak boleh ganda")
+		names[strings.ToLower(a.Name)] = true
+
+		v.check(len(a.Options) > 0, field+".options", "minimal satu pilihan")
+		values := make(map[string]bool)
+		for j := range a.Options {
+			a.Options[j] = strings.TrimSpace(a.Options[j])
+			v.required(a.Options[j], fmt.Sprintf("%s.options[%d]", field, j), 100)
+			v.check(!values[strings.ToLower(a.Options[j])], fmt.Sprintf("%s.options[%d]", field, j), "tidak boleh ganda")
+			values[strings.ToLower(a.Options[j])] = true
+		}
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SetAttributes(id, req.Attributes); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+// CreateVariant - buat varian produk induk dengan SKU, barcode, stok awal dan HPP sendiri.
+// Tanpa price varian mengikuti harga produk induk.
+func (s *ProductService) CreateVariant(parentID int, req *models.ProductVariantRequest) (*models.Product, error) {
+	v := &validator{}
+	v.check(req.Stock >= 0, "stock", "tidak boleh negatif")
+	v.check(req.Cost >= 0, "cost", "tidak boleh negatif")
+	variant := s.variant(v, req)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateVariant(parentID, variant); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(variant.ID)
+}
+
+// UpdateVariant - ubah SKU, barcode dan harga varian. Stok, HPP dan pilihan atribut
+// tidak ikut diubah.
+func (s *ProductService) UpdateVariant(parentID, id int, req *models.ProductVariantRequest) (*models.Product, error) {
+	v := &validator{}
+	variant := s.variant(v, req)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	variant.ID, variant.ParentID = id, parentID
+	if err := s.repo.UpdateVariant(variant); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+// variant - rapikan dan cek kode, harga dan pilihan atribut varian
+func (s *ProductService) variant(v *validator, req *models.ProductVariantRequest) *models.Product {
+	variant := &models.Product{Stock: req.Stock, Cost: req.Cost, Options: make(map[string]string, len(req.Options))}
+	variant.SKU, variant.Barcodes = normalizeCodes(v, req.SKU, req.Barcodes)
+	if req.Price != nil {
+		v.check(*req.Price >= 0, "price", "tidak boleh negatif")
+		variant.Price, variant.PriceOverride = *req.Price, true
+	}
+	for name, value := range req.Options {
+		variant.Options[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	return variant
+}
+
+// validate - rapikan input lalu cek nama, harga, kode, kategori dan tarif pajak produk
+func (s *ProductService) validate(v *validator, product *models.Product) error {
+	product.Name = strings.TrimSpace(product.Name)
+	product.ParentID, product.PriceOverride, product.Options = 0, false, nil
+	product.Attributes, product.Variants = nil, nil
+	v.required(product.Name, "name", 255)
+	v.check(product.Price >= 0, "price", "tidak boleh negatif")
+	product.SKU, product.Barcodes = normalizeCodes(v, product.SKU, product.Barcodes)
+
+	if product.CategoryID < 0 {
+		v.add("category_id", "tidak valid")
+	} else if product.CategoryID > 0 {
+		_, err := s.categoryRepo.GetByID(product.CategoryID)
+		if err := v.exists(err, "category_id", "kategori tidak ditemukan"); err != nil {
+			return err
+		}
+	}
+	if err := checkTaxRate(v, s.taxRateRepo, product.TaxRateID); err != nil {
+		return err
+	}
+
+	return v.err()
+}
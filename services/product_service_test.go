@@ -0,0 +1,99 @@
+package services
+
+import (
+	"errors"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"kasir-api/repositories/memory"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMemoryProductService() (*ProductService, *CategoryService) {
+	store := memory.NewStore()
+	taxRates := memory.NewTaxRateRepository(store)
+	categories := memory.NewCategoryRepository(store)
+	products := memory.NewProductRepository(store)
+	return NewProductService(products, categories, taxRates), NewCategoryService(categories, taxRates)
+}
+
+func TestProductServiceCreate(t *testing.T) {
+	products, categories := newMemoryProductService()
+
+	category := &models.Category{Name: " Minuman "}
+	require.NoError(t, categories.Create(category))
+
+	product := &models.Product{Name: " Kopi Susu ", Price: 18000, Stock: 10, SKU: " KP-01 ", CategoryID: category.ID}
+	require.NoError(t, products.Create(product))
+	assert.Equal(t, "Kopi Susu", product.Name)
+
+	got, err := products.GetByCode("KP-01")
+	require.NoError(t, err)
+	assert.Equal(t, product.ID, got.ID)
+	assert.Equal(t, "Minuman", got.CategoryName)
+	assert.Equal(t, 10, got.Stock)
+
+	page, err := products.GetAll(models.ProductFilter{CategoryID: category.ID})
+	require.NoError(t, err)
+	assert.Equal(t, 1, page.Total)
+}
+
+func TestProductServiceValidation(t *testing.T) {
+	products, _ := newMemoryProductService()
+
+	err := products.Create(&models.Product{Name: "Kopi", Price: -1, Stock: -1, CategoryID: 7, TaxRateID: 99})
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	fields := make(map[string]string)
+	for _, f := range validationErr.Fields {
+		fields[f.Field] = f.Message
+	}
+	assert.Equal(t, "tidak boleh negatif", fields["price"])
+	assert.Equal(t, "tidak boleh negatif", fields["stock"])
+	assert.Equal(t, "kategori tidak ditemukan", fields["category_id"])
+	assert.Contains(t, fields, "tax_rate_id")
+
+	_, err = products.GetByID(1)
+	assert.True(t, errors.Is(err, repositories.ErrNotFound))
+}
+
+func TestProductServiceVariants(t *testing.T) {
+	products, _ := newMemoryProductService()
+
+	parent := &models.Product{Name: "Kopi Susu", Price: 18000}
+	require.NoError(t, products.Create(parent))
+
+	_, err := products.SetAttributes(parent.ID, &models.ProductAttributesRequest{Attributes: []models.ProductAttribute{
+		{Name: "Ukuran", Options: []string{"Regular", "regular "}},
+		{Name: " ukuran", Options: []string{}},
+	}})
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Len(t, validationErr.Fields, 3)
+
+	got, err := products.SetAttributes(parent.ID, &models.ProductAttributesRequest{Attributes: []models.ProductAttribute{
+		{Name: " Ukuran ", Options: []string{" Regular", "Large "}},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, []models.ProductAttribute{{Name: "Ukuran", Options: []string{"Regular", "Large"}}}, got.Attributes)
+
+	variant, err := products.CreateVariant(parent.ID, &models.ProductVariantRequest{SKU: " KS-R ", Stock: 4, Options: map[string]string{" Ukuran": "Regular "}})
+	require.NoError(t, err)
+	assert.Equal(t, "Kopi Susu Regular", variant.Name)
+	assert.Equal(t, "KS-R", variant.SKU)
+	assert.Equal(t, 18000, variant.Price)
+	assert.False(t, variant.PriceOverride)
+	assert.Equal(t, "Regular", variant.Options["Ukuran"])
+
+	price := 22000
+	variant, err = products.UpdateVariant(parent.ID, variant.ID, &models.ProductVariantRequest{SKU: "KS-R", Price: &price})
+	require.NoError(t, err)
+	assert.Equal(t, 22000, variant.Price)
+	assert.True(t, variant.PriceOverride)
+
+	price = -1
+	_, err = products.CreateVariant(parent.ID, &models.ProductVariantRequest{Price: &price, Stock: -1, Options: map[string]string{"Ukuran": "Large"}})
+	require.ErrorAs(t, err, &validationErr)
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"strings"
+	"time"
+)
+
+type PromotionService struct {
+	repo         *repositories.PromotionRepository
+	productRepo  repositories.ProductRepositoryInterface
+	categoryRepo repositories.CategoryRepositoryInterface
+	pricing      *Pricing
+}
+
+func NewPromotionService(repo *repositories.PromotionRepository, productRepo repositories.ProductRepositoryInterface, categoryRepo repositories.CategoryRepositoryInterface, pricing *Pricing) *PromotionService {
+	return &PromotionService{repo: repo, productRepo: productRepo, categoryRepo: categoryRepo, pricing: pricing}
+}
+
+func (s *PromotionService) GetAll(filter models.PromotionFilter) (*models.Page[models.Promotion], error) {
+	filter.Page, filter.Limit = normalizePage(filter.Page, filter.Limit)
+	promotions, total, err := s.repo.GetAll(filter)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewPage(promotions, filter.Page, filter.Limit, total), nil
+}
+
+func (s *PromotionService) GetByID(id int) (*models.Promotion, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *PromotionService) Create(p *models.Promotion) error {
+	if err := s.validate(p); err != nil {
+		return err
+	}
+	return s.repo.Create(p)
+}
+
+func (s *PromotionService) Update(p *models.Promotion) error {
+	if err := s.validate(p); err != nil {
+		return err
+	}
+	return s.repo.Update(p)
+}
+
+func (s *PromotionService) Delete(id int) error {
+	return s.repo.Delete(id)
+}
+
+// Preview - hitung diskon dan pajak keranjang dengan harga produk saat ini tanpa
+// menyimpan transaksi atau mengubah stok, untuk ditampilkan di layar kasir
+func (s *PromotionService) Preview(req *models.CheckoutRequest) (*models.PricedBasket, error) {
+	v := &validator{}
+	items := checkoutItems(v, req)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	lines := make([]models.BasketLine, 0, len(items))
+	for _, item := range items {
+		p, err := s.productRepo.GetByID(item.ProductID)
+		if err := v.exists(err, "items", fmt.Sprintf("produk id %d tidak ditemukan", item.ProductID)); err != nil {
+			return nil, err
+		}
+		if p == nil {
+			continue
+		}
+
+		// Sama seperti checkout: tarif produk, atau tarif kategorinya jika produk tidak punya
+		taxRateID := p.TaxRateID
+		if taxRateID == 0 && p.CategoryID != 0 {
+			category, err := s.categoryRepo.GetByID(p.CategoryID)
+			if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+				return nil, err
+			}
+			if category != nil {
+				taxRateID = category.TaxRateID
+			}
+		}
+
+		lines = append(lines, models.BasketLine{
+			ProductID:   p.ID,
+			ParentID:    p.ParentID,
+			CategoryID:  p.CategoryID,
+			TaxRateID:   taxRateID,
+			ProductName: p.Name,
+			Quantity:    item.Quantity,
+			Price:       p.Price,
+		})
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	price, err := s.pricing.Pricer(req.VoucherCode)
+	if err != nil {
+		return nil, err
+	}
+	return price(lines), nil
+}
+
+// validate - rapikan input lalu cek aturan sesuai jenis promosi
+func (s *PromotionService) validate(p *models.Promotion) error {
+	v := &validator{}
+	p.Name = strings.TrimSpace(p.Name)
+	p.VoucherCode = strings.ToUpper(strings.TrimSpace(p.VoucherCode))
+	v.required(p.Name, "name", 255)
+	v.maxLength(p.VoucherCode, "voucher_code", 50)
+	v.check(p.MinSpend >= 0, "min_spend", "tidak boleh negatif")
+
+	switch p.Type {
+	case models.PromotionPercentage:
+		v.check(p.Value > 0 && p.Value <= 100, "value", "harus antara 1 dan 100")
+	case models.PromotionFixed:
+		v.check(p.Value > 0, "value", "harus lebih dari 0")
+	case models.PromotionBuyXGetY:
+		v.check(p.BuyQuantity > 0, "buy_quantity", "harus lebih dari 0")
+		v.check(p.GetQuantity > 0, "get_quantity", "harus lebih dari 0")
+	case models.PromotionMinSpend:
+		v.check(p.Value > 0, "value", "harus lebih dari 0")
+		v.check(p.MinSpend > 0, "min_spend", "harus lebih dari 0")
+		v.check(p.ProductID == 0 && p.CategoryID == 0, "product_id", "promosi min_spend berlaku untuk seluruh keranjang")
+	default:
+		v.add("type", "harus percentage, fixed, buy_x_get_y atau min_spend")
+	}
+
+	if p.StartsAt != nil && p.EndsAt != nil {
+		v.check(p.EndsAt.After(*p.StartsAt), "ends_at", "harus setelah starts_at")
+	}
+	if p.HappyHourStart != "" || p.HappyHourEnd != "" {
+		v.check(validClock(p.HappyHourStart), "happy_hour_start", "format jam harus HH:MM")
+		v.check(validClock(p.HappyHourEnd), "happy_hour_end", "format jam harus HH:MM")
+	}
+
+	v.check(p.ProductID == 0 || p.CategoryID == 0, "category_id", "pilih product_id atau category_id, tidak keduanya")
+	if p.ProductID < 0 {
+		v.add("product_id", "tidak valid")
+	} else if p.ProductID > 0 {
+		_, err := s.productRepo.GetByID(p.ProductID)
+		if err := v.exists(err, "product_id", "produk tidak ditemukan"); err != nil {
+			return err
+		}
+	}
+	if p.CategoryID < 0 {
+		v.add("category_id", "tidak valid")
+	} else if p.CategoryID > 0 {
+		_, err := s.categoryRepo.GetByID(p.CategoryID)
+		if err := v.exists(err, "category_id", "kategori tidak ditemukan"); err != nil {
+			return err
+		}
+	}
+
+	return v.err()
+}
+
+// validClock - jam harian dalam format 24 jam "15:04"
+func validClock(value string) bool {
+	_, err := time.Parse("15:04", value)
+	return err == nil && len(value) == 5
+}
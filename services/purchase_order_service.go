@@ -0,0 +1,115 @@
+package services
+
+import (
+	"fmt"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"strings"
+)
+
+type PurchaseOrderService struct {
+	repo         *repositories.PurchaseOrderRepository
+	supplierRepo *repositories.SupplierRepository
+}
+
+func NewPurchaseOrderService(repo *repositories.PurchaseOrderRepository, supplierRepo *repositories.SupplierRepository) *PurchaseOrderService {
+	return &PurchaseOrderService{repo: repo, supplierRepo: supplierRepo}
+}
+
+func (s *PurchaseOrderService) GetAll(filter models.PurchaseOrderFilter) (*models.Page[models.PurchaseOrder], error) {
+	v := &validator{}
+	v.check(filter.Status == "" || validPurchaseStatus(filter.Status), "status",
+		"harus draft, ordered, partially_received, received atau cancelled")
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	filter.Page, filter.Limit = normalizePage(filter.Page, filter.Limit)
+	orders, total, err := s.repo.GetAll(filter)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewPage(orders, filter.Page, filter.Limit, total), nil
+}
+
+func (s *PurchaseOrderService) GetByID(id int) (*models.PurchaseOrder, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *PurchaseOrderService) Create(req *models.PurchaseOrderRequest) (*models.PurchaseOrder, error) {
+	if err := s.validate(req); err != nil {
+		return nil, err
+	}
+	return s.repo.Create(req)
+}
+
+// Update - ubah purchase order yang masih draft
+func (s *PurchaseOrderService) Update(id int, req *models.PurchaseOrderRequest) (*models.PurchaseOrder, error) {
+	if err := s.validate(req); err != nil {
+		return nil, err
+	}
+	return s.repo.Update(id, req)
+}
+
+func (s *PurchaseOrderService) validate(req *models.PurchaseOrderRequest) error {
+	req.Notes = strings.TrimSpace(req.Notes)
+
+	v := &validator{}
+	v.maxLength(req.Notes, "notes", 1000)
+	v.check(len(req.Lines) > 0, "lines", "minimal satu baris")
+	seen := make(map[int]bool, len(req.Lines))
+	for i, l := range req.Lines {
+		field := fmt.Sprintf("lines[%d]", i)
+		v.check(l.ProductID > 0, field+".product_id", "wajib diisi")
+		v.check(!seen[l.ProductID], field+".product_id", "produk duplikat, gabungkan jumlahnya")
+		v.check(l.Quantity > 0, field+".quantity", "harus lebih dari 0")
+		v.check(l.UnitCost >= 0, field+".unit_cost", "tidak boleh negatif")
+		seen[l.ProductID] = true
+	}
+
+	if req.SupplierID <= 0 {
+		v.add("supplier_id", "wajib diisi")
+	} else {
+		_, err := s.supplierRepo.GetByID(req.SupplierID)
+		if err := v.exists(err, "supplier_id", "supplier tidak ditemukan"); err != nil {
+			return err
+		}
+	}
+	return v.err()
+}
+
+// Order - tandai purchase order sudah dikirim ke supplier
+func (s *PurchaseOrderService) Order(id int) (*models.PurchaseOrder, error) {
+	return s.repo.Order(id)
+}
+
+func (s *PurchaseOrderService) Cancel(id int) (*models.PurchaseOrder, error) {
+	return s.repo.Cancel(id)
+}
+
+// Receive - terima barang dari supplier, stok bertambah lewat ledger
+func (s *PurchaseOrderService) Receive(id int, req *models.GoodsReceiptRequest) (*models.PurchaseOrder, error) {
+	req.Note = strings.TrimSpace(req.Note)
+
+	v := &validator{}
+	v.maxLength(req.Note, "note", 255)
+	v.check(len(req.Lines) > 0, "lines", "minimal satu baris")
+	for i, l := range req.Lines {
+		field := fmt.Sprintf("lines[%d]", i)
+		v.check(l.LineID > 0, field+".line_id", "wajib diisi")
+		v.check(l.Quantity > 0, field+".quantity", "harus lebih dari 0")
+		v.check(l.UnitCost == nil || *l.UnitCost >= 0, field+".unit_cost", "tidak boleh negatif")
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	return s.repo.Receive(id, req)
+}
+
+func validPurchaseStatus(status string) bool {
+	switch status {
+	case models.PurchaseDraft, models.PurchaseOrdered, models.PurchasePartiallyReceived, models.PurchaseReceived, models.PurchaseCancelled:
+		return true
+	}
+	return false
+}
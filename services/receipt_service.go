@@ -0,0 +1,69 @@
+package services
+
+import (
+	"fmt"
+	"kasir-api/models"
+	"kasir-api/receipt"
+	"kasir-api/repositories"
+	"time"
+)
+
+// fileExtensions - ekstensi file untuk header Content-Disposition
+var fileExtensions = map[string]string{
+	receipt.FormatText:   "txt",
+	receipt.FormatESCPOS: "bin",
+	receipt.FormatPDF:    "pdf",
+}
+
+type ReceiptService struct {
+	repo     *repositories.TransactionRepository
+	store    receipt.Store
+	width    int
+	location *time.Location
+}
+
+func NewReceiptService(repo *repositories.TransactionRepository, store receipt.Store, width int, location *time.Location) *ReceiptService {
+	if width != receipt.Width48 {
+		width = receipt.Width32
+	}
+	return &ReceiptService{repo: repo, store: store, width: width, location: location}
+}
+
+// Render - cetak ulang struk transaksi dalam format text, escpos atau pdf.
+// Lebar 0 memakai lebar default dari konfigurasi.
+func (s *ReceiptService) Render(transactionID int, format string, width int) (*models.RenderedReceipt, error) {
+	if format == "" {
+		format = receipt.FormatText
+	}
+	if width == 0 {
+		width = s.width
+	}
+
+	v := &validator{}
+	_, ok := fileExtensions[format]
+	v.check(ok, "format", "harus text, escpos atau pdf")
+	v.check(width == receipt.Width32 || width == receipt.Width48, "width", "harus 32 atau 48")
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	transaction, err := s.repo.GetByID(transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	body, contentType, err := receipt.Render(receipt.Receipt{
+		Store:       s.store,
+		Transaction: *transaction,
+		Location:    s.location,
+	}, format, width)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.RenderedReceipt{
+		ContentType: contentType,
+		Filename:    fmt.Sprintf("struk-%d.%s", transaction.ID, fileExtensions[format]),
+		Body:        body,
+	}, nil
+}
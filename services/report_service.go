@@ -0,0 +1,251 @@
+package services
+
+import (
+	"fmt"
+	"kasir-api/costing"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// Batas jumlah baris laporan produk terlaris dan rentang laporan harian
+const (
+	defaultTopLimit = 10
+	maxTopLimit     = 100
+	maxReportDays   = 366
+)
+
+type ReportService struct {
+	repo     *repositories.ReportRepository
+	location *time.Location
+}
+
+func NewReportService(repo *repositories.ReportRepository, location *time.Location) *ReportService {
+	return &ReportService{repo: repo, location: location}
+}
+
+// Margin - laporan margin kotor per produk, kategori atau periode. Tanpa group_by
+// laporan dikelompokkan per produk.
+func (s *ReportService) Margin(filter models.ReportFilter) (*models.MarginReport, error) {
+	if filter.GroupBy == "" {
+		filter.GroupBy = models.GroupByProduct
+	}
+
+	v := &validator{}
+	v.check(filter.GroupBy == models.GroupByProduct || filter.GroupBy == models.GroupByCategory || validPeriod(filter.GroupBy),
+		"group_by", "harus product, category, day, week atau month")
+	reportRange(v, &filter, time.Now().In(s.location))
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.repo.Margin(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.MarginReport{From: filter.From, To: filter.To, GroupBy: filter.GroupBy, Rows: rows}
+	for i := range rows {
+		r := &rows[i]
+		r.GrossProfit = r.Revenue - r.Cost
+		r.MarginBps = costing.MarginBps(r.Revenue, r.Cost)
+		report.Total.Quantity += r.Quantity
+		report.Total.Revenue += r.Revenue
+		report.Total.Cost += r.Cost
+	}
+	report.Total.GrossProfit = report.Total.Revenue - report.Total.Cost
+	report.Total.MarginBps = costing.MarginBps(report.Total.Revenue, report.Total.Cost)
+	return report, nil
+}
+
+// reportRange - isi Start dan End filter dari tanggal From dan To di zona waktu now.
+// Tanpa From laporan dimulai awal bulan berjalan, tanpa To sampai hari ini.
+func reportRange(v *validator, filter *models.ReportFilter, now time.Time) {
+	if filter.From == "" {
+		filter.From = now.Format("2006-01") + "-01"
+	}
+	if filter.To == "" {
+		filter.To = now.Format(dateLayout)
+	}
+
+	start, err := time.ParseInLocation(dateLayout, filter.From, now.Location())
+	v.check(err == nil, "from", "format tanggal harus YYYY-MM-DD")
+	end, toErr := time.ParseInLocation(dateLayout, filter.To, now.Location())
+	v.check(toErr == nil, "to", "format tanggal harus YYYY-MM-DD")
+	if err != nil || toErr != nil {
+		return
+	}
+	v.check(!end.Before(start), "to", "tidak boleh sebelum from")
+
+	filter.Start = start
+	filter.End = end.AddDate(0, 0, 1)
+}
+
+// Sales - ringkasan penjualan per hari, minggu atau bulan (default per hari). Periode
+// tanpa transaksi tetap muncul dengan nilai 0 supaya grafik tidak bolong.
+func (s *ReportService) Sales(filter models.ReportFilter) (*models.SalesReport, error) {
+	if filter.GroupBy == "" {
+		filter.GroupBy = models.GroupByDay
+	}
+
+	v := &validator{}
+	v.check(validPeriod(filter.GroupBy), "group_by", "harus day, week atau month")
+	reportRange(v, &filter, time.Now().In(s.location))
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	if filter.GroupBy == models.GroupByDay && filter.Start.AddDate(0, 0, maxReportDays).Before(filter.End) {
+		return nil, invalid("rentang laporan harian maksimal %d hari, gunakan group_by week atau month", maxReportDays)
+	}
+
+	rows, err := s.repo.Sales(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	byPeriod := make(map[string]models.SalesRow, len(rows))
+	for _, r := range rows {
+		byPeriod[r.Period] = r
+	}
+	report := &models.SalesReport{From: filter.From, To: filter.To, GroupBy: filter.GroupBy, Rows: make([]models.SalesRow, 0)}
+	for _, period := range periodStarts(filter.Start, filter.End, filter.GroupBy) {
+		r, ok := byPeriod[period]
+		if !ok {
+			r = models.SalesRow{Period: period}
+		}
+		completeSales(&r)
+		report.Rows = append(report.Rows, r)
+
+		report.Total.Transactions += r.Transactions
+		report.Total.Revenue += r.Revenue
+		report.Total.Discount += r.Discount
+		report.Total.Tax += r.Tax
+		report.Total.Refunds += r.Refunds
+	}
+	completeSales(&report.Total)
+	return report, nil
+}
+
+// TopProducts - produk terlaris berdasarkan quantity (default) atau revenue
+func (s *ReportService) TopProducts(filter models.ReportFilter) (*models.TopProductsReport, error) {
+	if filter.Sort == "" {
+		filter.Sort = "quantity"
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultTopLimit
+	}
+
+	v := &validator{}
+	v.check(filter.Sort == "quantity" || filter.Sort == "revenue", "sort", "harus quantity atau revenue")
+	v.check(filter.Limit > 0 && filter.Limit <= maxTopLimit, "limit", fmt.Sprintf("harus antara 1 dan %d", maxTopLimit))
+	reportRange(v, &filter, time.Now().In(s.location))
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.repo.TopProducts(filter)
+	if err != nil {
+		return nil, err
+	}
+	return &models.TopProductsReport{From: filter.From, To: filter.To, Sort: filter.Sort, Rows: rows}, nil
+}
+
+// Categories - penjualan bersih per kategori beserta porsinya dari total penjualan
+func (s *ReportService) Categories(filter models.ReportFilter) (*models.CategoryReport, error) {
+	v := &validator{}
+	reportRange(v, &filter, time.Now().In(s.location))
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.repo.Categories(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.CategoryReport{From: filter.From, To: filter.To, Rows: rows}
+	for _, r := range rows {
+		report.Revenue += r.Revenue
+	}
+	if report.Revenue > 0 {
+		for i := range rows {
+			rows[i].ShareBps = rows[i].Revenue * 10000 / report.Revenue
+		}
+	}
+	return report, nil
+}
+
+// Hourly - heatmap transaksi per hari dalam minggu dan jam. Semua 168 sel selalu ada.
+func (s *ReportService) Hourly(filter models.ReportFilter) (*models.HourlyReport, error) {
+	v := &validator{}
+	reportRange(v, &filter, time.Now().In(s.location))
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	cells, err := s.repo.Hourly(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.HourlyReport{
+		From:  filter.From,
+		To:    filter.To,
+		Cells: make([]models.HourlyCell, 7*24),
+		Hours: make([]models.HourlyCell, 24),
+	}
+	for i := range report.Cells {
+		report.Cells[i].Weekday = i/24 + 1
+		report.Cells[i].Hour = i % 24
+	}
+	for i := range report.Hours {
+		report.Hours[i].Hour = i
+	}
+	for _, c := range cells {
+		cell := &report.Cells[(c.Weekday-1)*24+c.Hour]
+		cell.Transactions, cell.Revenue = c.Transactions, c.Revenue
+		report.Hours[c.Hour].Transactions += c.Transactions
+		report.Hours[c.Hour].Revenue += c.Revenue
+	}
+	return report, nil
+}
+
+func validPeriod(groupBy string) bool {
+	return groupBy == models.GroupByDay || groupBy == models.GroupByWeek || groupBy == models.GroupByMonth
+}
+
+// completeSales - hitung penjualan bersih dan rata-rata nilai belanja per transaksi
+func completeSales(r *models.SalesRow) {
+	r.NetRevenue = r.Revenue - r.Refunds
+	if r.Transactions > 0 {
+		r.AverageBasket = (2*r.Revenue + r.Transactions) / (2 * r.Transactions)
+	}
+}
+
+// periodStarts - tanggal awal setiap periode day, week (mulai Senin) atau month yang
+// beririsan dengan [start, end), sama dengan date_trunc di PostgreSQL
+func periodStarts(start, end time.Time, unit string) []string {
+	cursor := start
+	switch unit {
+	case models.GroupByWeek:
+		cursor = cursor.AddDate(0, 0, -((int(cursor.Weekday()) + 6) % 7))
+	case models.GroupByMonth:
+		cursor = cursor.AddDate(0, 0, 1-cursor.Day())
+	}
+
+	periods := make([]string, 0)
+	for cursor.Before(end) {
+		periods = append(periods, cursor.Format(dateLayout))
+		switch unit {
+		case models.GroupByWeek:
+			cursor = cursor.AddDate(0, 0, 7)
+		case models.GroupByMonth:
+			cursor = cursor.AddDate(0, 1, 0)
+		default:
+			cursor = cursor.AddDate(0, 0, 1)
+		}
+	}
+	return periods
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"kasir-api/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportRangeDefaultsToCurrentMonth(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*3600)
+	now := time.Date(2026, 10, 16, 23, 30, 0, 0, jakarta)
+
+	v := &validator{}
+	filter := models.ReportFilter{}
+	reportRange(v, &filter, now)
+
+	assert.NoError(t, v.err())
+	assert.Equal(t, "2026-10-01", filter.From)
+	assert.Equal(t, "2026-10-16", filter.To)
+	assert.Equal(t, time.Date(2026, 10, 1, 0, 0, 0, 0, jakarta), filter.Start)
+	// To ikut dihitung sampai tengah malam waktu toko
+	assert.Equal(t, time.Date(2026, 10, 17, 0, 0, 0, 0, jakarta), filter.End)
+	assert.Equal(t, "2026-10-16T17:00:00Z", filter.End.UTC().Format(time.RFC3339))
+}
+
+func TestReportRangeInvalid(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	v := &validator{}
+	reportRange(v, &models.ReportFilter{From: "16-10-2026", To: "2026-10-16"}, now)
+	assert.Error(t, v.err())
+
+	v = &validator{}
+	reportRange(v, &models.ReportFilter{From: "2026-10-10", To: "2026-10-09"}, now)
+	assert.Error(t, v.err())
+}
+
+func TestPeriodStarts(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*3600)
+	start := time.Date(2026, 9, 30, 0, 0, 0, 0, jakarta) // Rabu
+	end := time.Date(2026, 10, 6, 0, 0, 0, 0, jakarta)
+
+	assert.Equal(t, []string{"2026-09-30", "2026-10-01", "2026-10-02", "2026-10-03", "2026-10-04", "2026-10-05"},
+		periodStarts(start, end, models.GroupByDay))
+	assert.Equal(t, []string{"2026-09-28", "2026-10-05"}, periodStarts(start, end, models.GroupByWeek))
+	assert.Equal(t, []string{"2026-09-01", "2026-10-01"}, periodStarts(start, end, models.GroupByMonth))
+}
+
+func TestCompleteSales(t *testing.T) {
+	r := models.SalesRow{Transactions: 3, Revenue: 100000, Refunds: 15000}
+	completeSales(&r)
+	assert.Equal(t, 85000, r.NetRevenue)
+	assert.Equal(t, 33333, r.AverageBasket)
+
+	empty := models.SalesRow{}
+	completeSales(&empty)
+	assert.Equal(t, 0, empty.AverageBasket)
+}
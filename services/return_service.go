@@ -0,0 +1,90 @@
+package services
+
+import (
+	"fmt"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"strings"
+)
+
+type ReturnService struct {
+	repo *repositories.ReturnRepository
+}
+
+func NewReturnService(repo *repositories.ReturnRepository) *ReturnService {
+	return &ReturnService{repo: repo}
+}
+
+func (s *ReturnService) GetAll(filter models.ReturnFilter) (*models.Page[models.Return], error) {
+	v := &validator{}
+	v.check(filter.Status == "" || validReturnStatus(filter.Status), "status", "harus pending, approved atau rejected")
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	filter.Page, filter.Limit = normalizePage(filter.Page, filter.Limit)
+	returns, total, err := s.repo.GetAll(filter)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewPage(returns, filter.Page, filter.Limit, total), nil
+}
+
+func (s *ReturnService) GetByID(id int) (*models.Return, error) {
+	return s.repo.GetByID(id)
+}
+
+// Create - ajukan retur untuk baris transaksi. Stok dan refund baru diproses setelah
+// disetujui supervisor.
+func (s *ReturnService) Create(req *models.ReturnRequest) (*models.Return, error) {
+	req.Reason = strings.TrimSpace(req.Reason)
+
+	v := &validator{}
+	v.check(req.TransactionID > 0, "transaction_id", "wajib diisi")
+	v.required(req.Reason, "reason", 255)
+	v.check(len(req.Lines) > 0, "lines", "minimal satu baris")
+	for i, l := range req.Lines {
+		field := fmt.Sprintf("lines[%d]", i)
+		v.check(l.TransactionDetailID > 0, field+".transaction_detail_id", "wajib diisi")
+		v.check(l.Quantity > 0, field+".quantity", "harus lebih dari 0")
+		v.check(l.Disposition == models.DispositionRestock || l.Disposition == models.DispositionWriteOff,
+			field+".disposition", "harus restock atau write_off")
+	}
+	for i, r := range req.Refunds {
+		field := fmt.Sprintf("refunds[%d]", i)
+		v.check(models.ValidPaymentMethod(r.Method), field+".method", "harus cash, debit_card, credit_card, qris, ewallet, store_credit atau points")
+		v.check(r.Amount > 0, field+".amount", "harus lebih dari 0")
+		v.maxLength(r.Reference, field+".reference", 100)
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	return s.repo.Create(req)
+}
+
+// Approve - setujui retur, barang restock kembali ke stok dan refund dianggap dibayarkan
+func (s *ReturnService) Approve(id int, decision *models.ReturnDecision) (*models.Return, error) {
+	decision.Note = strings.TrimSpace(decision.Note)
+	v := &validator{}
+	v.maxLength(decision.Note, "note", 255)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	return s.repo.Approve(id, *decision)
+}
+
+// Reject - tolak retur, alasan penolakan wajib diisi
+func (s *ReturnService) Reject(id int, decision *models.ReturnDecision) (*models.Return, error) {
+	decision.Note = strings.TrimSpace(decision.Note)
+	v := &validator{}
+	v.required(decision.Note, "note", 255)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	return s.repo.Reject(id, *decision)
+}
+
+func validReturnStatus(status string) bool {
+	return status == models.ReturnPending || status == models.ReturnApproved || status == models.ReturnRejected
+}
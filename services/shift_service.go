@@ -0,0 +1,160 @@
+package services
+
+import (
+	"fmt"
+	"kasir-api/models"
+	"kasir-api/receipt"
+	"kasir-api/repositories"
+	"strings"
+	"time"
+)
+
+type ShiftService struct {
+	repo     *repositories.ShiftRepository
+	store    receipt.Store
+	width    int
+	location *time.Location
+}
+
+func NewShiftService(repo *repositories.ShiftRepository, store receipt.Store, width int, location *time.Location) *ShiftService {
+	if width != receipt.Width48 {
+		width = receipt.Width32
+	}
+	return &ShiftService{repo: repo, store: store, width: width, location: location}
+}
+
+func (s *ShiftService) GetAll(filter models.ShiftFilter) (*models.Page[models.Shift], error) {
+	v := &validator{}
+	v.check(filter.Status == "" || filter.Status == models.ShiftOpen || filter.Status == models.ShiftClosed || filter.Status == models.ShiftLocked,
+		"status", "harus open, closed atau locked")
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	filter.Page, filter.Limit = normalizePage(filter.Page, filter.Limit)
+	shifts, total, err := s.repo.GetAll(filter)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewPage(shifts, filter.Page, filter.Limit, total), nil
+}
+
+func (s *ShiftService) GetByID(id int, actor *models.Claims) (*models.Shift, error) {
+	shift, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := canAccessShift(shift, actor); err != nil {
+		return nil, err
+	}
+	return shift, nil
+}
+
+// GetCurrent - shift terbuka milik kasir yang sedang login
+func (s *ShiftService) GetCurrent(cashier string) (*models.Shift, error) {
+	return s.repo.GetOpen(cashier)
+}
+
+// Open - buka shift dengan modal awal di laci kasir
+func (s *ShiftService) Open(req *models.OpenShiftRequest) (*models.Shift, error) {
+	req.Note = strings.TrimSpace(req.Note)
+	v := &validator{}
+	v.check(req.OpeningFloat >= 0, "opening_float", "tidak boleh negatif")
+	v.maxLength(req.Note, "note", 255)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	shift := &models.Shift{Cashier: req.Cashier, OpeningFloat: req.OpeningFloat, Note: req.Note}
+	if err := s.repo.Open(shift); err != nil {
+		return nil, err
+	}
+	return shift, nil
+}
+
+// AddCashEvent - catat kas masuk/keluar (petty cash) pada shift yang masih terbuka
+func (s *ShiftService) AddCashEvent(event *models.CashEvent, actor *models.Claims) error {
+	event.Reason = strings.TrimSpace(event.Reason)
+	v := &validator{}
+	v.check(event.Type == models.CashIn || event.Type == models.CashOut, "type", "harus cash_in atau cash_out")
+	v.check(event.Amount > 0, "amount", "harus lebih dari 0")
+	v.required(event.Reason, "reason", 255)
+	if err := v.err(); err != nil {
+		return err
+	}
+
+	if _, err := s.GetByID(event.ShiftID, actor); err != nil {
+		return err
+	}
+	return s.repo.AddCashEvent(event)
+}
+
+// Close - tutup shift dengan jumlah uang hasil hitung laci
+func (s *ShiftService) Close(id int, req *models.CloseShiftRequest, actor *models.Claims) (*models.Shift, error) {
+	req.Note = strings.TrimSpace(req.Note)
+	v := &validator{}
+	v.check(req.CountedCash >= 0, "counted_cash", "tidak boleh negatif")
+	v.maxLength(req.Note, "note", 255)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.GetByID(id, actor); err != nil {
+		return nil, err
+	}
+	return s.repo.Close(id, req)
+}
+
+// XReport - rekap shift saat ini tanpa mengubah apa pun
+func (s *ShiftService) XReport(id int, actor *models.Claims) (*models.ShiftReport, error) {
+	if _, err := s.GetByID(id, actor); err != nil {
+		return nil, err
+	}
+	return s.repo.Report(id)
+}
+
+// ZReport - rekap akhir shift yang sudah ditutup lalu kunci shift. Memanggil ulang
+// pada shift yang sudah dikunci mengembalikan laporan yang sama.
+func (s *ShiftService) ZReport(id int, user string) (*models.ShiftReport, error) {
+	return s.repo.Lock(id, user)
+}
+
+// RenderReport - cetak X/Z report dalam format text, escpos atau pdf.
+// Lebar 0 memakai lebar default dari konfigurasi.
+func (s *ShiftService) RenderReport(report *models.ShiftReport, format string, width int) (*models.RenderedReceipt, error) {
+	if width == 0 {
+		width = s.width
+	}
+
+	v := &validator{}
+	_, ok := fileExtensions[format]
+	v.check(ok, "format", "harus json, text, escpos atau pdf")
+	v.check(width == receipt.Width32 || width == receipt.Width48, "width", "harus 32 atau 48")
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	body, contentType, err := receipt.RenderShiftReport(receipt.ShiftReport{
+		Store:    s.store,
+		Report:   *report,
+		Location: s.location,
+	}, format, width)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.RenderedReceipt{
+		ContentType: contentType,
+		Filename:    fmt.Sprintf("%s-report-shift-%d.%s", strings.ToLower(report.Type), report.Shift.ID, fileExtensions[format]),
+		Body:        body,
+	}, nil
+}
+
+// canAccessShift - kasir hanya boleh melihat dan mengubah shift miliknya sendiri,
+// supervisor ke atas boleh semua shift
+func canAccessShift(shift *models.Shift, actor *models.Claims) error {
+	if actor == nil || actor.Username == shift.Cashier || models.RoleAtLeast(actor.Role, models.RoleSupervisor) {
+		return nil
+	}
+	return ErrForbidden
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"strings"
+)
+
+type StockMovementService struct {
+	repo        *repositories.StockMovementRepository
+	productRepo repositories.ProductRepositoryInterface
+}
+
+func NewStockMovementService(repo *repositories.StockMovementRepository, productRepo repositories.ProductRepositoryInterface) *StockMovementService {
+	return &StockMovementService{repo: repo, productRepo: productRepo}
+}
+
+// Record - validasi lalu catat pergerakan stok manual
+func (s *StockMovementService) Record(movement *models.StockMovement) error {
+	v := &validator{}
+	movement.Reason = strings.TrimSpace(movement.Reason)
+	movement.User = strings.TrimSpace(movement.User)
+	v.check(movement.Quantity != 0, "quantity", "tidak boleh 0")
+	v.required(movement.User, "user", 100)
+
+	switch movement.Type {
+	case models.MovementSale:
+		v.check(movement.Quantity <= 0, "quantity", "harus negatif untuk sale")
+	case models.MovementRestock, models.MovementReturn:
+		v.check(movement.Quantity >= 0, "quantity", "harus positif untuk "+movement.Type)
+	case models.MovementAdjustment, models.MovementTransfer:
+		v.check(movement.Reason != "", "reason", "wajib diisi untuk "+movement.Type)
+	default:
+		v.add("type", "harus sale, restock, adjustment, return atau transfer")
+	}
+	if err := v.err(); err != nil {
+		return err
+	}
+
+	return s.repo.Create(movement)
+}
+
+// History - riwayat movement produk beserta stok tersimpan, stok hasil ledger dan stok per outlet
+func (s *StockMovementService) History(productID int) (*models.StockHistory, error) {
+	product, err := s.productRepo.GetByID(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	movements, err := s.repo.GetByProductID(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	ledgerStock, err := s.repo.GetLedgerStock(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	outlets, err := s.repo.GetOutletStocks(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.StockHistory{
+		ProductID:    productID,
+		CurrentStock: product.Stock,
+		LedgerStock:  ledgerStock,
+		Outlets:      outlets,
+		Movements:    movements,
+	}, nil
+}
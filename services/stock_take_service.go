@@ -0,0 +1,182 @@
+package services
+
+import (
+	"fmt"
+	"kasir-api/models"
+	"kasir-api/receipt"
+	"kasir-api/repositories"
+	"strings"
+	"time"
+)
+
+type StockTakeService struct {
+	repo         *repositories.StockTakeRepository
+	categoryRepo repositories.CategoryRepositoryInterface
+	store        receipt.Store
+	width        int
+	location     *time.Location
+}
+
+func NewStockTakeService(repo *repositories.StockTakeRepository, categoryRepo repositories.CategoryRepositoryInterface, store receipt.Store, width int, location *time.Location) *StockTakeService {
+	if width != receipt.Width48 {
+		width = receipt.Width32
+	}
+	return &StockTakeService{repo: repo, categoryRepo: categoryRepo, store: store, width: width, location: location}
+}
+
+func (s *StockTakeService) GetAll(filter models.StockTakeFilter) (*models.Page[models.StockTake], error) {
+	v := &validator{}
+	v.check(filter.Status == "" || filter.Status == models.StockTakeCounting || filter.Status == models.StockTakeApproved || filter.Status == models.StockTakeCancelled,
+		"status", "harus counting, approved atau cancelled")
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	filter.Page, filter.Limit = normalizePage(filter.Page, filter.Limit)
+	takes, total, err := s.repo.GetAll(filter)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewPage(takes, filter.Page, filter.Limit, total), nil
+}
+
+// GetByID - sesi beserta seluruh baris dan ringkasan selisihnya
+func (s *StockTakeService) GetByID(id int) (*models.StockTake, error) {
+	return withSummary(s.repo.GetByID(id))
+}
+
+// Create - mulai stock opname dan snapshot stok sistem
+func (s *StockTakeService) Create(req *models.StockTakeRequest) (*models.StockTake, error) {
+	req.Note = strings.TrimSpace(req.Note)
+
+	v := &validator{}
+	v.check(req.OutletID >= 0, "outlet_id", "tidak valid")
+	v.maxLength(req.Note, "note", 1000)
+	if req.CategoryID < 0 {
+		v.add("category_id", "tidak valid")
+	} else if req.CategoryID > 0 {
+		_, err := s.categoryRepo.GetByID(req.CategoryID)
+		if err := v.exists(err, "category_id", "kategori tidak ditemukan"); err != nil {
+			return nil, err
+		}
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	return withSummary(s.repo.Create(req))
+}
+
+// Count - simpan hasil hitung dari satu perangkat. Tanpa replace, quantity negatif
+// boleh dipakai untuk mengoreksi kiriman sebelumnya selama hasilnya tidak negatif.
+func (s *StockTakeService) Count(id int, req *models.StockTakeCountRequest) ([]models.StockTakeLine, error) {
+	v := &validator{}
+	v.check(len(req.Entries) > 0, "entries", "minimal satu baris")
+	for i := range req.Entries {
+		e := &req.Entries[i]
+		field := fmt.Sprintf("entries[%d]", i)
+		e.Code = strings.TrimSpace(e.Code)
+		v.check(e.ProductID >= 0, field+".product_id", "tidak valid")
+		v.check(e.ProductID > 0 || e.Code != "", field+".product_id", "product_id atau code wajib diisi")
+		v.check(!req.Replace || e.Quantity >= 0, field+".quantity", "tidak boleh negatif")
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	return s.repo.Count(id, req)
+}
+
+// Approve - setujui hasil hitung, selisih diposting sebagai adjustment di ledger
+func (s *StockTakeService) Approve(id int, req *models.StockTakeApproveRequest) (*models.StockTake, error) {
+	return withSummary(s.repo.Approve(id, req))
+}
+
+func (s *StockTakeService) Cancel(id int) (*models.StockTake, error) {
+	return withSummary(s.repo.Cancel(id))
+}
+
+// VarianceReport - ringkasan seluruh baris beserta baris yang berselisih atau belum dihitung
+func (s *StockTakeService) VarianceReport(id int) (*models.StockTakeVarianceReport, error) {
+	st, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.StockTakeVarianceReport{
+		Summary:     summarizeStockTake(st.Lines),
+		Lines:       make([]models.StockTakeLine, 0),
+		GeneratedAt: time.Now(),
+	}
+	for _, l := range st.Lines {
+		if l.Variance == nil || *l.Variance != 0 {
+			report.Lines = append(report.Lines, l)
+		}
+	}
+	st.Lines = nil
+	report.StockTake = *st
+	return report, nil
+}
+
+// RenderVarianceReport - cetak laporan selisih dalam format text, escpos atau pdf.
+// Lebar 0 memakai lebar default dari konfigurasi.
+func (s *StockTakeService) RenderVarianceReport(report *models.StockTakeVarianceReport, format string, width int) (*models.RenderedReceipt, error) {
+	if width == 0 {
+		width = s.width
+	}
+
+	v := &validator{}
+	_, ok := fileExtensions[format]
+	v.check(ok, "format", "harus json, text, escpos atau pdf")
+	v.check(width == receipt.Width32 || width == receipt.Width48, "width", "harus 32 atau 48")
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	body, contentType, err := receipt.RenderStockTakeReport(receipt.StockTakeReport{
+		Store:    s.store,
+		Report:   *report,
+		Location: s.location,
+	}, format, width)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.RenderedReceipt{
+		ContentType: contentType,
+		Filename:    fmt.Sprintf("selisih-opname-%d.%s", report.StockTake.ID, fileExtensions[format]),
+		Body:        body,
+	}, nil
+}
+
+func withSummary(st *models.StockTake, err error) (*models.StockTake, error) {
+	if err != nil {
+		return nil, err
+	}
+	summary := summarizeStockTake(st.Lines)
+	st.Summary = &summary
+	return st, nil
+}
+
+// summarizeStockTake - hitung jumlah baris dan nilai selisih. Baris yang belum dihitung
+// tidak menyumbang selisih.
+func summarizeStockTake(lines []models.StockTakeLine) models.StockTakeSummary {
+	sum := models.StockTakeSummary{Lines: len(lines)}
+	for _, l := range lines {
+		if l.Variance == nil {
+			sum.Uncounted++
+			continue
+		}
+		sum.Counted++
+		if *l.Variance == 0 {
+			continue
+		}
+		sum.WithVariance++
+		sum.NetQuantity += *l.Variance
+		if *l.VarianceValue < 0 {
+			sum.Shortage += *l.VarianceValue
+		} else {
+			sum.Surplus += *l.VarianceValue
+		}
+	}
+	sum.NetValue = sum.Shortage + sum.Surplus
+	return sum
+}
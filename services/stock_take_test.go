@@ -0,0 +1,51 @@
+package services
+
+import (
+	"kasir-api/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func stockTakeLine(system, cost int, counted *int) models.StockTakeLine {
+	l := models.StockTakeLine{SystemStock: system, Cost: cost, Counted: counted}
+	if counted != nil {
+		variance := *counted - system
+		value := variance * cost
+		l.Variance, l.VarianceValue = &variance, &value
+	}
+	return l
+}
+
+func TestSummarizeStockTake(t *testing.T) {
+	three, twelve, five := 3, 12, 5
+	lines := []models.StockTakeLine{
+		stockTakeLine(5, 8000, &three),   // kurang 2
+		stockTakeLine(10, 2500, &twelve), // lebih 2
+		stockTakeLine(5, 1000, &five),    // sesuai
+		stockTakeLine(7, 4000, nil),      // belum dihitung
+	}
+
+	sum := summarizeStockTake(lines)
+	assert.Equal(t, 4, sum.Lines)
+	assert.Equal(t, 3, sum.Counted)
+	assert.Equal(t, 1, sum.Uncounted)
+	assert.Equal(t, 2, sum.WithVariance)
+	assert.Equal(t, 0, sum.NetQuantity)
+	assert.Equal(t, -16000, sum.Shortage)
+	assert.Equal(t, 5000, sum.Surplus)
+	assert.Equal(t, -11000, sum.NetValue)
+}
+
+func TestStockTakeCountValidation(t *testing.T) {
+	s := &StockTakeService{}
+
+	_, err := s.Count(1, &models.StockTakeCountRequest{})
+	assert.Error(t, err)
+
+	_, err = s.Count(1, &models.StockTakeCountRequest{Entries: []models.StockTakeCountEntry{{Code: "  ", Quantity: 2}}})
+	assert.Error(t, err)
+
+	_, err = s.Count(1, &models.StockTakeCountRequest{Replace: true, Entries: []models.StockTakeCountEntry{{ProductID: 3, Quantity: -1}}})
+	assert.Error(t, err)
+}
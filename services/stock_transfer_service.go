@@ -0,0 +1,82 @@
+package services
+
+import (
+	"fmt"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"strings"
+)
+
+type StockTransferService struct {
+	repo *repositories.StockTransferRepository
+}
+
+func NewStockTransferService(repo *repositories.StockTransferRepository) *StockTransferService {
+	return &StockTransferService{repo: repo}
+}
+
+func (s *StockTransferService) GetAll(filter models.StockTransferFilter) (*models.Page[models.StockTransfer], error) {
+	v := &validator{}
+	v.check(filter.Status == "" || validTransferStatus(filter.Status), "status", "harus draft, sent, received atau cancelled")
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	filter.Page, filter.Limit = normalizePage(filter.Page, filter.Limit)
+	transfers, total, err := s.repo.GetAll(filter)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewPage(transfers, filter.Page, filter.Limit, total), nil
+}
+
+func (s *StockTransferService) GetByID(id int) (*models.StockTransfer, error) {
+	return s.repo.GetByID(id)
+}
+
+// Create - buat transfer draft; outlet asal dan tujuan diperiksa di repository
+// karena harus masih aktif
+func (s *StockTransferService) Create(req *models.StockTransferRequest) (*models.StockTransfer, error) {
+	req.Note = strings.TrimSpace(req.Note)
+
+	v := &validator{}
+	v.check(req.FromOutletID > 0, "from_outlet_id", "wajib diisi")
+	v.check(req.ToOutletID > 0, "to_outlet_id", "wajib diisi")
+	v.check(req.FromOutletID != req.ToOutletID, "to_outlet_id", "harus berbeda dengan outlet asal")
+	v.maxLength(req.Note, "note", 1000)
+	v.check(len(req.Lines) > 0, "lines", "minimal satu baris")
+	seen := make(map[int]bool, len(req.Lines))
+	for i, l := range req.Lines {
+		field := fmt.Sprintf("lines[%d]", i)
+		v.check(l.ProductID > 0, field+".product_id", "wajib diisi")
+		v.check(!seen[l.ProductID], field+".product_id", "produk duplikat, gabungkan jumlahnya")
+		v.check(l.Quantity > 0, field+".quantity", "harus lebih dari 0")
+		seen[l.ProductID] = true
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	return s.repo.Create(req)
+}
+
+// Send - kirim barang dari outlet asal, stok asal berkurang
+func (s *StockTransferService) Send(id int, user string) (*models.StockTransfer, error) {
+	return s.repo.Send(id, user)
+}
+
+// Receive - terima barang di outlet tujuan, stok tujuan bertambah
+func (s *StockTransferService) Receive(id int, user string) (*models.StockTransfer, error) {
+	return s.repo.Receive(id, user)
+}
+
+func (s *StockTransferService) Cancel(id int) (*models.StockTransfer, error) {
+	return s.repo.Cancel(id)
+}
+
+func validTransferStatus(status string) bool {
+	switch status {
+	case models.TransferDraft, models.TransferSent, models.TransferReceived, models.TransferCancelled:
+		return true
+	}
+	return false
+}
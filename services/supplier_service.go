@@ -0,0 +1,76 @@
+package services
+
+import (
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"net/mail"
+	"strings"
+)
+
+type SupplierService struct {
+	repo *repositories.SupplierRepository
+}
+
+func NewSupplierService(repo *repositories.SupplierRepository) *SupplierService {
+	return &SupplierService{repo: repo}
+}
+
+func (s *SupplierService) GetAll(filter models.SupplierFilter) (*models.Page[models.Supplier], error) {
+	filter.Page, filter.Limit = normalizePage(filter.Page, filter.Limit)
+	filter.Search = strings.TrimSpace(filter.Search)
+	suppliers, total, err := s.repo.GetAll(filter)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewPage(suppliers, filter.Page, filter.Limit, total), nil
+}
+
+func (s *SupplierService) GetByID(id int) (*models.Supplier, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *SupplierService) Create(supplier *models.Supplier) error {
+	if err := validateSupplier(supplier); err != nil {
+		return err
+	}
+	return s.repo.Create(supplier)
+}
+
+func (s *SupplierService) Update(supplier *models.Supplier) error {
+	if err := validateSupplier(supplier); err != nil {
+		return err
+	}
+	return s.repo.Update(supplier)
+}
+
+func (s *SupplierService) Delete(id int) error {
+	return s.repo.Delete(id)
+}
+
+// validateSupplier - nomor telepon supplier opsional dan bisa berupa nomor kantor,
+// sehingga hanya dinormalisasi jika formatnya nomor Indonesia yang valid
+func validateSupplier(supplier *models.Supplier) error {
+	v := &validator{}
+	supplier.Name = strings.TrimSpace(supplier.Name)
+	supplier.ContactName = strings.TrimSpace(supplier.ContactName)
+	supplier.Phone = strings.TrimSpace(supplier.Phone)
+	supplier.Email = strings.TrimSpace(supplier.Email)
+	supplier.Address = strings.TrimSpace(supplier.Address)
+	supplier.Notes = strings.TrimSpace(supplier.Notes)
+	v.required(supplier.Name, "name", 255)
+	v.maxLength(supplier.ContactName, "contact_name", 255)
+	v.maxLength(supplier.Address, "address", 1000)
+	v.maxLength(supplier.Notes, "notes", 1000)
+
+	if phone, err := NormalizePhone(supplier.Phone); err == nil {
+		supplier.Phone = phone
+	}
+	v.maxLength(supplier.Phone, "phone", 20)
+
+	if supplier.Email != "" {
+		_, err := mail.ParseAddress(supplier.Email)
+		v.check(err == nil, "email", "format email tidak valid")
+		v.maxLength(supplier.Email, "email", 255)
+	}
+	return v.err()
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"strings"
+)
+
+type TaxRateService struct {
+	repo repositories.TaxRateRepositoryInterface
+}
+
+func NewTaxRateService(repo repositories.TaxRateRepositoryInterface) *TaxRateService {
+	return &TaxRateService{repo: repo}
+}
+
+func (s *TaxRateService) GetAll() ([]models.TaxRate, error) {
+	return s.repo.GetAll()
+}
+
+func (s *TaxRateService) GetByID(id int) (*models.TaxRate, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *TaxRateService) Create(rate *models.TaxRate) error {
+	if err := validateTaxRate(rate); err != nil {
+		return err
+	}
+	return s.repo.Create(rate)
+}
+
+func (s *TaxRateService) Update(rate *models.TaxRate) error {
+	if err := validateTaxRate(rate); err != nil {
+		return err
+	}
+	return s.repo.Update(rate)
+}
+
+func (s *TaxRateService) Delete(id int) error {
+	return s.repo.Delete(id)
+}
+
+func validateTaxRate(rate *models.TaxRate) error {
+	v := &validator{}
+	rate.Name = strings.TrimSpace(rate.Name)
+	v.required(rate.Name, "name", 100)
+	v.check(rate.RateBps >= 0 && rate.RateBps <= 10000, "rate_bps", "harus antara 0 dan 10000 (0% - 100%)")
+	return v.err()
+}
+
+// checkTaxRate - pastikan tarif pajak yang dipilih produk atau kategori ada; 0 berarti ikut default
+func checkTaxRate(v *validator, repo repositories.TaxRateRepositoryInterface, id int) error {
+	if id < 0 {
+		v.add("tax_rate_id", "tidak valid")
+		return nil
+	}
+	if id == 0 {
+		return nil
+	}
+	_, err := repo.GetByID(id)
+	return v.exists(err, "tax_rate_id", "tarif pajak tidak ditemukan")
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"kasir-api/models"
+	"strings"
+	"time"
+)
+
+// tokenHeader - header JWT HS256 yang sudah di-encode
+var tokenHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// SignToken - buat JWT HS256 dari claims
+func SignToken(claims models.Claims, secret []byte) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := tokenHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return unsigned + "." + tokenSignature(unsigned, secret), nil
+}
+
+// ParseToken - verifikasi tanda tangan dan masa berlaku token lalu kembalikan claims
+func ParseToken(token string, secret []byte, now time.Time) (*models.Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != tokenHeader {
+		return nil, errors.New("token tidak valid")
+	}
+
+	expected := tokenSignature(parts[0]+"."+parts[1], secret)
+	if !hmac.Equal([]byte(expected), []byte(parts[2])) {
+		return nil, errors.New("token tidak valid")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, errors.New("token tidak valid")
+	}
+
+	var claims models.Claims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, errors.New("token tidak valid")
+	}
+	if now.Unix() >= claims.ExpiresAt {
+		return nil, errors.New("token sudah kedaluwarsa")
+	}
+
+	return &claims, nil
+}
+
+func tokenSignature(unsigned string, secret []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(unsigned))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
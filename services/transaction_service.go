@@ -0,0 +1,177 @@
+package services
+
+import (
+	"fmt"
+	"kasir-api/models"
+	"kasir-api/payment"
+	"kasir-api/repositories"
+	"strings"
+)
+
+type TransactionService struct {
+	repo         *repositories.TransactionRepository
+	paymentRepo  *repositories.PaymentRepository
+	customerRepo *repositories.CustomerRepository
+	loyaltyRepo  *repositories.LoyaltyRepository
+	pricing      *Pricing
+	providers    payment.Providers
+	requireShift bool
+}
+
+func NewTransactionService(repo *repositories.TransactionRepository, paymentRepo *repositories.PaymentRepository, customerRepo *repositories.CustomerRepository, loyaltyRepo *repositories.LoyaltyRepository, pricing *Pricing, providers payment.Providers, requireShift bool) *TransactionService {
+	return &TransactionService{
+		repo:         repo,
+		paymentRepo:  paymentRepo,
+		customerRepo: customerRepo,
+		loyaltyRepo:  loyaltyRepo,
+		pricing:      pricing,
+		providers:    providers,
+		requireShift: requireShift,
+	}
+}
+
+// Checkout - validasi keranjang, terapkan promosi dan pajak lalu simpan transaksi.
+// Pembayaran QRIS/e-wallet dibuatkan tagihan ke provider setelah transaksi tersimpan;
+// transaksi tetap pending sampai callback pembayaran diterima. Transaksi dengan
+// pelanggan mendapat poin sesuai aturan loyalty dan boleh dibayar dengan poin.
+func (s *TransactionService) Checkout(req *models.CheckoutRequest) (*models.Transaction, error) {
+	var loyalty *models.LoyaltySettings
+	if req.CustomerID > 0 {
+		settings, err := s.loyaltyRepo.GetSettings()
+		if err != nil {
+			return nil, err
+		}
+		loyalty = settings
+	}
+
+	v := &validator{}
+	v.check(req.PaidAmount >= 0, "paid_amount", "tidak boleh negatif")
+	v.check(req.OutletID >= 0, "outlet_id", "tidak valid")
+	v.check(len(req.Payments) == 0 || req.PaidAmount == 0, "paid_amount", "gunakan payments atau paid_amount, tidak keduanya")
+	for i, p := range req.Payments {
+		field := fmt.Sprintf("payments[%d]", i)
+		switch {
+		case !models.ValidPaymentMethod(p.Method):
+			v.add(field+".method", "harus cash, debit_card, credit_card, qris, ewallet, store_credit atau points")
+		case models.PaymentNeedsProvider(p.Method) && s.providers[p.Method] == nil:
+			v.add(field+".method", "metode pembayaran belum tersedia")
+		}
+		v.check(p.Amount > 0, field+".amount", "harus lebih dari 0")
+		v.maxLength(p.Reference, field+".reference", 100)
+		if p.Method == models.PaymentPoints {
+			checkPointPayment(v, field, p.Amount, loyalty)
+		}
+	}
+	items := checkoutItems(v, req)
+	if err := checkCustomer(v, s.customerRepo, req.CustomerID); err != nil {
+		return nil, err
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	price, err := s.pricing.Pricer(req.VoucherCode)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction, err := s.repo.CreateTransaction(s.checkoutRequest(req, items, loyalty), price)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.createCharges(transaction); err != nil {
+		return nil, err
+	}
+	return transaction, nil
+}
+
+// checkoutRequest - susun permintaan yang diteruskan ke repository dari keranjang
+// yang sudah divalidasi. Outlet 0 berarti outlet default.
+func (s *TransactionService) checkoutRequest(req *models.CheckoutRequest, items []models.CheckoutItem, loyalty *models.LoyaltySettings) *models.CheckoutRequest {
+	return &models.CheckoutRequest{
+		Items:        items,
+		Payments:     req.Payments,
+		PaidAmount:   req.PaidAmount,
+		VoucherCode:  strings.ToUpper(strings.TrimSpace(req.VoucherCode)),
+		CustomerID:   req.CustomerID,
+		OutletID:     req.OutletID,
+		Cashier:      req.Cashier,
+		RequireShift: s.requireShift,
+		Loyalty:      loyalty,
+	}
+}
+
+// createCharges - minta tagihan ke provider untuk setiap pembayaran pending. Jika
+// provider gagal, pembayaran ditandai failed sehingga transaksi batal dan stok kembali.
+func (s *TransactionService) createCharges(transaction *models.Transaction) error {
+	for i := range transaction.Payments {
+		p := &transaction.Payments[i]
+		if p.Status != models.PaymentPending {
+			continue
+		}
+
+		provider := s.providers[p.Method]
+		charge, err := provider.CreateCharge(payment.ChargeRequest{
+			Reference: fmt.Sprintf("TRX-%d-P%d", transaction.ID, p.ID),
+			Method:    p.Method,
+			Amount:    p.Amount,
+		})
+		if err != nil {
+			if _, failErr := s.paymentRepo.UpdateStatus(p.ID, models.PaymentFailed); failErr != nil {
+				return failErr
+			}
+			return fmt.Errorf("gagal membuat tagihan %s: %w", p.Method, err)
+		}
+
+		p.Provider = provider.Name()
+		p.ProviderRef = charge.ProviderRef
+		p.Payload = charge.Payload
+		p.ExpiresAt = &charge.ExpiresAt
+		if err := s.paymentRepo.SetCharge(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *TransactionService) GetByID(id int) (*models.Transaction, error) {
+	return s.repo.GetByID(id)
+}
+
+// checkPointPayment - pembayaran poin membutuhkan pelanggan, program poin yang aktif
+// dan nominal kelipatan nilai poin. Kecukupan saldo dicek saat saldo dikunci.
+func checkPointPayment(v *validator, field string, amount int, settings *models.LoyaltySettings) {
+	switch {
+	case settings == nil:
+		v.add(field+".method", "pembayaran poin membutuhkan customer_id")
+	case !settings.Active:
+		v.add(field+".method", "program poin tidak aktif")
+	case amount > 0 && amount%settings.PointValue != 0:
+		v.add(field+".amount", fmt.Sprintf("harus kelipatan Rp %d", settings.PointValue))
+	}
+}
+
+// checkoutItems - validasi baris keranjang lalu gabungkan baris dengan produk yang sama
+// supaya pengecekan stok dan promosi per produk akurat
+func checkoutItems(v *validator, req *models.CheckoutRequest) []models.CheckoutItem {
+	items := req.Items
+	v.check(len(items) > 0, "items", "keranjang kosong")
+	for i, item := range items {
+		v.check(item.ProductID > 0, fmt.Sprintf("items[%d].product_id", i), "wajib diisi")
+		v.check(item.Quantity > 0, fmt.Sprintf("items[%d].quantity", i), "harus lebih dari 0")
+	}
+	v.maxLength(req.VoucherCode, "voucher_code", 50)
+
+	merged := make([]models.CheckoutItem, 0, len(items))
+	index := make(map[int]int, len(items))
+	for _, item := range items {
+		if i, ok := index[item.ProductID]; ok {
+			merged[i].Quantity += item.Quantity
+			continue
+		}
+		index[item.ProductID] = len(merged)
+		merged = append(merged, item)
+	}
+	return merged
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"errors"
+	"kasir-api/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckoutRequestForwardsOutlet(t *testing.T) {
+	s := &TransactionService{requireShift: true}
+	req := &models.CheckoutRequest{
+		OutletID:    2,
+		Items:       []models.CheckoutItem{{ProductID: 1, Quantity: 3}},
+		PaidAmount:  10000,
+		VoucherCode: " hemat ",
+		CustomerID:  4,
+		Cashier:     "siti",
+	}
+
+	got := s.checkoutRequest(req, req.Items, nil)
+	assert.Equal(t, 2, got.OutletID)
+	assert.Equal(t, "HEMAT", got.VoucherCode)
+	assert.Equal(t, 4, got.CustomerID)
+	assert.Equal(t, "siti", got.Cashier)
+	assert.True(t, got.RequireShift)
+}
+
+func TestCheckoutRejectsNegativeOutlet(t *testing.T) {
+	s := &TransactionService{}
+	_, err := s.Checkout(&models.CheckoutRequest{
+		OutletID: -1,
+		Items:    []models.CheckoutItem{{ProductID: 1, Quantity: 1}},
+	})
+
+	var validationErr *ValidationError
+	if assert.True(t, errors.As(err, &validationErr)) {
+		assert.Equal(t, []FieldError{{Field: "outlet_id", Message: "tidak valid"}}, validationErr.Fields)
+	}
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"errors"
+	"kasir-api/repositories"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// FieldError adalah pesan validasi untuk satu field request
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError berisi semua field yang tidak valid. errors.Is terhadap
+// repositories.ErrValidation bernilai true sehingga handler memperlakukannya sebagai 422.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		messages[i] = f.Field + ": " + f.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e *ValidationError) Unwrap() error {
+	return repositories.ErrValidation
+}
+
+// validator mengumpulkan error per field supaya client menerima semuanya sekaligus
+type validator struct {
+	fields []FieldError
+}
+
+func (v *validator) add(field, message string) {
+	v.fields = append(v.fields, FieldError{Field: field, Message: message})
+}
+
+// check - tambahkan error untuk field jika kondisi ok bernilai false
+func (v *validator) check(ok bool, field, message string) {
+	if !ok {
+		v.add(field, message)
+	}
+}
+
+func (v *validator) required(value, field string, max int) {
+	if strings.TrimSpace(value) == "" {
+		v.add(field, "wajib diisi")
+		return
+	}
+	v.maxLength(value, field, max)
+}
+
+func (v *validator) maxLength(value, field string, max int) {
+	if utf8.RuneCountInString(value) > max {
+		v.add(field, "maksimal "+strconv.Itoa(max)+" karakter")
+	}
+}
+
+// err - nil jika tidak ada field yang gagal
+func (v *validator) err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return &ValidationError{Fields: v.fields}
+}
+
+// exists - cek keberadaan data terkait; not found menjadi field error, error lain diteruskan
+func (v *validator) exists(err error, field, message string) error {
+	if errors.Is(err, repositories.ErrNotFound) {
+		v.add(field, message)
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,40 @@
+package services
+
+import (
+	"errors"
+	"kasir-api/models"
+	"kasir-api/repositories"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCategory(t *testing.T) {
+	category := &models.Category{Name: "  Minuman  ", Description: " Dingin "}
+	assert.NoError(t, validateCategory(category))
+	assert.Equal(t, "Minuman", category.Name)
+	assert.Equal(t, "Dingin", category.Description)
+
+	err := validateCategory(&models.Category{Name: "   ", Description: strings.Repeat("x", 1001)})
+
+	var validationErr *ValidationError
+	if assert.True(t, errors.As(err, &validationErr)) {
+		assert.Equal(t, []FieldError{
+			{Field: "name", Message: "wajib diisi"},
+			{Field: "description", Message: "maksimal 1000 karakter"},
+		}, validationErr.Fields)
+	}
+	assert.True(t, errors.Is(err, repositories.ErrValidation))
+}
+
+func TestValidatorExists(t *testing.T) {
+	v := &validator{}
+
+	err := v.exists(repositories.NewError(repositories.ErrNotFound, "kategori tidak ditemukan"), "category_id", "kategori tidak ditemukan")
+	assert.NoError(t, err)
+	assert.Equal(t, []FieldError{{Field: "category_id", Message: "kategori tidak ditemukan"}}, v.fields)
+
+	outage := errors.New("connection refused")
+	assert.Equal(t, outage, v.exists(outage, "category_id", "kategori tidak ditemukan"))
+}
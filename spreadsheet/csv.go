@@ -0,0 +1,45 @@
+package spreadsheet
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"io"
+)
+
+// utf8BOM ditambahkan Excel di awal file CSV UTF-8
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// ReadCSV - baca semua baris CSV. Pemisah koma atau titik koma (ekspor Excel dengan
+// regional Indonesia) dideteksi dari baris pertama, dan BOM UTF-8 diabaikan.
+func ReadCSV(r io.Reader) ([][]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimPrefix(data, utf8BOM)
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = detectDelimiter(data)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	return reader.ReadAll()
+}
+
+// detectDelimiter - titik koma jika baris pertama lebih banyak memuat titik koma daripada koma
+func detectDelimiter(data []byte) rune {
+	line, _, _ := bufio.NewReader(bytes.NewReader(data)).ReadLine()
+	if bytes.Count(line, []byte(";")) > bytes.Count(line, []byte(",")) {
+		return ';'
+	}
+	return ','
+}
+
+// WriteCSV - tulis baris sebagai CSV dengan pemisah koma
+func WriteCSV(w io.Writer, rows [][]string) error {
+	writer := csv.NewWriter(w)
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
@@ -0,0 +1,101 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadCSVSemicolonWithBOM(t *testing.T) {
+	data := "\xEF\xBB\xBFsku;name;price\nKP-01;\"Kopi; Susu\";18000\n"
+	rows, err := ReadCSV(strings.NewReader(data))
+
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"sku", "name", "price"}, {"KP-01", "Kopi; Susu", "18000"}}, rows)
+}
+
+func TestWriteCSVRoundTrip(t *testing.T) {
+	rows := [][]string{{"sku", "name"}, {"A-1", "Teh, manis"}}
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, rows))
+
+	read, err := ReadCSV(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, rows, read)
+}
+
+func TestXLSXRoundTrip(t *testing.T) {
+	rows := [][]string{
+		{"sku", "name", "price"},
+		{"00123", "Roti <Tawar> & Selai", "15000"},
+		{"B-2", "", "abc"},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, WriteXLSX(&buf, "Produk", rows, 2))
+
+	read, err := ReadXLSX(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	assert.Equal(t, rows, read)
+	// Hanya kolom harga yang ditulis sebagai angka; SKU "00123" tetap teks
+	sheet := readPart(t, buf.Bytes(), "xl/worksheets/sheet1.xml")
+	assert.Contains(t, sheet, `<c r="C2"><v>15000</v></c>`)
+	assert.NotContains(t, sheet, `<c r="A2"><v>`)
+}
+
+func TestReadXLSXSharedStringsAndGaps(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	parts := map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+			<sheets><sheet name="Data" sheetId="1" r:id="rId7"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+			<Relationship Id="rId7" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="/xl/worksheets/data.xml"/>
+			</Relationships>`,
+		"xl/sharedStrings.xml": `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+			<si><t>sku</t></si><si><r><t>Kopi </t></r><r><t>Gula Aren</t></r></si></sst>`,
+		"xl/worksheets/data.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+			<row r="1"><c r="A1" t="s"><v>0</v></c></row>
+			<row r="3"><c r="A3" t="s"><v>1</v></c><c r="C3"><v>22000</v></c></row>
+			</sheetData></worksheet>`,
+	}
+	for name, content := range parts {
+		f, err := zw.Create(name)
+		require.NoError(t, err)
+		io.WriteString(f, content)
+	}
+	require.NoError(t, zw.Close())
+
+	rows, err := ReadXLSX(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"sku"}, {}, {"Kopi Gula Aren", "", "22000"}}, rows)
+}
+
+func TestReadXLSXInvalid(t *testing.T) {
+	_, err := ReadXLSX(strings.NewReader("bukan zip"), 9)
+	assert.ErrorIs(t, err, ErrInvalidXLSX)
+}
+
+func TestColumnName(t *testing.T) {
+	for index, name := range map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"} {
+		assert.Equal(t, name, columnName(index))
+		i, err := columnIndex(name + "12")
+		assert.NoError(t, err)
+		assert.Equal(t, index, i)
+	}
+}
+
+func readPart(t *testing.T, data []byte, name string) string {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	f, err := zr.Open(name)
+	require.NoError(t, err)
+	defer f.Close()
+	content, err := io.ReadAll(f)
+	require.NoError(t, err)
+	return string(content)
+}
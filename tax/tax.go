@@ -0,0 +1,107 @@
+package tax
+
+import (
+	"fmt"
+	"kasir-api/models"
+	"sort"
+)
+
+// Apply - hitung DPP dan pajak setiap baris dari harga bersih setelah diskon.
+//
+// Harga inclusive sudah termasuk pajak: DPP = harga x 10000 / (10000 + tarif) dan
+// total tidak berubah. Harga exclusive belum termasuk pajak: DPP = harga dan pajak
+// ditambahkan ke total.
+//
+// Pajak dihitung sekali per tarif dari jumlah semua baris bertarif sama dan dibulatkan
+// half-up ke rupiah, lalu dibagi ke baris sebanding harga supaya jumlah per baris sama
+// dengan ringkasan. Baris tanpa tarif memakai tarif default; jika tidak ada default
+// baris dianggap tidak kena pajak.
+func Apply(basket *models.PricedBasket, lines []models.BasketLine, rates []models.TaxRate, inclusive bool) {
+	byID := make(map[int]models.TaxRate, len(rates))
+	var fallback *models.TaxRate
+	for i, r := range rates {
+		byID[r.ID] = r
+		if r.IsDefault {
+			fallback = &rates[i]
+		}
+	}
+
+	basket.PricesIncludeTax = inclusive
+	basket.Taxes = make([]models.TaxSummary, 0)
+
+	groups := make(map[int][]int)
+	order := make([]int, 0)
+	for i := range basket.Lines {
+		line := &basket.Lines[i]
+		line.DPP = line.Total
+
+		rate, ok := byID[lines[i].TaxRateID]
+		if !ok {
+			if fallback == nil {
+				continue
+			}
+			rate = *fallback
+		}
+		line.TaxRateID = rate.ID
+		line.TaxRateBps = rate.RateBps
+
+		if _, seen := groups[rate.ID]; !seen {
+			order = append(order, rate.ID)
+		}
+		groups[rate.ID] = append(groups[rate.ID], i)
+	}
+	sort.Ints(order)
+
+	for _, id := range order {
+		rate := byID[id]
+		indexes := groups[id]
+
+		weights := make([]int, len(indexes))
+		base := 0
+		for n, i := range indexes {
+			weights[n] = basket.Lines[i].Total
+			base += weights[n]
+		}
+
+		summary := models.TaxSummary{TaxRateID: rate.ID, Name: rate.Name, RateBps: rate.RateBps}
+		if inclusive {
+			summary.DPP = roundDiv(base*10000, 10000+rate.RateBps)
+			summary.Tax = base - summary.DPP
+		} else {
+			summary.DPP = base
+			summary.Tax = roundDiv(base*rate.RateBps, 10000)
+		}
+
+		for n, share := range models.Split(summary.Tax, weights) {
+			line := &basket.Lines[indexes[n]]
+			line.Tax = share
+			if inclusive {
+				line.DPP = line.Total - share
+			} else {
+				line.Total += share
+			}
+		}
+
+		basket.Taxes = append(basket.Taxes, summary)
+		basket.TaxTotal += summary.Tax
+		if !inclusive {
+			basket.Total += summary.Tax
+		}
+	}
+}
+
+// roundDiv - pembagian bilangan tidak negatif dengan pembulatan half-up
+func roundDiv(a, b int) int {
+	return (2*a + b) / (2 * b)
+}
+
+// FormatRate - tarif basis poin sebagai persen, misalnya 1100 menjadi "11%" dan 1150 menjadi "11,5%"
+func FormatRate(bps int) string {
+	if bps%100 == 0 {
+		return fmt.Sprintf("%d%%", bps/100)
+	}
+	if bps%10 == 0 {
+		return fmt.Sprintf("%d,%d%%", bps/100, bps%100/10)
+	}
+	return fmt.Sprintf("%d,%02d%%", bps/100, bps%100)
+}
@@ -0,0 +1,108 @@
+package tax
+
+import (
+	"kasir-api/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var rates = []models.TaxRate{
+	{ID: 1, Name: "PPN", RateBps: 1100, IsDefault: true},
+	{ID: 2, Name: "Bebas PPN", RateBps: 0},
+	{ID: 3, Name: "PPnBM", RateBps: 1200},
+}
+
+func lines() []models.BasketLine {
+	return []models.BasketLine{
+		{ProductID: 1, ProductName: "Kopi", Quantity: 3, Price: 10000},
+		{ProductID: 2, ProductName: "Beras", Quantity: 1, Price: 50000, TaxRateID: 2},
+		{ProductID: 3, ProductName: "Teh", Quantity: 1, Price: 5555},
+	}
+}
+
+// priced - keranjang tanpa diskon seperti hasil engine promosi
+func priced(l []models.BasketLine) *models.PricedBasket {
+	basket := &models.PricedBasket{Lines: make([]models.PricedLine, len(l))}
+	for i, line := range l {
+		subtotal := line.Price * line.Quantity
+		basket.Lines[i] = models.PricedLine{ProductID: line.ProductID, Quantity: line.Quantity, Price: line.Price, Subtotal: subtotal, Total: subtotal}
+		basket.Subtotal += subtotal
+	}
+	basket.Total = basket.Subtotal
+	return basket
+}
+
+func TestApplyInclusive(t *testing.T) {
+	l := lines()
+	basket := priced(l)
+	Apply(basket, l, rates, true)
+
+	// Kopi + Teh kena PPN 11%: 35.555 x 10000 / 11100 = 32.031,5 -> 32.032
+	assert.Equal(t, []models.TaxSummary{
+		{TaxRateID: 1, Name: "PPN", RateBps: 1100, DPP: 32032, Tax: 3523},
+		{TaxRateID: 2, Name: "Bebas PPN", RateBps: 0, DPP: 50000, Tax: 0},
+	}, basket.Taxes)
+	assert.Equal(t, 3523, basket.TaxTotal)
+	assert.Equal(t, 85555, basket.Total)
+	assert.True(t, basket.PricesIncludeTax)
+
+	// Pajak per baris berjumlah sama dengan ringkasan dan DPP + pajak = harga baris
+	assert.Equal(t, 3523, basket.Lines[0].Tax+basket.Lines[2].Tax)
+	for _, line := range basket.Lines {
+		assert.Equal(t, line.Total, line.DPP+line.Tax)
+	}
+	assert.Equal(t, 1100, basket.Lines[0].TaxRateBps)
+	assert.Equal(t, 0, basket.Lines[1].Tax)
+}
+
+func TestApplyExclusive(t *testing.T) {
+	l := lines()
+	basket := priced(l)
+	Apply(basket, l, rates, false)
+
+	// 35.555 x 11% = 3.911,05 -> 3.911
+	assert.Equal(t, 3911, basket.TaxTotal)
+	assert.Equal(t, 85555+3911, basket.Total)
+	assert.Equal(t, 30000, basket.Lines[0].DPP)
+	assert.Equal(t, basket.Total, basket.Lines[0].Total+basket.Lines[1].Total+basket.Lines[2].Total)
+}
+
+func TestApplyAfterDiscount(t *testing.T) {
+	l := lines()[:1]
+	basket := priced(l)
+	basket.Lines[0].Discount = 3000
+	basket.Lines[0].Total = 27000
+	basket.DiscountTotal = 3000
+	basket.Total = 27000
+	Apply(basket, l, rates, false)
+
+	// Pajak dihitung dari 27.000 setelah diskon
+	assert.Equal(t, 27000, basket.Lines[0].DPP)
+	assert.Equal(t, 2970, basket.TaxTotal)
+	assert.Equal(t, 29970, basket.Total)
+}
+
+func TestApplyWithoutDefault(t *testing.T) {
+	l := lines()
+	basket := priced(l)
+	Apply(basket, l, rates[1:], true)
+
+	assert.Equal(t, 0, basket.TaxTotal)
+	assert.Len(t, basket.Taxes, 1)
+	assert.Equal(t, 0, basket.Lines[0].TaxRateBps)
+	assert.Equal(t, 30000, basket.Lines[0].DPP)
+}
+
+func TestRoundDivHalfUp(t *testing.T) {
+	assert.Equal(t, 2, roundDiv(5, 3))
+	assert.Equal(t, 3, roundDiv(5, 2))
+	assert.Equal(t, 2, roundDiv(9, 4))
+}
+
+func TestFormatRate(t *testing.T) {
+	assert.Equal(t, "11%", FormatRate(1100))
+	assert.Equal(t, "11,5%", FormatRate(1150))
+	assert.Equal(t, "0,25%", FormatRate(25))
+	assert.Equal(t, "0%", FormatRate(0))
+}